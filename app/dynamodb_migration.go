@@ -0,0 +1,183 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/expression"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/refandas/duit-api/helper"
+	"github.com/refandas/duit-api/model/domain"
+	"github.com/refandas/duit-api/repository"
+)
+
+// MigrateSpendingAmounts converts the amounts of the spendings in the given
+// DynamoDB `Spending` table recorded as a number, in the major unit of the
+// default currency, into money in minor units, and returns the number of
+// converted spendings.
+//
+// Only the amounts still recorded as a number are converted, each on the
+// condition it has not been converted in the meantime, so the migration can
+// be run again after it is interrupted and while the API server is running.
+func MigrateSpendingAmounts(ctx context.Context, db *helper.DynamoDB) (int, error) {
+	filter := expression.Name("Amount").AttributeType(expression.Number)
+	expr, err := expression.NewBuilder().
+		WithFilter(filter).
+		WithProjection(expression.NamesList(expression.Name("Id"), expression.Name("Amount"))).
+		Build()
+	if err != nil {
+		return 0, err
+	}
+
+	converted := 0
+	paginator := dynamodb.NewScanPaginator(db.Client, &dynamodb.ScanInput{
+		TableName:                 aws.String(db.TableName),
+		ExpressionAttributeNames:  expr.Names(),
+		ExpressionAttributeValues: expr.Values(),
+		FilterExpression:          expr.Filter(),
+		ProjectionExpression:      expr.Projection(),
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return converted, err
+		}
+
+		for _, item := range page.Items {
+			var spending struct {
+				Id     string
+				Amount float64
+			}
+			if err := attributevalue.UnmarshalMap(item, &spending); err != nil {
+				return converted, err
+			}
+
+			err := convertAmount(ctx, db, spending.Id, domain.MoneyFromFloat(spending.Amount, domain.DefaultCurrency))
+			var conditionFailed *types.ConditionalCheckFailedException
+			if errors.As(err, &conditionFailed) {
+				continue
+			}
+			if err != nil {
+				return converted, err
+			}
+			converted++
+		}
+	}
+	return converted, nil
+}
+
+// convertAmount replaces the amount of the item with the given id by the
+// given money, unless the amount is not a number anymore.
+func convertAmount(ctx context.Context, db *helper.DynamoDB, spendingId string, amount domain.Money) error {
+	expr, err := expression.NewBuilder().
+		WithUpdate(expression.Set(expression.Name("Amount"), expression.Value(amount))).
+		WithCondition(expression.Name("Amount").AttributeType(expression.Number)).
+		Build()
+	if err != nil {
+		return err
+	}
+
+	_, err = db.Client.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+		TableName:                 aws.String(db.TableName),
+		Key:                       map[string]types.AttributeValue{"Id": &types.AttributeValueMemberS{Value: spendingId}},
+		ExpressionAttributeNames:  expr.Names(),
+		ExpressionAttributeValues: expr.Values(),
+		UpdateExpression:          expr.Update(),
+		ConditionExpression:       expr.Condition(),
+	})
+	return err
+}
+
+// MigrateBudgetAmounts converts the amounts of the budgets in the given
+// DynamoDB `Budgets` table recorded as a number, in the major unit of the
+// home currency of their user, into money in minor units, and returns the
+// number of converted budgets. The home currencies are read from the given
+// `Users` table.
+//
+// Like MigrateSpendingAmounts, the migration can be run again after it is
+// interrupted and while the API server is running.
+func MigrateBudgetAmounts(ctx context.Context, budgets *helper.DynamoDB, users *helper.DynamoDB) (int, error) {
+	filter := expression.Name("Amount").AttributeType(expression.Number)
+	expr, err := expression.NewBuilder().
+		WithFilter(filter).
+		WithProjection(expression.NamesList(expression.Name("Id"), expression.Name("UserId"), expression.Name("Amount"))).
+		Build()
+	if err != nil {
+		return 0, err
+	}
+
+	currencies := map[string]string{}
+	converted := 0
+	paginator := dynamodb.NewScanPaginator(budgets.Client, &dynamodb.ScanInput{
+		TableName:                 aws.String(budgets.TableName),
+		ExpressionAttributeNames:  expr.Names(),
+		ExpressionAttributeValues: expr.Values(),
+		FilterExpression:          expr.Filter(),
+		ProjectionExpression:      expr.Projection(),
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return converted, err
+		}
+
+		for _, item := range page.Items {
+			var budget struct {
+				Id     string
+				UserId string
+				Amount float64
+			}
+			if err := attributevalue.UnmarshalMap(item, &budget); err != nil {
+				return converted, err
+			}
+
+			currency, found := currencies[budget.UserId]
+			if !found {
+				currency, err = homeCurrency(ctx, users, budget.UserId)
+				if err != nil {
+					return converted, err
+				}
+				currencies[budget.UserId] = currency
+			}
+
+			err := convertAmount(ctx, budgets, budget.Id, domain.MoneyFromFloat(budget.Amount, currency))
+			var conditionFailed *types.ConditionalCheckFailedException
+			if errors.As(err, &conditionFailed) {
+				continue
+			}
+			if err != nil {
+				return converted, err
+			}
+			converted++
+		}
+	}
+	return converted, nil
+}
+
+// homeCurrency returns the home currency of the user with the given id, or
+// the default currency when the user has none or is deleted.
+func homeCurrency(ctx context.Context, users *helper.DynamoDB, userId string) (string, error) {
+	response, err := users.Client.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName: aws.String(users.TableName),
+		Key:       map[string]types.AttributeValue{"Id": &types.AttributeValueMemberS{Value: userId}},
+	})
+	if err != nil {
+		return "", err
+	}
+	user := domain.User{}
+	if err := attributevalue.UnmarshalMap(response.Item, &user); err != nil {
+		return "", err
+	}
+	return user.Currency(), nil
+}
+
+// MigrateEmailGuards puts the guard items keeping the emails of the users
+// unique for the users of the given DynamoDB `Users` table registered before
+// the emails were guarded, and returns the number of guarded emails and the
+// emails registered to more than one user, which have to be changed by hand.
+func MigrateEmailGuards(ctx context.Context, users *helper.DynamoDB) (int, []string, error) {
+	userRepository := &repository.UserRepositoryImpl{DB: users}
+	return userRepository.GuardEmails(ctx)
+}
@@ -0,0 +1,108 @@
+package app
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"io/fs"
+	"sort"
+	"strings"
+	"time"
+)
+
+// migrationChecks maps the versions of the migrations that fail on some
+// data to the checks of the data run before them, which fail with a message
+// telling how to fix the data instead.
+var migrationChecks = map[string]func(ctx context.Context, tx *sql.Tx) error{
+	"0016_add_unique_index_users_email": checkDuplicateEmails,
+}
+
+// Migrate applies the SQL migrations in the root directory of the given
+// file system to the database, in the order of their file names. Every
+// migration is applied in its own transaction and recorded in the
+// `schema_migrations` table, so a migration already applied is skipped.
+func Migrate(ctx context.Context, db *sql.DB, migrations fs.FS) error {
+	_, err := db.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS schema_migrations (
+		version    text PRIMARY KEY,
+		applied_at bigint NOT NULL
+	)`)
+	if err != nil {
+		return err
+	}
+
+	names, err := fs.Glob(migrations, "*.sql")
+	if err != nil {
+		return err
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if err := applyMigration(ctx, db, migrations, name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// applyMigration applies the migration in the file of the given name unless
+// it has been applied before.
+func applyMigration(ctx context.Context, db *sql.DB, migrations fs.FS, name string) error {
+	version := strings.TrimSuffix(name, ".sql")
+	statements, err := fs.ReadFile(migrations, name)
+	if err != nil {
+		return err
+	}
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var applied int
+	err = tx.QueryRowContext(ctx, "SELECT count(*) FROM schema_migrations WHERE version = $1", version).Scan(&applied)
+	if err != nil || applied > 0 {
+		return err
+	}
+
+	if check, found := migrationChecks[version]; found {
+		if err := check(ctx, tx); err != nil {
+			return err
+		}
+	}
+	if _, err := tx.ExecContext(ctx, string(statements)); err != nil {
+		return err
+	}
+	_, err = tx.ExecContext(ctx, "INSERT INTO schema_migrations (version, applied_at) VALUES ($1, $2)", version, time.Now().UnixMilli())
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// checkDuplicateEmails returns an error listing the emails registered to
+// more than one user regardless of their case, which have to be changed
+// before the emails are made unique.
+func checkDuplicateEmails(ctx context.Context, tx *sql.Tx) error {
+	rows, err := tx.QueryContext(ctx, "SELECT lower(email) FROM users GROUP BY lower(email) HAVING count(*) > 1 ORDER BY lower(email)")
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	var emails []string
+	for rows.Next() {
+		var email string
+		if err := rows.Scan(&email); err != nil {
+			return err
+		}
+		emails = append(emails, email)
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	if len(emails) > 0 {
+		return fmt.Errorf("the emails %s are registered to more than one user, change them before the emails are made unique", strings.Join(emails, ", "))
+	}
+	return nil
+}
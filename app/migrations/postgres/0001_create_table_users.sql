@@ -0,0 +1,13 @@
+-- The `users` table stores the user data. The `users_email_index` index
+-- mirrors the `EmailIndex` GSI of the DynamoDB `Users` table, used to look up
+-- a user by email address.
+CREATE TABLE users (
+    id         text PRIMARY KEY,
+    name       text   NOT NULL DEFAULT '',
+    email      text   NOT NULL DEFAULT '',
+    password   text   NOT NULL DEFAULT '',
+    time_zone  text   NOT NULL DEFAULT '',
+    created_at bigint NOT NULL DEFAULT 0
+);
+
+CREATE INDEX users_email_index ON users (email);
@@ -0,0 +1,17 @@
+-- The `spending` table stores the spending history of the users. The
+-- `spending_user_index` index mirrors the `UserIndex` GSI of the DynamoDB
+-- `Spending` table, used to list the spendings of a user by date. The ids are
+-- compared byte by byte, as DynamoDB does, to order the spendings of the
+-- same date.
+CREATE TABLE spending (
+    id          text COLLATE "C" PRIMARY KEY,
+    user_id     text             NOT NULL,
+    title       text             NOT NULL DEFAULT '',
+    description text             NOT NULL DEFAULT '',
+    amount      double precision NOT NULL DEFAULT 0,
+    date        bigint           NOT NULL DEFAULT 0,
+    category    text             NOT NULL DEFAULT '',
+    created_at  bigint           NOT NULL DEFAULT 0
+);
+
+CREATE INDEX spending_user_index ON spending (user_id, date, id);
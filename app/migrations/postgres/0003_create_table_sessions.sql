@@ -0,0 +1,17 @@
+-- The `sessions` table stores the refresh token sessions of the users. The
+-- `sessions_user_index` index mirrors the `UserIndex` GSI of the DynamoDB
+-- `Sessions` table.
+CREATE TABLE sessions (
+    id         text PRIMARY KEY,
+    family_id  text   NOT NULL,
+    user_id    text   NOT NULL,
+    token_hash text   NOT NULL DEFAULT '',
+    user_agent text   NOT NULL DEFAULT '',
+    started_at bigint NOT NULL DEFAULT 0,
+    created_at bigint NOT NULL DEFAULT 0,
+    expires_at bigint NOT NULL DEFAULT 0,
+    rotated_at bigint NOT NULL DEFAULT 0,
+    revoked_at bigint NOT NULL DEFAULT 0
+);
+
+CREATE INDEX sessions_user_index ON sessions (user_id, created_at);
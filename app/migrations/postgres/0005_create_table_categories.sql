@@ -0,0 +1,14 @@
+-- The `categories` table stores the spending categories of the users. The
+-- `categories_user_index` index mirrors the `UserIndex` GSI of the DynamoDB
+-- `Categories` table.
+CREATE TABLE categories (
+    id         text PRIMARY KEY,
+    user_id    text   NOT NULL,
+    name       text   NOT NULL DEFAULT '',
+    color      text   NOT NULL DEFAULT '',
+    icon       text   NOT NULL DEFAULT '',
+    parent_id  text   NOT NULL DEFAULT '',
+    created_at bigint NOT NULL DEFAULT 0
+);
+
+CREATE INDEX categories_user_index ON categories (user_id, name);
@@ -0,0 +1,3 @@
+-- The `language` column stores the language preference of the user, used
+-- to translate the error messages when the request does not choose one.
+ALTER TABLE users ADD COLUMN language text NOT NULL DEFAULT '';
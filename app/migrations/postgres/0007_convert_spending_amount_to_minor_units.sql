@@ -0,0 +1,8 @@
+-- The amount of a spending is stored as an integer number of the minor unit
+-- of its currency, so it is summed without rounding errors. The amounts
+-- recorded before were in the default currency, IDR, whose minor unit is a
+-- hundredth.
+ALTER TABLE spending ADD COLUMN amount_minor bigint NOT NULL DEFAULT 0;
+ALTER TABLE spending ADD COLUMN currency text NOT NULL DEFAULT 'IDR';
+UPDATE spending SET amount_minor = round(amount * 100);
+ALTER TABLE spending DROP COLUMN amount;
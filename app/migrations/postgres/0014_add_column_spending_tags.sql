@@ -0,0 +1,4 @@
+-- The `tags` column keeps the tags of a spending between commas, such as
+-- `,business-trip,reimbursable,`, so a tag is matched with `LIKE '%,tag,%'`.
+-- A spending without tags has an empty `tags`.
+ALTER TABLE spending ADD COLUMN tags text NOT NULL DEFAULT '';
@@ -0,0 +1,15 @@
+-- The `attachments` table stores the files attached to the spendings, such
+-- as the photos of their receipts, mirroring the DynamoDB `Attachments`
+-- table. The content of the files is kept in the blob store. The
+-- `attachments_spending_index` index mirrors its `SpendingIndex` GSI.
+CREATE TABLE attachments (
+    id           text PRIMARY KEY,
+    user_id      text    NOT NULL,
+    spending_id  text    NOT NULL,
+    file_name    text    NOT NULL DEFAULT '',
+    content_type text    NOT NULL DEFAULT '',
+    size         bigint  NOT NULL DEFAULT 0,
+    created_at   bigint  NOT NULL DEFAULT 0
+);
+
+CREATE INDEX attachments_spending_index ON attachments (spending_id, created_at);
@@ -0,0 +1,13 @@
+-- The `budgets` table stores the budgets of the users. The
+-- `budgets_user_index` index mirrors the `UserIndex` GSI of the DynamoDB
+-- `Budgets` table.
+CREATE TABLE budgets (
+    id         text PRIMARY KEY,
+    user_id    text    NOT NULL,
+    category   text    NOT NULL DEFAULT '',
+    amount     real    NOT NULL DEFAULT 0,
+    period     text    NOT NULL DEFAULT '',
+    created_at integer NOT NULL DEFAULT 0
+);
+
+CREATE INDEX budgets_user_index ON budgets (user_id, created_at);
@@ -0,0 +1,4 @@
+-- The `home_currency` column stores the ISO 4217 code of the currency the
+-- spendings of the user are converted into. It is empty for the users
+-- registered before, whose home currency is the default currency, IDR.
+ALTER TABLE users ADD COLUMN home_currency text NOT NULL DEFAULT '';
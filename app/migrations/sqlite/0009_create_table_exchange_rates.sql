@@ -0,0 +1,13 @@
+-- The `exchange_rates` table stores the rates of exchange between the
+-- currencies, mirroring the DynamoDB `ExchangeRates` table. A rate is
+-- effective from its `effective_from` until the next rate of the pair. The
+-- rate is stored as an exact decimal text.
+CREATE TABLE exchange_rates (
+    pair           text    NOT NULL,
+    base           text    NOT NULL,
+    quote          text    NOT NULL,
+    rate           text    NOT NULL,
+    effective_from integer NOT NULL,
+    created_at     integer NOT NULL DEFAULT 0,
+    PRIMARY KEY (pair, effective_from)
+);
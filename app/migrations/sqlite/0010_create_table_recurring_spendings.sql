@@ -0,0 +1,26 @@
+-- The `recurring_spendings` table stores the templates of the spendings
+-- repeated on a schedule, mirroring the DynamoDB `RecurringSpendings` table.
+-- The `rule_` columns are the recurrence rule, whose days of the week are
+-- separated by commas in `rule_by_day`. The `recurring_spendings_due_index`
+-- index finds the templates whose next occurrence is due.
+CREATE TABLE recurring_spendings (
+    id              text PRIMARY KEY,
+    user_id         text    NOT NULL,
+    title           text    NOT NULL DEFAULT '',
+    description     text    NOT NULL DEFAULT '',
+    amount_minor    integer NOT NULL DEFAULT 0,
+    currency        text    NOT NULL DEFAULT 'IDR',
+    category        text    NOT NULL DEFAULT '',
+    rule_frequency  text    NOT NULL,
+    rule_interval   integer NOT NULL DEFAULT 1,
+    rule_by_day     text    NOT NULL DEFAULT '',
+    rule_start      integer NOT NULL,
+    rule_until      integer NOT NULL DEFAULT 0,
+    rule_count      integer NOT NULL DEFAULT 0,
+    last_occurrence integer NOT NULL DEFAULT 0,
+    next_occurrence integer NOT NULL DEFAULT 0,
+    created_at      integer NOT NULL DEFAULT 0
+);
+
+CREATE INDEX recurring_spendings_user_index ON recurring_spendings (user_id, created_at);
+CREATE INDEX recurring_spendings_due_index ON recurring_spendings (next_occurrence);
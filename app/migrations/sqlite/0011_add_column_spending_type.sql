@@ -0,0 +1,3 @@
+-- The `type` column tells the money spent, `expense`, from the money
+-- received, `income`. The spendings recorded before are expenses.
+ALTER TABLE spending ADD COLUMN type text NOT NULL DEFAULT 'expense';
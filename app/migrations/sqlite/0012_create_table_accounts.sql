@@ -0,0 +1,17 @@
+-- The `accounts` table stores the sources of money of the users, mirroring
+-- the DynamoDB `Accounts` table. The `currency` column is the currency of
+-- the opening balance and of every amount of the account. The `account_id`
+-- column of the `spending` table is empty for the spendings of no account.
+CREATE TABLE accounts (
+    id                    text PRIMARY KEY,
+    user_id               text    NOT NULL,
+    name                  text    NOT NULL DEFAULT '',
+    type                  text    NOT NULL DEFAULT 'other',
+    opening_balance_minor integer NOT NULL DEFAULT 0,
+    currency              text    NOT NULL DEFAULT 'IDR',
+    created_at            integer NOT NULL DEFAULT 0
+);
+
+CREATE INDEX accounts_user_index ON accounts (user_id, created_at);
+
+ALTER TABLE spending ADD COLUMN account_id text NOT NULL DEFAULT '';
@@ -0,0 +1,20 @@
+-- The `transfers` table stores the money moved between the accounts of the
+-- users, mirroring the DynamoDB `Transfers` table. The
+-- `transfers_from_account_index` and `transfers_to_account_index` indexes
+-- mirror its `FromAccountIndex` and `ToAccountIndex` GSIs.
+CREATE TABLE transfers (
+    id                    text PRIMARY KEY,
+    user_id               text    NOT NULL,
+    from_account_id       text    NOT NULL,
+    to_account_id         text    NOT NULL,
+    amount_minor          integer NOT NULL DEFAULT 0,
+    currency              text    NOT NULL DEFAULT 'IDR',
+    received_amount_minor integer NOT NULL DEFAULT 0,
+    received_currency     text    NOT NULL DEFAULT 'IDR',
+    description           text    NOT NULL DEFAULT '',
+    date                  integer NOT NULL DEFAULT 0,
+    created_at            integer NOT NULL DEFAULT 0
+);
+
+CREATE INDEX transfers_from_account_index ON transfers (from_account_id, date);
+CREATE INDEX transfers_to_account_index ON transfers (to_account_id, date);
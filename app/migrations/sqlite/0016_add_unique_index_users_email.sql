@@ -0,0 +1,6 @@
+-- The `users_email_unique_index` index keeps the emails of the users unique
+-- regardless of their case, mirroring the `EMAIL#` guard items of the
+-- DynamoDB `Users` table, so two registrations of the same email cannot
+-- both succeed. The migration is not applied while emails are registered
+-- to more than one user, which are listed so they can be changed first.
+CREATE UNIQUE INDEX users_email_unique_index ON users (lower(email));
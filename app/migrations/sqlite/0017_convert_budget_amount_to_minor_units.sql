@@ -0,0 +1,14 @@
+-- The amount of a budget is stored as an integer number of the minor unit
+-- of its currency, like the amount of a spending. The amounts recorded
+-- before were in the home currency of the user, or the default currency,
+-- IDR, for the users without one.
+ALTER TABLE budgets ADD COLUMN amount_minor integer NOT NULL DEFAULT 0;
+ALTER TABLE budgets ADD COLUMN currency text NOT NULL DEFAULT 'IDR';
+UPDATE budgets SET currency = coalesce((SELECT nullif(home_currency, '') FROM users WHERE users.id = budgets.user_id), 'IDR');
+UPDATE budgets SET amount_minor = round(amount * CASE
+    WHEN currency IN ('BIF', 'CLP', 'DJF', 'GNF', 'ISK', 'JPY', 'KMF', 'KRW', 'PYG', 'RWF', 'UGX', 'UYI', 'VND', 'VUV', 'XAF', 'XOF', 'XPF') THEN 1
+    WHEN currency IN ('BHD', 'IQD', 'JOD', 'KWD', 'LYD', 'OMR', 'TND') THEN 1000
+    WHEN currency IN ('CLF', 'UYW') THEN 10000
+    ELSE 100
+END);
+ALTER TABLE budgets DROP COLUMN amount;
@@ -0,0 +1,40 @@
+package app
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"fmt"
+	_ "github.com/jackc/pgx/v5/stdlib"
+	"io/fs"
+)
+
+// postgresMigrations holds the migrations creating the PostgreSQL tables
+// equivalent to the DynamoDB tables created by SetupDatabase.
+//
+//go:embed migrations/postgres/*.sql
+var postgresMigrations embed.FS
+
+// SetupPostgres connects to the PostgreSQL database at the given URL,
+// applies the migrations which have not been applied yet, and returns the
+// repositories backed by the database.
+func SetupPostgres(ctx context.Context, databaseURL string) Repositories {
+	db, err := sql.Open("pgx", databaseURL)
+	if err != nil {
+		panic(err)
+	}
+	if err := db.PingContext(ctx); err != nil {
+		panic(err)
+	}
+
+	migrations, err := fs.Sub(postgresMigrations, "migrations/postgres")
+	if err != nil {
+		panic(err)
+	}
+	if err := Migrate(ctx, db, migrations); err != nil {
+		panic(err)
+	}
+
+	fmt.Println("--- Setup Database Done")
+	return NewSQLRepositories(db)
+}
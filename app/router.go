@@ -4,8 +4,18 @@ import (
 	"github.com/julienschmidt/httprouter"
 	"github.com/refandas/duit-api/controller"
 	"github.com/refandas/duit-api/exception"
+	"net/http"
 )
 
+// PublicRoutes lists the routes, formatted as "METHOD /path", that can be
+// accessed without an access token.
+var PublicRoutes = []string{
+	http.MethodPost + " /api/v1/users",
+	http.MethodPost + " /api/v1/auth/login",
+	http.MethodPost + " /api/v1/auth/refresh",
+	http.MethodPost + " /api/v1/auth/logout",
+}
+
 // Router is a struct representing an HTTP router and associated controllers.
 type Router struct {
 
@@ -14,6 +24,33 @@ type Router struct {
 
 	// SpendingController represents the controller for user's spending-related functionality.
 	SpendingController controller.SpendingController
+
+	// AuthController represents the controller for authentication-related functionality.
+	AuthController controller.AuthController
+
+	// SessionController represents the controller for user's session-related functionality.
+	SessionController controller.SessionController
+
+	// ReportController represents the controller for user's spending report-related functionality.
+	ReportController controller.ReportController
+
+	// BudgetController represents the controller for user's budget-related functionality.
+	BudgetController controller.BudgetController
+
+	// CategoryController represents the controller for user's category-related functionality.
+	CategoryController controller.CategoryController
+
+	// ExchangeRateController represents the controller for exchange rate-related functionality.
+	ExchangeRateController controller.ExchangeRateController
+
+	// RecurringSpendingController represents the controller for user's recurring spending-related functionality.
+	RecurringSpendingController controller.RecurringSpendingController
+
+	// AccountController represents the controller for user's account and transfer-related functionality.
+	AccountController controller.AccountController
+
+	// AttachmentController represents the controller for the files attached to user's spendings.
+	AttachmentController controller.AttachmentController
 }
 
 // NewRouter creates and returns a new instance of httprouter.Router
@@ -34,13 +71,95 @@ func (controller Router) NewRouter() *httprouter.Router {
 	// The user's spending handler will only be defined if the SpendingController is defined.
 	if controller.SpendingController != nil {
 		router.GET("/api/v1/users/:userId/spendings", controller.SpendingController.FindByUserId)
+		router.GET("/api/v1/users/:userId/spendings/upcoming", controller.SpendingController.FindUpcomingByUserId)
+		router.GET("/api/v1/users/:userId/spendings/export", controller.SpendingController.Export)
+		router.POST("/api/v1/users/:userId/spendings/import", controller.SpendingController.Import)
+		router.POST("/api/v1/users/:userId/spendings/tags", controller.SpendingController.UpdateTags)
+		router.GET("/api/v1/users/:userId/tags", controller.SpendingController.FindTags)
 		router.GET("/api/v1/spendings/:spendingId", controller.SpendingController.FindById)
 		router.PUT("/api/v1/spendings/:spendingId", controller.SpendingController.Update)
 		router.POST("/api/v1/spendings", controller.SpendingController.Create)
 		router.DELETE("/api/v1/spendings/:spendingId", controller.SpendingController.Delete)
 	}
 
-	// Setting an error handler when panic occurs.
+	// The authentication handler will only be defined if the AuthController is defined.
+	if controller.AuthController != nil {
+		router.POST("/api/v1/auth/login", controller.AuthController.Login)
+		router.POST("/api/v1/auth/refresh", controller.AuthController.Refresh)
+		router.POST("/api/v1/auth/logout", controller.AuthController.Logout)
+	}
+
+	// The user's session handler will only be defined if the SessionController is defined.
+	if controller.SessionController != nil {
+		router.GET("/api/v1/users/:userId/sessions", controller.SessionController.FindByUserId)
+		router.DELETE("/api/v1/users/:userId/sessions", controller.SessionController.DeleteByUserId)
+		router.DELETE("/api/v1/users/:userId/sessions/:sessionId", controller.SessionController.Delete)
+	}
+
+	// The user's report handler will only be defined if the ReportController is defined.
+	if controller.ReportController != nil {
+		router.GET("/api/v1/users/:userId/reports/summary", controller.ReportController.Summary)
+		router.GET("/api/v1/users/:userId/balance", controller.ReportController.Balance)
+	}
+
+	// The user's budget handler will only be defined if the BudgetController is defined.
+	if controller.BudgetController != nil {
+		router.GET("/api/v1/users/:userId/budgets", controller.BudgetController.FindByUserId)
+		router.POST("/api/v1/users/:userId/budgets", controller.BudgetController.Create)
+		router.GET("/api/v1/users/:userId/budgets/:budgetId", controller.BudgetController.FindById)
+		router.PUT("/api/v1/users/:userId/budgets/:budgetId", controller.BudgetController.Update)
+		router.DELETE("/api/v1/users/:userId/budgets/:budgetId", controller.BudgetController.Delete)
+		router.GET("/api/v1/users/:userId/budgets/:budgetId/status", controller.BudgetController.Status)
+	}
+
+	// The user's recurring spending handler will only be defined if the RecurringSpendingController is defined.
+	if controller.RecurringSpendingController != nil {
+		router.GET("/api/v1/users/:userId/recurring-spendings", controller.RecurringSpendingController.FindByUserId)
+		router.POST("/api/v1/users/:userId/recurring-spendings", controller.RecurringSpendingController.Create)
+		router.GET("/api/v1/users/:userId/recurring-spendings/:recurringId", controller.RecurringSpendingController.FindById)
+		router.PUT("/api/v1/users/:userId/recurring-spendings/:recurringId", controller.RecurringSpendingController.Update)
+		router.DELETE("/api/v1/users/:userId/recurring-spendings/:recurringId", controller.RecurringSpendingController.Delete)
+	}
+
+	// The user's account handler will only be defined if the AccountController is defined.
+	if controller.AccountController != nil {
+		router.GET("/api/v1/users/:userId/accounts", controller.AccountController.FindByUserId)
+		router.POST("/api/v1/users/:userId/accounts", controller.AccountController.Create)
+		router.GET("/api/v1/users/:userId/accounts/:accountId", controller.AccountController.FindById)
+		router.PUT("/api/v1/users/:userId/accounts/:accountId", controller.AccountController.Update)
+		router.DELETE("/api/v1/users/:userId/accounts/:accountId", controller.AccountController.Delete)
+		router.GET("/api/v1/users/:userId/accounts/:accountId/balance", controller.AccountController.Balance)
+		router.POST("/api/v1/users/:userId/transfers", controller.AccountController.Transfer)
+		router.GET("/api/v1/users/:userId/transfers/:transferId", controller.AccountController.FindTransferById)
+		router.DELETE("/api/v1/users/:userId/transfers/:transferId", controller.AccountController.DeleteTransfer)
+	}
+
+	// The spending's attachment handler will only be defined if the AttachmentController is defined.
+	if controller.AttachmentController != nil {
+		router.GET("/api/v1/spendings/:spendingId/attachments", controller.AttachmentController.FindBySpendingId)
+		router.POST("/api/v1/spendings/:spendingId/attachments", controller.AttachmentController.Create)
+		router.GET("/api/v1/spendings/:spendingId/attachments/:attachmentId", controller.AttachmentController.Download)
+		router.DELETE("/api/v1/spendings/:spendingId/attachments/:attachmentId", controller.AttachmentController.Delete)
+	}
+
+	// The user's category handler will only be defined if the CategoryController is defined.
+	if controller.CategoryController != nil {
+		router.GET("/api/v1/users/:userId/categories", controller.CategoryController.FindByUserId)
+		router.POST("/api/v1/users/:userId/categories", controller.CategoryController.Create)
+		router.GET("/api/v1/users/:userId/categories/:categoryId", controller.CategoryController.FindById)
+		router.PUT("/api/v1/users/:userId/categories/:categoryId", controller.CategoryController.Update)
+		router.DELETE("/api/v1/users/:userId/categories/:categoryId", controller.CategoryController.Delete)
+		router.POST("/api/v1/users/:userId/categories/:categoryId/merge", controller.CategoryController.Merge)
+	}
+
+	// The exchange rate handler will only be defined if the ExchangeRateController is defined.
+	if controller.ExchangeRateController != nil {
+		router.GET("/api/v1/exchange-rates", controller.ExchangeRateController.FindByPair)
+		router.POST("/api/v1/exchange-rates", controller.ExchangeRateController.Create)
+	}
+
+	// Errors are responded by the controllers, the panic handler is only a
+	// last resort safety net.
 	router.PanicHandler = exception.ErrorHandler
 
 	return router
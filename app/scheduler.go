@@ -0,0 +1,33 @@
+package app
+
+import (
+	"context"
+	"github.com/refandas/duit-api/service"
+	"log"
+	"time"
+)
+
+// RunRecurringScheduler creates the spendings of the due occurrences of the
+// recurring spendings right away and then every interval, until the context
+// is done. The occurrences are created idempotently, so the scheduler can
+// run in every server process.
+func RunRecurringScheduler(ctx context.Context, recurringSpendingService service.RecurringSpendingService, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		created, err := recurringSpendingService.Materialize(ctx, time.Now().UnixMilli())
+		if err != nil {
+			log.Printf("materializing recurring spendings: %v", err)
+		}
+		if created > 0 {
+			log.Printf("created %d spendings of recurring spendings", created)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
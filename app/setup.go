@@ -7,7 +7,10 @@ import (
 	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/golang-jwt/jwt/v5"
 	"github.com/refandas/duit-api/helper"
+	"github.com/refandas/duit-api/repository"
+	"os"
 	"time"
 )
 
@@ -53,21 +56,437 @@ func SetupClient(ctx context.Context) *dynamodb.Client {
 
 // CreateTableUser creates a new DynamoDB table named `Users` for storing user data
 // using the specified DynamoDB instance.
+//
+// The `Users` table has a hash key of `Id` and a Global Secondary Index (GSI)
+// named `EmailIndex` with a hash key of `Email`.
 func CreateTableUser(ctx context.Context, db *helper.DynamoDB) error {
 	_, err := db.Client.CreateTable(
 		ctx,
 		&dynamodb.CreateTableInput{
 			AttributeDefinitions: []types.AttributeDefinition{
 				{
-					AttributeName: aws.String("Id"),
+					AttributeName: aws.String("Id"),
+					AttributeType: types.ScalarAttributeTypeS,
+				},
+				{
+					AttributeName: aws.String("Email"),
+					AttributeType: types.ScalarAttributeTypeS,
+				},
+			},
+			KeySchema: []types.KeySchemaElement{
+				{
+					AttributeName: aws.String("Id"),
+					KeyType:       types.KeyTypeHash,
+				},
+			},
+			GlobalSecondaryIndexes: []types.GlobalSecondaryIndex{
+				emailIndex(),
+			},
+			TableName: aws.String(db.TableName),
+			ProvisionedThroughput: &types.ProvisionedThroughput{
+				ReadCapacityUnits:  aws.Int64(1),
+				WriteCapacityUnits: aws.Int64(1),
+			},
+		},
+	)
+	if err != nil {
+		panic(err)
+	}
+
+	waiter := dynamodb.NewTableExistsWaiter(db.Client)
+	err = waiter.Wait(context.TODO(), &dynamodb.DescribeTableInput{
+		TableName: aws.String(db.TableName),
+	}, 5*time.Minute)
+
+	if err != nil {
+		panic(err)
+	}
+
+	return err
+}
+
+// emailIndex returns the definition of the `EmailIndex` GSI of the `Users`
+// table, used to look up a user by email address.
+func emailIndex() types.GlobalSecondaryIndex {
+	return types.GlobalSecondaryIndex{
+		IndexName: aws.String("EmailIndex"),
+		KeySchema: []types.KeySchemaElement{
+			{
+				AttributeName: aws.String("Email"),
+				KeyType:       types.KeyTypeHash,
+			},
+		},
+		Projection: &types.Projection{
+			ProjectionType: types.ProjectionTypeAll,
+		},
+		ProvisionedThroughput: &types.ProvisionedThroughput{
+			ReadCapacityUnits:  aws.Int64(1),
+			WriteCapacityUnits: aws.Int64(1),
+		},
+	}
+}
+
+// CreateEmailIndex adds the `EmailIndex` GSI to a `Users` table created
+// before the index existed, and waits until the index is active. It does
+// nothing when the table already has the index.
+func CreateEmailIndex(ctx context.Context, db *helper.DynamoDB) error {
+	for {
+		table, err := db.Client.DescribeTable(ctx, &dynamodb.DescribeTableInput{
+			TableName: aws.String(db.TableName),
+		})
+		if err != nil {
+			return err
+		}
+
+		var index *types.GlobalSecondaryIndexDescription
+		for i := range table.Table.GlobalSecondaryIndexes {
+			if aws.ToString(table.Table.GlobalSecondaryIndexes[i].IndexName) == "EmailIndex" {
+				index = &table.Table.GlobalSecondaryIndexes[i]
+			}
+		}
+
+		if index == nil {
+			_, err = db.Client.UpdateTable(ctx, &dynamodb.UpdateTableInput{
+				TableName: aws.String(db.TableName),
+				AttributeDefinitions: []types.AttributeDefinition{
+					{
+						AttributeName: aws.String("Email"),
+						AttributeType: types.ScalarAttributeTypeS,
+					},
+				},
+				GlobalSecondaryIndexUpdates: []types.GlobalSecondaryIndexUpdate{
+					{
+						Create: &types.CreateGlobalSecondaryIndexAction{
+							IndexName:             emailIndex().IndexName,
+							KeySchema:             emailIndex().KeySchema,
+							Projection:            emailIndex().Projection,
+							ProvisionedThroughput: emailIndex().ProvisionedThroughput,
+						},
+					},
+				},
+			})
+			if err != nil {
+				return err
+			}
+		} else if index.IndexStatus == types.IndexStatusActive {
+			return nil
+		}
+
+		// Wait for the existing users to be backfilled into the index.
+		time.Sleep(5 * time.Second)
+	}
+}
+
+// CreateTableSpending creates a new DynamoDB table named `Spending` for
+// storing user's spending data using the specified DynamoDB instance.
+//
+// The `Spending` table has a hash key of `Id` and a Global Secondary Index (GSI)
+// named `UserIndex` with a hash key of `UserId` and sort key of `Date`.
+func CreateTableSpending(ctx context.Context, db *helper.DynamoDB) error {
+	_, err := db.Client.CreateTable(
+		ctx,
+		&dynamodb.CreateTableInput{
+			AttributeDefinitions: []types.AttributeDefinition{
+				{
+					AttributeName: aws.String("Id"),
+					AttributeType: types.ScalarAttributeTypeS,
+				},
+				{
+					AttributeName: aws.String("UserId"),
+					AttributeType: types.ScalarAttributeTypeS,
+				},
+				{
+					AttributeName: aws.String("Date"),
+					AttributeType: types.ScalarAttributeTypeN,
+				},
+			},
+			KeySchema: []types.KeySchemaElement{
+				{
+					AttributeName: aws.String("Id"),
+					KeyType:       types.KeyTypeHash,
+				},
+			},
+			GlobalSecondaryIndexes: []types.GlobalSecondaryIndex{
+				{
+					IndexName: aws.String("UserIndex"),
+					KeySchema: []types.KeySchemaElement{
+						{
+							AttributeName: aws.String("UserId"),
+							KeyType:       types.KeyTypeHash,
+						},
+						{
+							AttributeName: aws.String("Date"),
+							KeyType:       types.KeyTypeRange,
+						},
+					},
+					Projection: &types.Projection{
+						ProjectionType: types.ProjectionTypeAll,
+					},
+					ProvisionedThroughput: &types.ProvisionedThroughput{
+						ReadCapacityUnits:  aws.Int64(3),
+						WriteCapacityUnits: aws.Int64(1),
+					},
+				},
+			},
+			TableName: aws.String(db.TableName),
+			ProvisionedThroughput: &types.ProvisionedThroughput{
+				ReadCapacityUnits:  aws.Int64(3),
+				WriteCapacityUnits: aws.Int64(1),
+			},
+		},
+	)
+	if err != nil {
+		panic(err)
+	}
+
+	waiter := dynamodb.NewTableExistsWaiter(db.Client)
+	err = waiter.Wait(context.TODO(), &dynamodb.DescribeTableInput{
+		TableName: aws.String(db.TableName),
+	}, 5*time.Minute)
+
+	return err
+}
+
+// CreateTableSession creates a new DynamoDB table named `Sessions` for
+// storing user's refresh token sessions using the specified DynamoDB instance.
+//
+// The `Sessions` table has a hash key of `Id` and a Global Secondary Index (GSI)
+// named `UserIndex` with a hash key of `UserId` and sort key of `CreatedAt`.
+func CreateTableSession(ctx context.Context, db *helper.DynamoDB) error {
+	_, err := db.Client.CreateTable(
+		ctx,
+		&dynamodb.CreateTableInput{
+			AttributeDefinitions: []types.AttributeDefinition{
+				{
+					AttributeName: aws.String("Id"),
+					AttributeType: types.ScalarAttributeTypeS,
+				},
+				{
+					AttributeName: aws.String("UserId"),
+					AttributeType: types.ScalarAttributeTypeS,
+				},
+				{
+					AttributeName: aws.String("CreatedAt"),
+					AttributeType: types.ScalarAttributeTypeN,
+				},
+			},
+			KeySchema: []types.KeySchemaElement{
+				{
+					AttributeName: aws.String("Id"),
+					KeyType:       types.KeyTypeHash,
+				},
+			},
+			GlobalSecondaryIndexes: []types.GlobalSecondaryIndex{
+				{
+					IndexName: aws.String("UserIndex"),
+					KeySchema: []types.KeySchemaElement{
+						{
+							AttributeName: aws.String("UserId"),
+							KeyType:       types.KeyTypeHash,
+						},
+						{
+							AttributeName: aws.String("CreatedAt"),
+							KeyType:       types.KeyTypeRange,
+						},
+					},
+					Projection: &types.Projection{
+						ProjectionType: types.ProjectionTypeAll,
+					},
+					ProvisionedThroughput: &types.ProvisionedThroughput{
+						ReadCapacityUnits:  aws.Int64(1),
+						WriteCapacityUnits: aws.Int64(1),
+					},
+				},
+			},
+			TableName: aws.String(db.TableName),
+			ProvisionedThroughput: &types.ProvisionedThroughput{
+				ReadCapacityUnits:  aws.Int64(1),
+				WriteCapacityUnits: aws.Int64(1),
+			},
+		},
+	)
+	if err != nil {
+		panic(err)
+	}
+
+	waiter := dynamodb.NewTableExistsWaiter(db.Client)
+	err = waiter.Wait(context.TODO(), &dynamodb.DescribeTableInput{
+		TableName: aws.String(db.TableName),
+	}, 5*time.Minute)
+
+	return err
+}
+
+// CreateTableBudget creates a new DynamoDB table named `Budgets` for
+// storing user's budgets using the specified DynamoDB instance.
+//
+// The `Budgets` table has a hash key of `Id` and a Global Secondary Index (GSI)
+// named `UserIndex` with a hash key of `UserId` and sort key of `CreatedAt`.
+func CreateTableBudget(ctx context.Context, db *helper.DynamoDB) error {
+	_, err := db.Client.CreateTable(
+		ctx,
+		&dynamodb.CreateTableInput{
+			AttributeDefinitions: []types.AttributeDefinition{
+				{
+					AttributeName: aws.String("Id"),
+					AttributeType: types.ScalarAttributeTypeS,
+				},
+				{
+					AttributeName: aws.String("UserId"),
+					AttributeType: types.ScalarAttributeTypeS,
+				},
+				{
+					AttributeName: aws.String("CreatedAt"),
+					AttributeType: types.ScalarAttributeTypeN,
+				},
+			},
+			KeySchema: []types.KeySchemaElement{
+				{
+					AttributeName: aws.String("Id"),
+					KeyType:       types.KeyTypeHash,
+				},
+			},
+			GlobalSecondaryIndexes: []types.GlobalSecondaryIndex{
+				{
+					IndexName: aws.String("UserIndex"),
+					KeySchema: []types.KeySchemaElement{
+						{
+							AttributeName: aws.String("UserId"),
+							KeyType:       types.KeyTypeHash,
+						},
+						{
+							AttributeName: aws.String("CreatedAt"),
+							KeyType:       types.KeyTypeRange,
+						},
+					},
+					Projection: &types.Projection{
+						ProjectionType: types.ProjectionTypeAll,
+					},
+					ProvisionedThroughput: &types.ProvisionedThroughput{
+						ReadCapacityUnits:  aws.Int64(1),
+						WriteCapacityUnits: aws.Int64(1),
+					},
+				},
+			},
+			TableName: aws.String(db.TableName),
+			ProvisionedThroughput: &types.ProvisionedThroughput{
+				ReadCapacityUnits:  aws.Int64(1),
+				WriteCapacityUnits: aws.Int64(1),
+			},
+		},
+	)
+	if err != nil {
+		panic(err)
+	}
+
+	waiter := dynamodb.NewTableExistsWaiter(db.Client)
+	err = waiter.Wait(context.TODO(), &dynamodb.DescribeTableInput{
+		TableName: aws.String(db.TableName),
+	}, 5*time.Minute)
+
+	return err
+}
+
+// CreateTableCategory creates a new DynamoDB table named `Categories` for
+// storing user's spending categories using the specified DynamoDB instance.
+//
+// The `Categories` table has a hash key of `Id` and a Global Secondary Index (GSI)
+// named `UserIndex` with a hash key of `UserId` and sort key of `Name`.
+func CreateTableCategory(ctx context.Context, db *helper.DynamoDB) error {
+	_, err := db.Client.CreateTable(
+		ctx,
+		&dynamodb.CreateTableInput{
+			AttributeDefinitions: []types.AttributeDefinition{
+				{
+					AttributeName: aws.String("Id"),
+					AttributeType: types.ScalarAttributeTypeS,
+				},
+				{
+					AttributeName: aws.String("UserId"),
+					AttributeType: types.ScalarAttributeTypeS,
+				},
+				{
+					AttributeName: aws.String("Name"),
+					AttributeType: types.ScalarAttributeTypeS,
+				},
+			},
+			KeySchema: []types.KeySchemaElement{
+				{
+					AttributeName: aws.String("Id"),
+					KeyType:       types.KeyTypeHash,
+				},
+			},
+			GlobalSecondaryIndexes: []types.GlobalSecondaryIndex{
+				{
+					IndexName: aws.String("UserIndex"),
+					KeySchema: []types.KeySchemaElement{
+						{
+							AttributeName: aws.String("UserId"),
+							KeyType:       types.KeyTypeHash,
+						},
+						{
+							AttributeName: aws.String("Name"),
+							KeyType:       types.KeyTypeRange,
+						},
+					},
+					Projection: &types.Projection{
+						ProjectionType: types.ProjectionTypeAll,
+					},
+					ProvisionedThroughput: &types.ProvisionedThroughput{
+						ReadCapacityUnits:  aws.Int64(1),
+						WriteCapacityUnits: aws.Int64(1),
+					},
+				},
+			},
+			TableName: aws.String(db.TableName),
+			ProvisionedThroughput: &types.ProvisionedThroughput{
+				ReadCapacityUnits:  aws.Int64(1),
+				WriteCapacityUnits: aws.Int64(1),
+			},
+		},
+	)
+	if err != nil {
+		panic(err)
+	}
+
+	waiter := dynamodb.NewTableExistsWaiter(db.Client)
+	err = waiter.Wait(context.TODO(), &dynamodb.DescribeTableInput{
+		TableName: aws.String(db.TableName),
+	}, 5*time.Minute)
+
+	return err
+}
+
+// CreateTableExchangeRate creates a new DynamoDB table named `ExchangeRates`
+// for storing the exchange rates between currencies using the specified
+// DynamoDB instance.
+//
+// The `ExchangeRates` table has a hash key of `Pair` and a sort key of
+// `EffectiveFrom`, so the rates of a currency pair are read in the order
+// they become effective.
+func CreateTableExchangeRate(ctx context.Context, db *helper.DynamoDB) error {
+	_, err := db.Client.CreateTable(
+		ctx,
+		&dynamodb.CreateTableInput{
+			AttributeDefinitions: []types.AttributeDefinition{
+				{
+					AttributeName: aws.String("Pair"),
 					AttributeType: types.ScalarAttributeTypeS,
 				},
+				{
+					AttributeName: aws.String("EffectiveFrom"),
+					AttributeType: types.ScalarAttributeTypeN,
+				},
 			},
 			KeySchema: []types.KeySchemaElement{
 				{
-					AttributeName: aws.String("Id"),
+					AttributeName: aws.String("Pair"),
 					KeyType:       types.KeyTypeHash,
 				},
+				{
+					AttributeName: aws.String("EffectiveFrom"),
+					KeyType:       types.KeyTypeRange,
+				},
 			},
 			TableName: aws.String(db.TableName),
 			ProvisionedThroughput: &types.ProvisionedThroughput{
@@ -85,19 +504,86 @@ func CreateTableUser(ctx context.Context, db *helper.DynamoDB) error {
 		TableName: aws.String(db.TableName),
 	}, 5*time.Minute)
 
+	return err
+}
+
+// CreateTableRecurringSpending creates a new DynamoDB table named
+// `RecurringSpendings` for storing user's recurring spendings using the
+// specified DynamoDB instance.
+//
+// The `RecurringSpendings` table has a hash key of `Id` and a Global Secondary Index (GSI)
+// named `UserIndex` with a hash key of `UserId` and sort key of `CreatedAt`.
+func CreateTableRecurringSpending(ctx context.Context, db *helper.DynamoDB) error {
+	_, err := db.Client.CreateTable(
+		ctx,
+		&dynamodb.CreateTableInput{
+			AttributeDefinitions: []types.AttributeDefinition{
+				{
+					AttributeName: aws.String("Id"),
+					AttributeType: types.ScalarAttributeTypeS,
+				},
+				{
+					AttributeName: aws.String("UserId"),
+					AttributeType: types.ScalarAttributeTypeS,
+				},
+				{
+					AttributeName: aws.String("CreatedAt"),
+					AttributeType: types.ScalarAttributeTypeN,
+				},
+			},
+			KeySchema: []types.KeySchemaElement{
+				{
+					AttributeName: aws.String("Id"),
+					KeyType:       types.KeyTypeHash,
+				},
+			},
+			GlobalSecondaryIndexes: []types.GlobalSecondaryIndex{
+				{
+					IndexName: aws.String("UserIndex"),
+					KeySchema: []types.KeySchemaElement{
+						{
+							AttributeName: aws.String("UserId"),
+							KeyType:       types.KeyTypeHash,
+						},
+						{
+							AttributeName: aws.String("CreatedAt"),
+							KeyType:       types.KeyTypeRange,
+						},
+					},
+					Projection: &types.Projection{
+						ProjectionType: types.ProjectionTypeAll,
+					},
+					ProvisionedThroughput: &types.ProvisionedThroughput{
+						ReadCapacityUnits:  aws.Int64(1),
+						WriteCapacityUnits: aws.Int64(1),
+					},
+				},
+			},
+			TableName: aws.String(db.TableName),
+			ProvisionedThroughput: &types.ProvisionedThroughput{
+				ReadCapacityUnits:  aws.Int64(1),
+				WriteCapacityUnits: aws.Int64(1),
+			},
+		},
+	)
 	if err != nil {
 		panic(err)
 	}
 
+	waiter := dynamodb.NewTableExistsWaiter(db.Client)
+	err = waiter.Wait(context.TODO(), &dynamodb.DescribeTableInput{
+		TableName: aws.String(db.TableName),
+	}, 5*time.Minute)
+
 	return err
 }
 
-// CreateTableSpending creates a new DynamoDB table named `Spending` for
-// storing user's spending data using the specified DynamoDB instance.
+// CreateTableAccount creates a new DynamoDB table named `Accounts` for
+// storing user's accounts using the specified DynamoDB instance.
 //
-// The `Spending` table has a hash key of `Id` and a Global Secondary Index (GSI)
-// named `UserIndex` with a hash key of `UserId` and sort key of `Date`.
-func CreateTableSpending(ctx context.Context, db *helper.DynamoDB) error {
+// The `Accounts` table has a hash key of `Id` and a Global Secondary Index (GSI)
+// named `UserIndex` with a hash key of `UserId` and sort key of `CreatedAt`.
+func CreateTableAccount(ctx context.Context, db *helper.DynamoDB) error {
 	_, err := db.Client.CreateTable(
 		ctx,
 		&dynamodb.CreateTableInput{
@@ -111,7 +597,7 @@ func CreateTableSpending(ctx context.Context, db *helper.DynamoDB) error {
 					AttributeType: types.ScalarAttributeTypeS,
 				},
 				{
-					AttributeName: aws.String("Date"),
+					AttributeName: aws.String("CreatedAt"),
 					AttributeType: types.ScalarAttributeTypeN,
 				},
 			},
@@ -129,6 +615,82 @@ func CreateTableSpending(ctx context.Context, db *helper.DynamoDB) error {
 							AttributeName: aws.String("UserId"),
 							KeyType:       types.KeyTypeHash,
 						},
+						{
+							AttributeName: aws.String("CreatedAt"),
+							KeyType:       types.KeyTypeRange,
+						},
+					},
+					Projection: &types.Projection{
+						ProjectionType: types.ProjectionTypeAll,
+					},
+					ProvisionedThroughput: &types.ProvisionedThroughput{
+						ReadCapacityUnits:  aws.Int64(1),
+						WriteCapacityUnits: aws.Int64(1),
+					},
+				},
+			},
+			TableName: aws.String(db.TableName),
+			ProvisionedThroughput: &types.ProvisionedThroughput{
+				ReadCapacityUnits:  aws.Int64(1),
+				WriteCapacityUnits: aws.Int64(1),
+			},
+		},
+	)
+	if err != nil {
+		panic(err)
+	}
+
+	waiter := dynamodb.NewTableExistsWaiter(db.Client)
+	err = waiter.Wait(context.TODO(), &dynamodb.DescribeTableInput{
+		TableName: aws.String(db.TableName),
+	}, 5*time.Minute)
+
+	return err
+}
+
+// CreateTableTransfer creates a new DynamoDB table named `Transfers` for
+// storing the transfers between user's accounts using the specified DynamoDB
+// instance.
+//
+// The `Transfers` table has a hash key of `Id` and two Global Secondary
+// Indexes (GSI) named `FromAccountIndex` and `ToAccountIndex` with a hash key
+// of `FromAccountId` and `ToAccountId` respectively and sort key of `Date`.
+func CreateTableTransfer(ctx context.Context, db *helper.DynamoDB) error {
+	_, err := db.Client.CreateTable(
+		ctx,
+		&dynamodb.CreateTableInput{
+			AttributeDefinitions: []types.AttributeDefinition{
+				{
+					AttributeName: aws.String("Id"),
+					AttributeType: types.ScalarAttributeTypeS,
+				},
+				{
+					AttributeName: aws.String("FromAccountId"),
+					AttributeType: types.ScalarAttributeTypeS,
+				},
+				{
+					AttributeName: aws.String("ToAccountId"),
+					AttributeType: types.ScalarAttributeTypeS,
+				},
+				{
+					AttributeName: aws.String("Date"),
+					AttributeType: types.ScalarAttributeTypeN,
+				},
+			},
+			KeySchema: []types.KeySchemaElement{
+				{
+					AttributeName: aws.String("Id"),
+					KeyType:       types.KeyTypeHash,
+				},
+			},
+			GlobalSecondaryIndexes: []types.GlobalSecondaryIndex{
+				{
+					IndexName: aws.String("FromAccountIndex"),
+					KeySchema: []types.KeySchemaElement{
+						{
+							AttributeName: aws.String("FromAccountId"),
+							KeyType:       types.KeyTypeHash,
+						},
 						{
 							AttributeName: aws.String("Date"),
 							KeyType:       types.KeyTypeRange,
@@ -138,14 +700,106 @@ func CreateTableSpending(ctx context.Context, db *helper.DynamoDB) error {
 						ProjectionType: types.ProjectionTypeAll,
 					},
 					ProvisionedThroughput: &types.ProvisionedThroughput{
-						ReadCapacityUnits:  aws.Int64(3),
+						ReadCapacityUnits:  aws.Int64(1),
+						WriteCapacityUnits: aws.Int64(1),
+					},
+				},
+				{
+					IndexName: aws.String("ToAccountIndex"),
+					KeySchema: []types.KeySchemaElement{
+						{
+							AttributeName: aws.String("ToAccountId"),
+							KeyType:       types.KeyTypeHash,
+						},
+						{
+							AttributeName: aws.String("Date"),
+							KeyType:       types.KeyTypeRange,
+						},
+					},
+					Projection: &types.Projection{
+						ProjectionType: types.ProjectionTypeAll,
+					},
+					ProvisionedThroughput: &types.ProvisionedThroughput{
+						ReadCapacityUnits:  aws.Int64(1),
 						WriteCapacityUnits: aws.Int64(1),
 					},
 				},
 			},
 			TableName: aws.String(db.TableName),
 			ProvisionedThroughput: &types.ProvisionedThroughput{
-				ReadCapacityUnits:  aws.Int64(3),
+				ReadCapacityUnits:  aws.Int64(1),
+				WriteCapacityUnits: aws.Int64(1),
+			},
+		},
+	)
+	if err != nil {
+		panic(err)
+	}
+
+	waiter := dynamodb.NewTableExistsWaiter(db.Client)
+	err = waiter.Wait(context.TODO(), &dynamodb.DescribeTableInput{
+		TableName: aws.String(db.TableName),
+	}, 5*time.Minute)
+
+	return err
+}
+
+// CreateTableAttachment creates a new DynamoDB table named `Attachments` for
+// storing the files attached to user's spendings using the specified DynamoDB
+// instance.
+//
+// The `Attachments` table has a hash key of `Id` and a Global Secondary Index
+// (GSI) named `SpendingIndex` with a hash key of `SpendingId` and sort key of
+// `CreatedAt`.
+func CreateTableAttachment(ctx context.Context, db *helper.DynamoDB) error {
+	_, err := db.Client.CreateTable(
+		ctx,
+		&dynamodb.CreateTableInput{
+			AttributeDefinitions: []types.AttributeDefinition{
+				{
+					AttributeName: aws.String("Id"),
+					AttributeType: types.ScalarAttributeTypeS,
+				},
+				{
+					AttributeName: aws.String("SpendingId"),
+					AttributeType: types.ScalarAttributeTypeS,
+				},
+				{
+					AttributeName: aws.String("CreatedAt"),
+					AttributeType: types.ScalarAttributeTypeN,
+				},
+			},
+			KeySchema: []types.KeySchemaElement{
+				{
+					AttributeName: aws.String("Id"),
+					KeyType:       types.KeyTypeHash,
+				},
+			},
+			GlobalSecondaryIndexes: []types.GlobalSecondaryIndex{
+				{
+					IndexName: aws.String("SpendingIndex"),
+					KeySchema: []types.KeySchemaElement{
+						{
+							AttributeName: aws.String("SpendingId"),
+							KeyType:       types.KeyTypeHash,
+						},
+						{
+							AttributeName: aws.String("CreatedAt"),
+							KeyType:       types.KeyTypeRange,
+						},
+					},
+					Projection: &types.Projection{
+						ProjectionType: types.ProjectionTypeAll,
+					},
+					ProvisionedThroughput: &types.ProvisionedThroughput{
+						ReadCapacityUnits:  aws.Int64(1),
+						WriteCapacityUnits: aws.Int64(1),
+					},
+				},
+			},
+			TableName: aws.String(db.TableName),
+			ProvisionedThroughput: &types.ProvisionedThroughput{
+				ReadCapacityUnits:  aws.Int64(1),
 				WriteCapacityUnits: aws.Int64(1),
 			},
 		},
@@ -185,20 +839,87 @@ func DeleteTable(ctx context.Context, db *helper.DynamoDB) error {
 	return nil
 }
 
-// SetupDatabase sets up and returns a helper.DynamoDB instance with configured client
-// and created tables for user data and spending data.
-func SetupDatabase(ctx context.Context) helper.DynamoDB {
+// SetupDatabase sets up a DynamoDB client, creates the tables whose names
+// start with the given prefix when they do not exist yet, and returns the
+// repositories backed by the tables.
+func SetupDatabase(ctx context.Context, tablePrefix string) Repositories {
 	client := SetupClient(ctx)
-	db := helper.DynamoDB{Client: client}
+	table := func(name string, createTableFunc func(ctx context.Context, db *helper.DynamoDB) error) *helper.DynamoDB {
+		db := &helper.DynamoDB{Client: client, TableName: tablePrefix + name}
+		CreateTable(ctx, db, createTableFunc)
+		return db
+	}
 
 	// Create the table "Users" for user data.
-	db.TableName = "Users"
-	CreateTable(ctx, &db, CreateTableUser)
+	users := table("Users", CreateTableUser)
+	if err := CreateEmailIndex(ctx, users); err != nil {
+		panic(err)
+	}
 
 	// Create the table "Spending" for user  spending data
-	db.TableName = "Spending"
-	CreateTable(ctx, &db, CreateTableSpending)
+	spending := table("Spending", CreateTableSpending)
+
+	// Create the table "Sessions" for user's refresh token sessions.
+	sessions := table("Sessions", CreateTableSession)
+
+	// Create the table "Budgets" for user's budgets.
+	budgets := table("Budgets", CreateTableBudget)
+
+	// Create the table "Categories" for user's spending categories.
+	categories := table("Categories", CreateTableCategory)
+
+	// Create the table "ExchangeRates" for the exchange rates between currencies.
+	exchangeRates := table("ExchangeRates", CreateTableExchangeRate)
+
+	// Create the table "RecurringSpendings" for user's recurring spendings.
+	recurringSpendings := table("RecurringSpendings", CreateTableRecurringSpending)
+
+	// Create the table "Accounts" for user's accounts.
+	accounts := table("Accounts", CreateTableAccount)
+
+	// Create the table "Transfers" for the transfers between user's accounts.
+	transfers := table("Transfers", CreateTableTransfer)
+
+	// Create the table "Attachments" for the files attached to user's spendings.
+	attachments := table("Attachments", CreateTableAttachment)
+
+	repositories := Repositories{
+		User:              repository.NewUserRepository(users),
+		Spending:          repository.NewSpendingRepository(spending),
+		Session:           repository.NewSessionRepository(sessions),
+		Budget:            repository.NewBudgetRepository(budgets),
+		Category:          repository.NewCategoryRepository(categories),
+		ExchangeRate:      repository.NewExchangeRateRepository(exchangeRates),
+		RecurringSpending: repository.NewRecurringSpendingRepository(recurringSpendings),
+		Account:           repository.NewAccountRepository(accounts),
+		Transfer:          repository.NewTransferRepository(transfers, accounts),
+		Attachment:        repository.NewAttachmentRepository(attachments),
+	}
 
 	fmt.Println("--- Setup Database Done")
-	return db
+	return repositories
+}
+
+// SetupTokenManager creates and returns a helper.TokenManager using the
+// signing method, keys and expiry defined in the provided configuration.
+func SetupTokenManager(config Config) *helper.TokenManager {
+	switch config.JWTSigningMethod {
+	case jwt.SigningMethodHS256.Alg():
+		if config.JWTSecret == "" {
+			panic("JWT_SECRET must be set when using the HS256 signing method")
+		}
+		return helper.NewHS256TokenManager([]byte(config.JWTSecret), config.AccessTokenExpiry)
+	case jwt.SigningMethodRS256.Alg():
+		pem, err := os.ReadFile(config.JWTPrivateKeyFile)
+		if err != nil {
+			panic(err)
+		}
+		privateKey, err := jwt.ParseRSAPrivateKeyFromPEM(pem)
+		if err != nil {
+			panic(err)
+		}
+		return helper.NewRS256TokenManager(privateKey, config.AccessTokenExpiry)
+	default:
+		panic(fmt.Sprintf("unsupported JWT signing method %q", config.JWTSigningMethod))
+	}
 }
@@ -0,0 +1,66 @@
+package app
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"fmt"
+	"io/fs"
+	_ "modernc.org/sqlite"
+	"net/url"
+)
+
+// sqliteMigrations holds the migrations creating the SQLite tables
+// equivalent to the DynamoDB tables created by SetupDatabase.
+//
+//go:embed migrations/sqlite/*.sql
+var sqliteMigrations embed.FS
+
+// OpenSQLite opens the SQLite database in the file at the given path,
+// creating the file when it does not exist. Every connection uses the
+// write-ahead log, so the database can be read and backed up while it is
+// written, waits for a lock held by another connection instead of failing
+// right away, and matches LIKE patterns case-sensitively as the `contains`
+// function of DynamoDB does.
+func OpenSQLite(path string) *sql.DB {
+	options := url.Values{
+		"_pragma": []string{
+			"journal_mode(WAL)",
+			"busy_timeout(5000)",
+			"case_sensitive_like(1)",
+		},
+		"_txlock": []string{"immediate"},
+	}
+
+	db, err := sql.Open("sqlite", "file:"+path+"?"+options.Encode())
+	if err != nil {
+		panic(err)
+	}
+	return db
+}
+
+// SetupSQLite opens the SQLite database in the file at the given path,
+// applies the migrations which have not been applied yet, and returns the
+// repositories backed by the database.
+func SetupSQLite(ctx context.Context, path string) Repositories {
+	db := OpenSQLite(path)
+
+	migrations, err := fs.Sub(sqliteMigrations, "migrations/sqlite")
+	if err != nil {
+		panic(err)
+	}
+	if err := Migrate(ctx, db, migrations); err != nil {
+		panic(err)
+	}
+
+	fmt.Println("--- Setup Database Done")
+	return NewSQLRepositories(db)
+}
+
+// BackupSQLite writes a consistent copy of the SQLite database to a new
+// file at the given path. The database stays available for reads and
+// writes while it is copied.
+func BackupSQLite(ctx context.Context, db *sql.DB, path string) error {
+	_, err := db.ExecContext(ctx, "VACUUM INTO $1", path)
+	return err
+}
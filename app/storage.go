@@ -0,0 +1,91 @@
+package app
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"github.com/refandas/duit-api/repository"
+)
+
+const (
+	// StorageDynamoDB stores the data in DynamoDB tables.
+	StorageDynamoDB = "dynamodb"
+
+	// StorageMemory keeps the data in memory, so it is lost when the
+	// server stops. It is meant for development and tests.
+	StorageMemory = "memory"
+
+	// StoragePostgres stores the data in a PostgreSQL database.
+	StoragePostgres = "postgres"
+
+	// StorageSQLite stores the data in a single SQLite database file. It
+	// needs no database server, so it suits a personal deployment.
+	StorageSQLite = "sqlite"
+)
+
+// Repositories groups the repositories of every resource of the API, all
+// backed by the same storage.
+type Repositories struct {
+	User              repository.UserRepository
+	Spending          repository.SpendingRepository
+	Session           repository.SessionRepository
+	Budget            repository.BudgetRepository
+	Category          repository.CategoryRepository
+	ExchangeRate      repository.ExchangeRateRepository
+	RecurringSpending repository.RecurringSpendingRepository
+	Account           repository.AccountRepository
+	Transfer          repository.TransferRepository
+	Attachment        repository.AttachmentRepository
+}
+
+// NewMemoryRepositories returns empty repositories keeping the data in
+// memory.
+func NewMemoryRepositories() Repositories {
+	accounts := repository.NewAccountRepositoryMemory()
+	return Repositories{
+		User:              repository.NewUserRepositoryMemory(),
+		Spending:          repository.NewSpendingRepositoryMemory(),
+		Session:           repository.NewSessionRepositoryMemory(),
+		Budget:            repository.NewBudgetRepositoryMemory(),
+		Category:          repository.NewCategoryRepositoryMemory(),
+		ExchangeRate:      repository.NewExchangeRateRepositoryMemory(),
+		RecurringSpending: repository.NewRecurringSpendingRepositoryMemory(),
+		Account:           accounts,
+		Transfer:          repository.NewTransferRepositoryMemory(accounts),
+		Attachment:        repository.NewAttachmentRepositoryMemory(),
+	}
+}
+
+// NewSQLRepositories returns the repositories keeping the data in the
+// tables of the given SQL database.
+func NewSQLRepositories(db *sql.DB) Repositories {
+	return Repositories{
+		User:              repository.NewUserRepositorySQL(db),
+		Spending:          repository.NewSpendingRepositorySQL(db),
+		Session:           repository.NewSessionRepositorySQL(db),
+		Budget:            repository.NewBudgetRepositorySQL(db),
+		Category:          repository.NewCategoryRepositorySQL(db),
+		ExchangeRate:      repository.NewExchangeRateRepositorySQL(db),
+		RecurringSpending: repository.NewRecurringSpendingRepositorySQL(db),
+		Account:           repository.NewAccountRepositorySQL(db),
+		Transfer:          repository.NewTransferRepositorySQL(db),
+		Attachment:        repository.NewAttachmentRepositorySQL(db),
+	}
+}
+
+// SetupRepositories sets up the storage selected by the configuration and
+// returns the repositories backed by it.
+func SetupRepositories(ctx context.Context, config Config) Repositories {
+	switch config.Storage {
+	case StorageDynamoDB:
+		return SetupDatabase(ctx, config.DynamoDBTablePrefix)
+	case StorageMemory:
+		return NewMemoryRepositories()
+	case StoragePostgres:
+		return SetupPostgres(ctx, config.DatabaseURL)
+	case StorageSQLite:
+		return SetupSQLite(ctx, config.SQLitePath)
+	default:
+		panic(fmt.Sprintf("unsupported storage %q", config.Storage))
+	}
+}
@@ -0,0 +1,56 @@
+package app
+
+import (
+	"github.com/go-playground/locales/en"
+	"github.com/go-playground/locales/id"
+	ut "github.com/go-playground/universal-translator"
+	"github.com/go-playground/validator/v10"
+	en_translations "github.com/go-playground/validator/v10/translations/en"
+	id_translations "github.com/go-playground/validator/v10/translations/id"
+	"github.com/refandas/duit-api/exception"
+)
+
+// NewTranslator returns the translator of the error responses into the
+// supported languages, English and Indonesian, with English as the
+// fallback. The messages of the fields rejected by the given validator are
+// translated as well.
+func NewTranslator(validate *validator.Validate) *ut.UniversalTranslator {
+	english := en.New()
+	universal := ut.New(english, english, id.New())
+
+	englishTranslator, _ := universal.GetTranslator("en")
+	if err := en_translations.RegisterDefaultTranslations(validate, englishTranslator); err != nil {
+		panic(err)
+	}
+	if err := registerValidationTranslation(validate, englishTranslator, "timezone", "{0} must be a valid time zone"); err != nil {
+		panic(err)
+	}
+
+	indonesianTranslator, _ := universal.GetTranslator("id")
+	if err := id_translations.RegisterDefaultTranslations(validate, indonesianTranslator); err != nil {
+		panic(err)
+	}
+	if err := registerValidationTranslation(validate, indonesianTranslator, "timezone", "{0} harus berupa zona waktu yang valid"); err != nil {
+		panic(err)
+	}
+	if err := registerValidationTranslation(validate, indonesianTranslator, "lowercase", "{0} harus berupa huruf kecil"); err != nil {
+		panic(err)
+	}
+
+	if err := exception.RegisterTranslations(universal); err != nil {
+		panic(err)
+	}
+	return universal
+}
+
+// registerValidationTranslation registers the translation of the message of
+// a field rejected by the validation rule with the given tag, for the rules
+// without a default translation of the validator.
+func registerValidationTranslation(validate *validator.Validate, translator ut.Translator, tag string, translation string) error {
+	return validate.RegisterTranslation(tag, translator, func(translator ut.Translator) error {
+		return translator.Add(tag, translation, false)
+	}, func(translator ut.Translator, fieldError validator.FieldError) string {
+		message, _ := translator.T(tag, fieldError.Field())
+		return message
+	})
+}
@@ -0,0 +1,22 @@
+package app
+
+import (
+	"github.com/go-playground/validator/v10"
+	"reflect"
+	"strings"
+)
+
+// NewValidator returns the validator of the requests. The fields rejected by
+// the validator are named as in the JSON body of the request, such as
+// `user_id` rather than `UserId`.
+func NewValidator() *validator.Validate {
+	validate := validator.New()
+	validate.RegisterTagNameFunc(func(field reflect.StructField) string {
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			return ""
+		}
+		return name
+	})
+	return validate
+}
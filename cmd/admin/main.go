@@ -0,0 +1,145 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"github.com/refandas/duit-api/app"
+	"github.com/refandas/duit-api/helper"
+	"github.com/refandas/duit-api/service"
+	"os"
+)
+
+const usage = `Usage: admin <command> [arguments]
+
+Commands:
+  backup <path>   write a copy of the SQLite database at SQLITE_PATH to a new
+                  file at the given path while the API server keeps running
+  migrate-amounts convert the spending and budget amounts of the DynamoDB
+                  tables named with DYNAMODB_TABLE_PREFIX recorded as a
+                  number into integer minor units with a currency code
+  migrate-emails  guard the emails of the users of the DynamoDB tables named
+                  with DYNAMODB_TABLE_PREFIX registered before the emails
+                  were kept unique, listing the emails of several users
+  import-rates <path>
+                  save the exchange rates of a CSV file with the columns
+                  base, quote, rate and effective_from into the storage
+                  selected by STORAGE
+`
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	config := app.LoadConfig()
+	switch os.Args[1] {
+	case "backup":
+		if len(os.Args) != 3 {
+			fmt.Fprint(os.Stderr, usage)
+			os.Exit(2)
+		}
+		backup(config, os.Args[2])
+	case "migrate-amounts":
+		migrateAmounts(config)
+	case "migrate-emails":
+		migrateEmails(config)
+	case "import-rates":
+		if len(os.Args) != 3 {
+			fmt.Fprint(os.Stderr, usage)
+			os.Exit(2)
+		}
+		importRates(config, os.Args[2])
+	default:
+		fmt.Fprintf(os.Stderr, "unknown command %q\n\n%s", os.Args[1], usage)
+		os.Exit(2)
+	}
+}
+
+// backup writes a copy of the SQLite database to a new file at the given
+// path.
+func backup(config app.Config, path string) {
+	if _, err := os.Stat(config.SQLitePath); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	db := app.OpenSQLite(config.SQLitePath)
+	defer db.Close()
+
+	if err := app.BackupSQLite(context.Background(), db, path); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	fmt.Printf("--- Backup of %s written to %s\n", config.SQLitePath, path)
+}
+
+// migrateAmounts converts the amounts of the DynamoDB `Spending` and
+// `Budgets` tables recorded as a number into money in minor units.
+func migrateAmounts(config app.Config) {
+	ctx := context.Background()
+	client := app.SetupClient(ctx)
+	db := &helper.DynamoDB{
+		Client:    client,
+		TableName: config.DynamoDBTablePrefix + "Spending",
+	}
+
+	converted, err := app.MigrateSpendingAmounts(ctx, db)
+	fmt.Printf("--- Converted the amounts of %d spendings in %s\n", converted, db.TableName)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	budgets := &helper.DynamoDB{Client: client, TableName: config.DynamoDBTablePrefix + "Budgets"}
+	users := &helper.DynamoDB{Client: client, TableName: config.DynamoDBTablePrefix + "Users"}
+	converted, err = app.MigrateBudgetAmounts(ctx, budgets, users)
+	fmt.Printf("--- Converted the amounts of %d budgets in %s\n", converted, budgets.TableName)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+// migrateEmails puts the missing guard items of the emails of the users of
+// the DynamoDB `Users` table.
+func migrateEmails(config app.Config) {
+	ctx := context.Background()
+	db := &helper.DynamoDB{
+		Client:    app.SetupClient(ctx),
+		TableName: config.DynamoDBTablePrefix + "Users",
+	}
+
+	guarded, duplicates, err := app.MigrateEmailGuards(ctx, db)
+	fmt.Printf("--- Guarded the emails of %d users in %s\n", guarded, db.TableName)
+	for _, email := range duplicates {
+		fmt.Fprintf(os.Stderr, "email %s is registered to more than one user\n", email)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	if len(duplicates) > 0 {
+		os.Exit(1)
+	}
+}
+
+// importRates saves the exchange rates of the CSV file at the given path.
+func importRates(config app.Config, path string) {
+	file, err := os.Open(path)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	defer file.Close()
+
+	ctx := context.Background()
+	repositories := app.SetupRepositories(ctx, config)
+	exchangeRateService := service.NewExchangeRateService(repositories.ExchangeRate, app.NewValidator(), service.NewAdminPolicy(config.AdminUserIds))
+	rates, err := exchangeRateService.Import(helper.ContextWithAdmin(ctx), file)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	fmt.Printf("--- Imported %d exchange rates from %s\n", len(rates), path)
+}
@@ -0,0 +1,18 @@
+package controller
+
+import (
+	"github.com/julienschmidt/httprouter"
+	"net/http"
+)
+
+type AccountController interface {
+	Create(writer http.ResponseWriter, request *http.Request, params httprouter.Params)
+	Update(writer http.ResponseWriter, request *http.Request, params httprouter.Params)
+	Delete(writer http.ResponseWriter, request *http.Request, params httprouter.Params)
+	FindById(writer http.ResponseWriter, request *http.Request, params httprouter.Params)
+	FindByUserId(writer http.ResponseWriter, request *http.Request, params httprouter.Params)
+	Balance(writer http.ResponseWriter, request *http.Request, params httprouter.Params)
+	Transfer(writer http.ResponseWriter, request *http.Request, params httprouter.Params)
+	FindTransferById(writer http.ResponseWriter, request *http.Request, params httprouter.Params)
+	DeleteTransfer(writer http.ResponseWriter, request *http.Request, params httprouter.Params)
+}
@@ -0,0 +1,202 @@
+package controller
+
+import (
+	"github.com/google/uuid"
+	"github.com/julienschmidt/httprouter"
+	"github.com/refandas/duit-api/exception"
+	"github.com/refandas/duit-api/helper"
+	"github.com/refandas/duit-api/model/web"
+	"github.com/refandas/duit-api/service"
+	"net/http"
+	"time"
+)
+
+type AccountControllerImpl struct {
+	AccountService service.AccountService
+}
+
+func NewAccountController(accountService service.AccountService) AccountController {
+	return &AccountControllerImpl{AccountService: accountService}
+}
+
+func (controller *AccountControllerImpl) Create(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+	accountCreateRequest := web.AccountCreateRequest{}
+	if err := helper.ReadFromRequestBody(request, &accountCreateRequest); err != nil {
+		exception.WriteError(writer, request, exception.NewValidationError("invalid request body"))
+		return
+	}
+
+	accountId, _ := uuid.NewRandom()
+	accountCreateRequest.Id = accountId.String()
+	accountCreateRequest.UserId = params.ByName("userId")
+	accountCreateRequest.CreatedAt = time.Now().UnixMilli()
+
+	accountResponse, err := controller.AccountService.Create(request.Context(), accountCreateRequest)
+	if err != nil {
+		exception.WriteError(writer, request, err)
+		return
+	}
+	webResponse := web.WebResponse{
+		Code:   http.StatusCreated,
+		Status: "CREATED",
+		Data:   accountResponse,
+	}
+	helper.WriteToResponseBody(writer, webResponse)
+}
+
+func (controller *AccountControllerImpl) Update(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+	accountUpdateRequest := web.AccountUpdateRequest{}
+	if err := helper.ReadFromRequestBody(request, &accountUpdateRequest); err != nil {
+		exception.WriteError(writer, request, exception.NewValidationError("invalid request body"))
+		return
+	}
+
+	accountUpdateRequest.Id = params.ByName("accountId")
+	accountUpdateRequest.UserId = params.ByName("userId")
+
+	accountResponse, err := controller.AccountService.Update(request.Context(), accountUpdateRequest)
+	if err != nil {
+		exception.WriteError(writer, request, err)
+		return
+	}
+	webResponse := web.WebResponse{
+		Code:   http.StatusOK,
+		Status: "OK",
+		Data:   accountResponse,
+	}
+	helper.WriteToResponseBody(writer, webResponse)
+}
+
+func (controller *AccountControllerImpl) Delete(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+	userId := params.ByName("userId")
+	accountId := params.ByName("accountId")
+
+	if err := controller.AccountService.Delete(request.Context(), userId, accountId); err != nil {
+		exception.WriteError(writer, request, err)
+		return
+	}
+	webResponse := web.WebResponse{
+		Code:   http.StatusNoContent,
+		Status: "DELETED",
+	}
+	helper.WriteToResponseBody(writer, webResponse)
+}
+
+func (controller *AccountControllerImpl) FindById(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+	userId := params.ByName("userId")
+	accountId := params.ByName("accountId")
+
+	accountResponse, err := controller.AccountService.FindById(request.Context(), userId, accountId)
+	if err != nil {
+		exception.WriteError(writer, request, err)
+		return
+	}
+	webResponse := web.WebResponse{
+		Code:   http.StatusOK,
+		Status: "OK",
+		Data:   accountResponse,
+	}
+	helper.WriteToResponseBody(writer, webResponse)
+}
+
+func (controller *AccountControllerImpl) FindByUserId(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+	userId := params.ByName("userId")
+
+	accountResponses, err := controller.AccountService.FindByUserId(request.Context(), userId)
+	if err != nil {
+		exception.WriteError(writer, request, err)
+		return
+	}
+	webResponse := web.WebResponse{
+		Code:   http.StatusOK,
+		Status: "OK",
+		Data:   accountResponses,
+	}
+	helper.WriteToResponseBody(writer, webResponse)
+}
+
+func (controller *AccountControllerImpl) Balance(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+	query := request.URL.Query()
+	balanceRequest := web.AccountBalanceRequest{
+		UserId:    params.ByName("userId"),
+		AccountId: params.ByName("accountId"),
+	}
+	var err error
+	if balanceRequest.From, err = queryTime(query, "from", false); err != nil {
+		exception.WriteError(writer, request, err)
+		return
+	}
+	if balanceRequest.To, err = queryTime(query, "to", true); err != nil {
+		exception.WriteError(writer, request, err)
+		return
+	}
+
+	balanceResponse, err := controller.AccountService.Balance(request.Context(), balanceRequest)
+	if err != nil {
+		exception.WriteError(writer, request, err)
+		return
+	}
+	webResponse := web.WebResponse{
+		Code:   http.StatusOK,
+		Status: "OK",
+		Data:   balanceResponse,
+	}
+	helper.WriteToResponseBody(writer, webResponse)
+}
+
+func (controller *AccountControllerImpl) Transfer(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+	transferCreateRequest := web.TransferCreateRequest{}
+	if err := helper.ReadFromRequestBody(request, &transferCreateRequest); err != nil {
+		exception.WriteError(writer, request, exception.NewValidationError("invalid request body"))
+		return
+	}
+
+	transferId, _ := uuid.NewRandom()
+	transferCreateRequest.Id = transferId.String()
+	transferCreateRequest.UserId = params.ByName("userId")
+	transferCreateRequest.CreatedAt = time.Now().UnixMilli()
+
+	transferResponse, err := controller.AccountService.Transfer(request.Context(), transferCreateRequest)
+	if err != nil {
+		exception.WriteError(writer, request, err)
+		return
+	}
+	webResponse := web.WebResponse{
+		Code:   http.StatusCreated,
+		Status: "CREATED",
+		Data:   transferResponse,
+	}
+	helper.WriteToResponseBody(writer, webResponse)
+}
+
+func (controller *AccountControllerImpl) FindTransferById(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+	userId := params.ByName("userId")
+	transferId := params.ByName("transferId")
+
+	transferResponse, err := controller.AccountService.FindTransferById(request.Context(), userId, transferId)
+	if err != nil {
+		exception.WriteError(writer, request, err)
+		return
+	}
+	webResponse := web.WebResponse{
+		Code:   http.StatusOK,
+		Status: "OK",
+		Data:   transferResponse,
+	}
+	helper.WriteToResponseBody(writer, webResponse)
+}
+
+func (controller *AccountControllerImpl) DeleteTransfer(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+	userId := params.ByName("userId")
+	transferId := params.ByName("transferId")
+
+	if err := controller.AccountService.DeleteTransfer(request.Context(), userId, transferId); err != nil {
+		exception.WriteError(writer, request, err)
+		return
+	}
+	webResponse := web.WebResponse{
+		Code:   http.StatusNoContent,
+		Status: "DELETED",
+	}
+	helper.WriteToResponseBody(writer, webResponse)
+}
@@ -0,0 +1,13 @@
+package controller
+
+import (
+	"github.com/julienschmidt/httprouter"
+	"net/http"
+)
+
+type AttachmentController interface {
+	Create(writer http.ResponseWriter, request *http.Request, params httprouter.Params)
+	Delete(writer http.ResponseWriter, request *http.Request, params httprouter.Params)
+	FindBySpendingId(writer http.ResponseWriter, request *http.Request, params httprouter.Params)
+	Download(writer http.ResponseWriter, request *http.Request, params httprouter.Params)
+}
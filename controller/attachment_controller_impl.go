@@ -0,0 +1,129 @@
+package controller
+
+import (
+	"errors"
+	"github.com/google/uuid"
+	"github.com/julienschmidt/httprouter"
+	"github.com/refandas/duit-api/exception"
+	"github.com/refandas/duit-api/helper"
+	"github.com/refandas/duit-api/model/web"
+	"github.com/refandas/duit-api/service"
+	"io"
+	"log"
+	"mime"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// attachmentFormOverhead is the room left in the body of an upload for the
+// headers and the boundaries of the multipart form around the file.
+const attachmentFormOverhead = 64 << 10
+
+type AttachmentControllerImpl struct {
+	AttachmentService service.AttachmentService
+	MaxSize           int64
+}
+
+func NewAttachmentController(attachmentService service.AttachmentService, maxSize int64) AttachmentController {
+	return &AttachmentControllerImpl{AttachmentService: attachmentService, MaxSize: maxSize}
+}
+
+// Create attaches the file uploaded in the `file` field of a multipart form
+// to the spending. The fields before the file are skipped, and the ones
+// after it are not read.
+func (controller *AttachmentControllerImpl) Create(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+	request.Body = http.MaxBytesReader(writer, request.Body, controller.MaxSize+attachmentFormOverhead)
+	multipartReader, err := request.MultipartReader()
+	if err != nil {
+		exception.WriteError(writer, request, exception.NewValidationError("invalid request body"))
+		return
+	}
+	for {
+		part, err := multipartReader.NextPart()
+		if errors.Is(err, io.EOF) {
+			exception.WriteError(writer, request, exception.NewValidationError("file is required"))
+			return
+		}
+		if err != nil {
+			exception.WriteError(writer, request, exception.NewValidationError("invalid request body"))
+			return
+		}
+		if part.FormName() != "file" {
+			continue
+		}
+
+		attachmentId, _ := uuid.NewRandom()
+		attachmentCreateRequest := web.AttachmentCreateRequest{
+			Id:         attachmentId.String(),
+			SpendingId: params.ByName("spendingId"),
+			FileName:   part.FileName(),
+			CreatedAt:  time.Now().UnixMilli(),
+		}
+
+		attachmentResponse, err := controller.AttachmentService.Create(request.Context(), attachmentCreateRequest, part)
+		if err != nil {
+			exception.WriteError(writer, request, err)
+			return
+		}
+		webResponse := web.WebResponse{
+			Code:   http.StatusCreated,
+			Status: "CREATED",
+			Data:   attachmentResponse,
+		}
+		helper.WriteToResponseBody(writer, webResponse)
+		return
+	}
+}
+
+func (controller *AttachmentControllerImpl) Delete(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+	spendingId := params.ByName("spendingId")
+	attachmentId := params.ByName("attachmentId")
+
+	if err := controller.AttachmentService.Delete(request.Context(), spendingId, attachmentId); err != nil {
+		exception.WriteError(writer, request, err)
+		return
+	}
+	webResponse := web.WebResponse{
+		Code:   http.StatusNoContent,
+		Status: "DELETED",
+	}
+	helper.WriteToResponseBody(writer, webResponse)
+}
+
+func (controller *AttachmentControllerImpl) FindBySpendingId(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+	spendingId := params.ByName("spendingId")
+
+	attachmentResponses, err := controller.AttachmentService.FindBySpendingId(request.Context(), spendingId)
+	if err != nil {
+		exception.WriteError(writer, request, err)
+		return
+	}
+	webResponse := web.WebResponse{
+		Code:   http.StatusOK,
+		Status: "OK",
+		Data:   attachmentResponses,
+	}
+	helper.WriteToResponseBody(writer, webResponse)
+}
+
+func (controller *AttachmentControllerImpl) Download(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+	spendingId := params.ByName("spendingId")
+	attachmentId := params.ByName("attachmentId")
+
+	download, err := controller.AttachmentService.Download(request.Context(), spendingId, attachmentId)
+	if err != nil {
+		exception.WriteError(writer, request, err)
+		return
+	}
+	defer download.Content.Close()
+
+	writer.Header().Set("Content-Type", download.ContentType)
+	writer.Header().Set("Content-Length", strconv.FormatInt(download.Size, 10))
+	writer.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": download.FileName}))
+	writer.Header().Set("X-Content-Type-Options", "nosniff")
+	if _, err := io.Copy(writer, download.Content); err != nil {
+		// The file has been partly responded, so the error can not be.
+		log.Printf("%s %s: %v", request.Method, request.URL.Path, err)
+	}
+}
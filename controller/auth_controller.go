@@ -0,0 +1,12 @@
+package controller
+
+import (
+	"github.com/julienschmidt/httprouter"
+	"net/http"
+)
+
+type AuthController interface {
+	Login(writer http.ResponseWriter, request *http.Request, params httprouter.Params)
+	Refresh(writer http.ResponseWriter, request *http.Request, params httprouter.Params)
+	Logout(writer http.ResponseWriter, request *http.Request, params httprouter.Params)
+}
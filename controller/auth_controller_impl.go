@@ -0,0 +1,77 @@
+package controller
+
+import (
+	"github.com/julienschmidt/httprouter"
+	"github.com/refandas/duit-api/exception"
+	"github.com/refandas/duit-api/helper"
+	"github.com/refandas/duit-api/model/web"
+	"github.com/refandas/duit-api/service"
+	"net/http"
+)
+
+type AuthControllerImpl struct {
+	AuthService service.AuthService
+}
+
+func NewAuthController(authService service.AuthService) AuthController {
+	return &AuthControllerImpl{AuthService: authService}
+}
+
+func (controller *AuthControllerImpl) Login(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+	loginRequest := web.LoginRequest{}
+	if err := helper.ReadFromRequestBody(request, &loginRequest); err != nil {
+		exception.WriteError(writer, request, exception.NewValidationError("invalid request body"))
+		return
+	}
+	loginRequest.UserAgent = request.UserAgent()
+
+	tokenResponse, err := controller.AuthService.Login(request.Context(), loginRequest)
+	if err != nil {
+		exception.WriteError(writer, request, err)
+		return
+	}
+	webResponse := web.WebResponse{
+		Code:   http.StatusOK,
+		Status: "OK",
+		Data:   tokenResponse,
+	}
+	helper.WriteToResponseBody(writer, webResponse)
+}
+
+func (controller *AuthControllerImpl) Refresh(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+	refreshTokenRequest := web.RefreshTokenRequest{}
+	if err := helper.ReadFromRequestBody(request, &refreshTokenRequest); err != nil {
+		exception.WriteError(writer, request, exception.NewValidationError("invalid request body"))
+		return
+	}
+
+	tokenResponse, err := controller.AuthService.Refresh(request.Context(), refreshTokenRequest)
+	if err != nil {
+		exception.WriteError(writer, request, err)
+		return
+	}
+	webResponse := web.WebResponse{
+		Code:   http.StatusOK,
+		Status: "OK",
+		Data:   tokenResponse,
+	}
+	helper.WriteToResponseBody(writer, webResponse)
+}
+
+func (controller *AuthControllerImpl) Logout(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+	refreshTokenRequest := web.RefreshTokenRequest{}
+	if err := helper.ReadFromRequestBody(request, &refreshTokenRequest); err != nil {
+		exception.WriteError(writer, request, exception.NewValidationError("invalid request body"))
+		return
+	}
+
+	if err := controller.AuthService.Logout(request.Context(), refreshTokenRequest); err != nil {
+		exception.WriteError(writer, request, err)
+		return
+	}
+	webResponse := web.WebResponse{
+		Code:   http.StatusOK,
+		Status: "OK",
+	}
+	helper.WriteToResponseBody(writer, webResponse)
+}
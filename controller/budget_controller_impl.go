@@ -0,0 +1,133 @@
+package controller
+
+import (
+	"github.com/google/uuid"
+	"github.com/julienschmidt/httprouter"
+	"github.com/refandas/duit-api/exception"
+	"github.com/refandas/duit-api/helper"
+	"github.com/refandas/duit-api/model/web"
+	"github.com/refandas/duit-api/service"
+	"net/http"
+	"time"
+)
+
+type BudgetControllerImpl struct {
+	BudgetService service.BudgetService
+}
+
+func NewBudgetController(budgetService service.BudgetService) BudgetController {
+	return &BudgetControllerImpl{BudgetService: budgetService}
+}
+
+func (controller *BudgetControllerImpl) Create(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+	budgetCreateRequest := web.BudgetCreateRequest{}
+	if err := helper.ReadFromRequestBody(request, &budgetCreateRequest); err != nil {
+		exception.WriteError(writer, request, exception.NewValidationError("invalid request body"))
+		return
+	}
+
+	budgetId, _ := uuid.NewRandom()
+	budgetCreateRequest.Id = budgetId.String()
+	budgetCreateRequest.UserId = params.ByName("userId")
+	budgetCreateRequest.CreatedAt = time.Now().UnixMilli()
+
+	budgetResponse, err := controller.BudgetService.Create(request.Context(), budgetCreateRequest)
+	if err != nil {
+		exception.WriteError(writer, request, err)
+		return
+	}
+	webResponse := web.WebResponse{
+		Code:   http.StatusCreated,
+		Status: "CREATED",
+		Data:   budgetResponse,
+	}
+	helper.WriteToResponseBody(writer, webResponse)
+}
+
+func (controller *BudgetControllerImpl) Update(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+	budgetUpdateRequest := web.BudgetUpdateRequest{}
+	if err := helper.ReadFromRequestBody(request, &budgetUpdateRequest); err != nil {
+		exception.WriteError(writer, request, exception.NewValidationError("invalid request body"))
+		return
+	}
+
+	budgetUpdateRequest.Id = params.ByName("budgetId")
+	budgetUpdateRequest.UserId = params.ByName("userId")
+
+	budgetResponse, err := controller.BudgetService.Update(request.Context(), budgetUpdateRequest)
+	if err != nil {
+		exception.WriteError(writer, request, err)
+		return
+	}
+	webResponse := web.WebResponse{
+		Code:   http.StatusOK,
+		Status: "OK",
+		Data:   budgetResponse,
+	}
+	helper.WriteToResponseBody(writer, webResponse)
+}
+
+func (controller *BudgetControllerImpl) Delete(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+	userId := params.ByName("userId")
+	budgetId := params.ByName("budgetId")
+
+	if err := controller.BudgetService.Delete(request.Context(), userId, budgetId); err != nil {
+		exception.WriteError(writer, request, err)
+		return
+	}
+	webResponse := web.WebResponse{
+		Code:   http.StatusNoContent,
+		Status: "DELETED",
+	}
+	helper.WriteToResponseBody(writer, webResponse)
+}
+
+func (controller *BudgetControllerImpl) FindById(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+	userId := params.ByName("userId")
+	budgetId := params.ByName("budgetId")
+
+	budgetResponse, err := controller.BudgetService.FindById(request.Context(), userId, budgetId)
+	if err != nil {
+		exception.WriteError(writer, request, err)
+		return
+	}
+	webResponse := web.WebResponse{
+		Code:   http.StatusOK,
+		Status: "OK",
+		Data:   budgetResponse,
+	}
+	helper.WriteToResponseBody(writer, webResponse)
+}
+
+func (controller *BudgetControllerImpl) FindByUserId(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+	userId := params.ByName("userId")
+
+	budgetResponses, err := controller.BudgetService.FindByUserId(request.Context(), userId)
+	if err != nil {
+		exception.WriteError(writer, request, err)
+		return
+	}
+	webResponse := web.WebResponse{
+		Code:   http.StatusOK,
+		Status: "OK",
+		Data:   budgetResponses,
+	}
+	helper.WriteToResponseBody(writer, webResponse)
+}
+
+func (controller *BudgetControllerImpl) Status(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+	userId := params.ByName("userId")
+	budgetId := params.ByName("budgetId")
+
+	statusResponse, err := controller.BudgetService.Status(request.Context(), userId, budgetId)
+	if err != nil {
+		exception.WriteError(writer, request, err)
+		return
+	}
+	webResponse := web.WebResponse{
+		Code:   http.StatusOK,
+		Status: "OK",
+		Data:   statusResponse,
+	}
+	helper.WriteToResponseBody(writer, webResponse)
+}
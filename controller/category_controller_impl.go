@@ -0,0 +1,139 @@
+package controller
+
+import (
+	"github.com/google/uuid"
+	"github.com/julienschmidt/httprouter"
+	"github.com/refandas/duit-api/exception"
+	"github.com/refandas/duit-api/helper"
+	"github.com/refandas/duit-api/model/web"
+	"github.com/refandas/duit-api/service"
+	"net/http"
+	"time"
+)
+
+type CategoryControllerImpl struct {
+	CategoryService service.CategoryService
+}
+
+func NewCategoryController(categoryService service.CategoryService) CategoryController {
+	return &CategoryControllerImpl{CategoryService: categoryService}
+}
+
+func (controller *CategoryControllerImpl) Create(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+	categoryCreateRequest := web.CategoryCreateRequest{}
+	if err := helper.ReadFromRequestBody(request, &categoryCreateRequest); err != nil {
+		exception.WriteError(writer, request, exception.NewValidationError("invalid request body"))
+		return
+	}
+
+	categoryId, _ := uuid.NewRandom()
+	categoryCreateRequest.Id = categoryId.String()
+	categoryCreateRequest.UserId = params.ByName("userId")
+	categoryCreateRequest.CreatedAt = time.Now().UnixMilli()
+
+	categoryResponse, err := controller.CategoryService.Create(request.Context(), categoryCreateRequest)
+	if err != nil {
+		exception.WriteError(writer, request, err)
+		return
+	}
+	webResponse := web.WebResponse{
+		Code:   http.StatusCreated,
+		Status: "CREATED",
+		Data:   categoryResponse,
+	}
+	helper.WriteToResponseBody(writer, webResponse)
+}
+
+func (controller *CategoryControllerImpl) Update(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+	categoryUpdateRequest := web.CategoryUpdateRequest{}
+	if err := helper.ReadFromRequestBody(request, &categoryUpdateRequest); err != nil {
+		exception.WriteError(writer, request, exception.NewValidationError("invalid request body"))
+		return
+	}
+
+	categoryUpdateRequest.Id = params.ByName("categoryId")
+	categoryUpdateRequest.UserId = params.ByName("userId")
+
+	categoryResponse, err := controller.CategoryService.Update(request.Context(), categoryUpdateRequest)
+	if err != nil {
+		exception.WriteError(writer, request, err)
+		return
+	}
+	webResponse := web.WebResponse{
+		Code:   http.StatusOK,
+		Status: "OK",
+		Data:   categoryResponse,
+	}
+	helper.WriteToResponseBody(writer, webResponse)
+}
+
+func (controller *CategoryControllerImpl) Merge(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+	categoryMergeRequest := web.CategoryMergeRequest{}
+	if err := helper.ReadFromRequestBody(request, &categoryMergeRequest); err != nil {
+		exception.WriteError(writer, request, exception.NewValidationError("invalid request body"))
+		return
+	}
+
+	categoryMergeRequest.Id = params.ByName("categoryId")
+	categoryMergeRequest.UserId = params.ByName("userId")
+
+	categoryResponse, err := controller.CategoryService.Merge(request.Context(), categoryMergeRequest)
+	if err != nil {
+		exception.WriteError(writer, request, err)
+		return
+	}
+	webResponse := web.WebResponse{
+		Code:   http.StatusOK,
+		Status: "OK",
+		Data:   categoryResponse,
+	}
+	helper.WriteToResponseBody(writer, webResponse)
+}
+
+func (controller *CategoryControllerImpl) Delete(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+	userId := params.ByName("userId")
+	categoryId := params.ByName("categoryId")
+
+	if err := controller.CategoryService.Delete(request.Context(), userId, categoryId); err != nil {
+		exception.WriteError(writer, request, err)
+		return
+	}
+	webResponse := web.WebResponse{
+		Code:   http.StatusNoContent,
+		Status: "DELETED",
+	}
+	helper.WriteToResponseBody(writer, webResponse)
+}
+
+func (controller *CategoryControllerImpl) FindById(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+	userId := params.ByName("userId")
+	categoryId := params.ByName("categoryId")
+
+	categoryResponse, err := controller.CategoryService.FindById(request.Context(), userId, categoryId)
+	if err != nil {
+		exception.WriteError(writer, request, err)
+		return
+	}
+	webResponse := web.WebResponse{
+		Code:   http.StatusOK,
+		Status: "OK",
+		Data:   categoryResponse,
+	}
+	helper.WriteToResponseBody(writer, webResponse)
+}
+
+func (controller *CategoryControllerImpl) FindByUserId(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+	userId := params.ByName("userId")
+
+	categoryResponses, err := controller.CategoryService.FindByUserId(request.Context(), userId)
+	if err != nil {
+		exception.WriteError(writer, request, err)
+		return
+	}
+	webResponse := web.WebResponse{
+		Code:   http.StatusOK,
+		Status: "OK",
+		Data:   categoryResponses,
+	}
+	helper.WriteToResponseBody(writer, webResponse)
+}
@@ -0,0 +1,11 @@
+package controller
+
+import (
+	"github.com/julienschmidt/httprouter"
+	"net/http"
+)
+
+type ExchangeRateController interface {
+	Create(writer http.ResponseWriter, request *http.Request, params httprouter.Params)
+	FindByPair(writer http.ResponseWriter, request *http.Request, params httprouter.Params)
+}
@@ -0,0 +1,69 @@
+package controller
+
+import (
+	"github.com/julienschmidt/httprouter"
+	"github.com/refandas/duit-api/exception"
+	"github.com/refandas/duit-api/helper"
+	"github.com/refandas/duit-api/model/web"
+	"github.com/refandas/duit-api/service"
+	"mime"
+	"net/http"
+	"strings"
+)
+
+type ExchangeRateControllerImpl struct {
+	ExchangeRateService service.ExchangeRateService
+}
+
+func NewExchangeRateController(exchangeRateService service.ExchangeRateService) ExchangeRateController {
+	return &ExchangeRateControllerImpl{ExchangeRateService: exchangeRateService}
+}
+
+// Create saves the rates of a JSON request body, or of a CSV file when the
+// request body is of the `text/csv` content type.
+func (controller *ExchangeRateControllerImpl) Create(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+	var rateResponses []web.ExchangeRateResponse
+	var err error
+
+	mediaType, _, _ := mime.ParseMediaType(request.Header.Get("Content-Type"))
+	if mediaType == "text/csv" {
+		rateResponses, err = controller.ExchangeRateService.Import(request.Context(), request.Body)
+	} else {
+		batchRequest := web.ExchangeRateBatchRequest{}
+		if err := helper.ReadFromRequestBody(request, &batchRequest); err != nil {
+			exception.WriteError(writer, request, exception.NewValidationError("invalid request body"))
+			return
+		}
+		rateResponses, err = controller.ExchangeRateService.Create(request.Context(), batchRequest)
+	}
+	if err != nil {
+		exception.WriteError(writer, request, err)
+		return
+	}
+	webResponse := web.WebResponse{
+		Code:   http.StatusCreated,
+		Status: "CREATED",
+		Data:   rateResponses,
+	}
+	helper.WriteToResponseBody(writer, webResponse)
+}
+
+func (controller *ExchangeRateControllerImpl) FindByPair(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+	query := request.URL.Query()
+	listRequest := web.ExchangeRateListRequest{
+		Base:  strings.ToUpper(query.Get("base")),
+		Quote: strings.ToUpper(query.Get("quote")),
+	}
+
+	rateResponses, err := controller.ExchangeRateService.FindByPair(request.Context(), listRequest)
+	if err != nil {
+		exception.WriteError(writer, request, err)
+		return
+	}
+	webResponse := web.WebResponse{
+		Code:   http.StatusOK,
+		Status: "OK",
+		Data:   rateResponses,
+	}
+	helper.WriteToResponseBody(writer, webResponse)
+}
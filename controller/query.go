@@ -0,0 +1,86 @@
+package controller
+
+import (
+	"github.com/refandas/duit-api/exception"
+	"github.com/refandas/duit-api/helper"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// defaultPageLimit is the number of items in a page when the request does
+// not specify the `limit` query parameter.
+const defaultPageLimit = 50
+
+// queryInt returns the query parameter with the given name parsed as an
+// integer, or the fallback value when the parameter is not set. It returns
+// a validation error when the parameter is not an integer.
+func queryInt(query url.Values, name string, fallback int) (int, error) {
+	value := query.Get(name)
+	if value == "" {
+		return fallback, nil
+	}
+	number, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, exception.NewValidationError("{0} must be an integer", name)
+	}
+	return number, nil
+}
+
+// queryBool returns the query parameter with the given name parsed as a
+// boolean, or false when the parameter is not set. It returns a validation
+// error when the parameter is not a boolean.
+func queryBool(query url.Values, name string) (bool, error) {
+	value := query.Get(name)
+	if value == "" {
+		return false, nil
+	}
+	boolean, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, exception.NewValidationError("{0} must be a boolean", name)
+	}
+	return boolean, nil
+}
+
+// queryFloat returns the query parameter with the given name parsed as a
+// floating-point number, or nil when the parameter is not set. It returns a
+// validation error when the parameter is not a number.
+func queryFloat(query url.Values, name string) (*float64, error) {
+	value := query.Get(name)
+	if value == "" {
+		return nil, nil
+	}
+	number, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return nil, exception.NewValidationError("{0} must be a number", name)
+	}
+	return &number, nil
+}
+
+// queryList returns the query parameter with the given name split by
+// commas, without the empty items, or nil when the parameter is not set.
+func queryList(query url.Values, name string) []string {
+	var items []string
+	for _, item := range strings.Split(query.Get(name), ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// queryTime returns the query parameter with the given name parsed by
+// helper.ParseTime in UTC, or nil when the parameter is not set. It returns
+// a validation error when the parameter is not a time.
+func queryTime(query url.Values, name string, endOfDay bool) (*int64, error) {
+	value := query.Get(name)
+	if value == "" {
+		return nil, nil
+	}
+	milliseconds, err := helper.ParseTime(value, time.UTC, endOfDay)
+	if err != nil {
+		return nil, exception.NewValidationError("{0} must be a date", name)
+	}
+	return &milliseconds, nil
+}
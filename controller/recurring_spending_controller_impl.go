@@ -0,0 +1,116 @@
+package controller
+
+import (
+	"github.com/google/uuid"
+	"github.com/julienschmidt/httprouter"
+	"github.com/refandas/duit-api/exception"
+	"github.com/refandas/duit-api/helper"
+	"github.com/refandas/duit-api/model/web"
+	"github.com/refandas/duit-api/service"
+	"net/http"
+	"time"
+)
+
+type RecurringSpendingControllerImpl struct {
+	RecurringSpendingService service.RecurringSpendingService
+}
+
+func NewRecurringSpendingController(recurringSpendingService service.RecurringSpendingService) RecurringSpendingController {
+	return &RecurringSpendingControllerImpl{RecurringSpendingService: recurringSpendingService}
+}
+
+func (controller *RecurringSpendingControllerImpl) Create(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+	recurringCreateRequest := web.RecurringSpendingCreateRequest{}
+	if err := helper.ReadFromRequestBody(request, &recurringCreateRequest); err != nil {
+		exception.WriteError(writer, request, exception.NewValidationError("invalid request body"))
+		return
+	}
+
+	recurringId, _ := uuid.NewRandom()
+	recurringCreateRequest.Id = recurringId.String()
+	recurringCreateRequest.UserId = params.ByName("userId")
+	recurringCreateRequest.CreatedAt = time.Now().UnixMilli()
+
+	recurringResponse, err := controller.RecurringSpendingService.Create(request.Context(), recurringCreateRequest)
+	if err != nil {
+		exception.WriteError(writer, request, err)
+		return
+	}
+	webResponse := web.WebResponse{
+		Code:   http.StatusCreated,
+		Status: "CREATED",
+		Data:   recurringResponse,
+	}
+	helper.WriteToResponseBody(writer, webResponse)
+}
+
+func (controller *RecurringSpendingControllerImpl) Update(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+	recurringUpdateRequest := web.RecurringSpendingUpdateRequest{}
+	if err := helper.ReadFromRequestBody(request, &recurringUpdateRequest); err != nil {
+		exception.WriteError(writer, request, exception.NewValidationError("invalid request body"))
+		return
+	}
+
+	recurringUpdateRequest.Id = params.ByName("recurringId")
+	recurringUpdateRequest.UserId = params.ByName("userId")
+
+	recurringResponse, err := controller.RecurringSpendingService.Update(request.Context(), recurringUpdateRequest)
+	if err != nil {
+		exception.WriteError(writer, request, err)
+		return
+	}
+	webResponse := web.WebResponse{
+		Code:   http.StatusOK,
+		Status: "OK",
+		Data:   recurringResponse,
+	}
+	helper.WriteToResponseBody(writer, webResponse)
+}
+
+func (controller *RecurringSpendingControllerImpl) Delete(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+	userId := params.ByName("userId")
+	recurringId := params.ByName("recurringId")
+
+	if err := controller.RecurringSpendingService.Delete(request.Context(), userId, recurringId); err != nil {
+		exception.WriteError(writer, request, err)
+		return
+	}
+	webResponse := web.WebResponse{
+		Code:   http.StatusNoContent,
+		Status: "DELETED",
+	}
+	helper.WriteToResponseBody(writer, webResponse)
+}
+
+func (controller *RecurringSpendingControllerImpl) FindById(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+	userId := params.ByName("userId")
+	recurringId := params.ByName("recurringId")
+
+	recurringResponse, err := controller.RecurringSpendingService.FindById(request.Context(), userId, recurringId)
+	if err != nil {
+		exception.WriteError(writer, request, err)
+		return
+	}
+	webResponse := web.WebResponse{
+		Code:   http.StatusOK,
+		Status: "OK",
+		Data:   recurringResponse,
+	}
+	helper.WriteToResponseBody(writer, webResponse)
+}
+
+func (controller *RecurringSpendingControllerImpl) FindByUserId(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+	userId := params.ByName("userId")
+
+	recurringResponses, err := controller.RecurringSpendingService.FindByUserId(request.Context(), userId)
+	if err != nil {
+		exception.WriteError(writer, request, err)
+		return
+	}
+	webResponse := web.WebResponse{
+		Code:   http.StatusOK,
+		Status: "OK",
+		Data:   recurringResponses,
+	}
+	helper.WriteToResponseBody(writer, webResponse)
+}
@@ -0,0 +1,11 @@
+package controller
+
+import (
+	"github.com/julienschmidt/httprouter"
+	"net/http"
+)
+
+type ReportController interface {
+	Summary(writer http.ResponseWriter, request *http.Request, params httprouter.Params)
+	Balance(writer http.ResponseWriter, request *http.Request, params httprouter.Params)
+}
@@ -0,0 +1,70 @@
+package controller
+
+import (
+	"github.com/julienschmidt/httprouter"
+	"github.com/refandas/duit-api/exception"
+	"github.com/refandas/duit-api/helper"
+	"github.com/refandas/duit-api/model/web"
+	"github.com/refandas/duit-api/service"
+	"net/http"
+)
+
+type ReportControllerImpl struct {
+	ReportService service.ReportService
+}
+
+func NewReportController(reportService service.ReportService) ReportController {
+	return &ReportControllerImpl{ReportService: reportService}
+}
+
+func (controller *ReportControllerImpl) Summary(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+	query := request.URL.Query()
+	reportSummaryRequest := web.ReportSummaryRequest{
+		UserId:   params.ByName("userId"),
+		Period:   query.Get("period"),
+		From:     query.Get("from"),
+		To:       query.Get("to"),
+		TimeZone: query.Get("tz"),
+	}
+	if reportSummaryRequest.Period == "" {
+		reportSummaryRequest.Period = "month"
+	}
+
+	reportResponse, err := controller.ReportService.Summary(request.Context(), reportSummaryRequest)
+	if err != nil {
+		exception.WriteError(writer, request, err)
+		return
+	}
+	webResponse := web.WebResponse{
+		Code:   http.StatusOK,
+		Status: "OK",
+		Data:   reportResponse,
+	}
+	helper.WriteToResponseBody(writer, webResponse)
+}
+
+func (controller *ReportControllerImpl) Balance(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+	query := request.URL.Query()
+	balanceRequest := web.BalanceRequest{
+		UserId:   params.ByName("userId"),
+		Period:   query.Get("period"),
+		From:     query.Get("from"),
+		To:       query.Get("to"),
+		TimeZone: query.Get("tz"),
+	}
+	if balanceRequest.Period == "" {
+		balanceRequest.Period = "month"
+	}
+
+	balanceResponse, err := controller.ReportService.Balance(request.Context(), balanceRequest)
+	if err != nil {
+		exception.WriteError(writer, request, err)
+		return
+	}
+	webResponse := web.WebResponse{
+		Code:   http.StatusOK,
+		Status: "OK",
+		Data:   balanceResponse,
+	}
+	helper.WriteToResponseBody(writer, webResponse)
+}
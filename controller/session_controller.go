@@ -0,0 +1,12 @@
+package controller
+
+import (
+	"github.com/julienschmidt/httprouter"
+	"net/http"
+)
+
+type SessionController interface {
+	Delete(writer http.ResponseWriter, request *http.Request, params httprouter.Params)
+	DeleteByUserId(writer http.ResponseWriter, request *http.Request, params httprouter.Params)
+	FindByUserId(writer http.ResponseWriter, request *http.Request, params httprouter.Params)
+}
@@ -0,0 +1,63 @@
+package controller
+
+import (
+	"github.com/julienschmidt/httprouter"
+	"github.com/refandas/duit-api/exception"
+	"github.com/refandas/duit-api/helper"
+	"github.com/refandas/duit-api/model/web"
+	"github.com/refandas/duit-api/service"
+	"net/http"
+)
+
+type SessionControllerImpl struct {
+	SessionService service.SessionService
+}
+
+func NewSessionController(sessionService service.SessionService) SessionController {
+	return &SessionControllerImpl{SessionService: sessionService}
+}
+
+func (controller *SessionControllerImpl) Delete(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+	userId := params.ByName("userId")
+	sessionId := params.ByName("sessionId")
+
+	if err := controller.SessionService.Delete(request.Context(), userId, sessionId); err != nil {
+		exception.WriteError(writer, request, err)
+		return
+	}
+	webResponse := web.WebResponse{
+		Code:   http.StatusNoContent,
+		Status: "DELETED",
+	}
+	helper.WriteToResponseBody(writer, webResponse)
+}
+
+func (controller *SessionControllerImpl) DeleteByUserId(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+	userId := params.ByName("userId")
+
+	if err := controller.SessionService.DeleteByUserId(request.Context(), userId); err != nil {
+		exception.WriteError(writer, request, err)
+		return
+	}
+	webResponse := web.WebResponse{
+		Code:   http.StatusNoContent,
+		Status: "DELETED",
+	}
+	helper.WriteToResponseBody(writer, webResponse)
+}
+
+func (controller *SessionControllerImpl) FindByUserId(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+	userId := params.ByName("userId")
+
+	sessionResponses, err := controller.SessionService.FindByUserId(request.Context(), userId)
+	if err != nil {
+		exception.WriteError(writer, request, err)
+		return
+	}
+	webResponse := web.WebResponse{
+		Code:   http.StatusOK,
+		Status: "OK",
+		Data:   sessionResponses,
+	}
+	helper.WriteToResponseBody(writer, webResponse)
+}
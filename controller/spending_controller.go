@@ -11,4 +11,9 @@ type SpendingController interface {
 	Delete(writer http.ResponseWriter, request *http.Request, params httprouter.Params)
 	FindById(writer http.ResponseWriter, request *http.Request, params httprouter.Params)
 	FindByUserId(writer http.ResponseWriter, request *http.Request, params httprouter.Params)
+	FindUpcomingByUserId(writer http.ResponseWriter, request *http.Request, params httprouter.Params)
+	Export(writer http.ResponseWriter, request *http.Request, params httprouter.Params)
+	Import(writer http.ResponseWriter, request *http.Request, params httprouter.Params)
+	FindTags(writer http.ResponseWriter, request *http.Request, params httprouter.Params)
+	UpdateTags(writer http.ResponseWriter, request *http.Request, params httprouter.Params)
 }
@@ -3,9 +3,13 @@ package controller
 import (
 	"github.com/google/uuid"
 	"github.com/julienschmidt/httprouter"
+	"github.com/refandas/duit-api/exception"
 	"github.com/refandas/duit-api/helper"
+	"github.com/refandas/duit-api/model/domain"
 	"github.com/refandas/duit-api/model/web"
 	"github.com/refandas/duit-api/service"
+	"log"
+	"mime"
 	"net/http"
 	"time"
 )
@@ -20,13 +24,29 @@ func NewSpendingController(spendingService service.SpendingService) SpendingCont
 
 func (controller *SpendingControllerImpl) Create(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
 	spendingCreateRequest := web.SpendingCreateRequest{}
-	helper.ReadFromRequestBody(request, &spendingCreateRequest)
+	if err := helper.ReadFromRequestBody(request, &spendingCreateRequest); err != nil {
+		exception.WriteError(writer, request, exception.NewValidationError("invalid request body"))
+		return
+	}
+
+	// The spending always belongs to the authenticated user, regardless of
+	// the user id sent in the request body.
+	userId, ok := helper.UserIdFromContext(request.Context())
+	if !ok {
+		exception.WriteError(writer, request, exception.NewUnauthorizedError("missing access token"))
+		return
+	}
+	spendingCreateRequest.UserId = userId
 
 	spendingId, _ := uuid.NewRandom()
 	spendingCreateRequest.Id = spendingId.String()
 	spendingCreateRequest.CreatedAt = time.Now().UnixMilli()
 
-	spendingResponse := controller.SpendingService.Create(request.Context(), spendingCreateRequest)
+	spendingResponse, err := controller.SpendingService.Create(request.Context(), spendingCreateRequest)
+	if err != nil {
+		exception.WriteError(writer, request, err)
+		return
+	}
 	webResponse := web.WebResponse{
 		Code:   http.StatusCreated,
 		Status: "CREATED",
@@ -37,12 +57,19 @@ func (controller *SpendingControllerImpl) Create(writer http.ResponseWriter, req
 
 func (controller *SpendingControllerImpl) Update(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
 	spendingUpdateRequest := web.SpendingUpdateRequest{}
-	helper.ReadFromRequestBody(request, &spendingUpdateRequest)
+	if err := helper.ReadFromRequestBody(request, &spendingUpdateRequest); err != nil {
+		exception.WriteError(writer, request, exception.NewValidationError("invalid request body"))
+		return
+	}
 
 	spendingId := params.ByName("spendingId")
 	spendingUpdateRequest.Id = spendingId
 
-	spendingResponse := controller.SpendingService.Update(request.Context(), spendingUpdateRequest)
+	spendingResponse, err := controller.SpendingService.Update(request.Context(), spendingUpdateRequest)
+	if err != nil {
+		exception.WriteError(writer, request, err)
+		return
+	}
 	webResponse := web.WebResponse{
 		Code:   http.StatusOK,
 		Status: "OK",
@@ -54,7 +81,10 @@ func (controller *SpendingControllerImpl) Update(writer http.ResponseWriter, req
 func (controller *SpendingControllerImpl) Delete(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
 	spendingId := params.ByName("spendingId")
 
-	controller.SpendingService.Delete(request.Context(), spendingId)
+	if err := controller.SpendingService.Delete(request.Context(), spendingId); err != nil {
+		exception.WriteError(writer, request, err)
+		return
+	}
 	webResponse := web.WebResponse{
 		Code:   http.StatusNoContent,
 		Status: "DELETED",
@@ -65,7 +95,11 @@ func (controller *SpendingControllerImpl) Delete(writer http.ResponseWriter, req
 func (controller *SpendingControllerImpl) FindById(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
 	spendingId := params.ByName("spendingId")
 
-	spendingResponse := controller.SpendingService.FindById(request.Context(), spendingId)
+	spendingResponse, err := controller.SpendingService.FindById(request.Context(), spendingId)
+	if err != nil {
+		exception.WriteError(writer, request, err)
+		return
+	}
 	webResponse := web.WebResponse{
 		Code:   http.StatusOK,
 		Status: "OK",
@@ -75,13 +109,186 @@ func (controller *SpendingControllerImpl) FindById(writer http.ResponseWriter, r
 }
 
 func (controller *SpendingControllerImpl) FindByUserId(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+	spendingListRequest, err := toSpendingListRequest(request, params)
+	if err != nil {
+		exception.WriteError(writer, request, err)
+		return
+	}
+
+	spendingResponses, pagination, err := controller.SpendingService.FindByUserId(request.Context(), spendingListRequest)
+	if err != nil {
+		exception.WriteError(writer, request, err)
+		return
+	}
+	webResponse := web.WebResponse{
+		Code:       http.StatusOK,
+		Status:     "OK",
+		Data:       spendingResponses,
+		Pagination: &pagination,
+	}
+	helper.WriteToResponseBody(writer, webResponse)
+}
+
+func (controller *SpendingControllerImpl) FindUpcomingByUserId(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+	spendingListRequest, err := toSpendingListRequest(request, params)
+	if err != nil {
+		exception.WriteError(writer, request, err)
+		return
+	}
+	spendingListRequest.Status = domain.SpendingStatusPlanned
+
+	spendingResponses, pagination, err := controller.SpendingService.FindByUserId(request.Context(), spendingListRequest)
+	if err != nil {
+		exception.WriteError(writer, request, err)
+		return
+	}
+	webResponse := web.WebResponse{
+		Code:       http.StatusOK,
+		Status:     "OK",
+		Data:       spendingResponses,
+		Pagination: &pagination,
+	}
+	helper.WriteToResponseBody(writer, webResponse)
+}
+
+func (controller *SpendingControllerImpl) Export(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+	spendingListRequest, err := toSpendingListRequest(request, params)
+	if err != nil {
+		exception.WriteError(writer, request, err)
+		return
+	}
+	exportRequest := web.SpendingExportRequest{
+		SpendingListRequest: spendingListRequest,
+		Format:              request.URL.Query().Get("format"),
+		TimeZone:            request.URL.Query().Get("tz"),
+	}
+	if exportRequest.Format == "" {
+		exportRequest.Format = "csv"
+	}
+
+	export, err := controller.SpendingService.Export(request.Context(), exportRequest)
+	if err != nil {
+		exception.WriteError(writer, request, err)
+		return
+	}
+	writer.Header().Set("Content-Type", export.ContentType)
+	writer.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": export.FileName}))
+	if err := export.Write(request.Context(), writer); err != nil {
+		// The file has been partly responded, so the error can not be.
+		log.Printf("%s %s: %v", request.Method, request.URL.Path, err)
+	}
+}
+
+func (controller *SpendingControllerImpl) Import(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+	query := request.URL.Query()
+	importRequest := web.SpendingImportRequest{
+		UserId:            params.ByName("userId"),
+		Delimiter:         query.Get("delimiter"),
+		DecimalSeparator:  query.Get("decimal_separator"),
+		DateFormat:        query.Get("date_format"),
+		TitleColumn:       query.Get("title_column"),
+		AmountColumn:      query.Get("amount_column"),
+		CurrencyColumn:    query.Get("currency_column"),
+		DateColumn:        query.Get("date_column"),
+		CategoryColumn:    query.Get("category_column"),
+		DescriptionColumn: query.Get("description_column"),
+	}
+	var err error
+	if importRequest.DryRun, err = queryBool(query, "dry_run"); err != nil {
+		exception.WriteError(writer, request, err)
+		return
+	}
+
+	importResponse, err := controller.SpendingService.Import(request.Context(), importRequest, request.Body)
+	if err != nil {
+		exception.WriteError(writer, request, err)
+		return
+	}
+	webResponse := web.WebResponse{
+		Code:   http.StatusCreated,
+		Status: "CREATED",
+		Data:   importResponse,
+	}
+	if importRequest.DryRun {
+		webResponse.Code = http.StatusOK
+		webResponse.Status = "OK"
+	}
+	helper.WriteToResponseBody(writer, webResponse)
+}
+
+func (controller *SpendingControllerImpl) FindTags(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
 	userId := params.ByName("userId")
 
-	spendingResponse := controller.SpendingService.FindByUserId(request.Context(), userId)
+	tagResponses, err := controller.SpendingService.FindTags(request.Context(), userId)
+	if err != nil {
+		exception.WriteError(writer, request, err)
+		return
+	}
 	webResponse := web.WebResponse{
 		Code:   http.StatusOK,
 		Status: "OK",
-		Data:   spendingResponse,
+		Data:   tagResponses,
 	}
 	helper.WriteToResponseBody(writer, webResponse)
 }
+
+func (controller *SpendingControllerImpl) UpdateTags(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+	tagsRequest := web.SpendingTagsRequest{}
+	if err := helper.ReadFromRequestBody(request, &tagsRequest); err != nil {
+		exception.WriteError(writer, request, exception.NewValidationError("invalid request body"))
+		return
+	}
+	tagsRequest.UserId = params.ByName("userId")
+
+	tagsResponse, err := controller.SpendingService.UpdateTags(request.Context(), tagsRequest)
+	if err != nil {
+		exception.WriteError(writer, request, err)
+		return
+	}
+	webResponse := web.WebResponse{
+		Code:   http.StatusOK,
+		Status: "OK",
+		Data:   tagsResponse,
+	}
+	helper.WriteToResponseBody(writer, webResponse)
+}
+
+// toSpendingListRequest reads the user id from the route and the listing
+// criteria from the query parameters of the request.
+func toSpendingListRequest(request *http.Request, params httprouter.Params) (web.SpendingListRequest, error) {
+	query := request.URL.Query()
+	listRequest := web.SpendingListRequest{
+		UserId:    params.ByName("userId"),
+		Cursor:    query.Get("cursor"),
+		Category:  query.Get("category"),
+		Type:      query.Get("type"),
+		AccountId: query.Get("account_id"),
+		Tags:      queryList(query, "tags"),
+		TagMatch:  query.Get("tag_match"),
+		Query:     query.Get("q"),
+		Order:     query.Get("order"),
+		Status:    query.Get("status"),
+		Currency:  query.Get("currency"),
+	}
+
+	var err error
+	if listRequest.Limit, err = queryInt(query, "limit", defaultPageLimit); err != nil {
+		return web.SpendingListRequest{}, err
+	}
+	if listRequest.From, err = queryTime(query, "from", false); err != nil {
+		return web.SpendingListRequest{}, err
+	}
+	if listRequest.To, err = queryTime(query, "to", true); err != nil {
+		return web.SpendingListRequest{}, err
+	}
+	if listRequest.MinAmount, err = queryFloat(query, "min_amount"); err != nil {
+		return web.SpendingListRequest{}, err
+	}
+	if listRequest.MaxAmount, err = queryFloat(query, "max_amount"); err != nil {
+		return web.SpendingListRequest{}, err
+	}
+	if listRequest.IncludeFuture, err = queryBool(query, "include_future"); err != nil {
+		return web.SpendingListRequest{}, err
+	}
+	return listRequest, nil
+}
@@ -3,6 +3,7 @@ package controller
 import (
 	"github.com/google/uuid"
 	"github.com/julienschmidt/httprouter"
+	"github.com/refandas/duit-api/exception"
 	"github.com/refandas/duit-api/helper"
 	"github.com/refandas/duit-api/model/web"
 	"github.com/refandas/duit-api/service"
@@ -21,23 +22,32 @@ func NewUserController(userService service.UserService) UserController {
 
 func (controller *UserControllerImpl) Create(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
 	userCreateRequest := web.UserCreateRequest{}
-	helper.ReadFromRequestBody(request, &userCreateRequest)
+	if err := helper.ReadFromRequestBody(request, &userCreateRequest); err != nil {
+		exception.WriteError(writer, request, exception.NewValidationError("invalid request body"))
+		return
+	}
 
 	userId, err := uuid.NewRandom()
 	if err != nil {
-		panic(err)
+		exception.WriteError(writer, request, err)
+		return
 	}
 	userCreateRequest.Id = userId.String()
 
 	password := []byte(userCreateRequest.Password)
 	hashedPassword, err := bcrypt.GenerateFromPassword(password, bcrypt.DefaultCost)
 	if err != nil {
-		panic(err)
+		exception.WriteError(writer, request, err)
+		return
 	}
 	userCreateRequest.Password = string(hashedPassword)
 	userCreateRequest.CreatedAt = time.Now().UnixMilli()
 
-	userResponse := controller.UserService.Create(request.Context(), userCreateRequest)
+	userResponse, err := controller.UserService.Create(request.Context(), userCreateRequest)
+	if err != nil {
+		exception.WriteError(writer, request, err)
+		return
+	}
 	webResponse := web.WebResponse{
 		Code:   http.StatusCreated,
 		Status: "CREATED",
@@ -48,19 +58,30 @@ func (controller *UserControllerImpl) Create(writer http.ResponseWriter, request
 
 func (controller *UserControllerImpl) Update(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
 	userUpdateRequest := web.UserUpdateRequest{}
-	helper.ReadFromRequestBody(request, &userUpdateRequest)
+	if err := helper.ReadFromRequestBody(request, &userUpdateRequest); err != nil {
+		exception.WriteError(writer, request, exception.NewValidationError("invalid request body"))
+		return
+	}
 
 	userId := params.ByName("userId")
 	userUpdateRequest.Id = userId
 
-	password := []byte(userUpdateRequest.Password)
-	hashedPassword, err := bcrypt.GenerateFromPassword(password, bcrypt.DefaultCost)
-	if err != nil {
-		panic(err)
+	// The password is only changed when the request has one, so the hash
+	// of an empty password never replaces the stored one.
+	if userUpdateRequest.Password != "" {
+		hashedPassword, err := bcrypt.GenerateFromPassword([]byte(userUpdateRequest.Password), bcrypt.DefaultCost)
+		if err != nil {
+			exception.WriteError(writer, request, err)
+			return
+		}
+		userUpdateRequest.Password = string(hashedPassword)
 	}
-	userUpdateRequest.Password = string(hashedPassword)
 
-	userResponse := controller.UserService.Update(request.Context(), userUpdateRequest)
+	userResponse, err := controller.UserService.Update(request.Context(), userUpdateRequest)
+	if err != nil {
+		exception.WriteError(writer, request, err)
+		return
+	}
 	webResponse := web.WebResponse{
 		Code:   http.StatusOK,
 		Status: "OK",
@@ -72,7 +93,10 @@ func (controller *UserControllerImpl) Update(writer http.ResponseWriter, request
 func (controller *UserControllerImpl) Delete(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
 	userId := params.ByName("userId")
 
-	controller.UserService.Delete(request.Context(), userId)
+	if err := controller.UserService.Delete(request.Context(), userId); err != nil {
+		exception.WriteError(writer, request, err)
+		return
+	}
 	webResponse := web.WebResponse{
 		Code:   http.StatusNoContent,
 		Status: "DELETED",
@@ -83,7 +107,11 @@ func (controller *UserControllerImpl) Delete(writer http.ResponseWriter, request
 func (controller *UserControllerImpl) FindById(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
 	userId := params.ByName("userId")
 
-	userResponse := controller.UserService.FindById(request.Context(), userId)
+	userResponse, err := controller.UserService.FindById(request.Context(), userId)
+	if err != nil {
+		exception.WriteError(writer, request, err)
+		return
+	}
 	webResponse := web.WebResponse{
 		Code:   http.StatusOK,
 		Status: "OK",
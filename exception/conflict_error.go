@@ -0,0 +1,5 @@
+package exception
+
+func NewConflictError(message string) error {
+	return &Error{Kind: ErrConflict, Message: message}
+}
@@ -0,0 +1,63 @@
+package exception
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+)
+
+// The kinds of errors returned by the repositories and the services. An
+// error of a kind matches the sentinel error of the kind with errors.Is, and
+// WriteError responds to each kind with its own status code.
+var (
+	ErrNotFound     = errors.New("not found")
+	ErrConflict     = errors.New("conflict")
+	ErrValidation   = errors.New("validation failed")
+	ErrUnauthorized = errors.New("unauthorized")
+	ErrForbidden    = errors.New("forbidden")
+	ErrUnavailable  = errors.New("unavailable")
+
+	ErrTooManyRequests = errors.New("too many requests")
+)
+
+// Error represents an error of a known kind, carrying the message shown to
+// the client.
+type Error struct {
+
+	// Kind represents the sentinel error of the kind of the error, such as
+	// ErrNotFound.
+	Kind error
+
+	// Message represents the description of the error shown to the client,
+	// in English. It is also the key of its translations, so the values
+	// varying between errors are left out as the {0}, {1}, ... placeholders
+	// of the Params.
+	Message string
+
+	// Params represents the values of the placeholders of the Message.
+	Params []string
+
+	// Err represents the error causing the error, if any. It is logged but
+	// never shown to the client.
+	Err error
+}
+
+func (err *Error) Error() string {
+	return formatMessage(err.Message, err.Params)
+}
+
+func (err *Error) Unwrap() []error {
+	if err.Err != nil {
+		return []error{err.Kind, err.Err}
+	}
+	return []error{err.Kind}
+}
+
+// formatMessage replaces the {0}, {1}, ... placeholders of the message with
+// the given params.
+func formatMessage(message string, params []string) string {
+	for i, param := range params {
+		message = strings.ReplaceAll(message, "{"+strconv.Itoa(i)+"}", param)
+	}
+	return message
+}
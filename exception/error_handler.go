@@ -1,67 +1,98 @@
 package exception
 
 import (
-	"github.com/go-playground/validator/v10"
+	"errors"
 	"github.com/refandas/duit-api/helper"
 	"github.com/refandas/duit-api/model/web"
+	"log"
 	"net/http"
+	"runtime/debug"
 )
 
-func ErrorHandler(writer http.ResponseWriter, request *http.Request, err interface{}) {
-	if notFoundError(writer, request, err) {
-		return
-	}
+// problemType is the type of every problem. The problems are distinguished
+// by their status and code, so no further documentation is linked.
+const problemType = "about:blank"
 
-	if validationError(writer, request, err) {
-		return
-	}
+// problemKind is how an error of a kind is responded.
+type problemKind struct {
+	kind   error
+	status int
+	code   string
+}
 
-	internalServerError(writer, request, err)
+// problemKinds lists the kinds of errors with the status code and the
+// stable machine-readable code of their response.
+var problemKinds = []problemKind{
+	{kind: ErrValidation, status: http.StatusBadRequest, code: "validation_failed"},
+	{kind: ErrUnauthorized, status: http.StatusUnauthorized, code: "unauthorized"},
+	{kind: ErrForbidden, status: http.StatusForbidden, code: "forbidden"},
+	{kind: ErrNotFound, status: http.StatusNotFound, code: "not_found"},
+	{kind: ErrConflict, status: http.StatusConflict, code: "conflict"},
+	{kind: ErrTooManyRequests, status: http.StatusTooManyRequests, code: "too_many_requests"},
+	{kind: ErrUnavailable, status: http.StatusServiceUnavailable, code: "service_unavailable"},
 }
 
-func validationError(writer http.ResponseWriter, request *http.Request, err interface{}) bool {
-	if exception, ok := err.(validator.ValidationErrors); ok {
-		writer.Header().Set("Content-Type", "application/json")
-		writer.WriteHeader(http.StatusBadRequest)
+// WriteError writes the response of an error returned by a service as an
+// RFC 7807 problem. Each kind of error is responded with its own status
+// code, code and the message of the error as the detail, translated to the
+// language chosen for the request. Any other error is logged and responded
+// with an internal server error, without revealing its details to the
+// client.
+func WriteError(writer http.ResponseWriter, request *http.Request, err error) {
+	var apiError *Error
+	if errors.As(err, &apiError) {
+		for _, problemKind := range problemKinds {
+			if !errors.Is(err, problemKind.kind) {
+				continue
+			}
+			if problemKind.kind == ErrUnavailable {
+				log.Printf("%s %s: %v", request.Method, request.URL.Path, apiError.Err)
+			}
 
-		webResponse := web.WebResponse{
-			Code:   http.StatusBadRequest,
-			Status: "BAD REQUEST",
-			Data:   exception.Error(),
+			translator, _ := helper.TranslatorFromContext(request.Context())
+			problem := newProblem(writer, request, problemKind.status, problemKind.code,
+				translate(translator, apiError.Message, apiError.Params...))
+			problem.Errors = fieldErrors(apiError.Err, translator)
+			helper.WriteProblemToResponseBody(writer, problem.Status, problem)
+			return
 		}
-
-		helper.WriteToResponseBody(writer, webResponse)
-		return true
 	}
-	return false
-}
-
-func notFoundError(writer http.ResponseWriter, request *http.Request, err interface{}) bool {
-	if exception, ok := err.(NotFoundError); ok {
-		writer.Header().Set("Content-Type", "application/json")
-		writer.WriteHeader(http.StatusNotFound)
 
-		webResponse := web.WebResponse{
-			Code:   http.StatusNotFound,
-			Status: "NOT FOUND",
-			Data:   exception.Error,
-		}
+	log.Printf("%s %s: %v", request.Method, request.URL.Path, err)
+	internalServerError(writer, request)
+}
 
-		helper.WriteToResponseBody(writer, webResponse)
-		return true
-	}
-	return false
+// ErrorHandler recovers from a panic while serving a request. Errors are
+// returned rather than panicked, so a panic is a bug: it is logged with the
+// stack trace and responded with an internal server error, without
+// revealing its details to the client.
+func ErrorHandler(writer http.ResponseWriter, request *http.Request, err interface{}) {
+	log.Printf("panic serving %s %s: %v\n%s", request.Method, request.URL.Path, err, debug.Stack())
+	internalServerError(writer, request)
 }
 
-func internalServerError(writer http.ResponseWriter, request *http.Request, err interface{}) {
-	writer.Header().Set("Content-Type", "application/json")
-	writer.WriteHeader(http.StatusInternalServerError)
+func internalServerError(writer http.ResponseWriter, request *http.Request) {
+	translator, _ := helper.TranslatorFromContext(request.Context())
+	problem := newProblem(writer, request, http.StatusInternalServerError, "internal_error",
+		translate(translator, "internal server error"))
+	helper.WriteProblemToResponseBody(writer, problem.Status, problem)
+}
 
-	webResponse := web.WebResponse{
-		Code:   http.StatusInternalServerError,
-		Status: "INTERNAL SERVER ERROR",
-		Data:   err,
+// newProblem returns the problem with the given status, code and detail.
+// The title is translated to the language chosen for the request, which is
+// announced by the Content-Language header of the response.
+func newProblem(writer http.ResponseWriter, request *http.Request, status int, code string, detail string) web.ProblemDetails {
+	translator, ok := helper.TranslatorFromContext(request.Context())
+	if ok {
+		writer.Header().Set("Content-Language", translator.Locale())
 	}
 
-	helper.WriteToResponseBody(writer, webResponse)
+	return web.ProblemDetails{
+		Type:     problemType,
+		Title:    translate(translator, http.StatusText(status)),
+		Status:   status,
+		Detail:   detail,
+		Instance: request.URL.Path,
+		Code:     code,
+	}
 }
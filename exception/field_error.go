@@ -0,0 +1,63 @@
+package exception
+
+import (
+	"context"
+	"errors"
+	ut "github.com/go-playground/universal-translator"
+	"github.com/go-playground/validator/v10"
+	"github.com/refandas/duit-api/helper"
+	"github.com/refandas/duit-api/model/web"
+)
+
+// fieldErrors describes each field rejected by the validator in the given
+// error, or returns nil when the error does not come from the validator.
+// The fields are named by the tag name function of the validator, which is
+// the JSON name of the field, and described in the language of the given
+// translator.
+func fieldErrors(err error, translator ut.Translator) []web.FieldErrorResponse {
+	var validationErrors validator.ValidationErrors
+	if !errors.As(err, &validationErrors) {
+		return nil
+	}
+
+	responses := make([]web.FieldErrorResponse, 0, len(validationErrors))
+	for _, fieldError := range validationErrors {
+		responses = append(responses, web.FieldErrorResponse{
+			Field:   fieldError.Field(),
+			Code:    fieldError.Tag(),
+			Message: fieldErrorMessage(fieldError, translator),
+		})
+	}
+	return responses
+}
+
+// fieldErrorMessage returns a human readable description of why the field
+// is rejected, translated by the translations registered in the validator.
+func fieldErrorMessage(fieldError validator.FieldError, translator ut.Translator) string {
+	if translator != nil {
+		// A rule without a registered translation is translated to the
+		// error of the validator, which is not meant for the client.
+		if message := fieldError.Translate(translator); message != fieldError.Error() {
+			return message
+		}
+	}
+	return translate(translator, "{0} is invalid", fieldError.Field())
+}
+
+// FieldErrors describes why the given validation error rejects a value, in
+// the language chosen for the request of the context, to report the errors
+// of an item among many. An error of the validator describes each rejected
+// field, any other error describes the given field with its message.
+func FieldErrors(ctx context.Context, field string, err error) []web.FieldErrorResponse {
+	translator, _ := helper.TranslatorFromContext(ctx)
+	if responses := fieldErrors(err, translator); responses != nil {
+		return responses
+	}
+
+	message := err.Error()
+	var apiError *Error
+	if errors.As(err, &apiError) {
+		message = translate(translator, apiError.Message, apiError.Params...)
+	}
+	return []web.FieldErrorResponse{{Field: field, Code: "invalid", Message: message}}
+}
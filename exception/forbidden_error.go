@@ -0,0 +1,5 @@
+package exception
+
+func NewForbiddenError(message string) error {
+	return &Error{Kind: ErrForbidden, Message: message}
+}
@@ -1,9 +1,5 @@
 package exception
 
-type NotFoundError struct {
-	Error string
-}
-
-func NewNotFoundError(error string) NotFoundError {
-	return NotFoundError{Error: error}
+func NewNotFoundError(message string) error {
+	return &Error{Kind: ErrNotFound, Message: message}
 }
@@ -0,0 +1,5 @@
+package exception
+
+func NewTooManyRequestsError(message string) error {
+	return &Error{Kind: ErrTooManyRequests, Message: message}
+}
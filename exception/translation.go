@@ -0,0 +1,106 @@
+package exception
+
+import (
+	ut "github.com/go-playground/universal-translator"
+)
+
+// indonesianMessages translates the messages of the error responses to
+// Indonesian, keyed by their English message.
+var indonesianMessages = map[string]string{
+	// The titles of the problems.
+	"Bad Request":           "Permintaan Tidak Valid",
+	"Unauthorized":          "Tidak Terautentikasi",
+	"Forbidden":             "Dilarang",
+	"Not Found":             "Tidak Ditemukan",
+	"Conflict":              "Konflik",
+	"Too Many Requests":     "Terlalu Banyak Permintaan",
+	"Internal Server Error": "Kesalahan Server Internal",
+	"Service Unavailable":   "Layanan Tidak Tersedia",
+
+	// The details of the problems.
+	"a report must not have more than {0} periods":     "laporan tidak boleh memiliki lebih dari {0} periode",
+	"a spending must not have more than {0} tags":      "pengeluaran tidak boleh memiliki lebih dari {0} tag",
+	"access to the resource is forbidden":              "akses ke sumber daya ini dilarang",
+	"account is used by spendings or transfers":        "akun digunakan oleh pengeluaran atau transfer",
+	"account not found":                                "akun tidak ditemukan",
+	"add or remove must have a tag":                    "add atau remove harus memiliki tag",
+	"attachment not found":                             "lampiran tidak ditemukan",
+	"budget for the category already exists":           "anggaran untuk kategori tersebut sudah ada",
+	"budget not found":                                 "anggaran tidak ditemukan",
+	"by_day is only allowed with the weekly frequency": "by_day hanya boleh digunakan dengan frekuensi weekly",
+	"category already exists":                          "kategori sudah ada",
+	"category can not be a subcategory of itself":      "kategori tidak dapat menjadi subkategori dari dirinya sendiri",
+	"category has subcategories":                       "kategori memiliki subkategori",
+	"category is used by recurring spendings, merge it into another category instead": "kategori digunakan oleh pengeluaran berulang, gabungkan ke kategori lain sebagai gantinya",
+	"category is used by spendings, merge it into another category instead":           "kategori digunakan oleh pengeluaran, gabungkan ke kategori lain sebagai gantinya",
+	"category not found": "kategori tidak ditemukan",
+	"currency must be the currency of the account {0}":               "mata uang harus sama dengan mata uang akun {0}",
+	"email is already registered":                                    "email sudah terdaftar",
+	"file is required":                                               "file wajib diisi",
+	"file must be a JPEG, PNG, WebP or HEIC image or a PDF document": "file harus berupa gambar JPEG, PNG, WebP atau HEIC atau dokumen PDF",
+	"file must not be empty":                                         "file tidak boleh kosong",
+	"file must not be larger than {0} bytes":                         "file tidak boleh lebih besar dari {0} byte",
+	"from must not be after to":                                      "from tidak boleh setelah to",
+	"internal server error":                                          "terjadi kesalahan pada server",
+	"invalid access token":                                           "access token tidak valid",
+	"invalid cursor":                                                 "cursor tidak valid",
+	"invalid email or password":                                      "email atau kata sandi salah",
+	"invalid refresh token":                                          "refresh token tidak valid",
+	"invalid request body":                                           "isi permintaan tidak valid",
+	"invalid time zone":                                              "zona waktu tidak valid",
+	"item not found":                                                 "item tidak ditemukan",
+	"line {0} of the CSV file is invalid":                            "baris {0} pada berkas CSV tidak valid",
+	"min_amount must not be greater than max_amount":                 "min_amount tidak boleh lebih besar dari max_amount",
+	"missing access token":                                           "access token tidak ada",
+	"only administrators can change the exchange rates":              "hanya administrator yang dapat mengubah kurs",
+	"parent category not found":                                      "kategori induk tidak ditemukan",
+	"received_amount is required between accounts of different currencies": "received_amount wajib diisi untuk transfer antar akun dengan mata uang berbeda",
+	"recurring spending not found":                                         "pengeluaran berulang tidak ditemukan",
+	"refresh token has already been used":                                  "refresh token sudah pernah digunakan",
+	"refresh token is expired or revoked":                                  "refresh token sudah kedaluwarsa atau dicabut",
+	"service is temporarily unavailable":                                   "layanan sedang tidak tersedia untuk sementara",
+	"session not found":                                                    "sesi tidak ditemukan",
+	"the CSV file must have a header with the columns {0}":                 "berkas CSV harus memiliki header dengan kolom {0}",
+	"the CSV file must not have more than {0} rows":                        "berkas CSV tidak boleh memiliki lebih dari {0} baris",
+	"the request has invalid fields":                                       "permintaan memiliki field yang tidak valid",
+	"the user was changed meanwhile, try again":                            "pengguna telah diubah sementara itu, coba lagi",
+	"too many requests":                                                    "terlalu banyak permintaan",
+	"transfer not found":                                                   "transfer tidak ditemukan",
+	"until and count must not both be set":                                 "until dan count tidak boleh diisi bersamaan",
+	"user not found":                                                       "pengguna tidak ditemukan",
+	"{0} is invalid":                                                       "{0} tidak valid",
+	"{0} must be a boolean":                                                "{0} harus berupa boolean",
+	"{0} must be a date":                                                   "{0} harus berupa tanggal",
+	"{0} must be a number":                                                 "{0} harus berupa angka",
+	"{0} must be a positive amount with at most {1} decimal places":        "{0} harus berupa jumlah positif dengan paling banyak {1} angka desimal",
+	"{0} must be a positive decimal number":                                "{0} harus berupa bilangan desimal positif",
+	"{0} must be an amount with at most {1} decimal places":                "{0} harus berupa jumlah dengan paling banyak {1} angka desimal",
+	"{0} must be an integer":                                               "{0} harus berupa bilangan bulat",
+}
+
+// RegisterTranslations registers the translations of the messages of the
+// error responses into the translators of the supported languages. English
+// messages are not translated.
+func RegisterTranslations(universal *ut.UniversalTranslator) error {
+	translator, found := universal.GetTranslator("id")
+	if !found {
+		return nil
+	}
+	for message, translation := range indonesianMessages {
+		if err := translator.Add(message, translation, false); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// translate returns the message translated by the given translator, or the
+// English message when the translator is nil or does not know the message.
+func translate(translator ut.Translator, message string, params ...string) string {
+	if translator != nil {
+		if translation, err := translator.T(message, params...); err == nil {
+			return translation
+		}
+	}
+	return formatMessage(message, params)
+}
@@ -0,0 +1,5 @@
+package exception
+
+func NewUnauthorizedError(message string) error {
+	return &Error{Kind: ErrUnauthorized, Message: message}
+}
@@ -0,0 +1,8 @@
+package exception
+
+// NewUnavailableError returns the error of a storage failing with the given
+// error, such as a database that can not be reached. The cause is hidden
+// from the client, who may try again later.
+func NewUnavailableError(err error) error {
+	return &Error{Kind: ErrUnavailable, Message: "service is temporarily unavailable", Err: err}
+}
@@ -0,0 +1,14 @@
+package exception
+
+// NewValidationError returns the validation error with the given message,
+// whose {0}, {1}, ... placeholders are replaced with the given params.
+func NewValidationError(message string, params ...string) error {
+	return &Error{Kind: ErrValidation, Message: message, Params: params}
+}
+
+// NewValidationErrors returns the validation error of a request whose
+// fields are rejected by the validator, described by the given error of the
+// validator. Each rejected field is listed in the `errors` of the response.
+func NewValidationErrors(err error) error {
+	return &Error{Kind: ErrValidation, Message: "the request has invalid fields", Err: err}
+}
@@ -0,0 +1,75 @@
+package helper
+
+import (
+	"context"
+	ut "github.com/go-playground/universal-translator"
+)
+
+// contextKey is the type of the keys used to store values in a request
+// context, preventing collisions with keys defined in other packages.
+type contextKey string
+
+const (
+	userIdContextKey     contextKey = "userId"
+	translatorContextKey contextKey = "translator"
+	adminContextKey      contextKey = "admin"
+)
+
+// ContextWithUserId returns a copy of the provided context that carries the
+// id of the authenticated user.
+func ContextWithUserId(ctx context.Context, userId string) context.Context {
+	return context.WithValue(ctx, userIdContextKey, userId)
+}
+
+// UserIdFromContext returns the id of the authenticated user stored in the
+// provided context, and whether the context carries one.
+func UserIdFromContext(ctx context.Context) (string, bool) {
+	userId, ok := ctx.Value(userIdContextKey).(string)
+	return userId, ok && userId != ""
+}
+
+// ContextWithAdmin returns a copy of the provided context whose caller is an
+// administrator without being a user, such as an admin command run on the
+// server.
+func ContextWithAdmin(ctx context.Context) context.Context {
+	return context.WithValue(ctx, adminContextKey, true)
+}
+
+// IsAdminContext reports whether the caller of the provided context is an
+// administrator by ContextWithAdmin.
+func IsAdminContext(ctx context.Context) bool {
+	admin, _ := ctx.Value(adminContextKey).(bool)
+	return admin
+}
+
+// ContextWithTranslator returns a copy of the provided context that carries
+// the translator of the language chosen for the request.
+func ContextWithTranslator(ctx context.Context, translator ut.Translator) context.Context {
+	return context.WithValue(ctx, translatorContextKey, translator)
+}
+
+// TranslatorResolver resolves the translator of the language chosen for a
+// request from the context of the request, which may carry the
+// authenticated user by then.
+type TranslatorResolver func(ctx context.Context) ut.Translator
+
+// ContextWithTranslatorResolver returns a copy of the provided context that
+// carries the resolver of the translator of the language chosen for the
+// request, for a language that is only known once the request is
+// authenticated.
+func ContextWithTranslatorResolver(ctx context.Context, resolve TranslatorResolver) context.Context {
+	return context.WithValue(ctx, translatorContextKey, resolve)
+}
+
+// TranslatorFromContext returns the translator of the language chosen for
+// the request stored in the provided context, and whether the context
+// carries one.
+func TranslatorFromContext(ctx context.Context) (ut.Translator, bool) {
+	switch translator := ctx.Value(translatorContextKey).(type) {
+	case ut.Translator:
+		return translator, true
+	case TranslatorResolver:
+		return translator(ctx), true
+	}
+	return nil, false
+}
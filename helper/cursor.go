@@ -0,0 +1,75 @@
+package helper
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+)
+
+// ErrInvalidCursor is returned by DecodeCursor when a cursor is malformed
+// or has been tampered with.
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// EncodeCursor encodes the given value, usually the key of the last item of
+// a page, into an opaque pagination cursor. The value is encrypted and
+// authenticated with AES-GCM using a key derived from the secret, so clients
+// can neither read nor forge a cursor.
+func EncodeCursor(secret []byte, value interface{}) string {
+	plaintext, err := json.Marshal(value)
+	if err != nil {
+		panic(err)
+	}
+
+	gcm := newCursorCipher(secret)
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		panic(err)
+	}
+
+	ciphertext := gcm.Seal(nonce, nonce, plaintext, nil)
+	return base64.RawURLEncoding.EncodeToString(ciphertext)
+}
+
+// DecodeCursor decodes a pagination cursor created by EncodeCursor with the
+// same secret into the value pointed to by the given pointer.
+func DecodeCursor(secret []byte, cursor string, value interface{}) error {
+	ciphertext, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return ErrInvalidCursor
+	}
+
+	gcm := newCursorCipher(secret)
+	if len(ciphertext) < gcm.NonceSize() {
+		return ErrInvalidCursor
+	}
+
+	nonce, ciphertext := ciphertext[:gcm.NonceSize()], ciphertext[gcm.NonceSize():]
+	plaintext, err := gcm.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return ErrInvalidCursor
+	}
+
+	if err := json.Unmarshal(plaintext, value); err != nil {
+		return ErrInvalidCursor
+	}
+	return nil
+}
+
+// newCursorCipher creates the AES-GCM cipher used for the cursors, keyed by
+// the SHA-256 hash of the secret.
+func newCursorCipher(secret []byte) cipher.AEAD {
+	key := sha256.Sum256(secret)
+	block, err := aes.NewCipher(key[:])
+	if err != nil {
+		panic(err)
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		panic(err)
+	}
+	return gcm
+}
@@ -9,12 +9,11 @@ import (
 // request's body and decode it into the specified result interface{}.
 //
 // The function uses the json.NewDecoder from the encoding/json package
-// to decode the JSON data.
-func ReadFromRequestBody(request *http.Request, result interface{}) {
+// to decode the JSON data, and returns the error of the decoder when the
+// body is not valid JSON for the result.
+func ReadFromRequestBody(request *http.Request, result interface{}) error {
 	decoder := json.NewDecoder(request.Body)
-	if err := decoder.Decode(result); err != nil {
-		panic(err)
-	}
+	return decoder.Decode(result)
 }
 
 // WriteToResponseBody writes the provided response data to the HTTP
@@ -31,3 +30,16 @@ func WriteToResponseBody(writer http.ResponseWriter, response interface{}) {
 		panic(err)
 	}
 }
+
+// WriteProblemToResponseBody writes the provided problem details to the HTTP
+// response writer in JSON format with the given status code. It sets the
+// "Content-Type" header to "application/problem+json".
+func WriteProblemToResponseBody(writer http.ResponseWriter, status int, problem interface{}) {
+	SetupSecurityHeaders(writer)
+	writer.Header().Set("Content-Type", "application/problem+json")
+	writer.WriteHeader(status)
+	encoder := json.NewEncoder(writer)
+	if err := encoder.Encode(problem); err != nil {
+		panic(err)
+	}
+}
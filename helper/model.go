@@ -1,8 +1,10 @@
 package helper
 
 import (
+	"encoding/json"
 	"github.com/refandas/duit-api/model/domain"
 	"github.com/refandas/duit-api/model/web"
+	"time"
 )
 
 // ToUserResponse converts a domain.User struct to a web.UserResponse struct.
@@ -12,10 +14,13 @@ import (
 // back as a response in API endpoints.
 func ToUserResponse(user domain.User) web.UserResponse {
 	return web.UserResponse{
-		Id:        user.Id,
-		Name:      user.Name,
-		Email:     user.Email,
-		CreatedAt: user.CreatedAt,
+		Id:           user.Id,
+		Name:         user.Name,
+		Email:        user.Email,
+		TimeZone:     user.TimeZone,
+		Language:     user.Language,
+		HomeCurrency: user.Currency(),
+		CreatedAt:    user.CreatedAt,
 	}
 }
 
@@ -40,13 +45,23 @@ func ToUserResponses(users []domain.User) []web.UserResponse {
 // retrieved from process the data on the database into a format suitable for
 // sending back as a response in API endpoints.
 func ToSpendingResponse(spending domain.Spending) web.SpendingResponse {
+	// The tags are responded as an empty list rather than null.
+	tags := spending.Tags
+	if tags == nil {
+		tags = []string{}
+	}
 	return web.SpendingResponse{
 		Id:          spending.Id,
 		UserId:      spending.UserId,
 		Title:       spending.Title,
-		Amount:      spending.Amount,
+		Amount:      json.Number(spending.Amount.String()),
+		Currency:    spending.Amount.Currency,
 		Description: spending.Description,
 		Category:    spending.Category,
+		Type:        spending.Kind(),
+		AccountId:   spending.AccountId,
+		Tags:        tags,
+		Status:      spending.Status(time.Now().UnixMilli()),
 		Date:        spending.Date,
 		CreatedAt:   spending.CreatedAt,
 	}
@@ -59,9 +74,203 @@ func ToSpendingResponse(spending domain.Spending) web.SpendingResponse {
 // retrieved from process the data on the database into a format suitable for
 // sending back as a response in API endpoints.
 func ToSpendingResponses(spendings []domain.Spending) []web.SpendingResponse {
-	var spendingResponses []web.SpendingResponse
+	spendingResponses := make([]web.SpendingResponse, 0, len(spendings))
 	for _, spending := range spendings {
 		spendingResponses = append(spendingResponses, ToSpendingResponse(spending))
 	}
 	return spendingResponses
 }
+
+// ToSessionResponse converts a domain.Session struct to a
+// web.SessionResponse struct.
+//
+// The session is presented as the device it was issued to, so the id of
+// the response is the id of the session family, which does not change
+// when the refresh token is rotated.
+func ToSessionResponse(session domain.Session) web.SessionResponse {
+	return web.SessionResponse{
+		Id:         session.FamilyId,
+		UserAgent:  session.UserAgent,
+		CreatedAt:  session.StartedAt,
+		LastUsedAt: session.CreatedAt,
+		ExpiresAt:  session.ExpiresAt,
+	}
+}
+
+// ToSessionResponses converts a slice of domain.Session struct to a
+// slice of web.SessionResponse struct.
+func ToSessionResponses(sessions []domain.Session) []web.SessionResponse {
+	var sessionResponses []web.SessionResponse
+	for _, session := range sessions {
+		sessionResponses = append(sessionResponses, ToSessionResponse(session))
+	}
+	return sessionResponses
+}
+
+// ToBudgetResponse converts a domain.Budget struct to a web.BudgetResponse
+// struct.
+func ToBudgetResponse(budget domain.Budget) web.BudgetResponse {
+	return web.BudgetResponse{
+		Id:        budget.Id,
+		UserId:    budget.UserId,
+		Category:  budget.Category,
+		Amount:    json.Number(budget.Amount.String()),
+		Currency:  budget.Amount.Currency,
+		Period:    budget.Period,
+		CreatedAt: budget.CreatedAt,
+	}
+}
+
+// ToBudgetResponses converts a slice of domain.Budget struct to a slice of
+// web.BudgetResponse struct.
+func ToBudgetResponses(budgets []domain.Budget) []web.BudgetResponse {
+	budgetResponses := make([]web.BudgetResponse, 0, len(budgets))
+	for _, budget := range budgets {
+		budgetResponses = append(budgetResponses, ToBudgetResponse(budget))
+	}
+	return budgetResponses
+}
+
+// ToCategoryResponse converts a domain.Category struct to a
+// web.CategoryResponse struct.
+func ToCategoryResponse(category domain.Category) web.CategoryResponse {
+	return web.CategoryResponse{
+		Id:        category.Id,
+		UserId:    category.UserId,
+		Name:      category.Name,
+		Color:     category.Color,
+		Icon:      category.Icon,
+		ParentId:  category.ParentId,
+		CreatedAt: category.CreatedAt,
+	}
+}
+
+// ToCategoryResponses converts a slice of domain.Category struct to a slice
+// of web.CategoryResponse struct.
+func ToCategoryResponses(categories []domain.Category) []web.CategoryResponse {
+	categoryResponses := make([]web.CategoryResponse, 0, len(categories))
+	for _, category := range categories {
+		categoryResponses = append(categoryResponses, ToCategoryResponse(category))
+	}
+	return categoryResponses
+}
+
+// ToExchangeRateResponse converts a domain.ExchangeRate struct to a
+// web.ExchangeRateResponse struct.
+func ToExchangeRateResponse(rate domain.ExchangeRate) web.ExchangeRateResponse {
+	return web.ExchangeRateResponse{
+		Base:          rate.Base,
+		Quote:         rate.Quote,
+		Rate:          json.Number(rate.Rate),
+		EffectiveFrom: rate.EffectiveFrom,
+		CreatedAt:     rate.CreatedAt,
+	}
+}
+
+// ToExchangeRateResponses converts a slice of domain.ExchangeRate struct to
+// a slice of web.ExchangeRateResponse struct.
+func ToExchangeRateResponses(rates []domain.ExchangeRate) []web.ExchangeRateResponse {
+	rateResponses := make([]web.ExchangeRateResponse, 0, len(rates))
+	for _, rate := range rates {
+		rateResponses = append(rateResponses, ToExchangeRateResponse(rate))
+	}
+	return rateResponses
+}
+
+// ToRecurringSpendingResponse converts a domain.RecurringSpending struct to
+// a web.RecurringSpendingResponse struct.
+func ToRecurringSpendingResponse(recurring domain.RecurringSpending) web.RecurringSpendingResponse {
+	return web.RecurringSpendingResponse{
+		Id:             recurring.Id,
+		UserId:         recurring.UserId,
+		Title:          recurring.Title,
+		Description:    recurring.Description,
+		Amount:         json.Number(recurring.Amount.String()),
+		Currency:       recurring.Amount.Currency,
+		Category:       recurring.Category,
+		Frequency:      recurring.Rule.Frequency,
+		Interval:       recurring.Rule.Interval,
+		ByDay:          recurring.Rule.ByDay,
+		Start:          recurring.Rule.Start,
+		Until:          recurring.Rule.Until,
+		Count:          recurring.Rule.Count,
+		LastOccurrence: recurring.LastOccurrence,
+		NextOccurrence: recurring.NextOccurrence,
+		CreatedAt:      recurring.CreatedAt,
+	}
+}
+
+// ToRecurringSpendingResponses converts a slice of domain.RecurringSpending
+// struct to a slice of web.RecurringSpendingResponse struct.
+func ToRecurringSpendingResponses(recurringSpendings []domain.RecurringSpending) []web.RecurringSpendingResponse {
+	recurringResponses := make([]web.RecurringSpendingResponse, 0, len(recurringSpendings))
+	for _, recurring := range recurringSpendings {
+		recurringResponses = append(recurringResponses, ToRecurringSpendingResponse(recurring))
+	}
+	return recurringResponses
+}
+
+// ToAccountResponse converts a domain.Account struct to a web.AccountResponse
+// struct.
+func ToAccountResponse(account domain.Account) web.AccountResponse {
+	return web.AccountResponse{
+		Id:             account.Id,
+		UserId:         account.UserId,
+		Name:           account.Name,
+		Type:           account.Type,
+		Currency:       account.Currency(),
+		OpeningBalance: json.Number(account.OpeningBalance.String()),
+		CreatedAt:      account.CreatedAt,
+	}
+}
+
+// ToAccountResponses converts a slice of domain.Account struct to a slice of
+// web.AccountResponse struct.
+func ToAccountResponses(accounts []domain.Account) []web.AccountResponse {
+	accountResponses := make([]web.AccountResponse, 0, len(accounts))
+	for _, account := range accounts {
+		accountResponses = append(accountResponses, ToAccountResponse(account))
+	}
+	return accountResponses
+}
+
+// ToTransferResponse converts a domain.Transfer struct to a
+// web.TransferResponse struct.
+func ToTransferResponse(transfer domain.Transfer) web.TransferResponse {
+	return web.TransferResponse{
+		Id:               transfer.Id,
+		UserId:           transfer.UserId,
+		FromAccountId:    transfer.FromAccountId,
+		ToAccountId:      transfer.ToAccountId,
+		Amount:           json.Number(transfer.Amount.String()),
+		Currency:         transfer.Amount.Currency,
+		ReceivedAmount:   json.Number(transfer.ReceivedAmount.String()),
+		ReceivedCurrency: transfer.ReceivedAmount.Currency,
+		Description:      transfer.Description,
+		Date:             transfer.Date,
+		CreatedAt:        transfer.CreatedAt,
+	}
+}
+
+// ToAttachmentResponse converts a domain.Attachment struct to a
+// web.AttachmentResponse struct.
+func ToAttachmentResponse(attachment domain.Attachment) web.AttachmentResponse {
+	return web.AttachmentResponse{
+		Id:          attachment.Id,
+		SpendingId:  attachment.SpendingId,
+		FileName:    attachment.FileName,
+		ContentType: attachment.ContentType,
+		Size:        attachment.Size,
+		CreatedAt:   attachment.CreatedAt,
+	}
+}
+
+// ToAttachmentResponses converts a slice of domain.Attachment struct to a
+// slice of web.AttachmentResponse struct.
+func ToAttachmentResponses(attachments []domain.Attachment) []web.AttachmentResponse {
+	attachmentResponses := make([]web.AttachmentResponse, 0, len(attachments))
+	for _, attachment := range attachments {
+		attachmentResponses = append(attachmentResponses, ToAttachmentResponse(attachment))
+	}
+	return attachmentResponses
+}
@@ -0,0 +1,45 @@
+package helper
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"strings"
+)
+
+// NewRefreshToken generates a new opaque refresh token for the session
+// with the given id. It returns the token to hand out to the client and
+// the hash of its secret to store with the session.
+func NewRefreshToken(sessionId string) (string, string) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		panic(err)
+	}
+	encodedSecret := base64.RawURLEncoding.EncodeToString(secret)
+	return sessionId + "." + encodedSecret, HashRefreshTokenSecret(encodedSecret)
+}
+
+// ParseRefreshToken splits the given refresh token into the session id
+// and the secret. It returns false when the token is malformed.
+func ParseRefreshToken(token string) (string, string, bool) {
+	sessionId, secret, found := strings.Cut(token, ".")
+	if !found || sessionId == "" || secret == "" {
+		return "", "", false
+	}
+	return sessionId, secret, true
+}
+
+// HashRefreshTokenSecret returns the hex encoded SHA-256 hash of the given
+// refresh token secret.
+func HashRefreshTokenSecret(secret string) string {
+	hash := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(hash[:])
+}
+
+// VerifyRefreshTokenSecret reports whether the given refresh token secret
+// matches the stored hash, using a constant time comparison.
+func VerifyRefreshTokenSecret(secret string, hash string) bool {
+	return subtle.ConstantTimeCompare([]byte(HashRefreshTokenSecret(secret)), []byte(hash)) == 1
+}
@@ -0,0 +1,30 @@
+package helper
+
+import (
+	"errors"
+	"strconv"
+	"time"
+)
+
+// ErrInvalidTime is returned by ParseTime when a value is not a time.
+var ErrInvalidTime = errors.New("invalid time")
+
+// ParseTime parses the given value as a time in Unix milliseconds. The
+// value is either Unix milliseconds, an RFC 3339 timestamp or a `YYYY-MM-DD`
+// date in the given location. A date means the start of the day, or the end
+// of the day when endOfDay is true.
+func ParseTime(value string, location *time.Location, endOfDay bool) (int64, error) {
+	if milliseconds, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return milliseconds, nil
+	}
+	if timestamp, err := time.Parse(time.RFC3339, value); err == nil {
+		return timestamp.UnixMilli(), nil
+	}
+	if date, err := time.ParseInLocation(time.DateOnly, value, location); err == nil {
+		if endOfDay {
+			date = date.AddDate(0, 0, 1).Add(-time.Millisecond)
+		}
+		return date.UnixMilli(), nil
+	}
+	return 0, ErrInvalidTime
+}
@@ -0,0 +1,100 @@
+package helper
+
+import (
+	"crypto/rsa"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"time"
+)
+
+// tokenIssuer is the value of the `iss` claim of every access token
+// issued by the API.
+const tokenIssuer = "duit-api"
+
+// TokenManager issues and verifies the signed JWT access tokens used to
+// authenticate the API requests.
+type TokenManager struct {
+
+	// Method is the signing method used for the issued tokens.
+	Method jwt.SigningMethod
+
+	// SignKey is the key used to sign the tokens.
+	SignKey interface{}
+
+	// VerifyKey is the key used to verify the token signatures.
+	VerifyKey interface{}
+
+	// Expiry is the lifetime of an issued token.
+	Expiry time.Duration
+}
+
+// NewHS256TokenManager creates a TokenManager that signs tokens with
+// HMAC-SHA256 using the provided shared secret.
+func NewHS256TokenManager(secret []byte, expiry time.Duration) *TokenManager {
+	return &TokenManager{
+		Method:    jwt.SigningMethodHS256,
+		SignKey:   secret,
+		VerifyKey: secret,
+		Expiry:    expiry,
+	}
+}
+
+// NewRS256TokenManager creates a TokenManager that signs tokens with
+// RSASSA-PKCS1-v1_5 SHA256 using the provided RSA private key, and verifies
+// them with its public key.
+func NewRS256TokenManager(privateKey *rsa.PrivateKey, expiry time.Duration) *TokenManager {
+	return &TokenManager{
+		Method:    jwt.SigningMethodRS256,
+		SignKey:   privateKey,
+		VerifyKey: &privateKey.PublicKey,
+		Expiry:    expiry,
+	}
+}
+
+// Issue creates a signed access token whose subject is the given user id.
+// It returns the token together with its expiration time.
+func (manager *TokenManager) Issue(userId string) (string, time.Time) {
+	tokenId, err := uuid.NewRandom()
+	if err != nil {
+		panic(err)
+	}
+
+	now := time.Now()
+	expiresAt := now.Add(manager.Expiry)
+	claims := jwt.RegisteredClaims{
+		ID:        tokenId.String(),
+		Issuer:    tokenIssuer,
+		Subject:   userId,
+		IssuedAt:  jwt.NewNumericDate(now),
+		ExpiresAt: jwt.NewNumericDate(expiresAt),
+	}
+
+	token, err := jwt.NewWithClaims(manager.Method, claims).SignedString(manager.SignKey)
+	if err != nil {
+		panic(err)
+	}
+	return token, expiresAt
+}
+
+// Verify checks the signature, issuer and expiration of the given access
+// token and returns the user id stored as its subject.
+func (manager *TokenManager) Verify(token string) (string, error) {
+	claims := jwt.RegisteredClaims{}
+	_, err := jwt.ParseWithClaims(
+		token,
+		&claims,
+		func(token *jwt.Token) (interface{}, error) {
+			return manager.VerifyKey, nil
+		},
+		jwt.WithValidMethods([]string{manager.Method.Alg()}),
+		jwt.WithIssuer(tokenIssuer),
+		jwt.WithExpirationRequired(),
+	)
+	if err != nil {
+		return "", err
+	}
+	if claims.Subject == "" {
+		return "", jwt.ErrTokenInvalidSubject
+	}
+	return claims.Subject, nil
+}
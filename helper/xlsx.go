@@ -0,0 +1,123 @@
+package helper
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// The parts of an XLSX workbook of a single sheet, except for the sheet.
+var xlsxParts = []struct {
+	name    string
+	content string
+}{
+	{"[Content_Types].xml", xml.Header + `<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">` +
+		`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>` +
+		`<Default Extension="xml" ContentType="application/xml"/>` +
+		`<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>` +
+		`<Override PartName="/xl/styles.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.styles+xml"/>` +
+		`<Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>` +
+		`</Types>`},
+	{"_rels/.rels", xml.Header + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+		`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>` +
+		`</Relationships>`},
+	{"xl/_rels/workbook.xml.rels", xml.Header + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+		`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/>` +
+		`<Relationship Id="rId2" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/styles" Target="styles.xml"/>` +
+		`</Relationships>`},
+	{"xl/styles.xml", xml.Header + `<styleSheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">` +
+		`<fonts count="1"><font/></fonts><fills count="1"><fill/></fills><borders count="1"><border/></borders>` +
+		`<cellStyleXfs count="1"><xf/></cellStyleXfs><cellXfs count="1"><xf/></cellXfs>` +
+		`</styleSheet>`},
+}
+
+// XLSXWriter writes an XLSX workbook of a single sheet a row at a time, so
+// the rows are streamed rather than kept in memory.
+type XLSXWriter struct {
+	zip   *zip.Writer
+	sheet io.Writer
+}
+
+// NewXLSXWriter writes the parts of a workbook with a sheet of the given
+// name into the writer, and returns the writer of the rows of the sheet.
+// The workbook is complete once the XLSXWriter is closed.
+func NewXLSXWriter(writer io.Writer, sheetName string) (*XLSXWriter, error) {
+	zipWriter := zip.NewWriter(writer)
+	for _, part := range xlsxParts {
+		partWriter, err := zipWriter.Create(part.name)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := io.WriteString(partWriter, part.content); err != nil {
+			return nil, err
+		}
+	}
+
+	workbook, err := zipWriter.Create("xl/workbook.xml")
+	if err != nil {
+		return nil, err
+	}
+	_, err = fmt.Fprintf(workbook, `%s<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" `+
+		`xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">`+
+		`<sheets><sheet name="%s" sheetId="1" r:id="rId1"/></sheets></workbook>`, xml.Header, escapeXML(sheetName))
+	if err != nil {
+		return nil, err
+	}
+
+	sheet, err := zipWriter.Create("xl/worksheets/sheet1.xml")
+	if err != nil {
+		return nil, err
+	}
+	_, err = io.WriteString(sheet, xml.Header+`<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`)
+	if err != nil {
+		return nil, err
+	}
+	return &XLSXWriter{zip: zipWriter, sheet: sheet}, nil
+}
+
+// WriteRow writes a row of the given cells. A json.Number or an integer is
+// written as a number, an empty json.Number as an empty cell, and any other
+// value as text.
+func (writer *XLSXWriter) WriteRow(cells ...interface{}) error {
+	var row strings.Builder
+	row.WriteString("<row>")
+	for _, cell := range cells {
+		switch value := cell.(type) {
+		case json.Number:
+			if value == "" {
+				row.WriteString(`<c/>`)
+				continue
+			}
+			row.WriteString(`<c><v>` + value.String() + `</v></c>`)
+		case int64:
+			row.WriteString(`<c><v>` + strconv.FormatInt(value, 10) + `</v></c>`)
+		case int:
+			row.WriteString(`<c><v>` + strconv.Itoa(value) + `</v></c>`)
+		default:
+			row.WriteString(`<c t="inlineStr"><is><t xml:space="preserve">` + escapeXML(fmt.Sprint(value)) + `</t></is></c>`)
+		}
+	}
+	row.WriteString("</row>")
+	_, err := io.WriteString(writer.sheet, row.String())
+	return err
+}
+
+// Close completes the sheet and the workbook. It does not close the
+// underlying writer.
+func (writer *XLSXWriter) Close() error {
+	if _, err := io.WriteString(writer.sheet, `</sheetData></worksheet>`); err != nil {
+		return err
+	}
+	return writer.zip.Close()
+}
+
+// escapeXML returns the text escaped as XML character data.
+func escapeXML(text string) string {
+	var escaped strings.Builder
+	_ = xml.EscapeText(&escaped, []byte(text))
+	return escaped.String()
+}
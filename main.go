@@ -2,7 +2,6 @@ package main
 
 import (
 	"context"
-	"github.com/go-playground/validator/v10"
 	"github.com/refandas/duit-api/app"
 	"github.com/refandas/duit-api/controller"
 	"github.com/refandas/duit-api/middleware"
@@ -12,33 +11,84 @@ import (
 )
 
 func main() {
-	db := app.SetupDatabase(context.Background())
-	validate := validator.New()
+	config := app.LoadConfig()
+	repositories := app.SetupRepositories(context.Background(), config)
+	validate := app.NewValidator()
+	translator := app.NewTranslator(validate)
+	tokenManager := app.SetupTokenManager(config)
+	ownershipPolicy := service.ParseOwnershipPolicy(config.OwnershipPolicy)
+	adminPolicy := service.NewAdminPolicy(config.AdminUserIds)
 
 	// Users configuration
-	dbUsers := db
-	dbUsers.TableName = "Users"
-	userRepository := repository.NewUserRepository()
-	userService := service.NewUserService(userRepository, &dbUsers, validate)
+	userService := service.NewUserService(repositories.User, repositories.Category, validate, ownershipPolicy)
 	userController := controller.NewUserController(userService)
 
 	// Spending configuration
-	dbSpending := db
-	dbSpending.TableName = "Spending"
-	spendingRepository := repository.NewSpendingRepository()
-	spendingService := service.NewSpendingService(spendingRepository, &dbSpending, validate)
+	blobStore := repository.NewBlobStoreLocal(config.AttachmentPath)
+	spendingService := service.NewSpendingService(repositories.Spending, repositories.Category, repositories.User, repositories.ExchangeRate, repositories.Account, repositories.Attachment, blobStore, validate, ownershipPolicy, config.CursorSecret)
 	spendingController := controller.NewSpendingController(spendingService)
 
+	// Sessions configuration
+	sessionService := service.NewSessionService(repositories.Session, ownershipPolicy)
+	sessionController := controller.NewSessionController(sessionService)
+
+	// Reports configuration
+	reportService := service.NewReportService(repositories.Spending, repositories.User, repositories.ExchangeRate, validate, ownershipPolicy)
+	reportController := controller.NewReportController(reportService)
+
+	// Budgets configuration
+	budgetService := service.NewBudgetService(repositories.Budget, repositories.Spending, repositories.User, repositories.ExchangeRate, validate, ownershipPolicy)
+	budgetController := controller.NewBudgetController(budgetService)
+
+	// Categories configuration
+	categoryService := service.NewCategoryService(repositories.Category, repositories.Spending, repositories.Budget, repositories.RecurringSpending, validate, ownershipPolicy)
+	categoryController := controller.NewCategoryController(categoryService)
+
+	// Exchange rates configuration
+	exchangeRateService := service.NewExchangeRateService(repositories.ExchangeRate, validate, adminPolicy)
+	exchangeRateController := controller.NewExchangeRateController(exchangeRateService)
+
+	// Recurring spendings configuration
+	recurringSpendingService := service.NewRecurringSpendingService(repositories.RecurringSpending, repositories.Spending, repositories.Category, repositories.User, validate, ownershipPolicy)
+	recurringSpendingController := controller.NewRecurringSpendingController(recurringSpendingService)
+	if config.RecurringInterval > 0 {
+		go app.RunRecurringScheduler(context.Background(), recurringSpendingService, config.RecurringInterval)
+	}
+
+	// Accounts configuration
+	accountService := service.NewAccountService(repositories.Account, repositories.Transfer, repositories.Spending, validate, ownershipPolicy)
+	accountController := controller.NewAccountController(accountService)
+
+	// Attachments configuration
+	attachmentService := service.NewAttachmentService(repositories.Attachment, repositories.Spending, blobStore, validate, ownershipPolicy, config.AttachmentMaxSize)
+	attachmentController := controller.NewAttachmentController(attachmentService, config.AttachmentMaxSize)
+
+	// Authentication configuration
+	authService := service.NewAuthService(repositories.User, repositories.Session, validate, tokenManager, config.RefreshTokenExpiry)
+	authController := controller.NewAuthController(authService)
+
 	router := app.Router{
-		UserController:     userController,
-		SpendingController: spendingController,
+		UserController:              userController,
+		SpendingController:          spendingController,
+		AuthController:              authController,
+		SessionController:           sessionController,
+		ReportController:            reportController,
+		BudgetController:            budgetController,
+		CategoryController:          categoryController,
+		ExchangeRateController:      exchangeRateController,
+		RecurringSpendingController: recurringSpendingController,
+		AccountController:           accountController,
+		AttachmentController:        attachmentController,
 	}
 
-	// Setup middleware
-	handler := middleware.NewRateLimitMiddleware(router.NewRouter())
+	// Setup middleware. The locale middleware comes first, so the errors of
+	// the rate limiting and the authentication are translated as well.
+	authMiddleware := middleware.NewAuthMiddleware(router.NewRouter(), tokenManager, app.PublicRoutes...)
+	rateLimitMiddleware := middleware.NewRateLimitMiddleware(authMiddleware)
+	handler := middleware.NewLocaleMiddleware(rateLimitMiddleware, translator, repositories.User)
 
 	server := http.Server{
-		Addr:    "localhost:8000",
+		Addr:    config.Address,
 		Handler: handler,
 	}
 
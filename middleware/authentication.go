@@ -0,0 +1,67 @@
+package middleware
+
+import (
+	"github.com/refandas/duit-api/exception"
+	"github.com/refandas/duit-api/helper"
+	"net/http"
+	"strings"
+)
+
+// AuthMiddleware authenticates incoming requests using the bearer access
+// token sent in the Authorization header, and puts the id of the
+// authenticated user into the request context.
+type AuthMiddleware struct {
+	Handler      http.Handler
+	TokenManager *helper.TokenManager
+
+	// PublicRoutes contains the routes, formatted as "METHOD /path", that
+	// can be accessed without an access token.
+	PublicRoutes map[string]bool
+}
+
+// NewAuthMiddleware takes an existing HTTP handler and returns a new
+// AuthMiddleware instance, which rejects the requests to the provided
+// handler that do not carry a valid access token, except for the given
+// public routes.
+func NewAuthMiddleware(handler http.Handler, tokenManager *helper.TokenManager, publicRoutes ...string) *AuthMiddleware {
+	routes := make(map[string]bool)
+	for _, route := range publicRoutes {
+		routes[route] = true
+	}
+	return &AuthMiddleware{
+		Handler:      handler,
+		TokenManager: tokenManager,
+		PublicRoutes: routes,
+	}
+}
+
+// ServeHTTP method satisfies the http.Handler interface and is responsible for
+// handling incoming HTTP requests. It verifies the access token of a request
+// before passing it to the wrapped handler.
+func (middleware *AuthMiddleware) ServeHTTP(writer http.ResponseWriter, request *http.Request) {
+	if middleware.PublicRoutes[request.Method+" "+request.URL.Path] {
+		middleware.Handler.ServeHTTP(writer, request)
+		return
+	}
+
+	token, found := strings.CutPrefix(request.Header.Get("Authorization"), "Bearer ")
+	if !found || token == "" {
+		unauthorized(writer, request, "missing access token")
+		return
+	}
+
+	userId, err := middleware.TokenManager.Verify(token)
+	if err != nil {
+		unauthorized(writer, request, "invalid access token")
+		return
+	}
+
+	ctx := helper.ContextWithUserId(request.Context(), userId)
+	middleware.Handler.ServeHTTP(writer, request.WithContext(ctx))
+}
+
+// unauthorized writes a 401 Unauthorized response with the given message.
+func unauthorized(writer http.ResponseWriter, request *http.Request, message string) {
+	writer.Header().Set("WWW-Authenticate", "Bearer")
+	exception.WriteError(writer, request, exception.NewUnauthorizedError(message))
+}
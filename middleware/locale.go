@@ -0,0 +1,129 @@
+package middleware
+
+import (
+	"context"
+	ut "github.com/go-playground/universal-translator"
+	"github.com/refandas/duit-api/helper"
+	"github.com/refandas/duit-api/repository"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// LocaleMiddleware chooses the language of the error messages of incoming
+// requests, and puts the translator of the language into the request
+// context.
+//
+// The language is chosen from the Accept-Language header. When the header
+// does not choose a supported language, the language preference of the
+// authenticated user is used if the UserRepository is set, otherwise the
+// fallback language of the Translator.
+type LocaleMiddleware struct {
+	Handler        http.Handler
+	Translator     *ut.UniversalTranslator
+	UserRepository repository.UserRepository
+}
+
+// NewLocaleMiddleware creates a new LocaleMiddleware, applying the language
+// chosen for incoming requests to the provided handler. The userRepository
+// may be nil to ignore the language preferences of the users.
+func NewLocaleMiddleware(handler http.Handler, translator *ut.UniversalTranslator, userRepository repository.UserRepository) *LocaleMiddleware {
+	return &LocaleMiddleware{
+		Handler:        handler,
+		Translator:     translator,
+		UserRepository: userRepository,
+	}
+}
+
+func (middleware *LocaleMiddleware) ServeHTTP(writer http.ResponseWriter, request *http.Request) {
+	writer.Header().Add("Vary", "Accept-Language")
+
+	ctx := request.Context()
+	translator, found := middleware.Translator.FindTranslator(acceptedLanguages(request.Header.Get("Accept-Language"))...)
+	if found {
+		ctx = helper.ContextWithTranslator(ctx, translator)
+	} else {
+		ctx = helper.ContextWithTranslatorResolver(ctx, middleware.preferredTranslator(translator))
+	}
+	middleware.Handler.ServeHTTP(writer, request.WithContext(ctx))
+}
+
+// preferredTranslator returns the resolver of the translator of the language
+// preference of the authenticated user, which resolves to the given fallback
+// when the user is unknown or has no supported preference. The middleware
+// runs before the authentication, so the user is only read once a message
+// is translated, and at most once per request.
+func (middleware *LocaleMiddleware) preferredTranslator(fallback ut.Translator) helper.TranslatorResolver {
+	var mutex sync.Mutex
+	var resolved ut.Translator
+	var resolvedUserId string
+
+	return func(ctx context.Context) ut.Translator {
+		userId, ok := helper.UserIdFromContext(ctx)
+		if !ok || middleware.UserRepository == nil {
+			return fallback
+		}
+
+		mutex.Lock()
+		defer mutex.Unlock()
+		if resolved != nil && resolvedUserId == userId {
+			return resolved
+		}
+		resolved, resolvedUserId = fallback, userId
+
+		// The language only affects the error messages, so a user that can
+		// not be read is served in the fallback language rather than failing.
+		user, err := middleware.UserRepository.FindById(ctx, userId)
+		if err != nil || user.Language == "" {
+			return resolved
+		}
+		if translator, found := middleware.Translator.GetTranslator(user.Language); found {
+			resolved = translator
+		}
+		return resolved
+	}
+}
+
+// acceptedLanguages returns the languages of the Accept-Language header
+// ordered by preference. Each language tag is followed by its primary
+// language, so `id-ID` also accepts `id`.
+func acceptedLanguages(header string) []string {
+	type acceptedLanguage struct {
+		tag     string
+		quality float64
+	}
+
+	var languages []acceptedLanguage
+	for _, part := range strings.Split(header, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		if tag == "" || tag == "*" {
+			continue
+		}
+
+		quality := 1.0
+		if value, found := strings.CutPrefix(strings.TrimSpace(params), "q="); found {
+			parsed, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				continue
+			}
+			quality = parsed
+		}
+		if quality > 0 {
+			languages = append(languages, acceptedLanguage{tag: tag, quality: quality})
+		}
+	}
+	sort.SliceStable(languages, func(i, j int) bool {
+		return languages[i].quality > languages[j].quality
+	})
+
+	tags := make([]string, 0, len(languages)*2)
+	for _, language := range languages {
+		tags = append(tags, language.tag)
+		if primary, _, found := strings.Cut(language.tag, "-"); found {
+			tags = append(tags, primary)
+		}
+	}
+	return tags
+}
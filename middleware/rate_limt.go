@@ -1,8 +1,8 @@
 package middleware
 
 import (
+	"github.com/refandas/duit-api/exception"
 	"github.com/refandas/duit-api/helper"
-	"github.com/refandas/duit-api/model/web"
 	"golang.org/x/time/rate"
 	"net"
 	"net/http"
@@ -84,20 +84,15 @@ func (middleware *RateLimitMiddleware) ServeHTTP(writer http.ResponseWriter, req
 	// Get the IP address from the current visitor.
 	ip, _, err := net.SplitHostPort(request.RemoteAddr)
 	if err != nil {
-		panic(err)
+		exception.WriteError(writer, request, err)
+		return
 	}
 
 	helper.SetupSecurityHeaders(writer)
 
 	limiter := getVisitor(ip)
 	if !limiter.Allow() {
-		writer.WriteHeader(http.StatusTooManyRequests)
-
-		webResponse := web.WebResponse{
-			Code:   http.StatusTooManyRequests,
-			Status: "TOO MANY REQUESTS",
-		}
-		helper.WriteToResponseBody(writer, webResponse)
+		exception.WriteError(writer, request, exception.NewTooManyRequestsError("too many requests"))
 	} else {
 		middleware.Handler.ServeHTTP(writer, request)
 	}
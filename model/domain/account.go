@@ -0,0 +1,45 @@
+package domain
+
+// The types of the accounts.
+const (
+	AccountTypeCash    = "cash"
+	AccountTypeBank    = "bank"
+	AccountTypeEWallet = "ewallet"
+	AccountTypeOther   = "other"
+)
+
+// Account represents a source of money of a user, such as cash, a bank
+// account or an e-wallet. The balance of an account is its opening balance
+// plus the incomes and the transfers into it, minus the expenses and the
+// transfers out of it.
+type Account struct {
+
+	// Id represents the unique identifier of the account. It is formatted
+	// as a UUID4.
+	Id string `dynamodbav:"Id"`
+
+	// UserId represents the unique identifier of the user who owns the
+	// account. It is formatted as a UUID4.
+	UserId string `dynamodbav:"UserId"`
+
+	// Name represents the name of the account chosen by the user.
+	Name string `dynamodbav:"Name"`
+
+	// Type represents the kind of the account, one of `cash`, `bank`,
+	// `ewallet` and `other`.
+	Type string `dynamodbav:"Type"`
+
+	// OpeningBalance represents the balance of the account before any of
+	// its spendings and transfers. Its currency is the currency of every
+	// amount of the account.
+	OpeningBalance Money `dynamodbav:"OpeningBalance"`
+
+	// CreatedAt represents the date and time when the account was created,
+	// stored in Unix time format.
+	CreatedAt int64 `dynamodbav:"CreatedAt"`
+}
+
+// Currency returns the ISO 4217 code of the currency of the account.
+func (account Account) Currency() string {
+	return account.OpeningBalance.Currency
+}
@@ -0,0 +1,40 @@
+package domain
+
+// Attachment represents a file attached to a spending, such as a photo of
+// its receipt. The content of the file is kept in a blob store, while the
+// attachment describes it.
+type Attachment struct {
+
+	// Id represents the unique identifier of the attachment. It is
+	// formatted as a UUID4.
+	Id string `dynamodbav:"Id"`
+
+	// UserId represents the unique identifier of the user who owns the
+	// spending of the attachment. It is formatted as a UUID4.
+	UserId string `dynamodbav:"UserId"`
+
+	// SpendingId represents the unique identifier of the spending the file
+	// is attached to.
+	SpendingId string `dynamodbav:"SpendingId"`
+
+	// FileName represents the name of the uploaded file, without its
+	// directories.
+	FileName string `dynamodbav:"FileName"`
+
+	// ContentType represents the media type of the file, detected from its
+	// content rather than taken from the upload.
+	ContentType string `dynamodbav:"ContentType"`
+
+	// Size represents the size of the file in bytes.
+	Size int64 `dynamodbav:"Size"`
+
+	// CreatedAt represents the date and time when the file was attached,
+	// stored in Unix time format.
+	CreatedAt int64 `dynamodbav:"CreatedAt"`
+}
+
+// BlobKey returns the key of the content of the file in the blob store. The
+// keys of the files of a user share the id of the user as their prefix.
+func (attachment Attachment) BlobKey() string {
+	return attachment.UserId + "/" + attachment.Id
+}
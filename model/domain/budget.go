@@ -0,0 +1,30 @@
+package domain
+
+// Budget represents a spending limit of a user for a category in every
+// period, such as every month.
+type Budget struct {
+
+	// Id represents the unique identifier of the budget. It is formatted as
+	// a UUID4.
+	Id string `dynamodbav:"Id"`
+
+	// UserId represents the unique identifier of the user who owns the
+	// budget. It is formatted as a UUID4.
+	UserId string `dynamodbav:"UserId"`
+
+	// Category represents the spending category the budget limits.
+	Category string `dynamodbav:"Category"`
+
+	// Amount represents the maximum amount the user plans to spend on the
+	// category in a period, in the home currency of the user when the
+	// budget was set.
+	Amount Money `dynamodbav:"Amount"`
+
+	// Period represents the period the budget is renewed every, one of
+	// `day`, `week`, `month` and `year`.
+	Period string `dynamodbav:"Period"`
+
+	// CreatedAt represents the date and time when the budget was created,
+	// stored in Unix time format.
+	CreatedAt int64 `dynamodbav:"CreatedAt"`
+}
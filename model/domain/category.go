@@ -0,0 +1,48 @@
+package domain
+
+// Category represents a spending category of a user. Spendings and
+// budgets refer to a category by its name.
+type Category struct {
+
+	// Id represents the unique identifier of the category. It is formatted
+	// as a UUID4.
+	Id string `dynamodbav:"Id"`
+
+	// UserId represents the unique identifier of the user who owns the
+	// category. It is formatted as a UUID4.
+	UserId string `dynamodbav:"UserId"`
+
+	// Name represents the lowercase name of the category, unique among the
+	// categories of the user.
+	Name string `dynamodbav:"Name"`
+
+	// Color represents the color the category is displayed with, formatted
+	// as a hex color such as `#FF7043`.
+	Color string `dynamodbav:"Color"`
+
+	// Icon represents the name of the icon the category is displayed with.
+	Icon string `dynamodbav:"Icon"`
+
+	// ParentId represents the unique identifier of the parent category of a
+	// subcategory. It is empty for a top-level category.
+	ParentId string `dynamodbav:"ParentId"`
+
+	// CreatedAt represents the date and time when the category was created,
+	// stored in Unix time format.
+	CreatedAt int64 `dynamodbav:"CreatedAt"`
+}
+
+// DefaultCategories returns the categories every new user starts with,
+// without the id, the user id and the creation time.
+func DefaultCategories() []Category {
+	return []Category{
+		{Name: "food", Color: "#FF7043", Icon: "utensils"},
+		{Name: "transportation", Color: "#42A5F5", Icon: "bus"},
+		{Name: "shopping", Color: "#AB47BC", Icon: "shopping-bag"},
+		{Name: "bills", Color: "#FFA726", Icon: "file-invoice"},
+		{Name: "entertainment", Color: "#EC407A", Icon: "film"},
+		{Name: "health", Color: "#66BB6A", Icon: "heart-pulse"},
+		{Name: "education", Color: "#26A69A", Icon: "graduation-cap"},
+		{Name: "other", Color: "#78909C", Icon: "ellipsis"},
+	}
+}
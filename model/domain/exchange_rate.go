@@ -0,0 +1,76 @@
+package domain
+
+import (
+	"errors"
+	"math/big"
+	"regexp"
+	"strings"
+)
+
+// ErrInvalidRate is returned when an exchange rate is not a positive
+// decimal number.
+var ErrInvalidRate = errors.New("invalid exchange rate")
+
+// ratePattern matches the decimal numbers accepted as an exchange rate.
+var ratePattern = regexp.MustCompile(`^[0-9]+(\.[0-9]+)?$`)
+
+// ExchangeRate represents the rate of exchange from a currency into another
+// currency, effective from a date until the next rate of the currencies.
+type ExchangeRate struct {
+
+	// Pair represents the currencies of the rate formatted as
+	// `Base/Quote`, such as `SGD/IDR`.
+	Pair string `dynamodbav:"Pair"`
+
+	// Base represents the ISO 4217 code of the currency exchanged from.
+	Base string `dynamodbav:"Base"`
+
+	// Quote represents the ISO 4217 code of the currency exchanged into.
+	Quote string `dynamodbav:"Quote"`
+
+	// Rate represents the amount of the quote currency per unit of the base
+	// currency as an exact decimal number, such as `11650.25`.
+	Rate string `dynamodbav:"Rate"`
+
+	// EffectiveFrom represents the time since the rate is effective, stored
+	// in Unix time format.
+	EffectiveFrom int64 `dynamodbav:"EffectiveFrom"`
+
+	// CreatedAt represents the date and time when the rate was recorded,
+	// stored in Unix time format.
+	CreatedAt int64 `dynamodbav:"CreatedAt"`
+}
+
+// CurrencyPair returns the pair of the rates exchanging the base currency
+// into the quote currency.
+func CurrencyPair(base string, quote string) string {
+	return base + "/" + quote
+}
+
+// ParseRate returns the given positive decimal exchange rate formatted
+// without leading zeros, such as `11650.25` for `011650.25`.
+func ParseRate(rate string) (string, error) {
+	if !ratePattern.MatchString(rate) {
+		return "", ErrInvalidRate
+	}
+	value, ok := new(big.Rat).SetString(rate)
+	if !ok || value.Sign() <= 0 {
+		return "", ErrInvalidRate
+	}
+
+	decimals := 0
+	if _, fraction, found := strings.Cut(rate, "."); found {
+		decimals = len(fraction)
+	}
+	return value.FloatString(decimals), nil
+}
+
+// Value returns the exact value of the rate, or zero when the rate is not
+// a decimal number.
+func (rate ExchangeRate) Value() *big.Rat {
+	value, ok := new(big.Rat).SetString(rate.Rate)
+	if !ok {
+		return new(big.Rat)
+	}
+	return value
+}
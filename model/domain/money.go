@@ -0,0 +1,125 @@
+package domain
+
+import (
+	"errors"
+	"math"
+	"math/big"
+	"strings"
+)
+
+// DefaultCurrency is the currency of the amounts given without a currency,
+// and of the amounts recorded before the currencies were stored.
+const DefaultCurrency = "IDR"
+
+var (
+	// ErrInvalidAmount is returned when an amount is not a non-negative
+	// decimal number that fits into the minor units of its currency.
+	ErrInvalidAmount = errors.New("invalid amount")
+
+	// ErrTooManyDecimals is returned when an amount has more decimal places
+	// than the minor units of its currency.
+	ErrTooManyDecimals = errors.New("amount has too many decimal places")
+)
+
+// currencyExponents lists the ISO 4217 currencies whose minor unit is not a
+// hundredth of the major unit, by the number of decimal places of the
+// minor unit.
+var currencyExponents = map[string]int{
+	"BIF": 0, "CLP": 0, "DJF": 0, "GNF": 0, "ISK": 0, "JPY": 0, "KMF": 0,
+	"KRW": 0, "PYG": 0, "RWF": 0, "UGX": 0, "UYI": 0, "VND": 0, "VUV": 0,
+	"XAF": 0, "XOF": 0, "XPF": 0,
+	"BHD": 3, "IQD": 3, "JOD": 3, "KWD": 3, "LYD": 3, "OMR": 3, "TND": 3,
+	"CLF": 4, "UYW": 4,
+}
+
+// CurrencyExponent returns the number of decimal places of the minor unit of
+// the ISO 4217 currency with the given code, which is 2 for most currencies.
+func CurrencyExponent(currency string) int {
+	if exponent, ok := currencyExponents[currency]; ok {
+		return exponent
+	}
+	return 2
+}
+
+// minorUnitScale returns the number of minor units in a major unit of the
+// currency.
+func minorUnitScale(currency string) *big.Rat {
+	return new(big.Rat).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(CurrencyExponent(currency))), nil))
+}
+
+// Money represents an exact amount of money in a currency.
+type Money struct {
+
+	// Minor represents the amount in the minor unit of the currency, such
+	// as cents for USD, so the amount is summed without rounding errors.
+	Minor int64 `dynamodbav:"Minor"`
+
+	// Currency represents the ISO 4217 code of the currency of the amount.
+	Currency string `dynamodbav:"Currency"`
+}
+
+// ParseMoney returns the money of the given non-negative decimal amount in
+// the major unit of the currency, such as `12.50` for USD. It returns
+// ErrTooManyDecimals when the amount is more precise than the minor unit of
+// the currency.
+func ParseMoney(amount string, currency string) (Money, error) {
+	value, ok := new(big.Rat).SetString(amount)
+	if !ok || value.Sign() < 0 {
+		return Money{}, ErrInvalidAmount
+	}
+
+	value.Mul(value, minorUnitScale(currency))
+	if !value.IsInt() {
+		return Money{}, ErrTooManyDecimals
+	}
+	if !value.Num().IsInt64() {
+		return Money{}, ErrInvalidAmount
+	}
+	return Money{Minor: value.Num().Int64(), Currency: currency}, nil
+}
+
+// MoneyFromFloat returns the money of an amount in the major unit of the
+// currency recorded as a floating point number, rounded to the nearest
+// minor unit.
+func MoneyFromFloat(amount float64, currency string) Money {
+	scale := math.Pow10(CurrencyExponent(currency))
+	return Money{Minor: int64(math.Round(amount * scale)), Currency: currency}
+}
+
+// Rat returns the exact amount in the major unit of the currency.
+func (money Money) Rat() *big.Rat {
+	return new(big.Rat).Quo(new(big.Rat).SetInt64(money.Minor), minorUnitScale(money.Currency))
+}
+
+// Convert returns the money converted into the given currency by the given
+// rate, the amount of the currency per unit of the currency of the money,
+// rounded half away from zero to the minor unit of the currency.
+func (money Money) Convert(rate *big.Rat, currency string) Money {
+	value := new(big.Rat).Mul(money.Rat(), rate)
+	value.Mul(value, minorUnitScale(currency))
+
+	minor, remainder := new(big.Int).QuoRem(value.Num(), value.Denom(), new(big.Int))
+	if remainder.Lsh(remainder.Abs(remainder), 1).Cmp(value.Denom()) >= 0 {
+		minor.Add(minor, big.NewInt(int64(value.Sign())))
+	}
+	return Money{Minor: minor.Int64(), Currency: currency}
+}
+
+// String returns the amount in the major unit of the currency with all the
+// decimal places of the minor unit, such as `12.50` for USD.
+func (money Money) String() string {
+	exponent := CurrencyExponent(money.Currency)
+	digits := new(big.Int).Abs(big.NewInt(money.Minor)).String()
+	if len(digits) <= exponent {
+		digits = strings.Repeat("0", exponent-len(digits)+1) + digits
+	}
+
+	sign := ""
+	if money.Minor < 0 {
+		sign = "-"
+	}
+	if exponent == 0 {
+		return sign + digits
+	}
+	return sign + digits[:len(digits)-exponent] + "." + digits[len(digits)-exponent:]
+}
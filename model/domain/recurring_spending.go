@@ -0,0 +1,217 @@
+package domain
+
+import (
+	"github.com/google/uuid"
+	"strconv"
+	"time"
+)
+
+// The frequencies of the recurrence rules.
+const (
+	FrequencyDaily   = "daily"
+	FrequencyWeekly  = "weekly"
+	FrequencyMonthly = "monthly"
+	FrequencyYearly  = "yearly"
+)
+
+// weekdayOffsets maps the RFC 5545 codes of the days of the week to their
+// offsets from Monday.
+var weekdayOffsets = map[string]int{"MO": 0, "TU": 1, "WE": 2, "TH": 3, "FR": 4, "SA": 5, "SU": 6}
+
+// occurrenceNamespace is the namespace of the ids of the spendings of the
+// occurrences of recurring spendings.
+var occurrenceNamespace = uuid.MustParse("6f1c2a0e-8a5d-4c1b-9f3e-2b7d4e6a9c10")
+
+// RecurrenceRule represents the schedule of a recurring spending, a subset
+// of the RFC 5545 recurrence rules.
+type RecurrenceRule struct {
+
+	// Frequency represents the unit of the interval between occurrences,
+	// one of `daily`, `weekly`, `monthly` and `yearly`.
+	Frequency string `dynamodbav:"Frequency"`
+
+	// Interval represents the number of units of the frequency between
+	// occurrences, such as 2 for every other week.
+	Interval int `dynamodbav:"Interval"`
+
+	// ByDay represents the days of the week a weekly rule occurs on, as
+	// `MO` to `SU`. A weekly rule without days occurs on the day of the
+	// week of the start.
+	ByDay []string `dynamodbav:"ByDay"`
+
+	// Start represents the date of the first occurrence, stored in Unix
+	// time format. The later occurrences are at the same time of the day.
+	Start int64 `dynamodbav:"Start"`
+
+	// Until represents the latest date of an occurrence, stored in Unix
+	// time format. The rule has no end date when it is zero.
+	Until int64 `dynamodbav:"Until"`
+
+	// Count represents the number of occurrences of the rule. The rule
+	// has no number of occurrences when it is zero.
+	Count int `dynamodbav:"Count"`
+}
+
+// Next returns the date of the first occurrence of the rule after the given
+// date, both in Unix time format, with the days of the rule in the given
+// location. It returns false when the rule has no more occurrences.
+//
+// Like RFC 5545, a monthly or yearly rule skips the months without the day
+// of the month of the start, such as February for a rule starting on the
+// 30th.
+func (rule RecurrenceRule) Next(after int64, location *time.Location) (int64, bool) {
+	var next int64
+	found := false
+	rule.each(location, func(occurrence time.Time) bool {
+		if occurrence.UnixMilli() <= after {
+			return true
+		}
+		next, found = occurrence.UnixMilli(), true
+		return false
+	})
+	return next, found
+}
+
+// each calls the function with the occurrences of the rule in order, until
+// the function returns false or the rule has no more occurrences.
+func (rule RecurrenceRule) each(location *time.Location, fn func(occurrence time.Time) bool) {
+	start := time.UnixMilli(rule.Start).In(location)
+	interval := max(rule.Interval, 1)
+	occurrences := 0
+	emit := func(occurrence time.Time) bool {
+		if occurrence.Before(start) {
+			return true
+		}
+		if rule.Until != 0 && occurrence.UnixMilli() > rule.Until {
+			return false
+		}
+		if rule.Count != 0 && occurrences >= rule.Count {
+			return false
+		}
+		occurrences++
+		return fn(occurrence)
+	}
+
+	switch rule.Frequency {
+	case FrequencyDaily:
+		for i := 0; emit(start.AddDate(0, 0, i*interval)); i++ {
+		}
+	case FrequencyWeekly:
+		if len(rule.ByDay) == 0 {
+			for i := 0; emit(start.AddDate(0, 0, 7*i*interval)); i++ {
+			}
+			return
+		}
+		var offsets []int
+		for offset := 0; offset < 7; offset++ {
+			for _, day := range rule.ByDay {
+				if weekdayOffsets[day] == offset {
+					offsets = append(offsets, offset)
+					break
+				}
+			}
+		}
+		monday := start.AddDate(0, 0, -((int(start.Weekday()) + 6) % 7))
+		for i := 0; ; i++ {
+			for _, offset := range offsets {
+				if !emit(monday.AddDate(0, 0, 7*i*interval+offset)) {
+					return
+				}
+			}
+		}
+	case FrequencyMonthly:
+		for i := 0; ; i++ {
+			occurrence := start.AddDate(0, i*interval, 0)
+			if occurrence.Day() != start.Day() {
+				continue
+			}
+			if !emit(occurrence) {
+				return
+			}
+		}
+	case FrequencyYearly:
+		for i := 0; ; i++ {
+			occurrence := start.AddDate(i*interval, 0, 0)
+			if occurrence.Day() != start.Day() {
+				continue
+			}
+			if !emit(occurrence) {
+				return
+			}
+		}
+	}
+}
+
+// RecurringSpending represents a template of a spending repeated on a
+// schedule, such as the rent every month. The spendings of its occurrences
+// are created as they become due.
+type RecurringSpending struct {
+
+	// Id represents the unique identifier of the recurring spending. It is
+	// formatted as a UUID4.
+	Id string `dynamodbav:"Id"`
+
+	// UserId represents the unique identifier of the user who owns the
+	// recurring spending. It is formatted as a UUID4.
+	UserId string `dynamodbav:"UserId"`
+
+	// Title represents the title of the spendings of the occurrences.
+	Title string `dynamodbav:"Title"`
+
+	// Description represents the description of the spendings of the
+	// occurrences.
+	Description string `dynamodbav:"Description"`
+
+	// Amount represents the amount of the spendings of the occurrences.
+	Amount Money `dynamodbav:"Amount"`
+
+	// Category represents the category of the spendings of the
+	// occurrences.
+	Category string `dynamodbav:"Category"`
+
+	// Rule represents the schedule of the occurrences.
+	Rule RecurrenceRule `dynamodbav:"Rule"`
+
+	// LastOccurrence represents the date of the latest occurrence whose
+	// spending has been created, stored in Unix time format. It is zero
+	// before the first occurrence is due.
+	LastOccurrence int64 `dynamodbav:"LastOccurrence"`
+
+	// NextOccurrence represents the date of the next occurrence whose
+	// spending is to be created, stored in Unix time format. It is zero
+	// when the rule has no more occurrences.
+	NextOccurrence int64 `dynamodbav:"NextOccurrence"`
+
+	// CreatedAt represents the date and time when the recurring spending
+	// was created, stored in Unix time format.
+	CreatedAt int64 `dynamodbav:"CreatedAt"`
+}
+
+// Schedule sets the next occurrence to the first occurrence of the rule
+// after the last occurrence, with the days of the rule in the given
+// location.
+func (recurring *RecurringSpending) Schedule(location *time.Location) {
+	recurring.NextOccurrence, _ = recurring.Rule.Next(recurring.LastOccurrence, location)
+}
+
+// Occurrence returns the spending of the occurrence of the given date,
+// created at the given time.
+//
+// The id of the spending is derived from the id of the recurring spending
+// and the date, so the spending of an occurrence is never created twice.
+// It is shaped as a UUID4, like the ids of the other spendings.
+func (recurring RecurringSpending) Occurrence(date int64, createdAt int64) Spending {
+	id := uuid.NewSHA1(occurrenceNamespace, []byte(recurring.Id+"/"+strconv.FormatInt(date, 10)))
+	id[6] = id[6]&0x0f | 0x40
+	return Spending{
+		Id:          id.String(),
+		UserId:      recurring.UserId,
+		Title:       recurring.Title,
+		Description: recurring.Description,
+		Category:    recurring.Category,
+		Type:        SpendingTypeExpense,
+		Date:        date,
+		Amount:      recurring.Amount,
+		CreatedAt:   createdAt,
+	}
+}
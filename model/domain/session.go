@@ -0,0 +1,55 @@
+package domain
+
+// Session represents a refresh token issued to a user. Every time a
+// refresh token is used it is rotated into a new Session of the same
+// family, so a family represents one logged in device.
+type Session struct {
+
+	// Id represents the unique identifier of the refresh token. It is
+	// formatted as a UUID4.
+	Id string `dynamodbav:"Id"`
+
+	// FamilyId represents the unique identifier of the login the refresh
+	// token descends from. It is formatted as a UUID4.
+	FamilyId string `dynamodbav:"FamilyId"`
+
+	// UserId represents the unique identifier of the user who owns the
+	// refresh token. It is formatted as a UUID4.
+	UserId string `dynamodbav:"UserId"`
+
+	// TokenHash represents the SHA-256 hash of the refresh token secret.
+	// The secret itself is never stored.
+	TokenHash string `dynamodbav:"TokenHash"`
+
+	// UserAgent represents the User-Agent header of the login request,
+	// used to identify the device.
+	UserAgent string `dynamodbav:"UserAgent"`
+
+	// StartedAt represents the date and time of the login the family
+	// started with, stored in Unix time format.
+	StartedAt int64 `dynamodbav:"StartedAt"`
+
+	// CreatedAt represents the date and time when the refresh token was
+	// issued, stored in Unix time format.
+	CreatedAt int64 `dynamodbav:"CreatedAt"`
+
+	// ExpiresAt represents the date and time when the refresh token
+	// expires, stored in Unix time format.
+	ExpiresAt int64 `dynamodbav:"ExpiresAt"`
+
+	// RotatedAt represents the date and time when the refresh token was
+	// exchanged for a new one, stored in Unix time format. It is zero while
+	// the refresh token has not been used.
+	RotatedAt int64 `dynamodbav:"RotatedAt"`
+
+	// RevokedAt represents the date and time when the refresh token was
+	// revoked, stored in Unix time format. It is zero while the refresh
+	// token has not been revoked.
+	RevokedAt int64 `dynamodbav:"RevokedAt"`
+}
+
+// Active reports whether the refresh token can still be exchanged for a
+// new one at the given time in Unix time format.
+func (session Session) Active(now int64) bool {
+	return session.RotatedAt == 0 && session.RevokedAt == 0 && session.ExpiresAt > now
+}
@@ -1,5 +1,26 @@
 package domain
 
+import (
+	"slices"
+	"strings"
+)
+
+const (
+	// SpendingStatusPlanned is the status of a spending dated in the future.
+	SpendingStatusPlanned = "planned"
+
+	// SpendingStatusPosted is the status of a spending whose date has passed.
+	SpendingStatusPosted = "posted"
+)
+
+const (
+	// SpendingTypeExpense is the type of a spending of money.
+	SpendingTypeExpense = "expense"
+
+	// SpendingTypeIncome is the type of money received, such as a salary.
+	SpendingTypeIncome = "income"
+)
+
 // Spending represent the spending history data structure.
 type Spending struct {
 
@@ -19,8 +40,8 @@ type Spending struct {
 	Description string `dynamodbav:"Description"`
 
 	// Amount represents the spending amount. It is used to store the
-	// monetary value of a user's spending.
-	Amount float64 `dynamodbav:"Amount"`
+	// monetary value of a user's spending in its currency.
+	Amount Money `dynamodbav:"Amount"`
 
 	// Date represents the date when a spending was done, stored in
 	// Unix time format. It is used to store the timestamp of when
@@ -30,8 +51,59 @@ type Spending struct {
 	// Category represents the spending category chosen by the user.
 	Category string `dynamodbav:"Category"`
 
+	// Type represents whether the money was spent or received, either
+	// `expense` or `income`.
+	Type string `dynamodbav:"Type"`
+
+	// AccountId represents the unique identifier of the account the money
+	// was spent from or received into. The spending belongs to no account
+	// when it is empty.
+	AccountId string `dynamodbav:"AccountId"`
+
+	// Tags represents the labels chosen by the user, such as
+	// `business-trip` and `reimbursable`. Unlike the category, a spending
+	// may have any number of tags. They are normalized by NormalizeTags.
+	Tags []string `dynamodbav:"Tags,stringset,omitempty"`
+
 	// CreatedAt represents the date and time when the spending data
 	// was created, stored in Unix time format. It is used to store
 	// the timestamp of when the spending data was initially recorded.
 	CreatedAt int64 `dynamodbav:"CreatedAt"`
 }
+
+// Kind returns the type of the spending. The spendings stored before incomes
+// were recorded have no type, and are expenses.
+func (spending Spending) Kind() string {
+	if spending.Type == "" {
+		return SpendingTypeExpense
+	}
+	return spending.Type
+}
+
+// Status returns the status of the spending at the given time in Unix time
+// format. A spending is planned until its date passes, and is posted since.
+func (spending Spending) Status(now int64) string {
+	if spending.Date > now {
+		return SpendingStatusPlanned
+	}
+	return SpendingStatusPosted
+}
+
+// HasTag reports whether the spending has the given normalized tag.
+func (spending Spending) HasTag(tag string) bool {
+	return slices.Contains(spending.Tags, tag)
+}
+
+// NormalizeTags returns the given tags trimmed, in lowercase, sorted and
+// without duplicates or empty tags. It returns nil when no tag remains.
+func NormalizeTags(tags []string) []string {
+	var normalized []string
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag != "" {
+			normalized = append(normalized, tag)
+		}
+	}
+	slices.Sort(normalized)
+	return slices.Compact(normalized)
+}
@@ -0,0 +1,100 @@
+package domain
+
+import "strings"
+
+// SpendingQuery represents the criteria used to list the spending history
+// of a user, ordered by the date of the spendings.
+type SpendingQuery struct {
+
+	// UserId represents the unique identifier of the user whose spending
+	// history is listed.
+	UserId string
+
+	// Limit represents the maximum number of spendings in a page.
+	Limit int
+
+	// After represents the key of the last spending of the previous page.
+	// The listing starts from the beginning when it is nil.
+	After *SpendingKey
+
+	// From represents the earliest date of the listed spendings, stored in
+	// Unix time format. There is no lower bound when it is nil.
+	From *int64
+
+	// To represents the latest date of the listed spendings, stored in Unix
+	// time format. There is no upper bound when it is nil.
+	To *int64
+
+	// Category represents the category of the listed spendings. Spendings
+	// of any category are listed when it is empty.
+	Category string
+
+	// Type represents the type of the listed spendings. Spendings of both
+	// types are listed when it is empty.
+	Type string
+
+	// AccountId represents the unique identifier of the account of the
+	// listed spendings. Spendings of any account, or of none, are listed
+	// when it is empty.
+	AccountId string
+
+	// AnyTags represents the tags of which the listed spendings have at
+	// least one.
+	AnyTags []string
+
+	// AllTags represents the tags the listed spendings all have.
+	AllTags []string
+
+	// Currency represents the ISO 4217 code of the currency of the listed
+	// spendings. Spendings of any currency are listed when it is empty, and
+	// the amount filters are in the minor units of the currency.
+	Currency string
+
+	// MinAmount represents the minimum amount of the listed spendings in
+	// minor units.
+	MinAmount *int64
+
+	// MaxAmount represents the maximum amount of the listed spendings in
+	// minor units.
+	MaxAmount *int64
+
+	// Search represents a text the title or the description of the listed
+	// spendings must contain, regardless of case.
+	Search string
+
+	// Descending represents whether the spendings are listed from the latest
+	// date instead of the earliest.
+	Descending bool
+}
+
+// Filtered reports whether the query narrows down the spendings listed
+// other than by their date.
+func (query SpendingQuery) Filtered() bool {
+	return query.From != nil || query.Category != "" || query.Type != "" || query.AccountId != "" || query.Currency != "" ||
+		len(query.AnyTags) > 0 || len(query.AllTags) > 0 || query.MinAmount != nil || query.MaxAmount != nil || query.Search != ""
+}
+
+// MatchesSearch reports whether the title or the description of the
+// spending contains the Search text of the query, regardless of case.
+func (query SpendingQuery) MatchesSearch(spending Spending) bool {
+	search := strings.ToLower(query.Search)
+	return strings.Contains(strings.ToLower(spending.Title), search) || strings.Contains(strings.ToLower(spending.Description), search)
+}
+
+// SpendingKey represents the position of a spending in the spending
+// history of a user.
+type SpendingKey struct {
+	Id   string `json:"id"`
+	Date int64  `json:"date"`
+}
+
+// SpendingPage represents a page of the spending history of a user.
+type SpendingPage struct {
+
+	// Spendings represents the spendings in the page.
+	Spendings []Spending
+
+	// Next represents the key to continue the listing from. It is nil when
+	// there are no more spendings.
+	Next *SpendingKey
+}
@@ -0,0 +1,44 @@
+package domain
+
+// Transfer represents money moved from an account of a user into another
+// account of the user, such as a cash withdrawal from a bank account. It
+// debits the source account and credits the destination account at once.
+type Transfer struct {
+
+	// Id represents the unique identifier of the transfer. It is formatted
+	// as a UUID4.
+	Id string `dynamodbav:"Id"`
+
+	// UserId represents the unique identifier of the user who owns the
+	// accounts. It is formatted as a UUID4.
+	UserId string `dynamodbav:"UserId"`
+
+	// FromAccountId represents the unique identifier of the account the
+	// money is moved out of.
+	FromAccountId string `dynamodbav:"FromAccountId"`
+
+	// ToAccountId represents the unique identifier of the account the
+	// money is moved into.
+	ToAccountId string `dynamodbav:"ToAccountId"`
+
+	// Amount represents the amount debited from the source account, in the
+	// currency of the source account.
+	Amount Money `dynamodbav:"Amount"`
+
+	// ReceivedAmount represents the amount credited to the destination
+	// account, in the currency of the destination account. It equals the
+	// amount between accounts of the same currency.
+	ReceivedAmount Money `dynamodbav:"ReceivedAmount"`
+
+	// Description represents additional details or notes regarding the
+	// transfer.
+	Description string `dynamodbav:"Description"`
+
+	// Date represents the date when the money was moved, stored in Unix
+	// time format.
+	Date int64 `dynamodbav:"Date"`
+
+	// CreatedAt represents the date and time when the transfer was
+	// created, stored in Unix time format.
+	CreatedAt int64 `dynamodbav:"CreatedAt"`
+}
@@ -1,9 +1,22 @@
 package domain
 
 type User struct {
-	Id        string `dynamodbav:"Id"`
-	Name      string `dynamodbav:"Name"`
-	Email     string `dynamodbav:"Email"`
-	Password  string `dynamodbav:"Password"`
-	CreatedAt int64  `dynamodbav:"CreatedAt"`
+	Id           string `dynamodbav:"Id"`
+	Name         string `dynamodbav:"Name"`
+	Email        string `dynamodbav:"Email"`
+	Password     string `dynamodbav:"Password"`
+	TimeZone     string `dynamodbav:"TimeZone"`
+	Language     string `dynamodbav:"Language"`
+	HomeCurrency string `dynamodbav:"HomeCurrency"`
+	CreatedAt    int64  `dynamodbav:"CreatedAt"`
+}
+
+// Currency returns the ISO 4217 code of the home currency of the user, which
+// the spendings of the user are converted into. The users registered before
+// the home currency was stored have the default currency.
+func (user User) Currency() string {
+	if user.HomeCurrency == "" {
+		return DefaultCurrency
+	}
+	return user.HomeCurrency
 }
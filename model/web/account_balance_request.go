@@ -0,0 +1,8 @@
+package web
+
+type AccountBalanceRequest struct {
+	UserId    string `validate:"required" json:"user_id"`
+	AccountId string `validate:"required" json:"account_id"`
+	From      *int64 `validate:"" json:"from"`
+	To        *int64 `validate:"" json:"to"`
+}
@@ -0,0 +1,23 @@
+package web
+
+import "encoding/json"
+
+type AccountEntryResponse struct {
+	Id      string      `json:"id"`
+	Type    string      `json:"type"`
+	Title   string      `json:"title"`
+	Date    int64       `json:"date"`
+	Amount  json.Number `json:"amount"`
+	Balance json.Number `json:"balance"`
+}
+
+type AccountBalanceResponse struct {
+	AccountId      string                 `json:"account_id"`
+	Currency       string                 `json:"currency"`
+	OpeningBalance json.Number            `json:"opening_balance"`
+	From           *int64                 `json:"from,omitempty"`
+	To             int64                  `json:"to"`
+	StartBalance   json.Number            `json:"start_balance"`
+	Balance        json.Number            `json:"balance"`
+	Entries        []AccountEntryResponse `json:"entries"`
+}
@@ -0,0 +1,13 @@
+package web
+
+import "encoding/json"
+
+type AccountCreateRequest struct {
+	Id             string      `validate:"required,uuid4" json:"id"`
+	UserId         string      `validate:"required" json:"user_id"`
+	Name           string      `validate:"required,max=100" json:"name"`
+	Type           string      `validate:"required,oneof=cash bank ewallet other" json:"type"`
+	Currency       string      `validate:"omitempty,iso4217" json:"currency"`
+	OpeningBalance json.Number `validate:"" json:"opening_balance"`
+	CreatedAt      int64       `validate:"required" json:"created_at"`
+}
@@ -0,0 +1,13 @@
+package web
+
+import "encoding/json"
+
+type AccountResponse struct {
+	Id             string      `json:"id"`
+	UserId         string      `json:"user_id"`
+	Name           string      `json:"name"`
+	Type           string      `json:"type"`
+	Currency       string      `json:"currency"`
+	OpeningBalance json.Number `json:"opening_balance"`
+	CreatedAt      int64       `json:"created_at"`
+}
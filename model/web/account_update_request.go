@@ -0,0 +1,11 @@
+package web
+
+import "encoding/json"
+
+type AccountUpdateRequest struct {
+	Id             string      `validate:"required" json:"id"`
+	UserId         string      `validate:"required" json:"user_id"`
+	Name           string      `validate:"required,max=100" json:"name"`
+	Type           string      `validate:"required,oneof=cash bank ewallet other" json:"type"`
+	OpeningBalance json.Number `validate:"" json:"opening_balance"`
+}
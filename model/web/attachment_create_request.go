@@ -0,0 +1,8 @@
+package web
+
+type AttachmentCreateRequest struct {
+	Id         string `validate:"required,uuid4" json:"id"`
+	SpendingId string `validate:"required" json:"spending_id"`
+	FileName   string `validate:"required,max=255" json:"file_name"`
+	CreatedAt  int64  `validate:"required" json:"created_at"`
+}
@@ -0,0 +1,10 @@
+package web
+
+type AttachmentResponse struct {
+	Id          string `json:"id"`
+	SpendingId  string `json:"spending_id"`
+	FileName    string `json:"file_name"`
+	ContentType string `json:"content_type"`
+	Size        int64  `json:"size"`
+	CreatedAt   int64  `json:"created_at"`
+}
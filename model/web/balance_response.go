@@ -0,0 +1,26 @@
+package web
+
+import "encoding/json"
+
+type CashFlowResponse struct {
+	Income   json.Number `json:"income"`
+	Expenses json.Number `json:"expenses"`
+	Net      json.Number `json:"net"`
+}
+
+type BalancePeriodResponse struct {
+	Start int64 `json:"start"`
+	End   int64 `json:"end"`
+	CashFlowResponse
+}
+
+type BalanceResponse struct {
+	Period      string `json:"period"`
+	TimeZone    string `json:"time_zone"`
+	Currency    string `json:"currency"`
+	From        int64  `json:"from"`
+	To          int64  `json:"to"`
+	Unconverted int    `json:"unconverted"`
+	CashFlowResponse
+	Periods []BalancePeriodResponse `json:"periods"`
+}
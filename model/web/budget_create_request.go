@@ -0,0 +1,12 @@
+package web
+
+import "encoding/json"
+
+type BudgetCreateRequest struct {
+	Id        string      `validate:"required,uuid4" json:"id"`
+	UserId    string      `validate:"required" json:"user_id"`
+	Category  string      `validate:"required,lowercase" json:"category"`
+	Amount    json.Number `validate:"required" json:"amount"`
+	Period    string      `validate:"omitempty,oneof=day week month year" json:"period"`
+	CreatedAt int64       `validate:"required" json:"created_at"`
+}
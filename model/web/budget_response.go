@@ -0,0 +1,13 @@
+package web
+
+import "encoding/json"
+
+type BudgetResponse struct {
+	Id        string      `json:"id"`
+	UserId    string      `json:"user_id"`
+	Category  string      `json:"category"`
+	Amount    json.Number `json:"amount"`
+	Currency  string      `json:"currency"`
+	Period    string      `json:"period"`
+	CreatedAt int64       `json:"created_at"`
+}
@@ -0,0 +1,18 @@
+package web
+
+import "encoding/json"
+
+type BudgetStatusResponse struct {
+	BudgetId    string      `json:"budget_id"`
+	Category    string      `json:"category"`
+	Period      string      `json:"period"`
+	Start       int64       `json:"start"`
+	End         int64       `json:"end"`
+	Amount      json.Number `json:"amount"`
+	Spent       json.Number `json:"spent"`
+	Remaining   json.Number `json:"remaining"`
+	Percent     float64     `json:"percent"`
+	Overspent   bool        `json:"overspent"`
+	Currency    string      `json:"currency"`
+	Unconverted int         `json:"unconverted"`
+}
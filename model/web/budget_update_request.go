@@ -0,0 +1,11 @@
+package web
+
+import "encoding/json"
+
+type BudgetUpdateRequest struct {
+	Id       string      `validate:"required" json:"id"`
+	UserId   string      `validate:"required" json:"user_id"`
+	Category string      `validate:"required,lowercase" json:"category"`
+	Amount   json.Number `validate:"required" json:"amount"`
+	Period   string      `validate:"omitempty,oneof=day week month year" json:"period"`
+}
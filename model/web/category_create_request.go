@@ -0,0 +1,11 @@
+package web
+
+type CategoryCreateRequest struct {
+	Id        string `validate:"required,uuid4" json:"id"`
+	UserId    string `validate:"required" json:"user_id"`
+	Name      string `validate:"required,lowercase,max=50" json:"name"`
+	Color     string `validate:"omitempty,hexcolor" json:"color"`
+	Icon      string `validate:"max=50" json:"icon"`
+	ParentId  string `validate:"omitempty,uuid4" json:"parent_id"`
+	CreatedAt int64  `validate:"required" json:"created_at"`
+}
@@ -0,0 +1,7 @@
+package web
+
+type CategoryMergeRequest struct {
+	Id       string `validate:"required" json:"id"`
+	UserId   string `validate:"required" json:"user_id"`
+	TargetId string `validate:"required,nefield=Id" json:"target_id"`
+}
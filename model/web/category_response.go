@@ -0,0 +1,11 @@
+package web
+
+type CategoryResponse struct {
+	Id        string `json:"id"`
+	UserId    string `json:"user_id"`
+	Name      string `json:"name"`
+	Color     string `json:"color"`
+	Icon      string `json:"icon"`
+	ParentId  string `json:"parent_id"`
+	CreatedAt int64  `json:"created_at"`
+}
@@ -0,0 +1,10 @@
+package web
+
+type CategoryUpdateRequest struct {
+	Id       string `validate:"required" json:"id"`
+	UserId   string `validate:"required" json:"user_id"`
+	Name     string `validate:"required,lowercase,max=50" json:"name"`
+	Color    string `validate:"omitempty,hexcolor" json:"color"`
+	Icon     string `validate:"max=50" json:"icon"`
+	ParentId string `validate:"omitempty,uuid4" json:"parent_id"`
+}
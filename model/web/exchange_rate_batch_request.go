@@ -0,0 +1,5 @@
+package web
+
+type ExchangeRateBatchRequest struct {
+	Rates []ExchangeRateCreateRequest `validate:"required,min=1,max=1000,dive" json:"rates"`
+}
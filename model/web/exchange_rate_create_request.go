@@ -0,0 +1,10 @@
+package web
+
+import "encoding/json"
+
+type ExchangeRateCreateRequest struct {
+	Base          string      `validate:"required,iso4217" json:"base"`
+	Quote         string      `validate:"required,iso4217,nefield=Base" json:"quote"`
+	Rate          json.Number `validate:"required" json:"rate"`
+	EffectiveFrom string      `validate:"required" json:"effective_from"`
+}
@@ -0,0 +1,6 @@
+package web
+
+type ExchangeRateListRequest struct {
+	Base  string `validate:"required,iso4217" json:"base"`
+	Quote string `validate:"required,iso4217" json:"quote"`
+}
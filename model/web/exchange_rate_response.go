@@ -0,0 +1,11 @@
+package web
+
+import "encoding/json"
+
+type ExchangeRateResponse struct {
+	Base          string      `json:"base"`
+	Quote         string      `json:"quote"`
+	Rate          json.Number `json:"rate"`
+	EffectiveFrom int64       `json:"effective_from"`
+	CreatedAt     int64       `json:"created_at"`
+}
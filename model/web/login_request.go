@@ -0,0 +1,7 @@
+package web
+
+type LoginRequest struct {
+	Email     string `validate:"required,email" json:"email"`
+	Password  string `validate:"required" json:"password"`
+	UserAgent string `validate:"" json:"-"`
+}
@@ -0,0 +1,21 @@
+package web
+
+// ProblemDetails is the body of an error response, following RFC 7807
+// Problem Details for HTTP APIs.
+type ProblemDetails struct {
+	Type     string               `json:"type"`
+	Title    string               `json:"title"`
+	Status   int                  `json:"status"`
+	Detail   string               `json:"detail,omitempty"`
+	Instance string               `json:"instance,omitempty"`
+	Code     string               `json:"code"`
+	Errors   []FieldErrorResponse `json:"errors,omitempty"`
+}
+
+// FieldErrorResponse describes why a field of the request is rejected. The
+// field is named as in the JSON body or the query of the request.
+type FieldErrorResponse struct {
+	Field   string `json:"field"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
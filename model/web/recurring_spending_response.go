@@ -0,0 +1,22 @@
+package web
+
+import "encoding/json"
+
+type RecurringSpendingResponse struct {
+	Id             string      `json:"id"`
+	UserId         string      `json:"user_id"`
+	Title          string      `json:"title"`
+	Description    string      `json:"description"`
+	Amount         json.Number `json:"amount"`
+	Currency       string      `json:"currency"`
+	Category       string      `json:"category"`
+	Frequency      string      `json:"frequency"`
+	Interval       int         `json:"interval"`
+	ByDay          []string    `json:"by_day,omitempty"`
+	Start          int64       `json:"start"`
+	Until          int64       `json:"until,omitempty"`
+	Count          int         `json:"count,omitempty"`
+	LastOccurrence int64       `json:"last_occurrence,omitempty"`
+	NextOccurrence int64       `json:"next_occurrence,omitempty"`
+	CreatedAt      int64       `json:"created_at"`
+}
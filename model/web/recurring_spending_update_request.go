@@ -0,0 +1,19 @@
+package web
+
+import "encoding/json"
+
+type RecurringSpendingUpdateRequest struct {
+	Id          string      `validate:"required" json:"id"`
+	UserId      string      `validate:"required" json:"user_id"`
+	Title       string      `validate:"required,min=3" json:"title"`
+	Description string      `validate:"" json:"description"`
+	Amount      json.Number `validate:"required" json:"amount"`
+	Currency    string      `validate:"omitempty,iso4217" json:"currency"`
+	Category    string      `validate:"lowercase" json:"category"`
+	Frequency   string      `validate:"required,oneof=daily weekly monthly yearly" json:"frequency"`
+	Interval    int         `validate:"omitempty,gte=1,lte=1000" json:"interval"`
+	ByDay       []string    `validate:"omitempty,unique,dive,oneof=MO TU WE TH FR SA SU" json:"by_day"`
+	Start       int64       `validate:"required" json:"start"`
+	Until       int64       `validate:"omitempty,gtefield=Start" json:"until"`
+	Count       int         `validate:"omitempty,gte=1,lte=1000" json:"count"`
+}
@@ -0,0 +1,5 @@
+package web
+
+type RefreshTokenRequest struct {
+	RefreshToken string `validate:"required" json:"refresh_token"`
+}
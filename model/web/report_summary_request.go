@@ -0,0 +1,9 @@
+package web
+
+type ReportSummaryRequest struct {
+	UserId   string `validate:"required" json:"user_id"`
+	Period   string `validate:"oneof=day week month year" json:"period"`
+	From     string `validate:"" json:"from"`
+	To       string `validate:"" json:"to"`
+	TimeZone string `validate:"omitempty,timezone" json:"tz"`
+}
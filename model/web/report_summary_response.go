@@ -0,0 +1,35 @@
+package web
+
+import "encoding/json"
+
+type SpendingStatisticsResponse struct {
+	Total   json.Number `json:"total"`
+	Count   int         `json:"count"`
+	Average json.Number `json:"average"`
+	Min     json.Number `json:"min"`
+	Max     json.Number `json:"max"`
+}
+
+type ReportCategoryResponse struct {
+	Category string `json:"category"`
+	SpendingStatisticsResponse
+}
+
+type ReportPeriodResponse struct {
+	Start int64 `json:"start"`
+	End   int64 `json:"end"`
+	SpendingStatisticsResponse
+	Categories []ReportCategoryResponse `json:"categories"`
+}
+
+type ReportSummaryResponse struct {
+	Period      string `json:"period"`
+	TimeZone    string `json:"time_zone"`
+	Currency    string `json:"currency"`
+	From        int64  `json:"from"`
+	To          int64  `json:"to"`
+	Unconverted int    `json:"unconverted"`
+	SpendingStatisticsResponse
+	Periods    []ReportPeriodResponse   `json:"periods"`
+	Categories []ReportCategoryResponse `json:"categories"`
+}
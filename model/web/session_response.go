@@ -0,0 +1,9 @@
+package web
+
+type SessionResponse struct {
+	Id         string `json:"id"`
+	UserAgent  string `json:"user_agent"`
+	CreatedAt  int64  `json:"created_at"`
+	LastUsedAt int64  `json:"last_used_at"`
+	ExpiresAt  int64  `json:"expires_at"`
+}
@@ -1,12 +1,18 @@
 package web
 
+import "encoding/json"
+
 type SpendingCreateRequest struct {
-	Id          string  `validate:"required,uuid4" json:"id"`
-	UserId      string  `validate:"required,uuid4" json:"user_id"`
-	Title       string  `validate:"required,min=3" json:"title"`
-	Description string  `validate:"" json:"description"`
-	Amount      float64 `validate:"required,gte=0" json:"amount"`
-	Date        int64   `validate:"required" json:"date"`
-	Category    string  `validate:"lowercase" json:"category"`
-	CreatedAt   int64   `validate:"required" json:"created_at"`
+	Id          string      `validate:"required,uuid4" json:"id"`
+	UserId      string      `validate:"required,uuid4" json:"user_id"`
+	Title       string      `validate:"required,min=3" json:"title"`
+	Description string      `validate:"" json:"description"`
+	Amount      json.Number `validate:"required" json:"amount"`
+	Currency    string      `validate:"omitempty,iso4217" json:"currency"`
+	Date        int64       `validate:"required" json:"date"`
+	Category    string      `validate:"lowercase" json:"category"`
+	Type        string      `validate:"omitempty,oneof=expense income" json:"type"`
+	AccountId   string      `validate:"omitempty,uuid4" json:"account_id"`
+	Tags        []string    `validate:"max=20,dive,required,max=50,excludesall=0x2C" json:"tags"`
+	CreatedAt   int64       `validate:"required" json:"created_at"`
 }
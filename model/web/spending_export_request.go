@@ -0,0 +1,7 @@
+package web
+
+type SpendingExportRequest struct {
+	SpendingListRequest
+	Format   string `validate:"required,oneof=csv jsonl xlsx" json:"format"`
+	TimeZone string `validate:"" json:"tz"`
+}
@@ -0,0 +1,17 @@
+package web
+
+import "encoding/json"
+
+type SpendingExportResponse struct {
+	Id                string      `json:"id"`
+	Date              string      `json:"date"`
+	Title             string      `json:"title"`
+	Description       string      `json:"description"`
+	Category          string      `json:"category"`
+	Type              string      `json:"type"`
+	Amount            json.Number `json:"amount"`
+	Currency          string      `json:"currency"`
+	ConvertedAmount   json.Number `json:"converted_amount,omitempty"`
+	ConvertedCurrency string      `json:"converted_currency,omitempty"`
+	Status            string      `json:"status"`
+}
@@ -0,0 +1,15 @@
+package web
+
+type SpendingImportRequest struct {
+	UserId            string `validate:"required" json:"user_id"`
+	DryRun            bool   `validate:"" json:"dry_run"`
+	Delimiter         string `validate:"omitempty,len=1" json:"delimiter"`
+	DecimalSeparator  string `validate:"omitempty,oneof=. 0x2C" json:"decimal_separator"`
+	DateFormat        string `validate:"" json:"date_format"`
+	TitleColumn       string `validate:"" json:"title_column"`
+	AmountColumn      string `validate:"" json:"amount_column"`
+	CurrencyColumn    string `validate:"" json:"currency_column"`
+	DateColumn        string `validate:"" json:"date_column"`
+	CategoryColumn    string `validate:"" json:"category_column"`
+	DescriptionColumn string `validate:"" json:"description_column"`
+}
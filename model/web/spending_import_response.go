@@ -0,0 +1,18 @@
+package web
+
+type SpendingImportRowResponse struct {
+	Line   int                  `json:"line"`
+	Status string               `json:"status"`
+	Id     string               `json:"id,omitempty"`
+	Errors []FieldErrorResponse `json:"errors,omitempty"`
+}
+
+type SpendingImportResponse struct {
+	DryRun     bool                        `json:"dry_run"`
+	Total      int                         `json:"total"`
+	Valid      int                         `json:"valid"`
+	Created    int                         `json:"created"`
+	Duplicates int                         `json:"duplicates"`
+	Invalid    int                         `json:"invalid"`
+	Rows       []SpendingImportRowResponse `json:"rows"`
+}
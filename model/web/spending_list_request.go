@@ -0,0 +1,22 @@
+package web
+
+type SpendingListRequest struct {
+	UserId    string   `validate:"required" json:"user_id"`
+	Limit     int      `validate:"gte=1,lte=100" json:"limit"`
+	Cursor    string   `validate:"" json:"cursor"`
+	From      *int64   `validate:"" json:"from"`
+	To        *int64   `validate:"" json:"to"`
+	Category  string   `validate:"omitempty,lowercase" json:"category"`
+	Type      string   `validate:"omitempty,oneof=expense income" json:"type"`
+	AccountId string   `validate:"omitempty,uuid4" json:"account_id"`
+	Tags      []string `validate:"max=20" json:"tags"`
+	TagMatch  string   `validate:"omitempty,oneof=any all" json:"tag_match"`
+	Currency  string   `validate:"omitempty,iso4217" json:"currency"`
+	MinAmount *float64 `validate:"omitempty,gte=0" json:"min_amount"`
+	MaxAmount *float64 `validate:"omitempty,gte=0" json:"max_amount"`
+	Query     string   `validate:"" json:"q"`
+	Order     string   `validate:"omitempty,oneof=asc desc" json:"order"`
+
+	Status        string `validate:"omitempty,oneof=planned posted" json:"status"`
+	IncludeFuture bool   `validate:"" json:"include_future"`
+}
@@ -1,12 +1,21 @@
 package web
 
+import "encoding/json"
+
 type SpendingResponse struct {
-	Id          string  `json:"id"`
-	UserId      string  `json:"user_id"`
-	Title       string  `json:"title"`
-	Description string  `json:"description"`
-	Amount      float64 `json:"amount"`
-	Date        int64   `json:"date"`
-	Category    string  `json:"category"`
-	CreatedAt   int64   `json:"created_at"`
+	Id                string      `json:"id"`
+	UserId            string      `json:"user_id"`
+	Title             string      `json:"title"`
+	Description       string      `json:"description"`
+	Amount            json.Number `json:"amount"`
+	Currency          string      `json:"currency"`
+	ConvertedAmount   json.Number `json:"converted_amount,omitempty"`
+	ConvertedCurrency string      `json:"converted_currency,omitempty"`
+	Date              int64       `json:"date"`
+	Category          string      `json:"category"`
+	Type              string      `json:"type"`
+	AccountId         string      `json:"account_id,omitempty"`
+	Tags              []string    `json:"tags"`
+	Status            string      `json:"status"`
+	CreatedAt         int64       `json:"created_at"`
 }
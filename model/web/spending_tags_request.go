@@ -0,0 +1,8 @@
+package web
+
+type SpendingTagsRequest struct {
+	UserId      string   `validate:"required" json:"user_id"`
+	SpendingIds []string `validate:"required,min=1,max=100,dive,uuid4" json:"spending_ids"`
+	Add         []string `validate:"max=20,dive,required,max=50,excludesall=0x2C" json:"add"`
+	Remove      []string `validate:"max=20,dive,required" json:"remove"`
+}
@@ -0,0 +1,5 @@
+package web
+
+type SpendingTagsResponse struct {
+	Updated int `json:"updated"`
+}
@@ -1,10 +1,16 @@
 package web
 
+import "encoding/json"
+
 type SpendingUpdateRequest struct {
-	Id          string  `validate:"required,uuid4" json:"id"`
-	Title       string  `validate:"required,min=3" json:"title"`
-	Description string  `validate:"" json:"description"`
-	Amount      float64 `validate:"required,gte=0" json:"amount"`
-	Date        int64   `validate:"required" json:"date"`
-	Category    string  `validate:"lowercase" json:"category"`
+	Id          string      `validate:"required,uuid4" json:"id"`
+	Title       string      `validate:"required,min=3" json:"title"`
+	Description string      `validate:"" json:"description"`
+	Amount      json.Number `validate:"required" json:"amount"`
+	Currency    string      `validate:"omitempty,iso4217" json:"currency"`
+	Date        int64       `validate:"required" json:"date"`
+	Category    string      `validate:"lowercase" json:"category"`
+	Type        string      `validate:"omitempty,oneof=expense income" json:"type"`
+	AccountId   *string     `validate:"omitempty" json:"account_id"`
+	Tags        []string    `validate:"max=20,dive,required,max=50,excludesall=0x2C" json:"tags"`
 }
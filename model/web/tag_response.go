@@ -0,0 +1,6 @@
+package web
+
+type TagResponse struct {
+	Tag   string `json:"tag"`
+	Count int    `json:"count"`
+}
@@ -0,0 +1,15 @@
+package web
+
+import "encoding/json"
+
+type TransferCreateRequest struct {
+	Id             string      `validate:"required,uuid4" json:"id"`
+	UserId         string      `validate:"required" json:"user_id"`
+	FromAccountId  string      `validate:"required,uuid4" json:"from_account_id"`
+	ToAccountId    string      `validate:"required,uuid4,nefield=FromAccountId" json:"to_account_id"`
+	Amount         json.Number `validate:"required" json:"amount"`
+	ReceivedAmount json.Number `validate:"" json:"received_amount"`
+	Description    string      `validate:"" json:"description"`
+	Date           int64       `validate:"required" json:"date"`
+	CreatedAt      int64       `validate:"required" json:"created_at"`
+}
@@ -0,0 +1,17 @@
+package web
+
+import "encoding/json"
+
+type TransferResponse struct {
+	Id               string      `json:"id"`
+	UserId           string      `json:"user_id"`
+	FromAccountId    string      `json:"from_account_id"`
+	ToAccountId      string      `json:"to_account_id"`
+	Amount           json.Number `json:"amount"`
+	Currency         string      `json:"currency"`
+	ReceivedAmount   json.Number `json:"received_amount"`
+	ReceivedCurrency string      `json:"received_currency"`
+	Description      string      `json:"description"`
+	Date             int64       `json:"date"`
+	CreatedAt        int64       `json:"created_at"`
+}
@@ -1,9 +1,12 @@
 package web
 
 type UserCreateRequest struct {
-	Id        string `validate:"required,uuid4" json:"id"`
-	Name      string `validate:"required,min=3" json:"name"`
-	Email     string `validate:"required,email" json:"email"`
-	Password  string `validate:"required" json:"password"`
-	CreatedAt int64  `validate:"required" json:"created_at"`
+	Id           string `validate:"required,uuid4" json:"id"`
+	Name         string `validate:"required,min=3" json:"name"`
+	Email        string `validate:"required,email" json:"email"`
+	Password     string `validate:"required" json:"password"`
+	TimeZone     string `validate:"omitempty,timezone" json:"time_zone"`
+	Language     string `validate:"omitempty,oneof=en id" json:"language"`
+	HomeCurrency string `validate:"omitempty,iso4217" json:"home_currency"`
+	CreatedAt    int64  `validate:"required" json:"created_at"`
 }
@@ -1,8 +1,11 @@
 package web
 
 type UserResponse struct {
-	Id        string `json:"id"`
-	Name      string `json:"name"`
-	Email     string `json:"email"`
-	CreatedAt int64  `json:"created_at"`
+	Id           string `json:"id"`
+	Name         string `json:"name"`
+	Email        string `json:"email"`
+	TimeZone     string `json:"time_zone"`
+	Language     string `json:"language"`
+	HomeCurrency string `json:"home_currency"`
+	CreatedAt    int64  `json:"created_at"`
 }
@@ -1,8 +1,11 @@
 package web
 
 type UserUpdateRequest struct {
-	Id       string `validate:"required,uuid4" json:"id"`
-	Name     string `validate:"required,min=3" json:"name"`
-	Email    string `validate:"required,email" json:"email"`
-	Password string `json:"password"`
+	Id           string `validate:"required,uuid4" json:"id"`
+	Name         string `validate:"required,min=3" json:"name"`
+	Email        string `validate:"required,email" json:"email"`
+	Password     string `json:"password"`
+	TimeZone     string `validate:"omitempty,timezone" json:"time_zone"`
+	Language     string `validate:"omitempty,oneof=en id" json:"language"`
+	HomeCurrency string `validate:"omitempty,iso4217" json:"home_currency"`
 }
@@ -0,0 +1,14 @@
+package repository
+
+import (
+	"context"
+	"github.com/refandas/duit-api/model/domain"
+)
+
+type AccountRepository interface {
+	Save(ctx context.Context, account domain.Account) (domain.Account, error)
+	Update(ctx context.Context, account domain.Account) (domain.Account, error)
+	Delete(ctx context.Context, account domain.Account) error
+	FindById(ctx context.Context, accountId string) (domain.Account, error)
+	FindByUserId(ctx context.Context, userId string) ([]domain.Account, error)
+}
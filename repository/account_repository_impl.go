@@ -0,0 +1,137 @@
+package repository
+
+import (
+	"context"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/expression"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/refandas/duit-api/exception"
+	"github.com/refandas/duit-api/helper"
+	"github.com/refandas/duit-api/model/domain"
+)
+
+type AccountRepositoryImpl struct {
+	DB *helper.DynamoDB
+}
+
+func NewAccountRepository(db *helper.DynamoDB) AccountRepository {
+	return &AccountRepositoryImpl{DB: db}
+}
+
+func (repository *AccountRepositoryImpl) Save(ctx context.Context, account domain.Account) (domain.Account, error) {
+	item, err := attributevalue.MarshalMap(account)
+	if err != nil {
+		return domain.Account{}, err
+	}
+	_, err = repository.DB.Client.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName: aws.String(repository.DB.TableName),
+		Item:      item,
+	})
+	if err != nil {
+		return domain.Account{}, exception.NewUnavailableError(err)
+	}
+	return account, nil
+}
+
+func (repository *AccountRepositoryImpl) Update(ctx context.Context, account domain.Account) (domain.Account, error) {
+	accountId, err := attributevalue.Marshal(account.Id)
+	if err != nil {
+		return domain.Account{}, err
+	}
+
+	update := expression.Set(expression.Name("Name"), expression.Value(account.Name))
+	update.Set(expression.Name("Type"), expression.Value(account.Type))
+	update.Set(expression.Name("OpeningBalance"), expression.Value(account.OpeningBalance))
+
+	expr, err := expression.NewBuilder().WithUpdate(update).Build()
+	if err != nil {
+		return domain.Account{}, err
+	}
+
+	_, err = repository.DB.Client.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+		TableName:                 aws.String(repository.DB.TableName),
+		Key:                       map[string]types.AttributeValue{"Id": accountId},
+		ExpressionAttributeNames:  expr.Names(),
+		ExpressionAttributeValues: expr.Values(),
+		UpdateExpression:          expr.Update(),
+	})
+	if err != nil {
+		return domain.Account{}, exception.NewUnavailableError(err)
+	}
+	return account, nil
+}
+
+func (repository *AccountRepositoryImpl) Delete(ctx context.Context, account domain.Account) error {
+	accountId, err := attributevalue.Marshal(account.Id)
+	if err != nil {
+		return err
+	}
+	_, err = repository.DB.Client.DeleteItem(ctx, &dynamodb.DeleteItemInput{
+		TableName: aws.String(repository.DB.TableName),
+		Key:       map[string]types.AttributeValue{"Id": accountId},
+	})
+	if err != nil {
+		return exception.NewUnavailableError(err)
+	}
+	return nil
+}
+
+func (repository *AccountRepositoryImpl) FindById(ctx context.Context, accountId string) (domain.Account, error) {
+	account := domain.Account{Id: accountId}
+	id, err := attributevalue.Marshal(account.Id)
+	if err != nil {
+		return domain.Account{}, err
+	}
+
+	response, err := repository.DB.Client.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName: aws.String(repository.DB.TableName),
+		Key:       map[string]types.AttributeValue{"Id": id},
+	})
+	if err != nil {
+		return domain.Account{}, exception.NewUnavailableError(err)
+	}
+	if response.Item == nil {
+		return domain.Account{}, exception.NewNotFoundError("account not found")
+	}
+
+	err = attributevalue.UnmarshalMap(response.Item, &account)
+	if err != nil {
+		return domain.Account{}, err
+	}
+	return account, err
+}
+
+func (repository *AccountRepositoryImpl) FindByUserId(ctx context.Context, userId string) ([]domain.Account, error) {
+	var accounts []domain.Account
+
+	keyExpression := expression.Key("UserId").Equal(expression.Value(userId))
+	expr, err := expression.NewBuilder().WithKeyCondition(keyExpression).Build()
+	if err != nil {
+		return nil, err
+	}
+
+	paginator := dynamodb.NewQueryPaginator(repository.DB.Client, &dynamodb.QueryInput{
+		TableName:                 aws.String(repository.DB.TableName),
+		IndexName:                 aws.String("UserIndex"),
+		ExpressionAttributeNames:  expr.Names(),
+		ExpressionAttributeValues: expr.Values(),
+		KeyConditionExpression:    expr.KeyCondition(),
+		ScanIndexForward:          aws.Bool(true),
+	})
+	for paginator.HasMorePages() {
+		response, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, exception.NewUnavailableError(err)
+		}
+
+		var page []domain.Account
+		err = attributevalue.UnmarshalListOfMaps(response.Items, &page)
+		if err != nil {
+			return nil, err
+		}
+		accounts = append(accounts, page...)
+	}
+	return accounts, nil
+}
@@ -0,0 +1,78 @@
+package repository
+
+import (
+	"context"
+	"github.com/refandas/duit-api/exception"
+	"github.com/refandas/duit-api/model/domain"
+	"sort"
+	"sync"
+)
+
+// AccountRepositoryMemory is an AccountRepository keeping the accounts in
+// memory. It is safe for concurrent use.
+type AccountRepositoryMemory struct {
+	mutex    sync.RWMutex
+	accounts map[string]domain.Account
+}
+
+func NewAccountRepositoryMemory() AccountRepository {
+	return &AccountRepositoryMemory{accounts: map[string]domain.Account{}}
+}
+
+func (repository *AccountRepositoryMemory) Save(ctx context.Context, account domain.Account) (domain.Account, error) {
+	repository.mutex.Lock()
+	defer repository.mutex.Unlock()
+
+	repository.accounts[account.Id] = account
+	return account, nil
+}
+
+func (repository *AccountRepositoryMemory) Update(ctx context.Context, account domain.Account) (domain.Account, error) {
+	repository.mutex.Lock()
+	defer repository.mutex.Unlock()
+
+	stored, found := repository.accounts[account.Id]
+	if !found {
+		stored = domain.Account{Id: account.Id}
+	}
+	stored.Name = account.Name
+	stored.Type = account.Type
+	stored.OpeningBalance = account.OpeningBalance
+	repository.accounts[account.Id] = stored
+	return account, nil
+}
+
+func (repository *AccountRepositoryMemory) Delete(ctx context.Context, account domain.Account) error {
+	repository.mutex.Lock()
+	defer repository.mutex.Unlock()
+
+	delete(repository.accounts, account.Id)
+	return nil
+}
+
+func (repository *AccountRepositoryMemory) FindById(ctx context.Context, accountId string) (domain.Account, error) {
+	repository.mutex.RLock()
+	defer repository.mutex.RUnlock()
+
+	account, found := repository.accounts[accountId]
+	if !found {
+		return domain.Account{}, exception.NewNotFoundError("account not found")
+	}
+	return account, nil
+}
+
+func (repository *AccountRepositoryMemory) FindByUserId(ctx context.Context, userId string) ([]domain.Account, error) {
+	repository.mutex.RLock()
+	defer repository.mutex.RUnlock()
+
+	var accounts []domain.Account
+	for _, account := range repository.accounts {
+		if account.UserId == userId {
+			accounts = append(accounts, account)
+		}
+	}
+	sort.Slice(accounts, func(i, j int) bool {
+		return accounts[i].CreatedAt < accounts[j].CreatedAt
+	})
+	return accounts, nil
+}
@@ -0,0 +1,94 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"github.com/refandas/duit-api/exception"
+	"github.com/refandas/duit-api/model/domain"
+)
+
+// AccountRepositorySQL is an AccountRepository keeping the accounts in the
+// `accounts` table of a SQL database.
+type AccountRepositorySQL struct {
+	DB *sql.DB
+}
+
+func NewAccountRepositorySQL(db *sql.DB) AccountRepository {
+	return &AccountRepositorySQL{DB: db}
+}
+
+const accountColumns = "id, user_id, name, type, opening_balance_minor, currency, created_at"
+
+func (repository *AccountRepositorySQL) Save(ctx context.Context, account domain.Account) (domain.Account, error) {
+	_, err := repository.DB.ExecContext(ctx, `INSERT INTO accounts (`+accountColumns+`)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (id) DO UPDATE SET user_id = excluded.user_id, name = excluded.name, type = excluded.type,
+			opening_balance_minor = excluded.opening_balance_minor, currency = excluded.currency,
+			created_at = excluded.created_at`,
+		account.Id, account.UserId, account.Name, account.Type, account.OpeningBalance.Minor,
+		account.OpeningBalance.Currency, account.CreatedAt)
+	if err != nil {
+		return domain.Account{}, exception.NewUnavailableError(err)
+	}
+	return account, nil
+}
+
+func (repository *AccountRepositorySQL) Update(ctx context.Context, account domain.Account) (domain.Account, error) {
+	_, err := repository.DB.ExecContext(ctx, `UPDATE accounts
+		SET name = $2, type = $3, opening_balance_minor = $4, currency = $5
+		WHERE id = $1`,
+		account.Id, account.Name, account.Type, account.OpeningBalance.Minor, account.OpeningBalance.Currency)
+	if err != nil {
+		return domain.Account{}, exception.NewUnavailableError(err)
+	}
+	return account, nil
+}
+
+func (repository *AccountRepositorySQL) Delete(ctx context.Context, account domain.Account) error {
+	_, err := repository.DB.ExecContext(ctx, "DELETE FROM accounts WHERE id = $1", account.Id)
+	if err != nil {
+		return exception.NewUnavailableError(err)
+	}
+	return nil
+}
+
+func (repository *AccountRepositorySQL) FindById(ctx context.Context, accountId string) (domain.Account, error) {
+	row := repository.DB.QueryRowContext(ctx, "SELECT "+accountColumns+" FROM accounts WHERE id = $1", accountId)
+	account, err := scanAccount(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.Account{}, exception.NewNotFoundError("account not found")
+	}
+	if err != nil {
+		return domain.Account{}, exception.NewUnavailableError(err)
+	}
+	return account, nil
+}
+
+func (repository *AccountRepositorySQL) FindByUserId(ctx context.Context, userId string) ([]domain.Account, error) {
+	rows, err := repository.DB.QueryContext(ctx, "SELECT "+accountColumns+" FROM accounts WHERE user_id = $1 ORDER BY created_at", userId)
+	if err != nil {
+		return nil, exception.NewUnavailableError(err)
+	}
+	defer rows.Close()
+
+	var accounts []domain.Account
+	for rows.Next() {
+		account, err := scanAccount(rows)
+		if err != nil {
+			return nil, exception.NewUnavailableError(err)
+		}
+		accounts = append(accounts, account)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, exception.NewUnavailableError(err)
+	}
+	return accounts, nil
+}
+
+func scanAccount(row rowScanner) (domain.Account, error) {
+	account := domain.Account{}
+	err := row.Scan(&account.Id, &account.UserId, &account.Name, &account.Type, &account.OpeningBalance.Minor,
+		&account.OpeningBalance.Currency, &account.CreatedAt)
+	return account, err
+}
@@ -0,0 +1,13 @@
+package repository
+
+import (
+	"context"
+	"github.com/refandas/duit-api/model/domain"
+)
+
+type AttachmentRepository interface {
+	Save(ctx context.Context, attachment domain.Attachment) (domain.Attachment, error)
+	Delete(ctx context.Context, attachment domain.Attachment) error
+	FindById(ctx context.Context, attachmentId string) (domain.Attachment, error)
+	FindBySpendingId(ctx context.Context, spendingId string) ([]domain.Attachment, error)
+}
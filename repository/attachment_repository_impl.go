@@ -0,0 +1,109 @@
+package repository
+
+import (
+	"context"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/expression"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/refandas/duit-api/exception"
+	"github.com/refandas/duit-api/helper"
+	"github.com/refandas/duit-api/model/domain"
+)
+
+type AttachmentRepositoryImpl struct {
+	DB *helper.DynamoDB
+}
+
+func NewAttachmentRepository(db *helper.DynamoDB) AttachmentRepository {
+	return &AttachmentRepositoryImpl{DB: db}
+}
+
+func (repository *AttachmentRepositoryImpl) Save(ctx context.Context, attachment domain.Attachment) (domain.Attachment, error) {
+	item, err := attributevalue.MarshalMap(attachment)
+	if err != nil {
+		return domain.Attachment{}, err
+	}
+	_, err = repository.DB.Client.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName: aws.String(repository.DB.TableName),
+		Item:      item,
+	})
+	if err != nil {
+		return domain.Attachment{}, exception.NewUnavailableError(err)
+	}
+	return attachment, nil
+}
+
+func (repository *AttachmentRepositoryImpl) Delete(ctx context.Context, attachment domain.Attachment) error {
+	attachmentId, err := attributevalue.Marshal(attachment.Id)
+	if err != nil {
+		return err
+	}
+	_, err = repository.DB.Client.DeleteItem(ctx, &dynamodb.DeleteItemInput{
+		TableName: aws.String(repository.DB.TableName),
+		Key:       map[string]types.AttributeValue{"Id": attachmentId},
+	})
+	if err != nil {
+		return exception.NewUnavailableError(err)
+	}
+	return nil
+}
+
+func (repository *AttachmentRepositoryImpl) FindById(ctx context.Context, attachmentId string) (domain.Attachment, error) {
+	attachment := domain.Attachment{Id: attachmentId}
+	id, err := attributevalue.Marshal(attachment.Id)
+	if err != nil {
+		return domain.Attachment{}, err
+	}
+
+	response, err := repository.DB.Client.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName: aws.String(repository.DB.TableName),
+		Key:       map[string]types.AttributeValue{"Id": id},
+	})
+	if err != nil {
+		return domain.Attachment{}, exception.NewUnavailableError(err)
+	}
+	if response.Item == nil {
+		return domain.Attachment{}, exception.NewNotFoundError("attachment not found")
+	}
+
+	err = attributevalue.UnmarshalMap(response.Item, &attachment)
+	if err != nil {
+		return domain.Attachment{}, err
+	}
+	return attachment, err
+}
+
+func (repository *AttachmentRepositoryImpl) FindBySpendingId(ctx context.Context, spendingId string) ([]domain.Attachment, error) {
+	var attachments []domain.Attachment
+
+	keyExpression := expression.Key("SpendingId").Equal(expression.Value(spendingId))
+	expr, err := expression.NewBuilder().WithKeyCondition(keyExpression).Build()
+	if err != nil {
+		return nil, err
+	}
+
+	paginator := dynamodb.NewQueryPaginator(repository.DB.Client, &dynamodb.QueryInput{
+		TableName:                 aws.String(repository.DB.TableName),
+		IndexName:                 aws.String("SpendingIndex"),
+		ExpressionAttributeNames:  expr.Names(),
+		ExpressionAttributeValues: expr.Values(),
+		KeyConditionExpression:    expr.KeyCondition(),
+		ScanIndexForward:          aws.Bool(true),
+	})
+	for paginator.HasMorePages() {
+		response, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, exception.NewUnavailableError(err)
+		}
+
+		var page []domain.Attachment
+		err = attributevalue.UnmarshalListOfMaps(response.Items, &page)
+		if err != nil {
+			return nil, err
+		}
+		attachments = append(attachments, page...)
+	}
+	return attachments, nil
+}
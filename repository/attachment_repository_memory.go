@@ -0,0 +1,66 @@
+package repository
+
+import (
+	"context"
+	"github.com/refandas/duit-api/exception"
+	"github.com/refandas/duit-api/model/domain"
+	"sort"
+	"sync"
+)
+
+// AttachmentRepositoryMemory is an AttachmentRepository keeping the
+// attachments in memory. It is safe for concurrent use.
+type AttachmentRepositoryMemory struct {
+	mutex       sync.RWMutex
+	attachments map[string]domain.Attachment
+}
+
+func NewAttachmentRepositoryMemory() AttachmentRepository {
+	return &AttachmentRepositoryMemory{attachments: map[string]domain.Attachment{}}
+}
+
+func (repository *AttachmentRepositoryMemory) Save(ctx context.Context, attachment domain.Attachment) (domain.Attachment, error) {
+	repository.mutex.Lock()
+	defer repository.mutex.Unlock()
+
+	repository.attachments[attachment.Id] = attachment
+	return attachment, nil
+}
+
+func (repository *AttachmentRepositoryMemory) Delete(ctx context.Context, attachment domain.Attachment) error {
+	repository.mutex.Lock()
+	defer repository.mutex.Unlock()
+
+	delete(repository.attachments, attachment.Id)
+	return nil
+}
+
+func (repository *AttachmentRepositoryMemory) FindById(ctx context.Context, attachmentId string) (domain.Attachment, error) {
+	repository.mutex.RLock()
+	defer repository.mutex.RUnlock()
+
+	attachment, found := repository.attachments[attachmentId]
+	if !found {
+		return domain.Attachment{}, exception.NewNotFoundError("attachment not found")
+	}
+	return attachment, nil
+}
+
+func (repository *AttachmentRepositoryMemory) FindBySpendingId(ctx context.Context, spendingId string) ([]domain.Attachment, error) {
+	repository.mutex.RLock()
+	defer repository.mutex.RUnlock()
+
+	var attachments []domain.Attachment
+	for _, attachment := range repository.attachments {
+		if attachment.SpendingId == spendingId {
+			attachments = append(attachments, attachment)
+		}
+	}
+	sort.Slice(attachments, func(i, j int) bool {
+		if attachments[i].CreatedAt != attachments[j].CreatedAt {
+			return attachments[i].CreatedAt < attachments[j].CreatedAt
+		}
+		return attachments[i].Id < attachments[j].Id
+	})
+	return attachments, nil
+}
@@ -0,0 +1,83 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"github.com/refandas/duit-api/exception"
+	"github.com/refandas/duit-api/model/domain"
+)
+
+// AttachmentRepositorySQL is an AttachmentRepository keeping the
+// attachments in the `attachments` table of a SQL database.
+type AttachmentRepositorySQL struct {
+	DB *sql.DB
+}
+
+func NewAttachmentRepositorySQL(db *sql.DB) AttachmentRepository {
+	return &AttachmentRepositorySQL{DB: db}
+}
+
+const attachmentColumns = "id, user_id, spending_id, file_name, content_type, size, created_at"
+
+func (repository *AttachmentRepositorySQL) Save(ctx context.Context, attachment domain.Attachment) (domain.Attachment, error) {
+	_, err := repository.DB.ExecContext(ctx, `INSERT INTO attachments (`+attachmentColumns+`)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (id) DO UPDATE SET user_id = excluded.user_id, spending_id = excluded.spending_id,
+			file_name = excluded.file_name, content_type = excluded.content_type, size = excluded.size,
+			created_at = excluded.created_at`,
+		attachment.Id, attachment.UserId, attachment.SpendingId, attachment.FileName, attachment.ContentType,
+		attachment.Size, attachment.CreatedAt)
+	if err != nil {
+		return domain.Attachment{}, exception.NewUnavailableError(err)
+	}
+	return attachment, nil
+}
+
+func (repository *AttachmentRepositorySQL) Delete(ctx context.Context, attachment domain.Attachment) error {
+	_, err := repository.DB.ExecContext(ctx, "DELETE FROM attachments WHERE id = $1", attachment.Id)
+	if err != nil {
+		return exception.NewUnavailableError(err)
+	}
+	return nil
+}
+
+func (repository *AttachmentRepositorySQL) FindById(ctx context.Context, attachmentId string) (domain.Attachment, error) {
+	row := repository.DB.QueryRowContext(ctx, "SELECT "+attachmentColumns+" FROM attachments WHERE id = $1", attachmentId)
+	attachment, err := scanAttachment(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.Attachment{}, exception.NewNotFoundError("attachment not found")
+	}
+	if err != nil {
+		return domain.Attachment{}, exception.NewUnavailableError(err)
+	}
+	return attachment, nil
+}
+
+func (repository *AttachmentRepositorySQL) FindBySpendingId(ctx context.Context, spendingId string) ([]domain.Attachment, error) {
+	rows, err := repository.DB.QueryContext(ctx, "SELECT "+attachmentColumns+" FROM attachments WHERE spending_id = $1 ORDER BY created_at, id", spendingId)
+	if err != nil {
+		return nil, exception.NewUnavailableError(err)
+	}
+	defer rows.Close()
+
+	var attachments []domain.Attachment
+	for rows.Next() {
+		attachment, err := scanAttachment(rows)
+		if err != nil {
+			return nil, exception.NewUnavailableError(err)
+		}
+		attachments = append(attachments, attachment)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, exception.NewUnavailableError(err)
+	}
+	return attachments, nil
+}
+
+func scanAttachment(row rowScanner) (domain.Attachment, error) {
+	attachment := domain.Attachment{}
+	err := row.Scan(&attachment.Id, &attachment.UserId, &attachment.SpendingId, &attachment.FileName,
+		&attachment.ContentType, &attachment.Size, &attachment.CreatedAt)
+	return attachment, err
+}
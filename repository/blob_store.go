@@ -0,0 +1,24 @@
+package repository
+
+import (
+	"context"
+	"io"
+)
+
+// BlobStore stores the content of files, such as the files attached to the
+// spendings, by key. A key is a slash separated path, like `userId/id`.
+type BlobStore interface {
+
+	// Put stores the content read from the reader under the key, replacing
+	// the content stored under it before.
+	Put(ctx context.Context, key string, reader io.Reader) error
+
+	// Get returns a reader of the content stored under the key, which must
+	// be closed by the caller. It returns a not found error when nothing is
+	// stored under the key.
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+
+	// Delete removes the content stored under the key. It does nothing when
+	// nothing is stored under it.
+	Delete(ctx context.Context, key string) error
+}
@@ -0,0 +1,89 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/refandas/duit-api/exception"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// BlobStoreLocal is a BlobStore keeping the content of every key in a file
+// under a directory of the local filesystem.
+type BlobStoreLocal struct {
+	Dir string
+}
+
+func NewBlobStoreLocal(dir string) BlobStore {
+	return &BlobStoreLocal{Dir: dir}
+}
+
+// path returns the path of the file of the key, refusing the keys that
+// would escape the directory of the store.
+func (store *BlobStoreLocal) path(key string) (string, error) {
+	name := filepath.FromSlash(key)
+	if !filepath.IsLocal(name) {
+		return "", fmt.Errorf("invalid blob key %q", key)
+	}
+	return filepath.Join(store.Dir, name), nil
+}
+
+func (store *BlobStoreLocal) Put(ctx context.Context, key string, reader io.Reader) error {
+	path, err := store.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		return exception.NewUnavailableError(err)
+	}
+
+	// The content is written to a temporary file first, so a failed upload
+	// never leaves a partial file under the key.
+	file, err := os.CreateTemp(filepath.Dir(path), ".upload-*")
+	if err != nil {
+		return exception.NewUnavailableError(err)
+	}
+	defer os.Remove(file.Name())
+
+	_, err = io.Copy(file, reader)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+	if err := os.Rename(file.Name(), path); err != nil {
+		return exception.NewUnavailableError(err)
+	}
+	return nil
+}
+
+func (store *BlobStoreLocal) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	path, err := store.path(key)
+	if err != nil {
+		return nil, err
+	}
+	file, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, exception.NewNotFoundError("attachment not found")
+	}
+	if err != nil {
+		return nil, exception.NewUnavailableError(err)
+	}
+	return file, nil
+}
+
+func (store *BlobStoreLocal) Delete(ctx context.Context, key string) error {
+	path, err := store.path(key)
+	if err != nil {
+		return err
+	}
+	err = os.Remove(path)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return exception.NewUnavailableError(err)
+	}
+	return nil
+}
@@ -0,0 +1,14 @@
+package repository
+
+import (
+	"context"
+	"github.com/refandas/duit-api/model/domain"
+)
+
+type BudgetRepository interface {
+	Save(ctx context.Context, budget domain.Budget) (domain.Budget, error)
+	Update(ctx context.Context, budget domain.Budget) (domain.Budget, error)
+	Delete(ctx context.Context, budget domain.Budget) error
+	FindById(ctx context.Context, budgetId string) (domain.Budget, error)
+	FindByUserId(ctx context.Context, userId string) ([]domain.Budget, error)
+}
@@ -0,0 +1,137 @@
+package repository
+
+import (
+	"context"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/expression"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/refandas/duit-api/exception"
+	"github.com/refandas/duit-api/helper"
+	"github.com/refandas/duit-api/model/domain"
+)
+
+type BudgetRepositoryImpl struct {
+	DB *helper.DynamoDB
+}
+
+func NewBudgetRepository(db *helper.DynamoDB) BudgetRepository {
+	return &BudgetRepositoryImpl{DB: db}
+}
+
+func (repository *BudgetRepositoryImpl) Save(ctx context.Context, budget domain.Budget) (domain.Budget, error) {
+	item, err := attributevalue.MarshalMap(budget)
+	if err != nil {
+		return domain.Budget{}, err
+	}
+	_, err = repository.DB.Client.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName: aws.String(repository.DB.TableName),
+		Item:      item,
+	})
+	if err != nil {
+		return domain.Budget{}, exception.NewUnavailableError(err)
+	}
+	return budget, nil
+}
+
+func (repository *BudgetRepositoryImpl) Update(ctx context.Context, budget domain.Budget) (domain.Budget, error) {
+	budgetId, err := attributevalue.Marshal(budget.Id)
+	if err != nil {
+		return domain.Budget{}, err
+	}
+
+	update := expression.Set(expression.Name("Category"), expression.Value(budget.Category))
+	update.Set(expression.Name("Amount"), expression.Value(budget.Amount))
+	update.Set(expression.Name("Period"), expression.Value(budget.Period))
+
+	expr, err := expression.NewBuilder().WithUpdate(update).Build()
+	if err != nil {
+		return domain.Budget{}, err
+	}
+
+	_, err = repository.DB.Client.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+		TableName:                 aws.String(repository.DB.TableName),
+		Key:                       map[string]types.AttributeValue{"Id": budgetId},
+		ExpressionAttributeNames:  expr.Names(),
+		ExpressionAttributeValues: expr.Values(),
+		UpdateExpression:          expr.Update(),
+	})
+	if err != nil {
+		return domain.Budget{}, exception.NewUnavailableError(err)
+	}
+	return budget, nil
+}
+
+func (repository *BudgetRepositoryImpl) Delete(ctx context.Context, budget domain.Budget) error {
+	budgetId, err := attributevalue.Marshal(budget.Id)
+	if err != nil {
+		return err
+	}
+	_, err = repository.DB.Client.DeleteItem(ctx, &dynamodb.DeleteItemInput{
+		TableName: aws.String(repository.DB.TableName),
+		Key:       map[string]types.AttributeValue{"Id": budgetId},
+	})
+	if err != nil {
+		return exception.NewUnavailableError(err)
+	}
+	return nil
+}
+
+func (repository *BudgetRepositoryImpl) FindById(ctx context.Context, budgetId string) (domain.Budget, error) {
+	budget := domain.Budget{Id: budgetId}
+	id, err := attributevalue.Marshal(budget.Id)
+	if err != nil {
+		return domain.Budget{}, err
+	}
+
+	response, err := repository.DB.Client.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName: aws.String(repository.DB.TableName),
+		Key:       map[string]types.AttributeValue{"Id": id},
+	})
+	if err != nil {
+		return domain.Budget{}, exception.NewUnavailableError(err)
+	}
+	if response.Item == nil {
+		return domain.Budget{}, exception.NewNotFoundError("budget not found")
+	}
+
+	err = attributevalue.UnmarshalMap(response.Item, &budget)
+	if err != nil {
+		return domain.Budget{}, err
+	}
+	return budget, err
+}
+
+func (repository *BudgetRepositoryImpl) FindByUserId(ctx context.Context, userId string) ([]domain.Budget, error) {
+	var budgets []domain.Budget
+
+	keyExpression := expression.Key("UserId").Equal(expression.Value(userId))
+	expr, err := expression.NewBuilder().WithKeyCondition(keyExpression).Build()
+	if err != nil {
+		return nil, err
+	}
+
+	paginator := dynamodb.NewQueryPaginator(repository.DB.Client, &dynamodb.QueryInput{
+		TableName:                 aws.String(repository.DB.TableName),
+		IndexName:                 aws.String("UserIndex"),
+		ExpressionAttributeNames:  expr.Names(),
+		ExpressionAttributeValues: expr.Values(),
+		KeyConditionExpression:    expr.KeyCondition(),
+		ScanIndexForward:          aws.Bool(true),
+	})
+	for paginator.HasMorePages() {
+		response, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, exception.NewUnavailableError(err)
+		}
+
+		var page []domain.Budget
+		err = attributevalue.UnmarshalListOfMaps(response.Items, &page)
+		if err != nil {
+			return nil, err
+		}
+		budgets = append(budgets, page...)
+	}
+	return budgets, nil
+}
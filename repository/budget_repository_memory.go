@@ -0,0 +1,78 @@
+package repository
+
+import (
+	"context"
+	"github.com/refandas/duit-api/exception"
+	"github.com/refandas/duit-api/model/domain"
+	"sort"
+	"sync"
+)
+
+// BudgetRepositoryMemory is a BudgetRepository keeping the budgets in
+// memory. It is safe for concurrent use.
+type BudgetRepositoryMemory struct {
+	mutex   sync.RWMutex
+	budgets map[string]domain.Budget
+}
+
+func NewBudgetRepositoryMemory() BudgetRepository {
+	return &BudgetRepositoryMemory{budgets: map[string]domain.Budget{}}
+}
+
+func (repository *BudgetRepositoryMemory) Save(ctx context.Context, budget domain.Budget) (domain.Budget, error) {
+	repository.mutex.Lock()
+	defer repository.mutex.Unlock()
+
+	repository.budgets[budget.Id] = budget
+	return budget, nil
+}
+
+func (repository *BudgetRepositoryMemory) Update(ctx context.Context, budget domain.Budget) (domain.Budget, error) {
+	repository.mutex.Lock()
+	defer repository.mutex.Unlock()
+
+	stored, found := repository.budgets[budget.Id]
+	if !found {
+		stored = domain.Budget{Id: budget.Id}
+	}
+	stored.Category = budget.Category
+	stored.Amount = budget.Amount
+	stored.Period = budget.Period
+	repository.budgets[budget.Id] = stored
+	return budget, nil
+}
+
+func (repository *BudgetRepositoryMemory) Delete(ctx context.Context, budget domain.Budget) error {
+	repository.mutex.Lock()
+	defer repository.mutex.Unlock()
+
+	delete(repository.budgets, budget.Id)
+	return nil
+}
+
+func (repository *BudgetRepositoryMemory) FindById(ctx context.Context, budgetId string) (domain.Budget, error) {
+	repository.mutex.RLock()
+	defer repository.mutex.RUnlock()
+
+	budget, found := repository.budgets[budgetId]
+	if !found {
+		return domain.Budget{}, exception.NewNotFoundError("budget not found")
+	}
+	return budget, nil
+}
+
+func (repository *BudgetRepositoryMemory) FindByUserId(ctx context.Context, userId string) ([]domain.Budget, error) {
+	repository.mutex.RLock()
+	defer repository.mutex.RUnlock()
+
+	var budgets []domain.Budget
+	for _, budget := range repository.budgets {
+		if budget.UserId == userId {
+			budgets = append(budgets, budget)
+		}
+	}
+	sort.Slice(budgets, func(i, j int) bool {
+		return budgets[i].CreatedAt < budgets[j].CreatedAt
+	})
+	return budgets, nil
+}
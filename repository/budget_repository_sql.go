@@ -0,0 +1,90 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"github.com/refandas/duit-api/exception"
+	"github.com/refandas/duit-api/model/domain"
+)
+
+// BudgetRepositorySQL is a BudgetRepository keeping the budgets in the
+// `budgets` table of a SQL database.
+type BudgetRepositorySQL struct {
+	DB *sql.DB
+}
+
+func NewBudgetRepositorySQL(db *sql.DB) BudgetRepository {
+	return &BudgetRepositorySQL{DB: db}
+}
+
+const budgetColumns = "id, user_id, category, amount_minor, currency, period, created_at"
+
+func (repository *BudgetRepositorySQL) Save(ctx context.Context, budget domain.Budget) (domain.Budget, error) {
+	_, err := repository.DB.ExecContext(ctx, `INSERT INTO budgets (`+budgetColumns+`)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (id) DO UPDATE SET user_id = excluded.user_id, category = excluded.category,
+			amount_minor = excluded.amount_minor, currency = excluded.currency, period = excluded.period,
+			created_at = excluded.created_at`,
+		budget.Id, budget.UserId, budget.Category, budget.Amount.Minor, budget.Amount.Currency, budget.Period, budget.CreatedAt)
+	if err != nil {
+		return domain.Budget{}, exception.NewUnavailableError(err)
+	}
+	return budget, nil
+}
+
+func (repository *BudgetRepositorySQL) Update(ctx context.Context, budget domain.Budget) (domain.Budget, error) {
+	_, err := repository.DB.ExecContext(ctx, "UPDATE budgets SET category = $2, amount_minor = $3, currency = $4, period = $5 WHERE id = $1",
+		budget.Id, budget.Category, budget.Amount.Minor, budget.Amount.Currency, budget.Period)
+	if err != nil {
+		return domain.Budget{}, exception.NewUnavailableError(err)
+	}
+	return budget, nil
+}
+
+func (repository *BudgetRepositorySQL) Delete(ctx context.Context, budget domain.Budget) error {
+	_, err := repository.DB.ExecContext(ctx, "DELETE FROM budgets WHERE id = $1", budget.Id)
+	if err != nil {
+		return exception.NewUnavailableError(err)
+	}
+	return nil
+}
+
+func (repository *BudgetRepositorySQL) FindById(ctx context.Context, budgetId string) (domain.Budget, error) {
+	row := repository.DB.QueryRowContext(ctx, "SELECT "+budgetColumns+" FROM budgets WHERE id = $1", budgetId)
+	budget, err := scanBudget(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.Budget{}, exception.NewNotFoundError("budget not found")
+	}
+	if err != nil {
+		return domain.Budget{}, exception.NewUnavailableError(err)
+	}
+	return budget, nil
+}
+
+func (repository *BudgetRepositorySQL) FindByUserId(ctx context.Context, userId string) ([]domain.Budget, error) {
+	rows, err := repository.DB.QueryContext(ctx, "SELECT "+budgetColumns+" FROM budgets WHERE user_id = $1 ORDER BY created_at", userId)
+	if err != nil {
+		return nil, exception.NewUnavailableError(err)
+	}
+	defer rows.Close()
+
+	var budgets []domain.Budget
+	for rows.Next() {
+		budget, err := scanBudget(rows)
+		if err != nil {
+			return nil, exception.NewUnavailableError(err)
+		}
+		budgets = append(budgets, budget)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, exception.NewUnavailableError(err)
+	}
+	return budgets, nil
+}
+
+func scanBudget(row rowScanner) (domain.Budget, error) {
+	budget := domain.Budget{}
+	err := row.Scan(&budget.Id, &budget.UserId, &budget.Category, &budget.Amount.Minor, &budget.Amount.Currency, &budget.Period, &budget.CreatedAt)
+	return budget, err
+}
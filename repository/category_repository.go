@@ -0,0 +1,15 @@
+package repository
+
+import (
+	"context"
+	"github.com/refandas/duit-api/model/domain"
+)
+
+type CategoryRepository interface {
+	Save(ctx context.Context, category domain.Category) (domain.Category, error)
+	Update(ctx context.Context, category domain.Category) (domain.Category, error)
+	Delete(ctx context.Context, category domain.Category) error
+	FindById(ctx context.Context, categoryId string) (domain.Category, error)
+	FindByName(ctx context.Context, userId string, name string) (domain.Category, bool, error)
+	FindByUserId(ctx context.Context, userId string) ([]domain.Category, error)
+}
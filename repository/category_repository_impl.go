@@ -0,0 +1,171 @@
+package repository
+
+import (
+	"context"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/expression"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/refandas/duit-api/exception"
+	"github.com/refandas/duit-api/helper"
+	"github.com/refandas/duit-api/model/domain"
+)
+
+type CategoryRepositoryImpl struct {
+	DB *helper.DynamoDB
+}
+
+func NewCategoryRepository(db *helper.DynamoDB) CategoryRepository {
+	return &CategoryRepositoryImpl{DB: db}
+}
+
+func (repository *CategoryRepositoryImpl) Save(ctx context.Context, category domain.Category) (domain.Category, error) {
+	item, err := attributevalue.MarshalMap(category)
+	if err != nil {
+		return domain.Category{}, err
+	}
+	_, err = repository.DB.Client.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName: aws.String(repository.DB.TableName),
+		Item:      item,
+	})
+	if err != nil {
+		return domain.Category{}, exception.NewUnavailableError(err)
+	}
+	return category, nil
+}
+
+func (repository *CategoryRepositoryImpl) Update(ctx context.Context, category domain.Category) (domain.Category, error) {
+	categoryId, err := attributevalue.Marshal(category.Id)
+	if err != nil {
+		return domain.Category{}, err
+	}
+
+	update := expression.Set(expression.Name("Name"), expression.Value(category.Name))
+	update.Set(expression.Name("Color"), expression.Value(category.Color))
+	update.Set(expression.Name("Icon"), expression.Value(category.Icon))
+	update.Set(expression.Name("ParentId"), expression.Value(category.ParentId))
+
+	expr, err := expression.NewBuilder().WithUpdate(update).Build()
+	if err != nil {
+		return domain.Category{}, err
+	}
+
+	_, err = repository.DB.Client.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+		TableName:                 aws.String(repository.DB.TableName),
+		Key:                       map[string]types.AttributeValue{"Id": categoryId},
+		ExpressionAttributeNames:  expr.Names(),
+		ExpressionAttributeValues: expr.Values(),
+		UpdateExpression:          expr.Update(),
+	})
+	if err != nil {
+		return domain.Category{}, exception.NewUnavailableError(err)
+	}
+	return category, nil
+}
+
+func (repository *CategoryRepositoryImpl) Delete(ctx context.Context, category domain.Category) error {
+	categoryId, err := attributevalue.Marshal(category.Id)
+	if err != nil {
+		return err
+	}
+	_, err = repository.DB.Client.DeleteItem(ctx, &dynamodb.DeleteItemInput{
+		TableName: aws.String(repository.DB.TableName),
+		Key:       map[string]types.AttributeValue{"Id": categoryId},
+	})
+	if err != nil {
+		return exception.NewUnavailableError(err)
+	}
+	return nil
+}
+
+func (repository *CategoryRepositoryImpl) FindById(ctx context.Context, categoryId string) (domain.Category, error) {
+	category := domain.Category{Id: categoryId}
+	id, err := attributevalue.Marshal(category.Id)
+	if err != nil {
+		return domain.Category{}, err
+	}
+
+	response, err := repository.DB.Client.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName: aws.String(repository.DB.TableName),
+		Key:       map[string]types.AttributeValue{"Id": id},
+	})
+	if err != nil {
+		return domain.Category{}, exception.NewUnavailableError(err)
+	}
+	if response.Item == nil {
+		return domain.Category{}, exception.NewNotFoundError("category not found")
+	}
+
+	err = attributevalue.UnmarshalMap(response.Item, &category)
+	if err != nil {
+		return domain.Category{}, err
+	}
+	return category, err
+}
+
+// FindByName looks up the category of the user with the given name using
+// the `UserIndex` GSI.
+func (repository *CategoryRepositoryImpl) FindByName(ctx context.Context, userId string, name string) (domain.Category, bool, error) {
+	keyExpression := expression.Key("UserId").Equal(expression.Value(userId)).
+		And(expression.Key("Name").Equal(expression.Value(name)))
+	expr, err := expression.NewBuilder().WithKeyCondition(keyExpression).Build()
+	if err != nil {
+		return domain.Category{}, false, err
+	}
+
+	response, err := repository.DB.Client.Query(ctx, &dynamodb.QueryInput{
+		TableName:                 aws.String(repository.DB.TableName),
+		IndexName:                 aws.String("UserIndex"),
+		ExpressionAttributeNames:  expr.Names(),
+		ExpressionAttributeValues: expr.Values(),
+		KeyConditionExpression:    expr.KeyCondition(),
+		Limit:                     aws.Int32(1),
+	})
+	if err != nil {
+		return domain.Category{}, false, exception.NewUnavailableError(err)
+	}
+	if len(response.Items) == 0 {
+		return domain.Category{}, false, nil
+	}
+
+	category := domain.Category{}
+	err = attributevalue.UnmarshalMap(response.Items[0], &category)
+	if err != nil {
+		return domain.Category{}, false, err
+	}
+	return category, true, nil
+}
+
+func (repository *CategoryRepositoryImpl) FindByUserId(ctx context.Context, userId string) ([]domain.Category, error) {
+	var categorys []domain.Category
+
+	keyExpression := expression.Key("UserId").Equal(expression.Value(userId))
+	expr, err := expression.NewBuilder().WithKeyCondition(keyExpression).Build()
+	if err != nil {
+		return nil, err
+	}
+
+	paginator := dynamodb.NewQueryPaginator(repository.DB.Client, &dynamodb.QueryInput{
+		TableName:                 aws.String(repository.DB.TableName),
+		IndexName:                 aws.String("UserIndex"),
+		ExpressionAttributeNames:  expr.Names(),
+		ExpressionAttributeValues: expr.Values(),
+		KeyConditionExpression:    expr.KeyCondition(),
+		ScanIndexForward:          aws.Bool(true),
+	})
+	for paginator.HasMorePages() {
+		response, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, exception.NewUnavailableError(err)
+		}
+
+		var page []domain.Category
+		err = attributevalue.UnmarshalListOfMaps(response.Items, &page)
+		if err != nil {
+			return nil, err
+		}
+		categorys = append(categorys, page...)
+	}
+	return categorys, nil
+}
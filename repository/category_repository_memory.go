@@ -0,0 +1,91 @@
+package repository
+
+import (
+	"context"
+	"github.com/refandas/duit-api/exception"
+	"github.com/refandas/duit-api/model/domain"
+	"sort"
+	"sync"
+)
+
+// CategoryRepositoryMemory is a CategoryRepository keeping the categories
+// in memory. It is safe for concurrent use.
+type CategoryRepositoryMemory struct {
+	mutex      sync.RWMutex
+	categories map[string]domain.Category
+}
+
+func NewCategoryRepositoryMemory() CategoryRepository {
+	return &CategoryRepositoryMemory{categories: map[string]domain.Category{}}
+}
+
+func (repository *CategoryRepositoryMemory) Save(ctx context.Context, category domain.Category) (domain.Category, error) {
+	repository.mutex.Lock()
+	defer repository.mutex.Unlock()
+
+	repository.categories[category.Id] = category
+	return category, nil
+}
+
+func (repository *CategoryRepositoryMemory) Update(ctx context.Context, category domain.Category) (domain.Category, error) {
+	repository.mutex.Lock()
+	defer repository.mutex.Unlock()
+
+	stored, found := repository.categories[category.Id]
+	if !found {
+		stored = domain.Category{Id: category.Id}
+	}
+	stored.Name = category.Name
+	stored.Color = category.Color
+	stored.Icon = category.Icon
+	stored.ParentId = category.ParentId
+	repository.categories[category.Id] = stored
+	return category, nil
+}
+
+func (repository *CategoryRepositoryMemory) Delete(ctx context.Context, category domain.Category) error {
+	repository.mutex.Lock()
+	defer repository.mutex.Unlock()
+
+	delete(repository.categories, category.Id)
+	return nil
+}
+
+func (repository *CategoryRepositoryMemory) FindById(ctx context.Context, categoryId string) (domain.Category, error) {
+	repository.mutex.RLock()
+	defer repository.mutex.RUnlock()
+
+	category, found := repository.categories[categoryId]
+	if !found {
+		return domain.Category{}, exception.NewNotFoundError("category not found")
+	}
+	return category, nil
+}
+
+func (repository *CategoryRepositoryMemory) FindByName(ctx context.Context, userId string, name string) (domain.Category, bool, error) {
+	repository.mutex.RLock()
+	defer repository.mutex.RUnlock()
+
+	for _, category := range repository.categories {
+		if category.UserId == userId && category.Name == name {
+			return category, true, nil
+		}
+	}
+	return domain.Category{}, false, nil
+}
+
+func (repository *CategoryRepositoryMemory) FindByUserId(ctx context.Context, userId string) ([]domain.Category, error) {
+	repository.mutex.RLock()
+	defer repository.mutex.RUnlock()
+
+	var categories []domain.Category
+	for _, category := range repository.categories {
+		if category.UserId == userId {
+			categories = append(categories, category)
+		}
+	}
+	sort.Slice(categories, func(i, j int) bool {
+		return categories[i].Name < categories[j].Name
+	})
+	return categories, nil
+}
@@ -0,0 +1,103 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"github.com/refandas/duit-api/exception"
+	"github.com/refandas/duit-api/model/domain"
+)
+
+// CategoryRepositorySQL is a CategoryRepository keeping the categories in
+// the `categories` table of a SQL database.
+type CategoryRepositorySQL struct {
+	DB *sql.DB
+}
+
+func NewCategoryRepositorySQL(db *sql.DB) CategoryRepository {
+	return &CategoryRepositorySQL{DB: db}
+}
+
+const categoryColumns = "id, user_id, name, color, icon, parent_id, created_at"
+
+func (repository *CategoryRepositorySQL) Save(ctx context.Context, category domain.Category) (domain.Category, error) {
+	_, err := repository.DB.ExecContext(ctx, `INSERT INTO categories (`+categoryColumns+`)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (id) DO UPDATE SET user_id = excluded.user_id, name = excluded.name, color = excluded.color,
+			icon = excluded.icon, parent_id = excluded.parent_id, created_at = excluded.created_at`,
+		category.Id, category.UserId, category.Name, category.Color, category.Icon, category.ParentId, category.CreatedAt)
+	if err != nil {
+		return domain.Category{}, exception.NewUnavailableError(err)
+	}
+	return category, nil
+}
+
+func (repository *CategoryRepositorySQL) Update(ctx context.Context, category domain.Category) (domain.Category, error) {
+	_, err := repository.DB.ExecContext(ctx, "UPDATE categories SET name = $2, color = $3, icon = $4, parent_id = $5 WHERE id = $1",
+		category.Id, category.Name, category.Color, category.Icon, category.ParentId)
+	if err != nil {
+		return domain.Category{}, exception.NewUnavailableError(err)
+	}
+	return category, nil
+}
+
+func (repository *CategoryRepositorySQL) Delete(ctx context.Context, category domain.Category) error {
+	_, err := repository.DB.ExecContext(ctx, "DELETE FROM categories WHERE id = $1", category.Id)
+	if err != nil {
+		return exception.NewUnavailableError(err)
+	}
+	return nil
+}
+
+func (repository *CategoryRepositorySQL) FindById(ctx context.Context, categoryId string) (domain.Category, error) {
+	row := repository.DB.QueryRowContext(ctx, "SELECT "+categoryColumns+" FROM categories WHERE id = $1", categoryId)
+	category, err := scanCategory(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.Category{}, exception.NewNotFoundError("category not found")
+	}
+	if err != nil {
+		return domain.Category{}, exception.NewUnavailableError(err)
+	}
+	return category, nil
+}
+
+func (repository *CategoryRepositorySQL) FindByName(ctx context.Context, userId string, name string) (domain.Category, bool, error) {
+	row := repository.DB.QueryRowContext(ctx, "SELECT "+categoryColumns+" FROM categories WHERE user_id = $1 AND name = $2 LIMIT 1",
+		userId, name)
+	category, err := scanCategory(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.Category{}, false, nil
+	}
+	if err != nil {
+		return domain.Category{}, false, exception.NewUnavailableError(err)
+	}
+	return category, true, nil
+}
+
+func (repository *CategoryRepositorySQL) FindByUserId(ctx context.Context, userId string) ([]domain.Category, error) {
+	rows, err := repository.DB.QueryContext(ctx, "SELECT "+categoryColumns+" FROM categories WHERE user_id = $1 ORDER BY name", userId)
+	if err != nil {
+		return nil, exception.NewUnavailableError(err)
+	}
+	defer rows.Close()
+
+	var categories []domain.Category
+	for rows.Next() {
+		category, err := scanCategory(rows)
+		if err != nil {
+			return nil, exception.NewUnavailableError(err)
+		}
+		categories = append(categories, category)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, exception.NewUnavailableError(err)
+	}
+	return categories, nil
+}
+
+func scanCategory(row rowScanner) (domain.Category, error) {
+	category := domain.Category{}
+	err := row.Scan(&category.Id, &category.UserId, &category.Name, &category.Color, &category.Icon,
+		&category.ParentId, &category.CreatedAt)
+	return category, err
+}
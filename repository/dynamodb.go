@@ -0,0 +1,47 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/refandas/duit-api/exception"
+	"github.com/refandas/duit-api/helper"
+	"time"
+)
+
+// batchWriteLimit is the maximum number of items of a BatchWriteItem
+// request.
+const batchWriteLimit = 25
+
+// batchWriteAttempts limits how many times the unprocessed items of a
+// BatchWriteItem request are written again.
+const batchWriteAttempts = 8
+
+// batchWrite writes the items of the requests to the table with a single
+// BatchWriteItem request, writing the unprocessed items again after an
+// exponentially increasing delay.
+func batchWrite(ctx context.Context, db *helper.DynamoDB, requests []types.WriteRequest) error {
+	delay := 50 * time.Millisecond
+	for attempt := 0; attempt < batchWriteAttempts; attempt++ {
+		response, err := db.Client.BatchWriteItem(ctx, &dynamodb.BatchWriteItemInput{
+			RequestItems: map[string][]types.WriteRequest{db.TableName: requests},
+		})
+		if err != nil {
+			return exception.NewUnavailableError(err)
+		}
+
+		requests = response.UnprocessedItems[db.TableName]
+		if len(requests) == 0 {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return exception.NewUnavailableError(ctx.Err())
+		case <-time.After(delay):
+		}
+		delay *= 2
+	}
+	return exception.NewUnavailableError(errors.New("items of the batch write are left unprocessed"))
+}
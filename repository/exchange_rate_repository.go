@@ -0,0 +1,11 @@
+package repository
+
+import (
+	"context"
+	"github.com/refandas/duit-api/model/domain"
+)
+
+type ExchangeRateRepository interface {
+	Save(ctx context.Context, rate domain.ExchangeRate) (domain.ExchangeRate, error)
+	FindByPair(ctx context.Context, base string, quote string) ([]domain.ExchangeRate, error)
+}
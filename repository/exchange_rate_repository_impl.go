@@ -0,0 +1,69 @@
+package repository
+
+import (
+	"context"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/expression"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/refandas/duit-api/exception"
+	"github.com/refandas/duit-api/helper"
+	"github.com/refandas/duit-api/model/domain"
+)
+
+type ExchangeRateRepositoryImpl struct {
+	DB *helper.DynamoDB
+}
+
+func NewExchangeRateRepository(db *helper.DynamoDB) ExchangeRateRepository {
+	return &ExchangeRateRepositoryImpl{DB: db}
+}
+
+func (repository *ExchangeRateRepositoryImpl) Save(ctx context.Context, rate domain.ExchangeRate) (domain.ExchangeRate, error) {
+	item, err := attributevalue.MarshalMap(rate)
+	if err != nil {
+		return domain.ExchangeRate{}, err
+	}
+	_, err = repository.DB.Client.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName: aws.String(repository.DB.TableName),
+		Item:      item,
+	})
+	if err != nil {
+		return domain.ExchangeRate{}, exception.NewUnavailableError(err)
+	}
+	return rate, nil
+}
+
+// FindByPair lists the rates of the currency pair ordered by the time since
+// they are effective.
+func (repository *ExchangeRateRepositoryImpl) FindByPair(ctx context.Context, base string, quote string) ([]domain.ExchangeRate, error) {
+	var rates []domain.ExchangeRate
+
+	keyExpression := expression.Key("Pair").Equal(expression.Value(domain.CurrencyPair(base, quote)))
+	expr, err := expression.NewBuilder().WithKeyCondition(keyExpression).Build()
+	if err != nil {
+		return nil, err
+	}
+
+	paginator := dynamodb.NewQueryPaginator(repository.DB.Client, &dynamodb.QueryInput{
+		TableName:                 aws.String(repository.DB.TableName),
+		ExpressionAttributeNames:  expr.Names(),
+		ExpressionAttributeValues: expr.Values(),
+		KeyConditionExpression:    expr.KeyCondition(),
+		ScanIndexForward:          aws.Bool(true),
+	})
+	for paginator.HasMorePages() {
+		response, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, exception.NewUnavailableError(err)
+		}
+
+		var page []domain.ExchangeRate
+		err = attributevalue.UnmarshalListOfMaps(response.Items, &page)
+		if err != nil {
+			return nil, err
+		}
+		rates = append(rates, page...)
+	}
+	return rates, nil
+}
@@ -0,0 +1,46 @@
+package repository
+
+import (
+	"context"
+	"github.com/refandas/duit-api/model/domain"
+	"sort"
+	"sync"
+)
+
+// ExchangeRateRepositoryMemory is an ExchangeRateRepository keeping the
+// exchange rates in memory. It is safe for concurrent use.
+type ExchangeRateRepositoryMemory struct {
+	mutex sync.RWMutex
+	rates map[string]map[int64]domain.ExchangeRate
+}
+
+func NewExchangeRateRepositoryMemory() ExchangeRateRepository {
+	return &ExchangeRateRepositoryMemory{rates: map[string]map[int64]domain.ExchangeRate{}}
+}
+
+func (repository *ExchangeRateRepositoryMemory) Save(ctx context.Context, rate domain.ExchangeRate) (domain.ExchangeRate, error) {
+	repository.mutex.Lock()
+	defer repository.mutex.Unlock()
+
+	if repository.rates[rate.Pair] == nil {
+		repository.rates[rate.Pair] = map[int64]domain.ExchangeRate{}
+	}
+	repository.rates[rate.Pair][rate.EffectiveFrom] = rate
+	return rate, nil
+}
+
+// FindByPair lists the rates of the currency pair ordered by the time since
+// they are effective.
+func (repository *ExchangeRateRepositoryMemory) FindByPair(ctx context.Context, base string, quote string) ([]domain.ExchangeRate, error) {
+	repository.mutex.RLock()
+	defer repository.mutex.RUnlock()
+
+	var rates []domain.ExchangeRate
+	for _, rate := range repository.rates[domain.CurrencyPair(base, quote)] {
+		rates = append(rates, rate)
+	}
+	sort.Slice(rates, func(i, j int) bool {
+		return rates[i].EffectiveFrom < rates[j].EffectiveFrom
+	})
+	return rates, nil
+}
@@ -0,0 +1,57 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"github.com/refandas/duit-api/exception"
+	"github.com/refandas/duit-api/model/domain"
+)
+
+// ExchangeRateRepositorySQL is an ExchangeRateRepository keeping the
+// exchange rates in the `exchange_rates` table of a SQL database.
+type ExchangeRateRepositorySQL struct {
+	DB *sql.DB
+}
+
+func NewExchangeRateRepositorySQL(db *sql.DB) ExchangeRateRepository {
+	return &ExchangeRateRepositorySQL{DB: db}
+}
+
+const exchangeRateColumns = "pair, base, quote, rate, effective_from, created_at"
+
+func (repository *ExchangeRateRepositorySQL) Save(ctx context.Context, rate domain.ExchangeRate) (domain.ExchangeRate, error) {
+	_, err := repository.DB.ExecContext(ctx, `INSERT INTO exchange_rates (`+exchangeRateColumns+`)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (pair, effective_from) DO UPDATE SET base = excluded.base, quote = excluded.quote,
+			rate = excluded.rate, created_at = excluded.created_at`,
+		rate.Pair, rate.Base, rate.Quote, rate.Rate, rate.EffectiveFrom, rate.CreatedAt)
+	if err != nil {
+		return domain.ExchangeRate{}, exception.NewUnavailableError(err)
+	}
+	return rate, nil
+}
+
+// FindByPair lists the rates of the currency pair ordered by the time since
+// they are effective.
+func (repository *ExchangeRateRepositorySQL) FindByPair(ctx context.Context, base string, quote string) ([]domain.ExchangeRate, error) {
+	rows, err := repository.DB.QueryContext(ctx, "SELECT "+exchangeRateColumns+" FROM exchange_rates WHERE pair = $1 ORDER BY effective_from",
+		domain.CurrencyPair(base, quote))
+	if err != nil {
+		return nil, exception.NewUnavailableError(err)
+	}
+	defer rows.Close()
+
+	var rates []domain.ExchangeRate
+	for rows.Next() {
+		rate := domain.ExchangeRate{}
+		err := rows.Scan(&rate.Pair, &rate.Base, &rate.Quote, &rate.Rate, &rate.EffectiveFrom, &rate.CreatedAt)
+		if err != nil {
+			return nil, exception.NewUnavailableError(err)
+		}
+		rates = append(rates, rate)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, exception.NewUnavailableError(err)
+	}
+	return rates, nil
+}
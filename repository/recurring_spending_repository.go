@@ -0,0 +1,16 @@
+package repository
+
+import (
+	"context"
+	"github.com/refandas/duit-api/model/domain"
+)
+
+type RecurringSpendingRepository interface {
+	Save(ctx context.Context, recurring domain.RecurringSpending) (domain.RecurringSpending, error)
+	Update(ctx context.Context, recurring domain.RecurringSpending) (domain.RecurringSpending, error)
+	Advance(ctx context.Context, recurringId string, expectedLast int64, last int64, next int64) (bool, error)
+	Delete(ctx context.Context, recurring domain.RecurringSpending) error
+	FindById(ctx context.Context, recurringId string) (domain.RecurringSpending, error)
+	FindByUserId(ctx context.Context, userId string) ([]domain.RecurringSpending, error)
+	FindDue(ctx context.Context, date int64) ([]domain.RecurringSpending, error)
+}
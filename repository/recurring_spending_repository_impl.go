@@ -0,0 +1,223 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/expression"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/refandas/duit-api/exception"
+	"github.com/refandas/duit-api/helper"
+	"github.com/refandas/duit-api/model/domain"
+)
+
+type RecurringSpendingRepositoryImpl struct {
+	DB *helper.DynamoDB
+}
+
+func NewRecurringSpendingRepository(db *helper.DynamoDB) RecurringSpendingRepository {
+	return &RecurringSpendingRepositoryImpl{DB: db}
+}
+
+func (repository *RecurringSpendingRepositoryImpl) Save(ctx context.Context, recurring domain.RecurringSpending) (domain.RecurringSpending, error) {
+	item, err := attributevalue.MarshalMap(recurring)
+	if err != nil {
+		return domain.RecurringSpending{}, err
+	}
+	_, err = repository.DB.Client.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName: aws.String(repository.DB.TableName),
+		Item:      item,
+	})
+	if err != nil {
+		return domain.RecurringSpending{}, exception.NewUnavailableError(err)
+	}
+	return recurring, nil
+}
+
+func (repository *RecurringSpendingRepositoryImpl) Update(ctx context.Context, recurring domain.RecurringSpending) (domain.RecurringSpending, error) {
+	recurringId, err := attributevalue.Marshal(recurring.Id)
+	if err != nil {
+		return domain.RecurringSpending{}, err
+	}
+
+	update := expression.Set(expression.Name("Title"), expression.Value(recurring.Title))
+	update.Set(expression.Name("Description"), expression.Value(recurring.Description))
+	update.Set(expression.Name("Amount"), expression.Value(recurring.Amount))
+	update.Set(expression.Name("Category"), expression.Value(recurring.Category))
+	update.Set(expression.Name("Rule"), expression.Value(recurring.Rule))
+	update.Set(expression.Name("LastOccurrence"), expression.Value(recurring.LastOccurrence))
+	update.Set(expression.Name("NextOccurrence"), expression.Value(recurring.NextOccurrence))
+
+	condition := expression.AttributeExists(expression.Name("Id"))
+
+	expr, err := expression.NewBuilder().WithUpdate(update).WithCondition(condition).Build()
+	if err != nil {
+		return domain.RecurringSpending{}, err
+	}
+
+	_, err = repository.DB.Client.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+		TableName:                 aws.String(repository.DB.TableName),
+		Key:                       map[string]types.AttributeValue{"Id": recurringId},
+		ExpressionAttributeNames:  expr.Names(),
+		ExpressionAttributeValues: expr.Values(),
+		UpdateExpression:          expr.Update(),
+		ConditionExpression:       expr.Condition(),
+	})
+
+	var conditionalCheckFailed *types.ConditionalCheckFailedException
+	if errors.As(err, &conditionalCheckFailed) {
+		return domain.RecurringSpending{}, exception.NewNotFoundError("recurring spending not found")
+	}
+	if err != nil {
+		return domain.RecurringSpending{}, exception.NewUnavailableError(err)
+	}
+	return recurring, nil
+}
+
+// Advance moves the occurrences of the recurring spending on, only if it
+// still exists and its last occurrence is still expectedLast. It returns
+// false when the recurring spending has been deleted or advanced by another
+// call.
+func (repository *RecurringSpendingRepositoryImpl) Advance(ctx context.Context, recurringId string, expectedLast int64, last int64, next int64) (bool, error) {
+	id, err := attributevalue.Marshal(recurringId)
+	if err != nil {
+		return false, err
+	}
+
+	update := expression.Set(expression.Name("LastOccurrence"), expression.Value(last))
+	update.Set(expression.Name("NextOccurrence"), expression.Value(next))
+	condition := expression.AttributeExists(expression.Name("Id")).
+		And(expression.Name("LastOccurrence").Equal(expression.Value(expectedLast)))
+
+	expr, err := expression.NewBuilder().WithUpdate(update).WithCondition(condition).Build()
+	if err != nil {
+		return false, err
+	}
+
+	_, err = repository.DB.Client.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+		TableName:                 aws.String(repository.DB.TableName),
+		Key:                       map[string]types.AttributeValue{"Id": id},
+		ExpressionAttributeNames:  expr.Names(),
+		ExpressionAttributeValues: expr.Values(),
+		UpdateExpression:          expr.Update(),
+		ConditionExpression:       expr.Condition(),
+	})
+
+	var conditionalCheckFailed *types.ConditionalCheckFailedException
+	if errors.As(err, &conditionalCheckFailed) {
+		return false, nil
+	}
+	if err != nil {
+		return false, exception.NewUnavailableError(err)
+	}
+	return true, nil
+}
+
+func (repository *RecurringSpendingRepositoryImpl) Delete(ctx context.Context, recurring domain.RecurringSpending) error {
+	recurringId, err := attributevalue.Marshal(recurring.Id)
+	if err != nil {
+		return err
+	}
+	_, err = repository.DB.Client.DeleteItem(ctx, &dynamodb.DeleteItemInput{
+		TableName: aws.String(repository.DB.TableName),
+		Key:       map[string]types.AttributeValue{"Id": recurringId},
+	})
+	if err != nil {
+		return exception.NewUnavailableError(err)
+	}
+	return nil
+}
+
+func (repository *RecurringSpendingRepositoryImpl) FindById(ctx context.Context, recurringId string) (domain.RecurringSpending, error) {
+	id, err := attributevalue.Marshal(recurringId)
+	if err != nil {
+		return domain.RecurringSpending{}, err
+	}
+
+	response, err := repository.DB.Client.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName: aws.String(repository.DB.TableName),
+		Key:       map[string]types.AttributeValue{"Id": id},
+	})
+	if err != nil {
+		return domain.RecurringSpending{}, exception.NewUnavailableError(err)
+	}
+	if response.Item == nil {
+		return domain.RecurringSpending{}, exception.NewNotFoundError("recurring spending not found")
+	}
+
+	recurring := domain.RecurringSpending{}
+	err = attributevalue.UnmarshalMap(response.Item, &recurring)
+	if err != nil {
+		return domain.RecurringSpending{}, err
+	}
+	return recurring, nil
+}
+
+func (repository *RecurringSpendingRepositoryImpl) FindByUserId(ctx context.Context, userId string) ([]domain.RecurringSpending, error) {
+	var recurringSpendings []domain.RecurringSpending
+
+	keyExpression := expression.Key("UserId").Equal(expression.Value(userId))
+	expr, err := expression.NewBuilder().WithKeyCondition(keyExpression).Build()
+	if err != nil {
+		return nil, err
+	}
+
+	paginator := dynamodb.NewQueryPaginator(repository.DB.Client, &dynamodb.QueryInput{
+		TableName:                 aws.String(repository.DB.TableName),
+		IndexName:                 aws.String("UserIndex"),
+		ExpressionAttributeNames:  expr.Names(),
+		ExpressionAttributeValues: expr.Values(),
+		KeyConditionExpression:    expr.KeyCondition(),
+		ScanIndexForward:          aws.Bool(true),
+	})
+	for paginator.HasMorePages() {
+		response, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, exception.NewUnavailableError(err)
+		}
+
+		var page []domain.RecurringSpending
+		err = attributevalue.UnmarshalListOfMaps(response.Items, &page)
+		if err != nil {
+			return nil, err
+		}
+		recurringSpendings = append(recurringSpendings, page...)
+	}
+	return recurringSpendings, nil
+}
+
+// FindDue returns the recurring spendings whose next occurrence is due at
+// the given date, stored in Unix time format. The table is scanned, as the
+// due recurring spendings of every user are looked for.
+func (repository *RecurringSpendingRepositoryImpl) FindDue(ctx context.Context, date int64) ([]domain.RecurringSpending, error) {
+	var recurringSpendings []domain.RecurringSpending
+
+	filter := expression.Name("NextOccurrence").Between(expression.Value(1), expression.Value(date))
+	expr, err := expression.NewBuilder().WithFilter(filter).Build()
+	if err != nil {
+		return nil, err
+	}
+
+	paginator := dynamodb.NewScanPaginator(repository.DB.Client, &dynamodb.ScanInput{
+		TableName:                 aws.String(repository.DB.TableName),
+		ExpressionAttributeNames:  expr.Names(),
+		ExpressionAttributeValues: expr.Values(),
+		FilterExpression:          expr.Filter(),
+	})
+	for paginator.HasMorePages() {
+		response, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, exception.NewUnavailableError(err)
+		}
+
+		var page []domain.RecurringSpending
+		err = attributevalue.UnmarshalListOfMaps(response.Items, &page)
+		if err != nil {
+			return nil, err
+		}
+		recurringSpendings = append(recurringSpendings, page...)
+	}
+	return recurringSpendings, nil
+}
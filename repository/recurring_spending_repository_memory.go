@@ -0,0 +1,117 @@
+package repository
+
+import (
+	"context"
+	"github.com/refandas/duit-api/exception"
+	"github.com/refandas/duit-api/model/domain"
+	"sort"
+	"sync"
+)
+
+// RecurringSpendingRepositoryMemory is a RecurringSpendingRepository keeping
+// the recurring spendings in memory. It is safe for concurrent use.
+type RecurringSpendingRepositoryMemory struct {
+	mutex     sync.RWMutex
+	recurring map[string]domain.RecurringSpending
+}
+
+func NewRecurringSpendingRepositoryMemory() RecurringSpendingRepository {
+	return &RecurringSpendingRepositoryMemory{recurring: map[string]domain.RecurringSpending{}}
+}
+
+func (repository *RecurringSpendingRepositoryMemory) Save(ctx context.Context, recurring domain.RecurringSpending) (domain.RecurringSpending, error) {
+	repository.mutex.Lock()
+	defer repository.mutex.Unlock()
+
+	repository.recurring[recurring.Id] = recurring
+	return recurring, nil
+}
+
+func (repository *RecurringSpendingRepositoryMemory) Update(ctx context.Context, recurring domain.RecurringSpending) (domain.RecurringSpending, error) {
+	repository.mutex.Lock()
+	defer repository.mutex.Unlock()
+
+	stored, found := repository.recurring[recurring.Id]
+	if !found {
+		return domain.RecurringSpending{}, exception.NewNotFoundError("recurring spending not found")
+	}
+	stored.Title = recurring.Title
+	stored.Description = recurring.Description
+	stored.Amount = recurring.Amount
+	stored.Category = recurring.Category
+	stored.Rule = recurring.Rule
+	stored.LastOccurrence = recurring.LastOccurrence
+	stored.NextOccurrence = recurring.NextOccurrence
+	repository.recurring[recurring.Id] = stored
+	return recurring, nil
+}
+
+// Advance moves the occurrences of the recurring spending on, only if its
+// last occurrence is still expectedLast. It returns false when the recurring
+// spending has been deleted or advanced by another call.
+func (repository *RecurringSpendingRepositoryMemory) Advance(ctx context.Context, recurringId string, expectedLast int64, last int64, next int64) (bool, error) {
+	repository.mutex.Lock()
+	defer repository.mutex.Unlock()
+
+	stored, found := repository.recurring[recurringId]
+	if !found || stored.LastOccurrence != expectedLast {
+		return false, nil
+	}
+	stored.LastOccurrence = last
+	stored.NextOccurrence = next
+	repository.recurring[recurringId] = stored
+	return true, nil
+}
+
+func (repository *RecurringSpendingRepositoryMemory) Delete(ctx context.Context, recurring domain.RecurringSpending) error {
+	repository.mutex.Lock()
+	defer repository.mutex.Unlock()
+
+	delete(repository.recurring, recurring.Id)
+	return nil
+}
+
+func (repository *RecurringSpendingRepositoryMemory) FindById(ctx context.Context, recurringId string) (domain.RecurringSpending, error) {
+	repository.mutex.RLock()
+	defer repository.mutex.RUnlock()
+
+	recurring, found := repository.recurring[recurringId]
+	if !found {
+		return domain.RecurringSpending{}, exception.NewNotFoundError("recurring spending not found")
+	}
+	return recurring, nil
+}
+
+func (repository *RecurringSpendingRepositoryMemory) FindByUserId(ctx context.Context, userId string) ([]domain.RecurringSpending, error) {
+	repository.mutex.RLock()
+	defer repository.mutex.RUnlock()
+
+	var recurringSpendings []domain.RecurringSpending
+	for _, recurring := range repository.recurring {
+		if recurring.UserId == userId {
+			recurringSpendings = append(recurringSpendings, recurring)
+		}
+	}
+	sort.Slice(recurringSpendings, func(i, j int) bool {
+		return recurringSpendings[i].CreatedAt < recurringSpendings[j].CreatedAt
+	})
+	return recurringSpendings, nil
+}
+
+// FindDue returns the recurring spendings whose next occurrence is due at
+// the given date, stored in Unix time format.
+func (repository *RecurringSpendingRepositoryMemory) FindDue(ctx context.Context, date int64) ([]domain.RecurringSpending, error) {
+	repository.mutex.RLock()
+	defer repository.mutex.RUnlock()
+
+	var recurringSpendings []domain.RecurringSpending
+	for _, recurring := range repository.recurring {
+		if recurring.NextOccurrence != 0 && recurring.NextOccurrence <= date {
+			recurringSpendings = append(recurringSpendings, recurring)
+		}
+	}
+	sort.Slice(recurringSpendings, func(i, j int) bool {
+		return recurringSpendings[i].NextOccurrence < recurringSpendings[j].NextOccurrence
+	})
+	return recurringSpendings, nil
+}
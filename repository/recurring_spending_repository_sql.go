@@ -0,0 +1,146 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"github.com/refandas/duit-api/exception"
+	"github.com/refandas/duit-api/model/domain"
+	"strings"
+)
+
+// RecurringSpendingRepositorySQL is a RecurringSpendingRepository keeping the
+// recurring spendings in the `recurring_spendings` table of a SQL database.
+// The days of a rule are stored separated by commas.
+type RecurringSpendingRepositorySQL struct {
+	DB *sql.DB
+}
+
+func NewRecurringSpendingRepositorySQL(db *sql.DB) RecurringSpendingRepository {
+	return &RecurringSpendingRepositorySQL{DB: db}
+}
+
+const recurringSpendingColumns = "id, user_id, title, description, amount_minor, currency, category, rule_frequency, " +
+	"rule_interval, rule_by_day, rule_start, rule_until, rule_count, last_occurrence, next_occurrence, created_at"
+
+func (repository *RecurringSpendingRepositorySQL) Save(ctx context.Context, recurring domain.RecurringSpending) (domain.RecurringSpending, error) {
+	_, err := repository.DB.ExecContext(ctx, `INSERT INTO recurring_spendings (`+recurringSpendingColumns+`)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16)
+		ON CONFLICT (id) DO UPDATE SET user_id = excluded.user_id, title = excluded.title,
+			description = excluded.description, amount_minor = excluded.amount_minor, currency = excluded.currency,
+			category = excluded.category, rule_frequency = excluded.rule_frequency, rule_interval = excluded.rule_interval,
+			rule_by_day = excluded.rule_by_day, rule_start = excluded.rule_start, rule_until = excluded.rule_until,
+			rule_count = excluded.rule_count, last_occurrence = excluded.last_occurrence,
+			next_occurrence = excluded.next_occurrence, created_at = excluded.created_at`,
+		recurring.Id, recurring.UserId, recurring.Title, recurring.Description, recurring.Amount.Minor,
+		recurring.Amount.Currency, recurring.Category, recurring.Rule.Frequency, recurring.Rule.Interval,
+		strings.Join(recurring.Rule.ByDay, ","), recurring.Rule.Start, recurring.Rule.Until, recurring.Rule.Count,
+		recurring.LastOccurrence, recurring.NextOccurrence, recurring.CreatedAt)
+	if err != nil {
+		return domain.RecurringSpending{}, exception.NewUnavailableError(err)
+	}
+	return recurring, nil
+}
+
+func (repository *RecurringSpendingRepositorySQL) Update(ctx context.Context, recurring domain.RecurringSpending) (domain.RecurringSpending, error) {
+	result, err := repository.DB.ExecContext(ctx, `UPDATE recurring_spendings SET title = $2, description = $3,
+			amount_minor = $4, currency = $5, category = $6, rule_frequency = $7, rule_interval = $8, rule_by_day = $9,
+			rule_start = $10, rule_until = $11, rule_count = $12, last_occurrence = $13, next_occurrence = $14
+		WHERE id = $1`,
+		recurring.Id, recurring.Title, recurring.Description, recurring.Amount.Minor, recurring.Amount.Currency,
+		recurring.Category, recurring.Rule.Frequency, recurring.Rule.Interval, strings.Join(recurring.Rule.ByDay, ","),
+		recurring.Rule.Start, recurring.Rule.Until, recurring.Rule.Count, recurring.LastOccurrence, recurring.NextOccurrence)
+	if err != nil {
+		return domain.RecurringSpending{}, exception.NewUnavailableError(err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return domain.RecurringSpending{}, exception.NewUnavailableError(err)
+	}
+	if rows == 0 {
+		return domain.RecurringSpending{}, exception.NewNotFoundError("recurring spending not found")
+	}
+	return recurring, nil
+}
+
+// Advance moves the occurrences of the recurring spending on, only if its
+// last occurrence is still expectedLast, so neither a deleted recurring
+// spending nor one advanced by another server is written again.
+func (repository *RecurringSpendingRepositorySQL) Advance(ctx context.Context, recurringId string, expectedLast int64, last int64, next int64) (bool, error) {
+	result, err := repository.DB.ExecContext(ctx, `UPDATE recurring_spendings SET last_occurrence = $3, next_occurrence = $4
+		WHERE id = $1 AND last_occurrence = $2`,
+		recurringId, expectedLast, last, next)
+	if err != nil {
+		return false, exception.NewUnavailableError(err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, exception.NewUnavailableError(err)
+	}
+	return rows > 0, nil
+}
+
+func (repository *RecurringSpendingRepositorySQL) Delete(ctx context.Context, recurring domain.RecurringSpending) error {
+	_, err := repository.DB.ExecContext(ctx, "DELETE FROM recurring_spendings WHERE id = $1", recurring.Id)
+	if err != nil {
+		return exception.NewUnavailableError(err)
+	}
+	return nil
+}
+
+func (repository *RecurringSpendingRepositorySQL) FindById(ctx context.Context, recurringId string) (domain.RecurringSpending, error) {
+	row := repository.DB.QueryRowContext(ctx, "SELECT "+recurringSpendingColumns+" FROM recurring_spendings WHERE id = $1", recurringId)
+	recurring, err := scanRecurringSpending(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.RecurringSpending{}, exception.NewNotFoundError("recurring spending not found")
+	}
+	if err != nil {
+		return domain.RecurringSpending{}, exception.NewUnavailableError(err)
+	}
+	return recurring, nil
+}
+
+func (repository *RecurringSpendingRepositorySQL) FindByUserId(ctx context.Context, userId string) ([]domain.RecurringSpending, error) {
+	return repository.query(ctx, "SELECT "+recurringSpendingColumns+" FROM recurring_spendings WHERE user_id = $1 ORDER BY created_at", userId)
+}
+
+// FindDue returns the recurring spendings whose next occurrence is due at
+// the given date, stored in Unix time format.
+func (repository *RecurringSpendingRepositorySQL) FindDue(ctx context.Context, date int64) ([]domain.RecurringSpending, error) {
+	return repository.query(ctx, "SELECT "+recurringSpendingColumns+
+		" FROM recurring_spendings WHERE next_occurrence > 0 AND next_occurrence <= $1 ORDER BY next_occurrence", date)
+}
+
+func (repository *RecurringSpendingRepositorySQL) query(ctx context.Context, query string, args ...interface{}) ([]domain.RecurringSpending, error) {
+	rows, err := repository.DB.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, exception.NewUnavailableError(err)
+	}
+	defer rows.Close()
+
+	var recurringSpendings []domain.RecurringSpending
+	for rows.Next() {
+		recurring, err := scanRecurringSpending(rows)
+		if err != nil {
+			return nil, exception.NewUnavailableError(err)
+		}
+		recurringSpendings = append(recurringSpendings, recurring)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, exception.NewUnavailableError(err)
+	}
+	return recurringSpendings, nil
+}
+
+func scanRecurringSpending(row rowScanner) (domain.RecurringSpending, error) {
+	recurring := domain.RecurringSpending{}
+	var byDay string
+	err := row.Scan(&recurring.Id, &recurring.UserId, &recurring.Title, &recurring.Description, &recurring.Amount.Minor,
+		&recurring.Amount.Currency, &recurring.Category, &recurring.Rule.Frequency, &recurring.Rule.Interval, &byDay,
+		&recurring.Rule.Start, &recurring.Rule.Until, &recurring.Rule.Count, &recurring.LastOccurrence,
+		&recurring.NextOccurrence, &recurring.CreatedAt)
+	if byDay != "" {
+		recurring.Rule.ByDay = strings.Split(byDay, ",")
+	}
+	return recurring, err
+}
@@ -0,0 +1,14 @@
+package repository
+
+import (
+	"context"
+	"github.com/refandas/duit-api/model/domain"
+)
+
+type SessionRepository interface {
+	Save(ctx context.Context, session domain.Session) (domain.Session, error)
+	Rotate(ctx context.Context, session domain.Session) (bool, error)
+	RevokeFamily(ctx context.Context, userId string, familyId string) error
+	FindById(ctx context.Context, sessionId string) (domain.Session, bool, error)
+	FindByUserId(ctx context.Context, userId string) ([]domain.Session, error)
+}
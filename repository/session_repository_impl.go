@@ -0,0 +1,173 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/expression"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/refandas/duit-api/exception"
+	"github.com/refandas/duit-api/helper"
+	"github.com/refandas/duit-api/model/domain"
+	"time"
+)
+
+type SessionRepositoryImpl struct {
+	DB *helper.DynamoDB
+}
+
+func NewSessionRepository(db *helper.DynamoDB) SessionRepository {
+	return &SessionRepositoryImpl{DB: db}
+}
+
+func (repository *SessionRepositoryImpl) Save(ctx context.Context, session domain.Session) (domain.Session, error) {
+	item, err := attributevalue.MarshalMap(session)
+	if err != nil {
+		return domain.Session{}, err
+	}
+	_, err = repository.DB.Client.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName: aws.String(repository.DB.TableName),
+		Item:      item,
+	})
+	if err != nil {
+		return domain.Session{}, exception.NewUnavailableError(err)
+	}
+	return session, nil
+}
+
+// Rotate marks the session as rotated at session.RotatedAt, only if it has
+// not been rotated nor revoked yet. It returns false when another request
+// has already used or revoked the session.
+func (repository *SessionRepositoryImpl) Rotate(ctx context.Context, session domain.Session) (bool, error) {
+	sessionId, err := attributevalue.Marshal(session.Id)
+	if err != nil {
+		return false, err
+	}
+
+	update := expression.Set(expression.Name("RotatedAt"), expression.Value(session.RotatedAt))
+	condition := expression.Name("RotatedAt").Equal(expression.Value(0)).
+		And(expression.Name("RevokedAt").Equal(expression.Value(0)))
+
+	expr, err := expression.NewBuilder().WithUpdate(update).WithCondition(condition).Build()
+	if err != nil {
+		return false, err
+	}
+
+	_, err = repository.DB.Client.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+		TableName:                 aws.String(repository.DB.TableName),
+		Key:                       map[string]types.AttributeValue{"Id": sessionId},
+		ExpressionAttributeNames:  expr.Names(),
+		ExpressionAttributeValues: expr.Values(),
+		UpdateExpression:          expr.Update(),
+		ConditionExpression:       expr.Condition(),
+	})
+
+	var conditionalCheckFailed *types.ConditionalCheckFailedException
+	if errors.As(err, &conditionalCheckFailed) {
+		return false, nil
+	}
+	if err != nil {
+		return false, exception.NewUnavailableError(err)
+	}
+	return true, nil
+}
+
+// RevokeFamily revokes every session of the user that belongs to the given
+// family and has not been revoked yet.
+func (repository *SessionRepositoryImpl) RevokeFamily(ctx context.Context, userId string, familyId string) error {
+	revokedAt := time.Now().UnixMilli()
+
+	sessions, err := repository.FindByUserId(ctx, userId)
+	if err != nil {
+		return err
+	}
+	for _, session := range sessions {
+		if session.FamilyId != familyId || session.RevokedAt != 0 {
+			continue
+		}
+
+		sessionId, err := attributevalue.Marshal(session.Id)
+		if err != nil {
+			return err
+		}
+
+		update := expression.Set(expression.Name("RevokedAt"), expression.Value(revokedAt))
+		expr, err := expression.NewBuilder().WithUpdate(update).Build()
+		if err != nil {
+			return err
+		}
+
+		_, err = repository.DB.Client.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+			TableName:                 aws.String(repository.DB.TableName),
+			Key:                       map[string]types.AttributeValue{"Id": sessionId},
+			ExpressionAttributeNames:  expr.Names(),
+			ExpressionAttributeValues: expr.Values(),
+			UpdateExpression:          expr.Update(),
+		})
+		if err != nil {
+			return exception.NewUnavailableError(err)
+		}
+	}
+	return nil
+}
+
+func (repository *SessionRepositoryImpl) FindById(ctx context.Context, sessionId string) (domain.Session, bool, error) {
+	session := domain.Session{Id: sessionId}
+	id, err := attributevalue.Marshal(session.Id)
+	if err != nil {
+		return domain.Session{}, false, err
+	}
+
+	response, err := repository.DB.Client.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName:      aws.String(repository.DB.TableName),
+		Key:            map[string]types.AttributeValue{"Id": id},
+		ConsistentRead: aws.Bool(true),
+	})
+	if err != nil {
+		return domain.Session{}, false, exception.NewUnavailableError(err)
+	}
+	if response.Item == nil {
+		return session, false, nil
+	}
+
+	err = attributevalue.UnmarshalMap(response.Item, &session)
+	if err != nil {
+		return domain.Session{}, false, err
+	}
+	return session, true, nil
+}
+
+func (repository *SessionRepositoryImpl) FindByUserId(ctx context.Context, userId string) ([]domain.Session, error) {
+	var sessions []domain.Session
+
+	keyExpression := expression.Key("UserId").Equal(expression.Value(userId))
+	expr, err := expression.NewBuilder().WithKeyCondition(keyExpression).Build()
+	if err != nil {
+		return nil, err
+	}
+
+	paginator := dynamodb.NewQueryPaginator(repository.DB.Client, &dynamodb.QueryInput{
+		TableName:                 aws.String(repository.DB.TableName),
+		IndexName:                 aws.String("UserIndex"),
+		ExpressionAttributeNames:  expr.Names(),
+		ExpressionAttributeValues: expr.Values(),
+		KeyConditionExpression:    expr.KeyCondition(),
+		ScanIndexForward:          aws.Bool(true),
+	})
+	for paginator.HasMorePages() {
+		response, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, exception.NewUnavailableError(err)
+		}
+
+		var page []domain.Session
+		err = attributevalue.UnmarshalListOfMaps(response.Items, &page)
+		if err != nil {
+			return nil, err
+		}
+		sessions = append(sessions, page...)
+	}
+	return sessions, nil
+}
@@ -0,0 +1,82 @@
+package repository
+
+import (
+	"context"
+	"github.com/refandas/duit-api/model/domain"
+	"sort"
+	"sync"
+	"time"
+)
+
+// SessionRepositoryMemory is a SessionRepository keeping the sessions in
+// memory. It is safe for concurrent use.
+type SessionRepositoryMemory struct {
+	mutex    sync.RWMutex
+	sessions map[string]domain.Session
+}
+
+func NewSessionRepositoryMemory() SessionRepository {
+	return &SessionRepositoryMemory{sessions: map[string]domain.Session{}}
+}
+
+func (repository *SessionRepositoryMemory) Save(ctx context.Context, session domain.Session) (domain.Session, error) {
+	repository.mutex.Lock()
+	defer repository.mutex.Unlock()
+
+	repository.sessions[session.Id] = session
+	return session, nil
+}
+
+func (repository *SessionRepositoryMemory) Rotate(ctx context.Context, session domain.Session) (bool, error) {
+	repository.mutex.Lock()
+	defer repository.mutex.Unlock()
+
+	stored, found := repository.sessions[session.Id]
+	if !found || stored.RotatedAt != 0 || stored.RevokedAt != 0 {
+		return false, nil
+	}
+	stored.RotatedAt = session.RotatedAt
+	repository.sessions[session.Id] = stored
+	return true, nil
+}
+
+func (repository *SessionRepositoryMemory) RevokeFamily(ctx context.Context, userId string, familyId string) error {
+	repository.mutex.Lock()
+	defer repository.mutex.Unlock()
+
+	revokedAt := time.Now().UnixMilli()
+	for id, session := range repository.sessions {
+		if session.UserId == userId && session.FamilyId == familyId && session.RevokedAt == 0 {
+			session.RevokedAt = revokedAt
+			repository.sessions[id] = session
+		}
+	}
+	return nil
+}
+
+func (repository *SessionRepositoryMemory) FindById(ctx context.Context, sessionId string) (domain.Session, bool, error) {
+	repository.mutex.RLock()
+	defer repository.mutex.RUnlock()
+
+	session, found := repository.sessions[sessionId]
+	if !found {
+		return domain.Session{Id: sessionId}, false, nil
+	}
+	return session, true, nil
+}
+
+func (repository *SessionRepositoryMemory) FindByUserId(ctx context.Context, userId string) ([]domain.Session, error) {
+	repository.mutex.RLock()
+	defer repository.mutex.RUnlock()
+
+	var sessions []domain.Session
+	for _, session := range repository.sessions {
+		if session.UserId == userId {
+			sessions = append(sessions, session)
+		}
+	}
+	sort.Slice(sessions, func(i, j int) bool {
+		return sessions[i].CreatedAt < sessions[j].CreatedAt
+	})
+	return sessions, nil
+}
@@ -0,0 +1,104 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"github.com/refandas/duit-api/exception"
+	"github.com/refandas/duit-api/model/domain"
+	"time"
+)
+
+// SessionRepositorySQL is a SessionRepository keeping the sessions in the
+// `sessions` table of a SQL database.
+type SessionRepositorySQL struct {
+	DB *sql.DB
+}
+
+func NewSessionRepositorySQL(db *sql.DB) SessionRepository {
+	return &SessionRepositorySQL{DB: db}
+}
+
+const sessionColumns = "id, family_id, user_id, token_hash, user_agent, started_at, created_at, expires_at, rotated_at, revoked_at"
+
+func (repository *SessionRepositorySQL) Save(ctx context.Context, session domain.Session) (domain.Session, error) {
+	_, err := repository.DB.ExecContext(ctx, `INSERT INTO sessions (`+sessionColumns+`)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		ON CONFLICT (id) DO UPDATE SET family_id = excluded.family_id, user_id = excluded.user_id,
+			token_hash = excluded.token_hash, user_agent = excluded.user_agent, started_at = excluded.started_at,
+			created_at = excluded.created_at, expires_at = excluded.expires_at,
+			rotated_at = excluded.rotated_at, revoked_at = excluded.revoked_at`,
+		session.Id, session.FamilyId, session.UserId, session.TokenHash, session.UserAgent, session.StartedAt,
+		session.CreatedAt, session.ExpiresAt, session.RotatedAt, session.RevokedAt)
+	if err != nil {
+		return domain.Session{}, exception.NewUnavailableError(err)
+	}
+	return session, nil
+}
+
+// Rotate marks the session as rotated only when it has neither been
+// rotated nor revoked yet, so a refresh token is exchanged at most once even
+// when it is presented concurrently.
+func (repository *SessionRepositorySQL) Rotate(ctx context.Context, session domain.Session) (bool, error) {
+	result, err := repository.DB.ExecContext(ctx, `UPDATE sessions SET rotated_at = $2
+		WHERE id = $1 AND rotated_at = 0 AND revoked_at = 0`,
+		session.Id, session.RotatedAt)
+	if err != nil {
+		return false, exception.NewUnavailableError(err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, exception.NewUnavailableError(err)
+	}
+	return rows == 1, nil
+}
+
+func (repository *SessionRepositorySQL) RevokeFamily(ctx context.Context, userId string, familyId string) error {
+	_, err := repository.DB.ExecContext(ctx, `UPDATE sessions SET revoked_at = $3
+		WHERE user_id = $1 AND family_id = $2 AND revoked_at = 0`,
+		userId, familyId, time.Now().UnixMilli())
+	if err != nil {
+		return exception.NewUnavailableError(err)
+	}
+	return nil
+}
+
+func (repository *SessionRepositorySQL) FindById(ctx context.Context, sessionId string) (domain.Session, bool, error) {
+	row := repository.DB.QueryRowContext(ctx, "SELECT "+sessionColumns+" FROM sessions WHERE id = $1", sessionId)
+	session, err := scanSession(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.Session{Id: sessionId}, false, nil
+	}
+	if err != nil {
+		return domain.Session{}, false, exception.NewUnavailableError(err)
+	}
+	return session, true, nil
+}
+
+func (repository *SessionRepositorySQL) FindByUserId(ctx context.Context, userId string) ([]domain.Session, error) {
+	rows, err := repository.DB.QueryContext(ctx, "SELECT "+sessionColumns+" FROM sessions WHERE user_id = $1 ORDER BY created_at", userId)
+	if err != nil {
+		return nil, exception.NewUnavailableError(err)
+	}
+	defer rows.Close()
+
+	var sessions []domain.Session
+	for rows.Next() {
+		session, err := scanSession(rows)
+		if err != nil {
+			return nil, exception.NewUnavailableError(err)
+		}
+		sessions = append(sessions, session)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, exception.NewUnavailableError(err)
+	}
+	return sessions, nil
+}
+
+func scanSession(row rowScanner) (domain.Session, error) {
+	session := domain.Session{}
+	err := row.Scan(&session.Id, &session.FamilyId, &session.UserId, &session.TokenHash, &session.UserAgent,
+		&session.StartedAt, &session.CreatedAt, &session.ExpiresAt, &session.RotatedAt, &session.RevokedAt)
+	return session, err
+}
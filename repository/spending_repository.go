@@ -2,14 +2,15 @@ package repository
 
 import (
 	"context"
-	"github.com/refandas/duit-api/helper"
 	"github.com/refandas/duit-api/model/domain"
 )
 
 type SpendingRepository interface {
-	Save(ctx context.Context, db *helper.DynamoDB, spending domain.Spending) domain.Spending
-	Update(ctx context.Context, db *helper.DynamoDB, spending domain.Spending) domain.Spending
-	Delete(ctx context.Context, db *helper.DynamoDB, spending domain.Spending)
-	FindById(ctx context.Context, db *helper.DynamoDB, spendingId string) (domain.Spending, error)
-	FindByUserId(ctx context.Context, db *helper.DynamoDB, userId string) []domain.Spending
+	Save(ctx context.Context, spending domain.Spending) (domain.Spending, error)
+	SaveAll(ctx context.Context, spendings []domain.Spending) error
+	SaveIfAbsent(ctx context.Context, spending domain.Spending) (bool, error)
+	Update(ctx context.Context, spending domain.Spending) (domain.Spending, error)
+	Delete(ctx context.Context, spending domain.Spending) error
+	FindById(ctx context.Context, spendingId string) (domain.Spending, error)
+	FindByUserId(ctx context.Context, query domain.SpendingQuery) (domain.SpendingPage, error)
 }
@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"errors"
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
 	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/expression"
@@ -10,133 +11,316 @@ import (
 	"github.com/refandas/duit-api/exception"
 	"github.com/refandas/duit-api/helper"
 	"github.com/refandas/duit-api/model/domain"
-	"time"
+	"strconv"
 )
 
 type SpendingRepositoryImpl struct {
+	DB *helper.DynamoDB
 }
 
-func NewSpendingRepository() SpendingRepository {
-	return &SpendingRepositoryImpl{}
+func NewSpendingRepository(db *helper.DynamoDB) SpendingRepository {
+	return &SpendingRepositoryImpl{DB: db}
 }
 
-func (repository *SpendingRepositoryImpl) Save(ctx context.Context, db *helper.DynamoDB, spending domain.Spending) domain.Spending {
+func (repository *SpendingRepositoryImpl) Save(ctx context.Context, spending domain.Spending) (domain.Spending, error) {
 	item, err := attributevalue.MarshalMap(spending)
 	if err != nil {
-		panic(err)
+		return domain.Spending{}, err
 	}
-	_, err = db.Client.PutItem(ctx, &dynamodb.PutItemInput{
-		TableName: aws.String(db.TableName),
+	_, err = repository.DB.Client.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName: aws.String(repository.DB.TableName),
 		Item:      item,
 	})
 	if err != nil {
-		panic(err)
+		return domain.Spending{}, exception.NewUnavailableError(err)
 	}
-	return spending
+	return spending, nil
 }
 
-func (repository *SpendingRepositoryImpl) Update(ctx context.Context, db *helper.DynamoDB, spending domain.Spending) domain.Spending {
+// SaveIfAbsent saves the spending on the condition that no item has the
+// same id, and returns whether the spending is saved.
+func (repository *SpendingRepositoryImpl) SaveIfAbsent(ctx context.Context, spending domain.Spending) (bool, error) {
+	item, err := attributevalue.MarshalMap(spending)
+	if err != nil {
+		return false, err
+	}
+	expr, err := expression.NewBuilder().WithCondition(expression.AttributeNotExists(expression.Name("Id"))).Build()
+	if err != nil {
+		return false, err
+	}
+
+	_, err = repository.DB.Client.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName:                aws.String(repository.DB.TableName),
+		Item:                     item,
+		ExpressionAttributeNames: expr.Names(),
+		ConditionExpression:      expr.Condition(),
+	})
+	var conditionFailed *types.ConditionalCheckFailedException
+	if errors.As(err, &conditionFailed) {
+		return false, nil
+	}
+	if err != nil {
+		return false, exception.NewUnavailableError(err)
+	}
+	return true, nil
+}
+
+// SaveAll saves the spendings with BatchWriteItem requests of at most
+// batchWriteLimit items. The items DynamoDB leaves unprocessed, when the
+// table is throttled, are written again after an increasing delay.
+func (repository *SpendingRepositoryImpl) SaveAll(ctx context.Context, spendings []domain.Spending) error {
+	for start := 0; start < len(spendings); start += batchWriteLimit {
+		end := min(start+batchWriteLimit, len(spendings))
+
+		requests := make([]types.WriteRequest, 0, end-start)
+		for _, spending := range spendings[start:end] {
+			item, err := attributevalue.MarshalMap(spending)
+			if err != nil {
+				return err
+			}
+			requests = append(requests, types.WriteRequest{PutRequest: &types.PutRequest{Item: item}})
+		}
+		if err := batchWrite(ctx, repository.DB, requests); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (repository *SpendingRepositoryImpl) Update(ctx context.Context, spending domain.Spending) (domain.Spending, error) {
 	spendingId, err := attributevalue.Marshal(spending.Id)
 	if err != nil {
-		panic(err)
+		return domain.Spending{}, err
 	}
 
 	update := expression.Set(expression.Name("Amount"), expression.Value(spending.Amount))
 	update.Set(expression.Name("Date"), expression.Value(spending.Date))
 	update.Set(expression.Name("Category"), expression.Value(spending.Category))
+	update.Set(expression.Name("Type"), expression.Value(spending.Kind()))
+	update.Set(expression.Name("AccountId"), expression.Value(spending.AccountId))
+	// DynamoDB has no empty sets, so a spending without tags has no
+	// attribute of the tags.
+	if len(spending.Tags) > 0 {
+		update.Set(expression.Name("Tags"), expression.Value(&types.AttributeValueMemberSS{Value: spending.Tags}))
+	} else {
+		update.Remove(expression.Name("Tags"))
+	}
 	update.Set(expression.Name("Title"), expression.Value(spending.Title))
 	update.Set(expression.Name("Description"), expression.Value(spending.Description))
 
 	expr, err := expression.NewBuilder().WithUpdate(update).Build()
 	if err != nil {
-		panic(err)
-	} else {
-		_, err := db.Client.UpdateItem(ctx, &dynamodb.UpdateItemInput{
-			TableName:                 aws.String(db.TableName),
-			Key:                       map[string]types.AttributeValue{"Id": spendingId},
-			ExpressionAttributeNames:  expr.Names(),
-			ExpressionAttributeValues: expr.Values(),
-			UpdateExpression:          expr.Update(),
-			ReturnValues:              types.ReturnValueUpdatedNew,
-		})
-		if err != nil {
-			panic(err)
-		}
+		return domain.Spending{}, err
+	}
+
+	_, err = repository.DB.Client.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+		TableName:                 aws.String(repository.DB.TableName),
+		Key:                       map[string]types.AttributeValue{"Id": spendingId},
+		ExpressionAttributeNames:  expr.Names(),
+		ExpressionAttributeValues: expr.Values(),
+		UpdateExpression:          expr.Update(),
+		ReturnValues:              types.ReturnValueUpdatedNew,
+	})
+	if err != nil {
+		return domain.Spending{}, exception.NewUnavailableError(err)
 	}
-	return spending
+	return spending, nil
 }
 
-func (repository *SpendingRepositoryImpl) Delete(ctx context.Context, db *helper.DynamoDB, spending domain.Spending) {
+func (repository *SpendingRepositoryImpl) Delete(ctx context.Context, spending domain.Spending) error {
 	spendingId, err := attributevalue.Marshal(spending.Id)
 	if err != nil {
-		panic(err)
+		return err
 	}
-	_, err = db.Client.DeleteItem(ctx, &dynamodb.DeleteItemInput{
-		TableName: aws.String(db.TableName),
+	_, err = repository.DB.Client.DeleteItem(ctx, &dynamodb.DeleteItemInput{
+		TableName: aws.String(repository.DB.TableName),
 		Key:       map[string]types.AttributeValue{"Id": spendingId},
 	})
 	if err != nil {
-		panic(err)
+		return exception.NewUnavailableError(err)
 	}
+	return nil
 }
 
-func (repository *SpendingRepositoryImpl) FindById(ctx context.Context, db *helper.DynamoDB, spendingId string) (domain.Spending, error) {
+func (repository *SpendingRepositoryImpl) FindById(ctx context.Context, spendingId string) (domain.Spending, error) {
 	spending := domain.Spending{Id: spendingId}
 	id, err := attributevalue.Marshal(spending.Id)
 	if err != nil {
-		panic(err)
+		return domain.Spending{}, err
 	}
 
-	response, err := db.Client.GetItem(ctx, &dynamodb.GetItemInput{
-		TableName: aws.String(db.TableName),
+	response, err := repository.DB.Client.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName: aws.String(repository.DB.TableName),
 		Key:       map[string]types.AttributeValue{"Id": id},
 	})
 
 	if err != nil {
-		panic(err)
+		return domain.Spending{}, exception.NewUnavailableError(err)
 	}
 	if response.Item == nil {
-		panic(exception.NewNotFoundError("item not found"))
+		return domain.Spending{}, exception.NewNotFoundError("item not found")
 	}
 
 	err = attributevalue.UnmarshalMap(response.Item, &spending)
 	if err != nil {
-		panic(err)
+		return domain.Spending{}, err
 	}
 	return spending, err
 }
 
-func (repository *SpendingRepositoryImpl) FindByUserId(ctx context.Context, db *helper.DynamoDB, userId string) []domain.Spending {
-	var spendings []domain.Spending
-
-	keyExpression := expression.Key("UserId").Equal(expression.Value(userId)).
-		And(expression.Key("Date").LessThanEqual(expression.Value(time.Now().UnixMilli())))
+// FindByUserId lists a page of the user's spending history from the
+// `UserIndex` GSI, continuing after query.After when it is set. The date
+// range of the query is applied as the sort key condition of the index, and
+// the other criteria as a filter expression, except for the search text.
+// DynamoDB can only match texts case-sensitively, so the search text is
+// matched against the spendings read instead.
+//
+// DynamoDB returns at most 1 MB of items per request and applies the filter
+// after reading them, so the index is queried until the page is full or the
+// history is exhausted.
+func (repository *SpendingRepositoryImpl) FindByUserId(ctx context.Context, query domain.SpendingQuery) (domain.SpendingPage, error) {
+	page := domain.SpendingPage{}
 
-	expr, err := expression.NewBuilder().WithKeyCondition(keyExpression).Build()
+	builder := expression.NewBuilder().WithKeyCondition(spendingKeyCondition(query))
+	if filter, ok := spendingFilter(query); ok {
+		builder = builder.WithFilter(filter)
+	}
+	expr, err := builder.Build()
 	if err != nil {
-		panic(err)
+		return domain.SpendingPage{}, err
 	}
 
-	response, err := db.Client.Query(ctx, &dynamodb.QueryInput{
-		TableName:                 aws.String(db.TableName),
-		IndexName:                 aws.String("UserIndex"),
-		ExpressionAttributeNames:  expr.Names(),
-		ExpressionAttributeValues: expr.Values(),
-		KeyConditionExpression:    expr.KeyCondition(),
-		ScanIndexForward:          aws.Bool(true),
-	})
+	var startKey map[string]types.AttributeValue
+	if query.After != nil {
+		startKey = spendingIndexKey(query.UserId, *query.After)
+	}
 
-	if err != nil {
-		panic(err)
+	for {
+		response, err := repository.DB.Client.Query(ctx, &dynamodb.QueryInput{
+			TableName:                 aws.String(repository.DB.TableName),
+			IndexName:                 aws.String("UserIndex"),
+			ExpressionAttributeNames:  expr.Names(),
+			ExpressionAttributeValues: expr.Values(),
+			KeyConditionExpression:    expr.KeyCondition(),
+			FilterExpression:          expr.Filter(),
+			ScanIndexForward:          aws.Bool(!query.Descending),
+			ExclusiveStartKey:         startKey,
+			Limit:                     aws.Int32(int32(query.Limit - len(page.Spendings))),
+		})
+		if err != nil {
+			return domain.SpendingPage{}, exception.NewUnavailableError(err)
+		}
+
+		var spendings []domain.Spending
+		err = attributevalue.UnmarshalListOfMaps(response.Items, &spendings)
+		if err != nil {
+			return domain.SpendingPage{}, err
+		}
+		for _, spending := range spendings {
+			if query.Search == "" || query.MatchesSearch(spending) {
+				page.Spendings = append(page.Spendings, spending)
+			}
+		}
+
+		startKey = response.LastEvaluatedKey
+		if startKey == nil || len(page.Spendings) >= query.Limit {
+			break
+		}
 	}
-	if len(response.Items) == 0 {
-		panic(exception.NewNotFoundError("user not found"))
+
+	if startKey != nil && len(page.Spendings) > 0 {
+		last := page.Spendings[len(page.Spendings)-1]
+		page.Next = &domain.SpendingKey{Id: last.Id, Date: last.Date}
 	}
+	return page, nil
+}
 
-	err = attributevalue.UnmarshalListOfMaps(response.Items, &spendings)
-	if err != nil {
-		panic(err)
+// spendingKeyCondition builds the key condition of the `UserIndex` GSI
+// matching the user and the date range of the query.
+func spendingKeyCondition(query domain.SpendingQuery) expression.KeyConditionBuilder {
+	keyCondition := expression.Key("UserId").Equal(expression.Value(query.UserId))
+
+	date := expression.Key("Date")
+	switch {
+	case query.From != nil && query.To != nil:
+		return keyCondition.And(date.Between(expression.Value(*query.From), expression.Value(*query.To)))
+	case query.From != nil:
+		return keyCondition.And(date.GreaterThanEqual(expression.Value(*query.From)))
+	case query.To != nil:
+		return keyCondition.And(date.LessThanEqual(expression.Value(*query.To)))
+	default:
+		return keyCondition
 	}
+}
+
+// spendingFilter builds the filter expression matching the criteria of the
+// query other than the date range and the search text. It returns false when there is no such
+// criteria.
+func spendingFilter(query domain.SpendingQuery) (expression.ConditionBuilder, bool) {
+	var conditions []expression.ConditionBuilder
 
-	return spendings
+	if query.Category != "" {
+		conditions = append(conditions, expression.Name("Category").Equal(expression.Value(query.Category)))
+	}
+	switch query.Type {
+	case domain.SpendingTypeExpense:
+		// The spendings stored before incomes were recorded have no type.
+		conditions = append(conditions, expression.Or(
+			expression.Name("Type").AttributeNotExists(),
+			expression.Name("Type").Equal(expression.Value(query.Type)),
+		))
+	case domain.SpendingTypeIncome:
+		conditions = append(conditions, expression.Name("Type").Equal(expression.Value(query.Type)))
+	}
+	if query.AccountId != "" {
+		conditions = append(conditions, expression.Name("AccountId").Equal(expression.Value(query.AccountId)))
+	}
+	if len(query.AnyTags) > 0 {
+		conditions = append(conditions, joinConditions(expression.Or, tagConditions(query.AnyTags)))
+	}
+	if len(query.AllTags) > 0 {
+		conditions = append(conditions, joinConditions(expression.And, tagConditions(query.AllTags)))
+	}
+	if query.Currency != "" {
+		conditions = append(conditions, expression.Name("Amount.Currency").Equal(expression.Value(query.Currency)))
+	}
+	if query.MinAmount != nil {
+		conditions = append(conditions, expression.Name("Amount.Minor").GreaterThanEqual(expression.Value(*query.MinAmount)))
+	}
+	if query.MaxAmount != nil {
+		conditions = append(conditions, expression.Name("Amount.Minor").LessThanEqual(expression.Value(*query.MaxAmount)))
+	}
+	if len(conditions) == 0 {
+		return expression.ConditionBuilder{}, false
+	}
+	return joinConditions(expression.And, conditions), true
+}
+
+// tagConditions builds the conditions of the spendings having each of the
+// given tags.
+func tagConditions(tags []string) []expression.ConditionBuilder {
+	conditions := make([]expression.ConditionBuilder, 0, len(tags))
+	for _, tag := range tags {
+		conditions = append(conditions, expression.Name("Tags").Contains(tag))
+	}
+	return conditions
+}
+
+// joinConditions joins the non-empty conditions with the given operator,
+// either expression.And or expression.Or, which require two conditions.
+func joinConditions(join func(left, right expression.ConditionBuilder, other ...expression.ConditionBuilder) expression.ConditionBuilder, conditions []expression.ConditionBuilder) expression.ConditionBuilder {
+	if len(conditions) == 1 {
+		return conditions[0]
+	}
+	return join(conditions[0], conditions[1], conditions[2:]...)
+}
+
+// spendingIndexKey builds the key of a spending in the `UserIndex` GSI,
+// which consists of the index key and the table key.
+func spendingIndexKey(userId string, key domain.SpendingKey) map[string]types.AttributeValue {
+	return map[string]types.AttributeValue{
+		"Id":     &types.AttributeValueMemberS{Value: key.Id},
+		"UserId": &types.AttributeValueMemberS{Value: userId},
+		"Date":   &types.AttributeValueMemberN{Value: strconv.FormatInt(key.Date, 10)},
+	}
 }
@@ -0,0 +1,170 @@
+package repository
+
+import (
+	"context"
+	"github.com/refandas/duit-api/exception"
+	"github.com/refandas/duit-api/model/domain"
+	"slices"
+	"sort"
+	"sync"
+)
+
+// SpendingRepositoryMemory is a SpendingRepository keeping the spendings in
+// memory. It is safe for concurrent use.
+//
+// The spendings of a user are listed in the order of the `UserIndex` GSI of
+// the DynamoDB implementation, that is by date, and by id among the
+// spendings of the same date.
+type SpendingRepositoryMemory struct {
+	mutex     sync.RWMutex
+	spendings map[string]domain.Spending
+}
+
+func NewSpendingRepositoryMemory() SpendingRepository {
+	return &SpendingRepositoryMemory{spendings: map[string]domain.Spending{}}
+}
+
+func (repository *SpendingRepositoryMemory) Save(ctx context.Context, spending domain.Spending) (domain.Spending, error) {
+	repository.mutex.Lock()
+	defer repository.mutex.Unlock()
+
+	repository.spendings[spending.Id] = spending
+	return spending, nil
+}
+
+func (repository *SpendingRepositoryMemory) SaveAll(ctx context.Context, spendings []domain.Spending) error {
+	repository.mutex.Lock()
+	defer repository.mutex.Unlock()
+
+	for _, spending := range spendings {
+		repository.spendings[spending.Id] = spending
+	}
+	return nil
+}
+
+func (repository *SpendingRepositoryMemory) SaveIfAbsent(ctx context.Context, spending domain.Spending) (bool, error) {
+	repository.mutex.Lock()
+	defer repository.mutex.Unlock()
+
+	if _, found := repository.spendings[spending.Id]; found {
+		return false, nil
+	}
+	repository.spendings[spending.Id] = spending
+	return true, nil
+}
+
+func (repository *SpendingRepositoryMemory) Update(ctx context.Context, spending domain.Spending) (domain.Spending, error) {
+	repository.mutex.Lock()
+	defer repository.mutex.Unlock()
+
+	stored, found := repository.spendings[spending.Id]
+	if !found {
+		stored = domain.Spending{Id: spending.Id}
+	}
+	stored.Amount = spending.Amount
+	stored.Date = spending.Date
+	stored.Category = spending.Category
+	stored.Type = spending.Type
+	stored.AccountId = spending.AccountId
+	stored.Tags = spending.Tags
+	stored.Title = spending.Title
+	stored.Description = spending.Description
+	repository.spendings[spending.Id] = stored
+	return spending, nil
+}
+
+func (repository *SpendingRepositoryMemory) Delete(ctx context.Context, spending domain.Spending) error {
+	repository.mutex.Lock()
+	defer repository.mutex.Unlock()
+
+	delete(repository.spendings, spending.Id)
+	return nil
+}
+
+func (repository *SpendingRepositoryMemory) FindById(ctx context.Context, spendingId string) (domain.Spending, error) {
+	repository.mutex.RLock()
+	defer repository.mutex.RUnlock()
+
+	spending, found := repository.spendings[spendingId]
+	if !found {
+		return domain.Spending{}, exception.NewNotFoundError("item not found")
+	}
+	return spending, nil
+}
+
+func (repository *SpendingRepositoryMemory) FindByUserId(ctx context.Context, query domain.SpendingQuery) (domain.SpendingPage, error) {
+	repository.mutex.RLock()
+	defer repository.mutex.RUnlock()
+
+	var spendings []domain.Spending
+	for _, spending := range repository.spendings {
+		if spending.UserId == query.UserId && matchSpending(query, spending) {
+			spendings = append(spendings, spending)
+		}
+	}
+	sort.Slice(spendings, func(i, j int) bool {
+		return spendingBefore(spendings[i].Date, spendings[i].Id, spendings[j].Date, spendings[j].Id) != query.Descending
+	})
+
+	if query.After != nil {
+		// Skip the spendings up to and including the key of the last
+		// spending of the previous page.
+		start := sort.Search(len(spendings), func(i int) bool {
+			after := spendingBefore(query.After.Date, query.After.Id, spendings[i].Date, spendings[i].Id)
+			if query.Descending {
+				after = spendingBefore(spendings[i].Date, spendings[i].Id, query.After.Date, query.After.Id)
+			}
+			return after
+		})
+		spendings = spendings[start:]
+	}
+
+	page := domain.SpendingPage{Spendings: spendings}
+	if query.Limit > 0 && len(spendings) > query.Limit {
+		page.Spendings = spendings[:query.Limit]
+		last := page.Spendings[len(page.Spendings)-1]
+		page.Next = &domain.SpendingKey{Id: last.Id, Date: last.Date}
+	}
+	return page, nil
+}
+
+// spendingBefore reports whether the spending with the first date and id
+// comes before the spending with the second date and id in the `UserIndex`
+// order.
+func spendingBefore(date int64, id string, otherDate int64, otherId string) bool {
+	if date != otherDate {
+		return date < otherDate
+	}
+	return id < otherId
+}
+
+// matchSpending reports whether the spending matches the date range and the
+// filter criteria of the query.
+func matchSpending(query domain.SpendingQuery, spending domain.Spending) bool {
+	switch {
+	case query.From != nil && spending.Date < *query.From:
+		return false
+	case query.To != nil && spending.Date > *query.To:
+		return false
+	case query.Category != "" && spending.Category != query.Category:
+		return false
+	case query.Type != "" && spending.Kind() != query.Type:
+		return false
+	case query.AccountId != "" && spending.AccountId != query.AccountId:
+		return false
+	case len(query.AnyTags) > 0 && !slices.ContainsFunc(query.AnyTags, spending.HasTag):
+		return false
+	case slices.ContainsFunc(query.AllTags, func(tag string) bool { return !spending.HasTag(tag) }):
+		return false
+	case query.Currency != "" && spending.Amount.Currency != query.Currency:
+		return false
+	case query.MinAmount != nil && spending.Amount.Minor < *query.MinAmount:
+		return false
+	case query.MaxAmount != nil && spending.Amount.Minor > *query.MaxAmount:
+		return false
+	case query.Search != "" && !query.MatchesSearch(spending):
+		return false
+	default:
+		return true
+	}
+}
@@ -0,0 +1,245 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"github.com/refandas/duit-api/exception"
+	"github.com/refandas/duit-api/model/domain"
+	"strconv"
+	"strings"
+)
+
+// SpendingRepositorySQL is a SpendingRepository keeping the spendings in
+// the `spending` table of a SQL database.
+//
+// The spendings of a user are listed in the order of the `UserIndex` GSI of
+// the DynamoDB implementation, that is by date, and by id among the
+// spendings of the same date.
+type SpendingRepositorySQL struct {
+	DB *sql.DB
+}
+
+func NewSpendingRepositorySQL(db *sql.DB) SpendingRepository {
+	return &SpendingRepositorySQL{DB: db}
+}
+
+const spendingColumns = "id, user_id, title, description, amount_minor, currency, date, category, type, account_id, tags, created_at"
+
+func (repository *SpendingRepositorySQL) Save(ctx context.Context, spending domain.Spending) (domain.Spending, error) {
+	_, err := repository.DB.ExecContext(ctx, `INSERT INTO spending (`+spendingColumns+`)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+		ON CONFLICT (id) DO UPDATE SET user_id = excluded.user_id, title = excluded.title,
+			description = excluded.description, amount_minor = excluded.amount_minor,
+			currency = excluded.currency, date = excluded.date, category = excluded.category,
+			type = excluded.type, account_id = excluded.account_id, tags = excluded.tags,
+			created_at = excluded.created_at`,
+		spending.Id, spending.UserId, spending.Title, spending.Description, spending.Amount.Minor,
+		spending.Amount.Currency, spending.Date, spending.Category, spending.Kind(), spending.AccountId, joinTags(spending.Tags),
+		spending.CreatedAt)
+	if err != nil {
+		return domain.Spending{}, exception.NewUnavailableError(err)
+	}
+	return spending, nil
+}
+
+// SaveIfAbsent saves the spending unless a spending with the same id
+// exists, and returns whether the spending is saved.
+func (repository *SpendingRepositorySQL) SaveIfAbsent(ctx context.Context, spending domain.Spending) (bool, error) {
+	result, err := repository.DB.ExecContext(ctx, `INSERT INTO spending (`+spendingColumns+`)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+		ON CONFLICT (id) DO NOTHING`,
+		spending.Id, spending.UserId, spending.Title, spending.Description, spending.Amount.Minor,
+		spending.Amount.Currency, spending.Date, spending.Category, spending.Kind(), spending.AccountId, joinTags(spending.Tags),
+		spending.CreatedAt)
+	if err != nil {
+		return false, exception.NewUnavailableError(err)
+	}
+	saved, err := result.RowsAffected()
+	if err != nil {
+		return false, exception.NewUnavailableError(err)
+	}
+	return saved > 0, nil
+}
+
+// SaveAll saves the spendings in a single transaction, so either all or
+// none of them are saved.
+func (repository *SpendingRepositorySQL) SaveAll(ctx context.Context, spendings []domain.Spending) error {
+	tx, err := repository.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return exception.NewUnavailableError(err)
+	}
+	defer tx.Rollback()
+
+	for _, spending := range spendings {
+		_, err := tx.ExecContext(ctx, `INSERT INTO spending (`+spendingColumns+`)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)`,
+			spending.Id, spending.UserId, spending.Title, spending.Description, spending.Amount.Minor,
+			spending.Amount.Currency, spending.Date, spending.Category, spending.Kind(), spending.AccountId, joinTags(spending.Tags),
+			spending.CreatedAt)
+		if err != nil {
+			return exception.NewUnavailableError(err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return exception.NewUnavailableError(err)
+	}
+	return nil
+}
+
+func (repository *SpendingRepositorySQL) Update(ctx context.Context, spending domain.Spending) (domain.Spending, error) {
+	_, err := repository.DB.ExecContext(ctx, `UPDATE spending
+		SET amount_minor = $2, currency = $3, date = $4, category = $5, title = $6, description = $7, type = $8,
+			account_id = $9, tags = $10
+		WHERE id = $1`,
+		spending.Id, spending.Amount.Minor, spending.Amount.Currency, spending.Date, spending.Category, spending.Title,
+		spending.Description, spending.Kind(), spending.AccountId, joinTags(spending.Tags))
+	if err != nil {
+		return domain.Spending{}, exception.NewUnavailableError(err)
+	}
+	return spending, nil
+}
+
+func (repository *SpendingRepositorySQL) Delete(ctx context.Context, spending domain.Spending) error {
+	_, err := repository.DB.ExecContext(ctx, "DELETE FROM spending WHERE id = $1", spending.Id)
+	if err != nil {
+		return exception.NewUnavailableError(err)
+	}
+	return nil
+}
+
+func (repository *SpendingRepositorySQL) FindById(ctx context.Context, spendingId string) (domain.Spending, error) {
+	row := repository.DB.QueryRowContext(ctx, "SELECT "+spendingColumns+" FROM spending WHERE id = $1", spendingId)
+	spending, err := scanSpending(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.Spending{}, exception.NewNotFoundError("item not found")
+	}
+	if err != nil {
+		return domain.Spending{}, exception.NewUnavailableError(err)
+	}
+	return spending, nil
+}
+
+// FindByUserId lists a page of the user's spending history, continuing
+// after query.After when it is set. One spending more than the limit is
+// read to know whether there is a next page.
+func (repository *SpendingRepositorySQL) FindByUserId(ctx context.Context, query domain.SpendingQuery) (domain.SpendingPage, error) {
+	var args []any
+	arg := func(value any) string {
+		args = append(args, value)
+		return "$" + strconv.Itoa(len(args))
+	}
+
+	conditions := []string{"user_id = " + arg(query.UserId)}
+	if query.From != nil {
+		conditions = append(conditions, "date >= "+arg(*query.From))
+	}
+	if query.To != nil {
+		conditions = append(conditions, "date <= "+arg(*query.To))
+	}
+	if query.Category != "" {
+		conditions = append(conditions, "category = "+arg(query.Category))
+	}
+	if query.Type != "" {
+		conditions = append(conditions, "type = "+arg(query.Type))
+	}
+	if query.AccountId != "" {
+		conditions = append(conditions, "account_id = "+arg(query.AccountId))
+	}
+	if len(query.AnyTags) > 0 {
+		conditions = append(conditions, "("+strings.Join(tagLikeConditions(query.AnyTags, arg), " OR ")+")")
+	}
+	conditions = append(conditions, tagLikeConditions(query.AllTags, arg)...)
+	if query.Currency != "" {
+		conditions = append(conditions, "currency = "+arg(query.Currency))
+	}
+	if query.MinAmount != nil {
+		conditions = append(conditions, "amount_minor >= "+arg(*query.MinAmount))
+	}
+	if query.MaxAmount != nil {
+		conditions = append(conditions, "amount_minor <= "+arg(*query.MaxAmount))
+	}
+	if query.Search != "" {
+		// LIKE ignores the case of ASCII letters in SQLite only, so both
+		// sides are lowercased to match regardless of case in PostgreSQL too.
+		pattern := arg(containsPattern(strings.ToLower(query.Search)))
+		conditions = append(conditions, "(lower(title) LIKE "+pattern+` ESCAPE '\' OR lower(description) LIKE `+pattern+` ESCAPE '\')`)
+	}
+
+	order, comparison := "ASC", ">"
+	if query.Descending {
+		order, comparison = "DESC", "<"
+	}
+	if query.After != nil {
+		conditions = append(conditions, "(date, id) "+comparison+" ("+arg(query.After.Date)+", "+arg(query.After.Id)+")")
+	}
+
+	statement := "SELECT " + spendingColumns + " FROM spending WHERE " + strings.Join(conditions, " AND ") +
+		" ORDER BY date " + order + ", id " + order
+	if query.Limit > 0 {
+		statement += " LIMIT " + arg(query.Limit+1)
+	}
+
+	rows, err := repository.DB.QueryContext(ctx, statement, args...)
+	if err != nil {
+		return domain.SpendingPage{}, exception.NewUnavailableError(err)
+	}
+	defer rows.Close()
+
+	page := domain.SpendingPage{}
+	for rows.Next() {
+		spending, err := scanSpending(rows)
+		if err != nil {
+			return domain.SpendingPage{}, exception.NewUnavailableError(err)
+		}
+		page.Spendings = append(page.Spendings, spending)
+	}
+	if err := rows.Err(); err != nil {
+		return domain.SpendingPage{}, exception.NewUnavailableError(err)
+	}
+
+	if query.Limit > 0 && len(page.Spendings) > query.Limit {
+		page.Spendings = page.Spendings[:query.Limit]
+		last := page.Spendings[len(page.Spendings)-1]
+		page.Next = &domain.SpendingKey{Id: last.Id, Date: last.Date}
+	}
+	return page, nil
+}
+
+func scanSpending(row rowScanner) (domain.Spending, error) {
+	spending := domain.Spending{}
+	var tags string
+	err := row.Scan(&spending.Id, &spending.UserId, &spending.Title, &spending.Description,
+		&spending.Amount.Minor, &spending.Amount.Currency, &spending.Date, &spending.Category, &spending.Type, &spending.AccountId,
+		&tags, &spending.CreatedAt)
+	spending.Tags = splitTags(tags)
+	return spending, err
+}
+
+// tagLikeConditions returns the conditions of the spendings having each of
+// the given tags, whose values are added by the arg function.
+func tagLikeConditions(tags []string, arg func(value any) string) []string {
+	conditions := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		conditions = append(conditions, "tags LIKE "+arg(containsPattern(","+tag+","))+` ESCAPE '\'`)
+	}
+	return conditions
+}
+
+// joinTags returns the value of the `tags` column of the tags, which keeps
+// the tags between commas.
+func joinTags(tags []string) string {
+	if len(tags) == 0 {
+		return ""
+	}
+	return "," + strings.Join(tags, ",") + ","
+}
+
+// splitTags returns the tags of the value of the `tags` column.
+func splitTags(column string) []string {
+	column = strings.Trim(column, ",")
+	if column == "" {
+		return nil
+	}
+	return strings.Split(column, ",")
+}
@@ -0,0 +1,37 @@
+package repository
+
+import (
+	"errors"
+	"strings"
+)
+
+// rowScanner is implemented by both sql.Row and sql.Rows, so a row can be
+// scanned the same way whether it is queried alone or in a list.
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+// likeEscaper escapes the wildcards of a LIKE pattern, which is matched
+// with `\` as the escape character.
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// containsPattern returns the LIKE pattern matching a text that contains
+// the given text.
+func containsPattern(text string) string {
+	return "%" + likeEscaper.Replace(text) + "%"
+}
+
+// isUniqueViolation reports whether the error is a violation of a unique
+// constraint, as reported by either the PostgreSQL driver (SQLSTATE 23505)
+// or the SQLite driver (SQLITE_CONSTRAINT_UNIQUE).
+func isUniqueViolation(err error) bool {
+	var postgresError interface{ SQLState() string }
+	if errors.As(err, &postgresError) {
+		return postgresError.SQLState() == "23505"
+	}
+	var sqliteError interface{ Code() int }
+	if errors.As(err, &sqliteError) {
+		return sqliteError.Code() == 2067
+	}
+	return false
+}
@@ -0,0 +1,13 @@
+package repository
+
+import (
+	"context"
+	"github.com/refandas/duit-api/model/domain"
+)
+
+type TransferRepository interface {
+	Save(ctx context.Context, transfer domain.Transfer) (domain.Transfer, error)
+	Delete(ctx context.Context, transfer domain.Transfer) error
+	FindById(ctx context.Context, transferId string) (domain.Transfer, error)
+	FindByAccountId(ctx context.Context, accountId string) ([]domain.Transfer, error)
+}
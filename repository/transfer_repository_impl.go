@@ -0,0 +1,168 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/expression"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/refandas/duit-api/exception"
+	"github.com/refandas/duit-api/helper"
+	"github.com/refandas/duit-api/model/domain"
+)
+
+// TransferRepositoryImpl is a TransferRepository keeping the transfers in a
+// DynamoDB table. The accounts of the transfers are checked in the table of
+// the accounts.
+type TransferRepositoryImpl struct {
+	DB       *helper.DynamoDB
+	Accounts *helper.DynamoDB
+}
+
+func NewTransferRepository(db *helper.DynamoDB, accounts *helper.DynamoDB) TransferRepository {
+	return &TransferRepositoryImpl{DB: db, Accounts: accounts}
+}
+
+// Save saves the transfer with a single TransactWriteItems request, which
+// puts the transfer on the condition that both of its accounts exist and
+// belong to its user. The transfer debits and credits the accounts at once,
+// or not at all when an account is deleted meanwhile, in which case a not
+// found error is returned.
+func (repository *TransferRepositoryImpl) Save(ctx context.Context, transfer domain.Transfer) (domain.Transfer, error) {
+	item, err := attributevalue.MarshalMap(transfer)
+	if err != nil {
+		return domain.Transfer{}, err
+	}
+	owned, err := expression.NewBuilder().WithCondition(expression.And(
+		expression.AttributeExists(expression.Name("Id")),
+		expression.Name("UserId").Equal(expression.Value(transfer.UserId)),
+	)).Build()
+	if err != nil {
+		return domain.Transfer{}, err
+	}
+	absent, err := expression.NewBuilder().WithCondition(expression.AttributeNotExists(expression.Name("Id"))).Build()
+	if err != nil {
+		return domain.Transfer{}, err
+	}
+
+	items := []types.TransactWriteItem{{
+		Put: &types.Put{
+			TableName:                aws.String(repository.DB.TableName),
+			Item:                     item,
+			ExpressionAttributeNames: absent.Names(),
+			ConditionExpression:      absent.Condition(),
+		},
+	}}
+	for _, accountId := range []string{transfer.FromAccountId, transfer.ToAccountId} {
+		items = append(items, types.TransactWriteItem{
+			ConditionCheck: &types.ConditionCheck{
+				TableName:                 aws.String(repository.Accounts.TableName),
+				Key:                       map[string]types.AttributeValue{"Id": &types.AttributeValueMemberS{Value: accountId}},
+				ExpressionAttributeNames:  owned.Names(),
+				ExpressionAttributeValues: owned.Values(),
+				ConditionExpression:       owned.Condition(),
+			},
+		})
+	}
+
+	_, err = repository.DB.Client.TransactWriteItems(ctx, &dynamodb.TransactWriteItemsInput{
+		TransactItems: items,
+	})
+	var canceled *types.TransactionCanceledException
+	if errors.As(err, &canceled) {
+		for i, reason := range canceled.CancellationReasons {
+			if i > 0 && aws.ToString(reason.Code) == "ConditionalCheckFailed" {
+				return domain.Transfer{}, exception.NewNotFoundError("account not found")
+			}
+		}
+	}
+	if err != nil {
+		return domain.Transfer{}, exception.NewUnavailableError(err)
+	}
+	return transfer, nil
+}
+
+func (repository *TransferRepositoryImpl) Delete(ctx context.Context, transfer domain.Transfer) error {
+	transferId, err := attributevalue.Marshal(transfer.Id)
+	if err != nil {
+		return err
+	}
+	_, err = repository.DB.Client.DeleteItem(ctx, &dynamodb.DeleteItemInput{
+		TableName: aws.String(repository.DB.TableName),
+		Key:       map[string]types.AttributeValue{"Id": transferId},
+	})
+	if err != nil {
+		return exception.NewUnavailableError(err)
+	}
+	return nil
+}
+
+func (repository *TransferRepositoryImpl) FindById(ctx context.Context, transferId string) (domain.Transfer, error) {
+	transfer := domain.Transfer{Id: transferId}
+	id, err := attributevalue.Marshal(transfer.Id)
+	if err != nil {
+		return domain.Transfer{}, err
+	}
+
+	response, err := repository.DB.Client.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName: aws.String(repository.DB.TableName),
+		Key:       map[string]types.AttributeValue{"Id": id},
+	})
+	if err != nil {
+		return domain.Transfer{}, exception.NewUnavailableError(err)
+	}
+	if response.Item == nil {
+		return domain.Transfer{}, exception.NewNotFoundError("transfer not found")
+	}
+
+	err = attributevalue.UnmarshalMap(response.Item, &transfer)
+	if err != nil {
+		return domain.Transfer{}, err
+	}
+	return transfer, err
+}
+
+// FindByAccountId lists the transfers out of the account from the
+// `FromAccountIndex` GSI and the transfers into the account from the
+// `ToAccountIndex` GSI, ordered by date.
+func (repository *TransferRepositoryImpl) FindByAccountId(ctx context.Context, accountId string) ([]domain.Transfer, error) {
+	var transfers []domain.Transfer
+	for _, index := range []struct {
+		name      string
+		attribute string
+	}{
+		{"FromAccountIndex", "FromAccountId"},
+		{"ToAccountIndex", "ToAccountId"},
+	} {
+		keyExpression := expression.Key(index.attribute).Equal(expression.Value(accountId))
+		expr, err := expression.NewBuilder().WithKeyCondition(keyExpression).Build()
+		if err != nil {
+			return nil, err
+		}
+
+		paginator := dynamodb.NewQueryPaginator(repository.DB.Client, &dynamodb.QueryInput{
+			TableName:                 aws.String(repository.DB.TableName),
+			IndexName:                 aws.String(index.name),
+			ExpressionAttributeNames:  expr.Names(),
+			ExpressionAttributeValues: expr.Values(),
+			KeyConditionExpression:    expr.KeyCondition(),
+		})
+		for paginator.HasMorePages() {
+			response, err := paginator.NextPage(ctx)
+			if err != nil {
+				return nil, exception.NewUnavailableError(err)
+			}
+
+			var page []domain.Transfer
+			err = attributevalue.UnmarshalListOfMaps(response.Items, &page)
+			if err != nil {
+				return nil, err
+			}
+			transfers = append(transfers, page...)
+		}
+	}
+	sortTransfers(transfers)
+	return transfers, nil
+}
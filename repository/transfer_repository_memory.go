@@ -0,0 +1,83 @@
+package repository
+
+import (
+	"context"
+	"github.com/refandas/duit-api/exception"
+	"github.com/refandas/duit-api/model/domain"
+	"sort"
+	"sync"
+)
+
+// TransferRepositoryMemory is a TransferRepository keeping the transfers in
+// memory. It is safe for concurrent use.
+type TransferRepositoryMemory struct {
+	mutex     sync.RWMutex
+	transfers map[string]domain.Transfer
+	accounts  AccountRepository
+}
+
+// NewTransferRepositoryMemory returns a TransferRepositoryMemory saving the
+// transfers between the accounts of the given repository.
+func NewTransferRepositoryMemory(accountRepository AccountRepository) TransferRepository {
+	return &TransferRepositoryMemory{transfers: map[string]domain.Transfer{}, accounts: accountRepository}
+}
+
+// Save saves the transfer when both of its accounts belong to its user, and
+// returns a not found error otherwise.
+func (repository *TransferRepositoryMemory) Save(ctx context.Context, transfer domain.Transfer) (domain.Transfer, error) {
+	repository.mutex.Lock()
+	defer repository.mutex.Unlock()
+
+	for _, accountId := range []string{transfer.FromAccountId, transfer.ToAccountId} {
+		account, err := repository.accounts.FindById(ctx, accountId)
+		if err != nil {
+			return domain.Transfer{}, err
+		}
+		if account.UserId != transfer.UserId {
+			return domain.Transfer{}, exception.NewNotFoundError("account not found")
+		}
+	}
+	repository.transfers[transfer.Id] = transfer
+	return transfer, nil
+}
+
+func (repository *TransferRepositoryMemory) Delete(ctx context.Context, transfer domain.Transfer) error {
+	repository.mutex.Lock()
+	defer repository.mutex.Unlock()
+
+	delete(repository.transfers, transfer.Id)
+	return nil
+}
+
+func (repository *TransferRepositoryMemory) FindById(ctx context.Context, transferId string) (domain.Transfer, error) {
+	repository.mutex.RLock()
+	defer repository.mutex.RUnlock()
+
+	transfer, found := repository.transfers[transferId]
+	if !found {
+		return domain.Transfer{}, exception.NewNotFoundError("transfer not found")
+	}
+	return transfer, nil
+}
+
+func (repository *TransferRepositoryMemory) FindByAccountId(ctx context.Context, accountId string) ([]domain.Transfer, error) {
+	repository.mutex.RLock()
+	defer repository.mutex.RUnlock()
+
+	var transfers []domain.Transfer
+	for _, transfer := range repository.transfers {
+		if transfer.FromAccountId == accountId || transfer.ToAccountId == accountId {
+			transfers = append(transfers, transfer)
+		}
+	}
+	sortTransfers(transfers)
+	return transfers, nil
+}
+
+// sortTransfers sorts the transfers by date, and by id among the transfers
+// of the same date.
+func sortTransfers(transfers []domain.Transfer) {
+	sort.Slice(transfers, func(i, j int) bool {
+		return spendingBefore(transfers[i].Date, transfers[i].Id, transfers[j].Date, transfers[j].Id)
+	})
+}
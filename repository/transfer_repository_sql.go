@@ -0,0 +1,106 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"github.com/refandas/duit-api/exception"
+	"github.com/refandas/duit-api/model/domain"
+)
+
+// TransferRepositorySQL is a TransferRepository keeping the transfers in the
+// `transfers` table of a SQL database.
+type TransferRepositorySQL struct {
+	DB *sql.DB
+}
+
+func NewTransferRepositorySQL(db *sql.DB) TransferRepository {
+	return &TransferRepositorySQL{DB: db}
+}
+
+const transferColumns = "id, user_id, from_account_id, to_account_id, amount_minor, currency, " +
+	"received_amount_minor, received_currency, description, date, created_at"
+
+// Save saves the transfer in a transaction checking that both of its
+// accounts belong to its user, and returns a not found error otherwise.
+func (repository *TransferRepositorySQL) Save(ctx context.Context, transfer domain.Transfer) (domain.Transfer, error) {
+	tx, err := repository.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return domain.Transfer{}, exception.NewUnavailableError(err)
+	}
+	defer tx.Rollback()
+
+	var accounts int
+	err = tx.QueryRowContext(ctx, "SELECT COUNT(*) FROM accounts WHERE id IN ($1, $2) AND user_id = $3",
+		transfer.FromAccountId, transfer.ToAccountId, transfer.UserId).Scan(&accounts)
+	if err != nil {
+		return domain.Transfer{}, exception.NewUnavailableError(err)
+	}
+	if accounts != 2 {
+		return domain.Transfer{}, exception.NewNotFoundError("account not found")
+	}
+
+	_, err = tx.ExecContext(ctx, `INSERT INTO transfers (`+transferColumns+`)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)`,
+		transfer.Id, transfer.UserId, transfer.FromAccountId, transfer.ToAccountId, transfer.Amount.Minor,
+		transfer.Amount.Currency, transfer.ReceivedAmount.Minor, transfer.ReceivedAmount.Currency,
+		transfer.Description, transfer.Date, transfer.CreatedAt)
+	if err != nil {
+		return domain.Transfer{}, exception.NewUnavailableError(err)
+	}
+	if err := tx.Commit(); err != nil {
+		return domain.Transfer{}, exception.NewUnavailableError(err)
+	}
+	return transfer, nil
+}
+
+func (repository *TransferRepositorySQL) Delete(ctx context.Context, transfer domain.Transfer) error {
+	_, err := repository.DB.ExecContext(ctx, "DELETE FROM transfers WHERE id = $1", transfer.Id)
+	if err != nil {
+		return exception.NewUnavailableError(err)
+	}
+	return nil
+}
+
+func (repository *TransferRepositorySQL) FindById(ctx context.Context, transferId string) (domain.Transfer, error) {
+	row := repository.DB.QueryRowContext(ctx, "SELECT "+transferColumns+" FROM transfers WHERE id = $1", transferId)
+	transfer, err := scanTransfer(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.Transfer{}, exception.NewNotFoundError("transfer not found")
+	}
+	if err != nil {
+		return domain.Transfer{}, exception.NewUnavailableError(err)
+	}
+	return transfer, nil
+}
+
+func (repository *TransferRepositorySQL) FindByAccountId(ctx context.Context, accountId string) ([]domain.Transfer, error) {
+	rows, err := repository.DB.QueryContext(ctx, "SELECT "+transferColumns+` FROM transfers
+		WHERE from_account_id = $1 OR to_account_id = $1
+		ORDER BY date, id`, accountId)
+	if err != nil {
+		return nil, exception.NewUnavailableError(err)
+	}
+	defer rows.Close()
+
+	var transfers []domain.Transfer
+	for rows.Next() {
+		transfer, err := scanTransfer(rows)
+		if err != nil {
+			return nil, exception.NewUnavailableError(err)
+		}
+		transfers = append(transfers, transfer)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, exception.NewUnavailableError(err)
+	}
+	return transfers, nil
+}
+
+func scanTransfer(row rowScanner) (domain.Transfer, error) {
+	transfer := domain.Transfer{}
+	err := row.Scan(&transfer.Id, &transfer.UserId, &transfer.FromAccountId, &transfer.ToAccountId,
+		&transfer.Amount.Minor, &transfer.Amount.Currency, &transfer.ReceivedAmount.Minor,
+		&transfer.ReceivedAmount.Currency, &transfer.Description, &transfer.Date, &transfer.CreatedAt)
+	return transfer, err
+}
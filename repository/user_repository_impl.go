@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"errors"
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
 	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/expression"
@@ -10,100 +11,330 @@ import (
 	"github.com/refandas/duit-api/exception"
 	"github.com/refandas/duit-api/helper"
 	"github.com/refandas/duit-api/model/domain"
-	"log"
+	"strings"
 )
 
 type UserRepositoryImpl struct {
+	DB *helper.DynamoDB
 }
 
-func NewUserRepository() UserRepository {
-	return &UserRepositoryImpl{}
+func NewUserRepository(db *helper.DynamoDB) UserRepository {
+	return &UserRepositoryImpl{DB: db}
 }
 
-func (repository *UserRepositoryImpl) Save(ctx context.Context, db *helper.DynamoDB, user domain.User) domain.User {
-
+// Save puts the user together with the `EMAIL#` guard item of its email in
+// a single TransactWriteItems request. The guard item is put on the
+// condition that the email is not registered to another user, which keeps
+// the emails unique even when two users register the same email at once, in
+// which case a conflict error is returned.
+func (repository *UserRepositoryImpl) Save(ctx context.Context, user domain.User) (domain.User, error) {
+	if err := repository.checkUnguardedEmail(ctx, user); err != nil {
+		return domain.User{}, err
+	}
 	item, err := attributevalue.MarshalMap(user)
 	if err != nil {
-		panic(err)
+		return domain.User{}, err
 	}
-	_, err = db.Client.PutItem(ctx, &dynamodb.PutItemInput{
-		TableName: aws.String(db.TableName),
-		Item:      item,
+	guard, err := repository.emailGuard(user)
+	if err != nil {
+		return domain.User{}, err
+	}
+
+	_, err = repository.DB.Client.TransactWriteItems(ctx, &dynamodb.TransactWriteItemsInput{
+		TransactItems: []types.TransactWriteItem{
+			{Put: &types.Put{TableName: aws.String(repository.DB.TableName), Item: item}},
+			{Put: guard},
+		},
 	})
+	if emailConflict(err, 1) {
+		return domain.User{}, exception.NewConflictError("email is already registered")
+	}
 	if err != nil {
-		panic(err)
+		return domain.User{}, exception.NewUnavailableError(err)
 	}
-	return user
+	return user, nil
 }
 
-func (repository *UserRepositoryImpl) Update(ctx context.Context, db *helper.DynamoDB, user domain.User) domain.User {
-	userId, err := attributevalue.Marshal(user.Id)
+// Update updates the user in a single TransactWriteItems request. When the
+// email changes, the guard item of the new email is put on the same
+// condition as in Save, and the guard item of the old email is deleted. The
+// update is conditioned on the old email, so a concurrent change of the
+// email cannot leave a guard item behind.
+func (repository *UserRepositoryImpl) Update(ctx context.Context, user domain.User) (domain.User, error) {
+	stored, err := repository.FindById(ctx, user.Id)
 	if err != nil {
-		panic(err)
+		return domain.User{}, err
 	}
 
 	update := expression.Set(expression.Name("Name"), expression.Value(user.Name))
 	update.Set(expression.Name("Email"), expression.Value(user.Email))
+	update.Set(expression.Name("TimeZone"), expression.Value(user.TimeZone))
+	update.Set(expression.Name("Language"), expression.Value(user.Language))
+	update.Set(expression.Name("HomeCurrency"), expression.Value(user.HomeCurrency))
 
 	if user.Password != "" {
 		update.Set(expression.Name("Password"), expression.Value(user.Password))
 	}
 
-	expr, err := expression.NewBuilder().WithUpdate(update).Build()
+	condition := expression.Name("Email").Equal(expression.Value(stored.Email))
+	expr, err := expression.NewBuilder().WithUpdate(update).WithCondition(condition).Build()
 	if err != nil {
-		log.Printf("Couldn't build expression for update. Here's why: %v\n", err)
-	} else {
-		_, err := db.Client.UpdateItem(ctx, &dynamodb.UpdateItemInput{
-			TableName:                 aws.String(db.TableName),
-			Key:                       map[string]types.AttributeValue{"Id": userId},
+		return domain.User{}, err
+	}
+
+	items := []types.TransactWriteItem{{
+		Update: &types.Update{
+			TableName:                 aws.String(repository.DB.TableName),
+			Key:                       map[string]types.AttributeValue{"Id": &types.AttributeValueMemberS{Value: user.Id}},
 			ExpressionAttributeNames:  expr.Names(),
 			ExpressionAttributeValues: expr.Values(),
 			UpdateExpression:          expr.Update(),
-			ReturnValues:              types.ReturnValueUpdatedNew,
-		})
+			ConditionExpression:       expr.Condition(),
+		},
+	}}
+	if emailKey(stored.Email) != emailKey(user.Email) {
+		if err := repository.checkUnguardedEmail(ctx, user); err != nil {
+			return domain.User{}, err
+		}
+		guard, err := repository.emailGuard(user)
 		if err != nil {
-			log.Printf("Couldn't update user. Here's why: %v\n", err)
+			return domain.User{}, err
 		}
+		items = append(items, types.TransactWriteItem{Put: guard}, types.TransactWriteItem{
+			Delete: &types.Delete{
+				TableName: aws.String(repository.DB.TableName),
+				Key:       map[string]types.AttributeValue{"Id": &types.AttributeValueMemberS{Value: emailKey(stored.Email)}},
+			},
+		})
+	}
+
+	_, err = repository.DB.Client.TransactWriteItems(ctx, &dynamodb.TransactWriteItemsInput{
+		TransactItems: items,
+	})
+	if emailConflict(err, 0) {
+		return domain.User{}, exception.NewConflictError("the user was changed meanwhile, try again")
 	}
-	return user
+	if emailConflict(err, 1) {
+		return domain.User{}, exception.NewConflictError("email is already registered")
+	}
+	if err != nil {
+		return domain.User{}, exception.NewUnavailableError(err)
+	}
+	return user, nil
 }
 
-func (repository *UserRepositoryImpl) Delete(ctx context.Context, db *helper.DynamoDB, user domain.User) {
+// Delete deletes the user and then the guard item of its email, so the
+// email can be registered again.
+func (repository *UserRepositoryImpl) Delete(ctx context.Context, user domain.User) error {
 	userId, err := attributevalue.Marshal(user.Id)
 	if err != nil {
-		panic(err)
+		return err
 	}
-	_, err = db.Client.DeleteItem(ctx, &dynamodb.DeleteItemInput{
-		TableName: aws.String(db.TableName),
-		Key:       map[string]types.AttributeValue{"Id": userId},
+	response, err := repository.DB.Client.DeleteItem(ctx, &dynamodb.DeleteItemInput{
+		TableName:    aws.String(repository.DB.TableName),
+		Key:          map[string]types.AttributeValue{"Id": userId},
+		ReturnValues: types.ReturnValueAllOld,
 	})
 	if err != nil {
-		panic(err)
+		return exception.NewUnavailableError(err)
+	}
+
+	deleted := domain.User{}
+	err = attributevalue.UnmarshalMap(response.Attributes, &deleted)
+	if err != nil {
+		return err
+	}
+	if deleted.Email == "" {
+		return nil
+	}
+
+	owned, err := expression.NewBuilder().WithCondition(expression.Name("UserId").Equal(expression.Value(user.Id))).Build()
+	if err != nil {
+		return err
 	}
+	_, err = repository.DB.Client.DeleteItem(ctx, &dynamodb.DeleteItemInput{
+		TableName:                 aws.String(repository.DB.TableName),
+		Key:                       map[string]types.AttributeValue{"Id": &types.AttributeValueMemberS{Value: emailKey(deleted.Email)}},
+		ExpressionAttributeNames:  owned.Names(),
+		ExpressionAttributeValues: owned.Values(),
+		ConditionExpression:       owned.Condition(),
+	})
+	var conditionFailed *types.ConditionalCheckFailedException
+	if err != nil && !errors.As(err, &conditionFailed) {
+		return exception.NewUnavailableError(err)
+	}
+	return nil
 }
 
-func (repository *UserRepositoryImpl) FindById(ctx context.Context, db *helper.DynamoDB, userId string) (domain.User, error) {
+func (repository *UserRepositoryImpl) FindById(ctx context.Context, userId string) (domain.User, error) {
 	user := domain.User{Id: userId}
 	id, err := attributevalue.Marshal(user.Id)
 	if err != nil {
-		panic(err)
+		return domain.User{}, err
 	}
 
-	response, err := db.Client.GetItem(ctx, &dynamodb.GetItemInput{
-		TableName: aws.String(db.TableName),
+	response, err := repository.DB.Client.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName: aws.String(repository.DB.TableName),
 		Key:       map[string]types.AttributeValue{"Id": id},
 	})
 
 	if err != nil {
-		panic(err)
+		return domain.User{}, exception.NewUnavailableError(err)
 	}
 	if response.Item == nil {
-		panic(exception.NewNotFoundError("item not found"))
+		return domain.User{}, exception.NewNotFoundError("item not found")
 	}
 
 	err = attributevalue.UnmarshalMap(response.Item, &user)
 	if err != nil {
-		panic(err)
+		return domain.User{}, err
 	}
 	return user, err
 }
+
+// FindByEmail looks up the user registered with the given email address
+// using the `EmailIndex` GSI.
+func (repository *UserRepositoryImpl) FindByEmail(ctx context.Context, email string) (domain.User, bool, error) {
+	keyExpression := expression.Key("Email").Equal(expression.Value(email))
+	expr, err := expression.NewBuilder().WithKeyCondition(keyExpression).Build()
+	if err != nil {
+		return domain.User{}, false, err
+	}
+
+	response, err := repository.DB.Client.Query(ctx, &dynamodb.QueryInput{
+		TableName:                 aws.String(repository.DB.TableName),
+		IndexName:                 aws.String("EmailIndex"),
+		ExpressionAttributeNames:  expr.Names(),
+		ExpressionAttributeValues: expr.Values(),
+		KeyConditionExpression:    expr.KeyCondition(),
+		Limit:                     aws.Int32(1),
+	})
+	if err != nil {
+		return domain.User{}, false, exception.NewUnavailableError(err)
+	}
+	if len(response.Items) == 0 {
+		return domain.User{}, false, nil
+	}
+
+	user := domain.User{}
+	err = attributevalue.UnmarshalMap(response.Items[0], &user)
+	if err != nil {
+		return domain.User{}, false, err
+	}
+	return user, true, nil
+}
+
+// GuardEmails puts the missing guard items of the emails of the users
+// registered before the emails were guarded, and returns the number of
+// guarded emails and the emails registered to more than one user, which
+// stay guarded for one of their users only.
+//
+// The guard items are put on the same condition as in Save, so the
+// migration can be run again and while the API server is running.
+func (repository *UserRepositoryImpl) GuardEmails(ctx context.Context) (int, []string, error) {
+	expr, err := expression.NewBuilder().
+		WithFilter(expression.AttributeExists(expression.Name("Email"))).
+		WithProjection(expression.NamesList(expression.Name("Id"), expression.Name("Email"))).
+		Build()
+	if err != nil {
+		return 0, nil, err
+	}
+
+	guarded := 0
+	var duplicates []string
+	paginator := dynamodb.NewScanPaginator(repository.DB.Client, &dynamodb.ScanInput{
+		TableName:                 aws.String(repository.DB.TableName),
+		ExpressionAttributeNames:  expr.Names(),
+		ExpressionAttributeValues: expr.Values(),
+		FilterExpression:          expr.Filter(),
+		ProjectionExpression:      expr.Projection(),
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return guarded, duplicates, err
+		}
+
+		for _, item := range page.Items {
+			user := domain.User{}
+			if err := attributevalue.UnmarshalMap(item, &user); err != nil {
+				return guarded, duplicates, err
+			}
+			guard, err := repository.emailGuard(user)
+			if err != nil {
+				return guarded, duplicates, err
+			}
+
+			_, err = repository.DB.Client.PutItem(ctx, &dynamodb.PutItemInput{
+				TableName:                 guard.TableName,
+				Item:                      guard.Item,
+				ExpressionAttributeNames:  guard.ExpressionAttributeNames,
+				ExpressionAttributeValues: guard.ExpressionAttributeValues,
+				ConditionExpression:       guard.ConditionExpression,
+			})
+			var conditionFailed *types.ConditionalCheckFailedException
+			if errors.As(err, &conditionFailed) {
+				duplicates = append(duplicates, strings.ToLower(user.Email))
+				continue
+			}
+			if err != nil {
+				return guarded, duplicates, err
+			}
+			guarded++
+		}
+	}
+	return guarded, duplicates, nil
+}
+
+// checkUnguardedEmail returns a conflict error when the email of the user
+// is registered to another user through the `EmailIndex` GSI, which covers
+// the users registered before the emails were guarded until their guard
+// items are put by GuardEmails.
+func (repository *UserRepositoryImpl) checkUnguardedEmail(ctx context.Context, user domain.User) error {
+	registered, found, err := repository.FindByEmail(ctx, user.Email)
+	if err != nil {
+		return err
+	}
+	if found && registered.Id != user.Id {
+		return exception.NewConflictError("email is already registered")
+	}
+	return nil
+}
+
+// emailKey returns the Id of the guard item reserving the email for a user.
+// Emails are compared regardless of their case.
+func emailKey(email string) string {
+	return "EMAIL#" + strings.ToLower(email)
+}
+
+// emailGuard returns the put of the guard item reserving the email of the
+// user, on the condition that the email is not reserved for another user.
+func (repository *UserRepositoryImpl) emailGuard(user domain.User) (*types.Put, error) {
+	available, err := expression.NewBuilder().WithCondition(expression.Or(
+		expression.AttributeNotExists(expression.Name("Id")),
+		expression.Name("UserId").Equal(expression.Value(user.Id)),
+	)).Build()
+	if err != nil {
+		return nil, err
+	}
+	return &types.Put{
+		TableName: aws.String(repository.DB.TableName),
+		Item: map[string]types.AttributeValue{
+			"Id":     &types.AttributeValueMemberS{Value: emailKey(user.Email)},
+			"UserId": &types.AttributeValueMemberS{Value: user.Id},
+		},
+		ExpressionAttributeNames:  available.Names(),
+		ExpressionAttributeValues: available.Values(),
+		ConditionExpression:       available.Condition(),
+	}, nil
+}
+
+// emailConflict reports whether the error is a canceled transaction whose
+// item at the given index failed its condition.
+func emailConflict(err error, index int) bool {
+	var canceled *types.TransactionCanceledException
+	if !errors.As(err, &canceled) || index >= len(canceled.CancellationReasons) {
+		return false
+	}
+	return aws.ToString(canceled.CancellationReasons[index].Code) == "ConditionalCheckFailed"
+}
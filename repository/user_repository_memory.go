@@ -0,0 +1,96 @@
+package repository
+
+import (
+	"context"
+	"github.com/refandas/duit-api/exception"
+	"github.com/refandas/duit-api/model/domain"
+	"strings"
+	"sync"
+)
+
+// UserRepositoryMemory is a UserRepository keeping the users in memory.
+// It is safe for concurrent use.
+type UserRepositoryMemory struct {
+	mutex sync.RWMutex
+	users map[string]domain.User
+}
+
+func NewUserRepositoryMemory() UserRepository {
+	return &UserRepositoryMemory{users: map[string]domain.User{}}
+}
+
+func (repository *UserRepositoryMemory) Save(ctx context.Context, user domain.User) (domain.User, error) {
+	repository.mutex.Lock()
+	defer repository.mutex.Unlock()
+
+	if repository.emailTaken(user) {
+		return domain.User{}, exception.NewConflictError("email is already registered")
+	}
+	repository.users[user.Id] = user
+	return user, nil
+}
+
+func (repository *UserRepositoryMemory) Update(ctx context.Context, user domain.User) (domain.User, error) {
+	repository.mutex.Lock()
+	defer repository.mutex.Unlock()
+
+	if repository.emailTaken(user) {
+		return domain.User{}, exception.NewConflictError("email is already registered")
+	}
+	stored, found := repository.users[user.Id]
+	if !found {
+		stored = domain.User{Id: user.Id}
+	}
+	stored.Name = user.Name
+	stored.Email = user.Email
+	stored.TimeZone = user.TimeZone
+	stored.Language = user.Language
+	stored.HomeCurrency = user.HomeCurrency
+	if user.Password != "" {
+		stored.Password = user.Password
+	}
+	repository.users[user.Id] = stored
+	return user, nil
+}
+
+func (repository *UserRepositoryMemory) Delete(ctx context.Context, user domain.User) error {
+	repository.mutex.Lock()
+	defer repository.mutex.Unlock()
+
+	delete(repository.users, user.Id)
+	return nil
+}
+
+func (repository *UserRepositoryMemory) FindById(ctx context.Context, userId string) (domain.User, error) {
+	repository.mutex.RLock()
+	defer repository.mutex.RUnlock()
+
+	user, found := repository.users[userId]
+	if !found {
+		return domain.User{}, exception.NewNotFoundError("item not found")
+	}
+	return user, nil
+}
+
+func (repository *UserRepositoryMemory) FindByEmail(ctx context.Context, email string) (domain.User, bool, error) {
+	repository.mutex.RLock()
+	defer repository.mutex.RUnlock()
+
+	for _, user := range repository.users {
+		if user.Email == email {
+			return user, true, nil
+		}
+	}
+	return domain.User{}, false, nil
+}
+
+// emailTaken reports whether the email of the user belongs to another user.
+// The caller must hold the mutex.
+func (repository *UserRepositoryMemory) emailTaken(user domain.User) bool {
+	for _, stored := range repository.users {
+		if stored.Id != user.Id && strings.EqualFold(stored.Email, user.Email) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,91 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"github.com/refandas/duit-api/exception"
+	"github.com/refandas/duit-api/model/domain"
+)
+
+// UserRepositorySQL is a UserRepository keeping the users in the `users`
+// table of a SQL database.
+type UserRepositorySQL struct {
+	DB *sql.DB
+}
+
+func NewUserRepositorySQL(db *sql.DB) UserRepository {
+	return &UserRepositorySQL{DB: db}
+}
+
+const userColumns = "id, name, email, password, time_zone, language, home_currency, created_at"
+
+func (repository *UserRepositorySQL) Save(ctx context.Context, user domain.User) (domain.User, error) {
+	_, err := repository.DB.ExecContext(ctx, `INSERT INTO users (`+userColumns+`)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		ON CONFLICT (id) DO UPDATE SET name = excluded.name, email = excluded.email,
+			password = excluded.password, time_zone = excluded.time_zone, language = excluded.language,
+			home_currency = excluded.home_currency, created_at = excluded.created_at`,
+		user.Id, user.Name, user.Email, user.Password, user.TimeZone, user.Language, user.HomeCurrency, user.CreatedAt)
+	if isUniqueViolation(err) {
+		return domain.User{}, exception.NewConflictError("email is already registered")
+	}
+	if err != nil {
+		return domain.User{}, exception.NewUnavailableError(err)
+	}
+	return user, nil
+}
+
+func (repository *UserRepositorySQL) Update(ctx context.Context, user domain.User) (domain.User, error) {
+	_, err := repository.DB.ExecContext(ctx, `UPDATE users
+		SET name = $2, email = $3, time_zone = $4, language = $5, home_currency = $6,
+			password = CASE WHEN $7 = '' THEN password ELSE $7 END
+		WHERE id = $1`,
+		user.Id, user.Name, user.Email, user.TimeZone, user.Language, user.HomeCurrency, user.Password)
+	if isUniqueViolation(err) {
+		return domain.User{}, exception.NewConflictError("email is already registered")
+	}
+	if err != nil {
+		return domain.User{}, exception.NewUnavailableError(err)
+	}
+	return user, nil
+}
+
+func (repository *UserRepositorySQL) Delete(ctx context.Context, user domain.User) error {
+	_, err := repository.DB.ExecContext(ctx, "DELETE FROM users WHERE id = $1", user.Id)
+	if err != nil {
+		return exception.NewUnavailableError(err)
+	}
+	return nil
+}
+
+func (repository *UserRepositorySQL) FindById(ctx context.Context, userId string) (domain.User, error) {
+	row := repository.DB.QueryRowContext(ctx, "SELECT "+userColumns+" FROM users WHERE id = $1", userId)
+	user, err := scanUser(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.User{}, exception.NewNotFoundError("item not found")
+	}
+	if err != nil {
+		return domain.User{}, exception.NewUnavailableError(err)
+	}
+	return user, nil
+}
+
+func (repository *UserRepositorySQL) FindByEmail(ctx context.Context, email string) (domain.User, bool, error) {
+	row := repository.DB.QueryRowContext(ctx, "SELECT "+userColumns+" FROM users WHERE email = $1 LIMIT 1", email)
+	user, err := scanUser(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.User{}, false, nil
+	}
+	if err != nil {
+		return domain.User{}, false, exception.NewUnavailableError(err)
+	}
+	return user, true, nil
+}
+
+func scanUser(row rowScanner) (domain.User, error) {
+	user := domain.User{}
+	err := row.Scan(&user.Id, &user.Name, &user.Email, &user.Password, &user.TimeZone, &user.Language, &user.HomeCurrency,
+		&user.CreatedAt)
+	return user, err
+}
@@ -0,0 +1,18 @@
+package service
+
+import (
+	"context"
+	"github.com/refandas/duit-api/model/web"
+)
+
+type AccountService interface {
+	Create(ctx context.Context, request web.AccountCreateRequest) (web.AccountResponse, error)
+	Update(ctx context.Context, request web.AccountUpdateRequest) (web.AccountResponse, error)
+	Delete(ctx context.Context, userId string, accountId string) error
+	FindById(ctx context.Context, userId string, accountId string) (web.AccountResponse, error)
+	FindByUserId(ctx context.Context, userId string) ([]web.AccountResponse, error)
+	Balance(ctx context.Context, request web.AccountBalanceRequest) (web.AccountBalanceResponse, error)
+	Transfer(ctx context.Context, request web.TransferCreateRequest) (web.TransferResponse, error)
+	FindTransferById(ctx context.Context, userId string, transferId string) (web.TransferResponse, error)
+	DeleteTransfer(ctx context.Context, userId string, transferId string) error
+}
@@ -0,0 +1,373 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/go-playground/validator/v10"
+	"github.com/refandas/duit-api/exception"
+	"github.com/refandas/duit-api/helper"
+	"github.com/refandas/duit-api/model/domain"
+	"github.com/refandas/duit-api/model/web"
+	"github.com/refandas/duit-api/repository"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// accountEntryTransfer is the type of the transfers in the ledger of an
+// account, whose spendings are typed by their own types.
+const accountEntryTransfer = "transfer"
+
+type AccountServiceImpl struct {
+	AccountRepository  repository.AccountRepository
+	TransferRepository repository.TransferRepository
+	SpendingRepository repository.SpendingRepository
+	Validate           *validator.Validate
+	Policy             OwnershipPolicy
+}
+
+func NewAccountService(accountRepository repository.AccountRepository, transferRepository repository.TransferRepository, spendingRepository repository.SpendingRepository, validate *validator.Validate, policy OwnershipPolicy) AccountService {
+	return &AccountServiceImpl{
+		AccountRepository:  accountRepository,
+		TransferRepository: transferRepository,
+		SpendingRepository: spendingRepository,
+		Validate:           validate,
+		Policy:             policy,
+	}
+}
+
+func (service *AccountServiceImpl) Create(ctx context.Context, request web.AccountCreateRequest) (web.AccountResponse, error) {
+	err := service.Validate.Struct(request)
+	if err != nil {
+		return web.AccountResponse{}, exception.NewValidationErrors(err)
+	}
+	if err := service.Policy.checkOwnership(ctx, request.UserId, "user not found"); err != nil {
+		return web.AccountResponse{}, err
+	}
+	openingBalance, err := parseBalance(request.OpeningBalance, request.Currency)
+	if err != nil {
+		return web.AccountResponse{}, err
+	}
+
+	account := domain.Account{
+		Id:             request.Id,
+		UserId:         request.UserId,
+		Name:           request.Name,
+		Type:           request.Type,
+		OpeningBalance: openingBalance,
+		CreatedAt:      request.CreatedAt,
+	}
+	response, err := service.AccountRepository.Save(ctx, account)
+	if err != nil {
+		return web.AccountResponse{}, err
+	}
+	return helper.ToAccountResponse(response), nil
+}
+
+// Update changes the account. The currency of an account can not be
+// changed, since its spendings and transfers are in the currency.
+func (service *AccountServiceImpl) Update(ctx context.Context, request web.AccountUpdateRequest) (web.AccountResponse, error) {
+	err := service.Validate.Struct(request)
+	if err != nil {
+		return web.AccountResponse{}, exception.NewValidationErrors(err)
+	}
+
+	account, err := service.findAccount(ctx, request.UserId, request.Id)
+	if err != nil {
+		return web.AccountResponse{}, err
+	}
+	openingBalance, err := parseBalance(request.OpeningBalance, account.Currency())
+	if err != nil {
+		return web.AccountResponse{}, err
+	}
+	account.Name = request.Name
+	account.Type = request.Type
+	account.OpeningBalance = openingBalance
+
+	response, err := service.AccountRepository.Update(ctx, account)
+	if err != nil {
+		return web.AccountResponse{}, err
+	}
+	return helper.ToAccountResponse(response), nil
+}
+
+// Delete deletes the account. An account with spendings or transfers can
+// not be deleted, so the balances of the other accounts are kept.
+func (service *AccountServiceImpl) Delete(ctx context.Context, userId string, accountId string) error {
+	account, err := service.findAccount(ctx, userId, accountId)
+	if err != nil {
+		return err
+	}
+
+	page, err := service.SpendingRepository.FindByUserId(ctx, domain.SpendingQuery{
+		UserId:    userId,
+		Limit:     1,
+		AccountId: accountId,
+	})
+	if err != nil {
+		return err
+	}
+	transfers, err := service.TransferRepository.FindByAccountId(ctx, accountId)
+	if err != nil {
+		return err
+	}
+	if len(page.Spendings) > 0 || len(transfers) > 0 {
+		return exception.NewConflictError("account is used by spendings or transfers")
+	}
+	return service.AccountRepository.Delete(ctx, account)
+}
+
+func (service *AccountServiceImpl) FindById(ctx context.Context, userId string, accountId string) (web.AccountResponse, error) {
+	account, err := service.findAccount(ctx, userId, accountId)
+	if err != nil {
+		return web.AccountResponse{}, err
+	}
+	return helper.ToAccountResponse(account), nil
+}
+
+func (service *AccountServiceImpl) FindByUserId(ctx context.Context, userId string) ([]web.AccountResponse, error) {
+	if err := service.Policy.checkOwnership(ctx, userId, "user not found"); err != nil {
+		return nil, err
+	}
+	accounts, err := service.AccountRepository.FindByUserId(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+	return helper.ToAccountResponses(accounts), nil
+}
+
+// Balance returns the spendings and the transfers of the account in the
+// requested range, ordered by their dates, each with the balance of the
+// account after it. The range ends now unless requested otherwise, so the
+// planned spendings are left out.
+func (service *AccountServiceImpl) Balance(ctx context.Context, request web.AccountBalanceRequest) (web.AccountBalanceResponse, error) {
+	err := service.Validate.Struct(request)
+	if err != nil {
+		return web.AccountBalanceResponse{}, exception.NewValidationErrors(err)
+	}
+	if request.From != nil && request.To != nil && *request.From > *request.To {
+		return web.AccountBalanceResponse{}, exception.NewValidationError("from must not be after to")
+	}
+	account, err := service.findAccount(ctx, request.UserId, request.AccountId)
+	if err != nil {
+		return web.AccountBalanceResponse{}, err
+	}
+	to := time.Now().UnixMilli()
+	if request.To != nil {
+		to = *request.To
+	}
+
+	var entries []web.AccountEntryResponse
+	var amounts []int64
+	addEntry := func(entry web.AccountEntryResponse, minor int64) {
+		entry.Amount = json.Number(domain.Money{Minor: minor, Currency: account.Currency()}.String())
+		entries = append(entries, entry)
+		amounts = append(amounts, minor)
+	}
+	err = eachSpending(ctx, service.SpendingRepository, domain.SpendingQuery{
+		UserId:    request.UserId,
+		AccountId: account.Id,
+		To:        &to,
+	}, func(spending domain.Spending) error {
+		minor := -spending.Amount.Minor
+		if spending.Kind() == domain.SpendingTypeIncome {
+			minor = spending.Amount.Minor
+		}
+		addEntry(web.AccountEntryResponse{
+			Id:    spending.Id,
+			Type:  spending.Kind(),
+			Title: spending.Title,
+			Date:  spending.Date,
+		}, minor)
+		return nil
+	})
+	if err != nil {
+		return web.AccountBalanceResponse{}, err
+	}
+
+	transfers, err := service.TransferRepository.FindByAccountId(ctx, account.Id)
+	if err != nil {
+		return web.AccountBalanceResponse{}, err
+	}
+	for _, transfer := range transfers {
+		if transfer.Date > to {
+			continue
+		}
+		entry := web.AccountEntryResponse{
+			Id:    transfer.Id,
+			Type:  accountEntryTransfer,
+			Title: transfer.Description,
+			Date:  transfer.Date,
+		}
+		// A transfer between the same account is never saved, so the
+		// account is either its source or its destination.
+		if transfer.FromAccountId == account.Id {
+			addEntry(entry, -transfer.Amount.Minor)
+		} else {
+			addEntry(entry, transfer.ReceivedAmount.Minor)
+		}
+	}
+
+	order := make([]int, len(entries))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		first, second := entries[order[i]], entries[order[j]]
+		if first.Date != second.Date {
+			return first.Date < second.Date
+		}
+		return first.Id < second.Id
+	})
+
+	balance := account.OpeningBalance.Minor
+	startBalance := balance
+	entryResponses := make([]web.AccountEntryResponse, 0, len(entries))
+	for _, i := range order {
+		balance += amounts[i]
+		if request.From != nil && entries[i].Date < *request.From {
+			startBalance = balance
+			continue
+		}
+		entry := entries[i]
+		entry.Balance = json.Number(domain.Money{Minor: balance, Currency: account.Currency()}.String())
+		entryResponses = append(entryResponses, entry)
+	}
+
+	return web.AccountBalanceResponse{
+		AccountId:      account.Id,
+		Currency:       account.Currency(),
+		OpeningBalance: json.Number(account.OpeningBalance.String()),
+		From:           request.From,
+		To:             to,
+		StartBalance:   json.Number(domain.Money{Minor: startBalance, Currency: account.Currency()}.String()),
+		Balance:        json.Number(domain.Money{Minor: balance, Currency: account.Currency()}.String()),
+		Entries:        entryResponses,
+	}, nil
+}
+
+// Transfer moves money from an account of the user into another account of
+// the user. The received amount is required between accounts of different
+// currencies, and is the amount between accounts of the same currency.
+func (service *AccountServiceImpl) Transfer(ctx context.Context, request web.TransferCreateRequest) (web.TransferResponse, error) {
+	err := service.Validate.Struct(request)
+	if err != nil {
+		return web.TransferResponse{}, exception.NewValidationErrors(err)
+	}
+	if err := service.Policy.checkOwnership(ctx, request.UserId, "user not found"); err != nil {
+		return web.TransferResponse{}, err
+	}
+	fromAccount, err := service.findAccount(ctx, request.UserId, request.FromAccountId)
+	if err != nil {
+		return web.TransferResponse{}, err
+	}
+	toAccount, err := service.findAccount(ctx, request.UserId, request.ToAccountId)
+	if err != nil {
+		return web.TransferResponse{}, err
+	}
+
+	amount, err := parseAmount(request.Amount, fromAccount.Currency())
+	if err != nil {
+		return web.TransferResponse{}, err
+	}
+	receivedAmount := amount
+	if toAccount.Currency() != fromAccount.Currency() {
+		if request.ReceivedAmount == "" {
+			return web.TransferResponse{}, exception.NewValidationError("received_amount is required between accounts of different currencies")
+		}
+		receivedAmount, err = parseAmount(request.ReceivedAmount, toAccount.Currency())
+		if err != nil {
+			return web.TransferResponse{}, err
+		}
+	}
+
+	transfer := domain.Transfer{
+		Id:             request.Id,
+		UserId:         request.UserId,
+		FromAccountId:  fromAccount.Id,
+		ToAccountId:    toAccount.Id,
+		Amount:         amount,
+		ReceivedAmount: receivedAmount,
+		Description:    request.Description,
+		Date:           request.Date,
+		CreatedAt:      request.CreatedAt,
+	}
+	response, err := service.TransferRepository.Save(ctx, transfer)
+	if err != nil {
+		return web.TransferResponse{}, err
+	}
+	return helper.ToTransferResponse(response), nil
+}
+
+func (service *AccountServiceImpl) FindTransferById(ctx context.Context, userId string, transferId string) (web.TransferResponse, error) {
+	transfer, err := service.findTransfer(ctx, userId, transferId)
+	if err != nil {
+		return web.TransferResponse{}, err
+	}
+	return helper.ToTransferResponse(transfer), nil
+}
+
+// DeleteTransfer deletes the transfer, reverting both of its accounts.
+func (service *AccountServiceImpl) DeleteTransfer(ctx context.Context, userId string, transferId string) error {
+	transfer, err := service.findTransfer(ctx, userId, transferId)
+	if err != nil {
+		return err
+	}
+	return service.TransferRepository.Delete(ctx, transfer)
+}
+
+// findAccount returns the account of the user with the given id. An account
+// of another user is reported as not found.
+func (service *AccountServiceImpl) findAccount(ctx context.Context, userId string, accountId string) (domain.Account, error) {
+	if err := service.Policy.checkOwnership(ctx, userId, "user not found"); err != nil {
+		return domain.Account{}, err
+	}
+	account, err := service.AccountRepository.FindById(ctx, accountId)
+	if err != nil {
+		return domain.Account{}, err
+	}
+	if account.UserId != userId {
+		return domain.Account{}, exception.NewNotFoundError("account not found")
+	}
+	return account, nil
+}
+
+// findTransfer returns the transfer of the user with the given id. A
+// transfer of another user is reported as not found.
+func (service *AccountServiceImpl) findTransfer(ctx context.Context, userId string, transferId string) (domain.Transfer, error) {
+	if err := service.Policy.checkOwnership(ctx, userId, "user not found"); err != nil {
+		return domain.Transfer{}, err
+	}
+	transfer, err := service.TransferRepository.FindById(ctx, transferId)
+	if err != nil {
+		return domain.Transfer{}, err
+	}
+	if transfer.UserId != userId {
+		return domain.Transfer{}, exception.NewNotFoundError("transfer not found")
+	}
+	return transfer, nil
+}
+
+// parseBalance returns the money of the requested balance in the requested
+// currency, or in the default currency when none is requested. Unlike the
+// amounts of spendings, a balance may be zero or negative, such as the
+// balance of a credit card, and is zero when it is not requested.
+func parseBalance(balance json.Number, currency string) (domain.Money, error) {
+	if currency == "" {
+		currency = domain.DefaultCurrency
+	}
+	if balance == "" {
+		return domain.Money{Currency: currency}, nil
+	}
+	value, negative := strings.CutPrefix(balance.String(), "-")
+	money, err := domain.ParseMoney(value, currency)
+	if err != nil {
+		return domain.Money{}, exception.NewValidationError("{0} must be an amount with at most {1} decimal places",
+			"opening_balance", strconv.Itoa(domain.CurrencyExponent(currency)))
+	}
+	if negative {
+		money.Minor = -money.Minor
+	}
+	return money, nil
+}
@@ -0,0 +1,39 @@
+package service
+
+import (
+	"context"
+	"github.com/refandas/duit-api/exception"
+	"github.com/refandas/duit-api/helper"
+)
+
+// AdminPolicy decides which callers may change the data shared by every
+// user, such as the exchange rates the spendings are converted by.
+type AdminPolicy struct {
+	userIds map[string]bool
+}
+
+// NewAdminPolicy returns the AdminPolicy allowing the users of the given
+// ids to change the shared data.
+func NewAdminPolicy(userIds []string) AdminPolicy {
+	policy := AdminPolicy{userIds: map[string]bool{}}
+	for _, userId := range userIds {
+		policy.userIds[userId] = true
+	}
+	return policy
+}
+
+// checkAdmin returns an error unless the caller of the context is an
+// administrator, either a user allowed by the policy or an admin command.
+func (policy AdminPolicy) checkAdmin(ctx context.Context) error {
+	if helper.IsAdminContext(ctx) {
+		return nil
+	}
+	callerId, ok := helper.UserIdFromContext(ctx)
+	if !ok {
+		return exception.NewUnauthorizedError("missing access token")
+	}
+	if !policy.userIds[callerId] {
+		return exception.NewForbiddenError("only administrators can change the exchange rates")
+	}
+	return nil
+}
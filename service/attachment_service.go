@@ -0,0 +1,14 @@
+package service
+
+import (
+	"context"
+	"github.com/refandas/duit-api/model/web"
+	"io"
+)
+
+type AttachmentService interface {
+	Create(ctx context.Context, request web.AttachmentCreateRequest, reader io.Reader) (web.AttachmentResponse, error)
+	Delete(ctx context.Context, spendingId string, attachmentId string) error
+	FindBySpendingId(ctx context.Context, spendingId string) ([]web.AttachmentResponse, error)
+	Download(ctx context.Context, spendingId string, attachmentId string) (*AttachmentDownload, error)
+}
@@ -0,0 +1,241 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"github.com/gabriel-vasile/mimetype"
+	"github.com/go-playground/validator/v10"
+	"github.com/refandas/duit-api/exception"
+	"github.com/refandas/duit-api/helper"
+	"github.com/refandas/duit-api/model/domain"
+	"github.com/refandas/duit-api/model/web"
+	"github.com/refandas/duit-api/repository"
+	"io"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// attachmentSniffSize is the number of the first bytes of a file its type is
+// detected from.
+const attachmentSniffSize = 3072
+
+// attachmentContentTypes are the types of the files that can be attached to
+// a spending: the photos and the scans of receipts.
+var attachmentContentTypes = []string{
+	"image/jpeg",
+	"image/png",
+	"image/webp",
+	"image/heic",
+	"image/heif",
+	"application/pdf",
+}
+
+// errAttachmentTooLarge is returned by the reader of an uploaded file once
+// the file is larger than the size limit.
+var errAttachmentTooLarge = errors.New("attachment too large")
+
+// AttachmentDownload is a file attached to a spending being downloaded. The
+// content must be closed by the caller.
+type AttachmentDownload struct {
+	ContentType string
+	FileName    string
+	Size        int64
+	Content     io.ReadCloser
+}
+
+type AttachmentServiceImpl struct {
+	AttachmentRepository repository.AttachmentRepository
+	SpendingRepository   repository.SpendingRepository
+	BlobStore            repository.BlobStore
+	Validate             *validator.Validate
+	Policy               OwnershipPolicy
+	MaxSize              int64
+}
+
+func NewAttachmentService(attachmentRepository repository.AttachmentRepository, spendingRepository repository.SpendingRepository, blobStore repository.BlobStore, validate *validator.Validate, policy OwnershipPolicy, maxSize int64) AttachmentService {
+	return &AttachmentServiceImpl{
+		AttachmentRepository: attachmentRepository,
+		SpendingRepository:   spendingRepository,
+		BlobStore:            blobStore,
+		Validate:             validate,
+		Policy:               policy,
+		MaxSize:              maxSize,
+	}
+}
+
+// Create attaches the file read from the reader to the spending. The type
+// of the file is detected from its content, so the type claimed by the
+// upload is ignored.
+func (service *AttachmentServiceImpl) Create(ctx context.Context, request web.AttachmentCreateRequest, reader io.Reader) (web.AttachmentResponse, error) {
+	request.FileName = filepath.Base(filepath.FromSlash(strings.ReplaceAll(request.FileName, `\`, "/")))
+	err := service.Validate.Struct(request)
+	if err != nil {
+		return web.AttachmentResponse{}, exception.NewValidationErrors(err)
+	}
+	spending, err := service.findSpending(ctx, request.SpendingId)
+	if err != nil {
+		return web.AttachmentResponse{}, err
+	}
+
+	header := make([]byte, attachmentSniffSize)
+	n, err := io.ReadFull(reader, header)
+	if err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, io.ErrUnexpectedEOF) {
+		return web.AttachmentResponse{}, service.uploadError(err)
+	}
+	header = header[:n]
+	if n == 0 {
+		return web.AttachmentResponse{}, exception.NewValidationError("file must not be empty")
+	}
+	contentType, ok := detectAttachmentType(header)
+	if !ok {
+		return web.AttachmentResponse{}, exception.NewValidationError("file must be a JPEG, PNG, WebP or HEIC image or a PDF document")
+	}
+
+	attachment := domain.Attachment{
+		Id:          request.Id,
+		UserId:      spending.UserId,
+		SpendingId:  spending.Id,
+		FileName:    request.FileName,
+		ContentType: contentType,
+		CreatedAt:   request.CreatedAt,
+	}
+	content := &attachmentReader{
+		reader:  io.MultiReader(bytes.NewReader(header), reader),
+		maxSize: service.MaxSize,
+	}
+	if err := service.BlobStore.Put(ctx, attachment.BlobKey(), content); err != nil {
+		return web.AttachmentResponse{}, service.uploadError(err)
+	}
+	attachment.Size = content.size
+
+	response, err := service.AttachmentRepository.Save(ctx, attachment)
+	if err != nil {
+		// The file is not kept without the attachment describing it.
+		_ = service.BlobStore.Delete(ctx, attachment.BlobKey())
+		return web.AttachmentResponse{}, err
+	}
+	return helper.ToAttachmentResponse(response), nil
+}
+
+// Delete detaches the file from the spending and removes its content.
+func (service *AttachmentServiceImpl) Delete(ctx context.Context, spendingId string, attachmentId string) error {
+	attachment, err := service.findAttachment(ctx, spendingId, attachmentId)
+	if err != nil {
+		return err
+	}
+	return deleteAttachment(ctx, service.AttachmentRepository, service.BlobStore, attachment)
+}
+
+func (service *AttachmentServiceImpl) FindBySpendingId(ctx context.Context, spendingId string) ([]web.AttachmentResponse, error) {
+	spending, err := service.findSpending(ctx, spendingId)
+	if err != nil {
+		return nil, err
+	}
+	attachments, err := service.AttachmentRepository.FindBySpendingId(ctx, spending.Id)
+	if err != nil {
+		return nil, err
+	}
+	return helper.ToAttachmentResponses(attachments), nil
+}
+
+func (service *AttachmentServiceImpl) Download(ctx context.Context, spendingId string, attachmentId string) (*AttachmentDownload, error) {
+	attachment, err := service.findAttachment(ctx, spendingId, attachmentId)
+	if err != nil {
+		return nil, err
+	}
+	content, err := service.BlobStore.Get(ctx, attachment.BlobKey())
+	if err != nil {
+		return nil, err
+	}
+	return &AttachmentDownload{
+		ContentType: attachment.ContentType,
+		FileName:    attachment.FileName,
+		Size:        attachment.Size,
+		Content:     content,
+	}, nil
+}
+
+// findSpending returns the spending the files are attached to, once the
+// authenticated user is its owner.
+func (service *AttachmentServiceImpl) findSpending(ctx context.Context, spendingId string) (domain.Spending, error) {
+	spending, err := service.SpendingRepository.FindById(ctx, spendingId)
+	if err != nil {
+		return domain.Spending{}, err
+	}
+	if err := service.Policy.checkOwnership(ctx, spending.UserId, "item not found"); err != nil {
+		return domain.Spending{}, err
+	}
+	return spending, nil
+}
+
+// findAttachment returns the attachment of the spending. An attachment of
+// another spending is not found, even when both belong to the user.
+func (service *AttachmentServiceImpl) findAttachment(ctx context.Context, spendingId string, attachmentId string) (domain.Attachment, error) {
+	spending, err := service.findSpending(ctx, spendingId)
+	if err != nil {
+		return domain.Attachment{}, err
+	}
+	attachment, err := service.AttachmentRepository.FindById(ctx, attachmentId)
+	if err != nil {
+		return domain.Attachment{}, err
+	}
+	if attachment.SpendingId != spending.Id {
+		return domain.Attachment{}, exception.NewNotFoundError("attachment not found")
+	}
+	return attachment, nil
+}
+
+// uploadError returns the error responded when the upload of a file fails.
+func (service *AttachmentServiceImpl) uploadError(err error) error {
+	var maxBytesError *http.MaxBytesError
+	if errors.Is(err, errAttachmentTooLarge) || errors.As(err, &maxBytesError) {
+		return exception.NewValidationError("file must not be larger than {0} bytes", strconv.FormatInt(service.MaxSize, 10))
+	}
+	var apiError *exception.Error
+	if errors.As(err, &apiError) {
+		return err
+	}
+	return exception.NewValidationError("invalid request body")
+}
+
+// deleteAttachment removes the content of the file before the attachment
+// describing it, so a failure never leaves a file nothing refers to.
+func deleteAttachment(ctx context.Context, attachmentRepository repository.AttachmentRepository, blobStore repository.BlobStore, attachment domain.Attachment) error {
+	if err := blobStore.Delete(ctx, attachment.BlobKey()); err != nil {
+		return err
+	}
+	return attachmentRepository.Delete(ctx, attachment)
+}
+
+// detectAttachmentType returns the type of the file starting with the
+// header, when the file can be attached to a spending.
+func detectAttachmentType(header []byte) (string, bool) {
+	for detected := mimetype.Detect(header); detected != nil; detected = detected.Parent() {
+		for _, contentType := range attachmentContentTypes {
+			if detected.Is(contentType) {
+				return contentType, true
+			}
+		}
+	}
+	return "", false
+}
+
+// attachmentReader reads an uploaded file, counting its size and failing
+// with errAttachmentTooLarge once it is larger than the limit.
+type attachmentReader struct {
+	reader  io.Reader
+	maxSize int64
+	size    int64
+}
+
+func (reader *attachmentReader) Read(p []byte) (int, error) {
+	n, err := reader.reader.Read(p)
+	reader.size += int64(n)
+	if reader.size > reader.maxSize {
+		return n, errAttachmentTooLarge
+	}
+	return n, err
+}
@@ -0,0 +1,12 @@
+package service
+
+import (
+	"context"
+	"github.com/refandas/duit-api/model/web"
+)
+
+type AuthService interface {
+	Login(ctx context.Context, request web.LoginRequest) (web.TokenResponse, error)
+	Refresh(ctx context.Context, request web.RefreshTokenRequest) (web.TokenResponse, error)
+	Logout(ctx context.Context, request web.RefreshTokenRequest) error
+}
@@ -0,0 +1,176 @@
+package service
+
+import (
+	"context"
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/refandas/duit-api/exception"
+	"github.com/refandas/duit-api/helper"
+	"github.com/refandas/duit-api/model/domain"
+	"github.com/refandas/duit-api/model/web"
+	"github.com/refandas/duit-api/repository"
+	"golang.org/x/crypto/bcrypt"
+	"strings"
+	"time"
+)
+
+// dummyPasswordHash is compared against when the email of a login attempt
+// is not registered, so that unknown emails take as long to reject as wrong
+// passwords.
+const dummyPasswordHash = "$2a$10$0M18NrxFXA2cYDs.2JbIFOVlJxDUDVHl/dpcREUs3.mUeS8M7p47K"
+
+type AuthServiceImpl struct {
+	UserRepository     repository.UserRepository
+	SessionRepository  repository.SessionRepository
+	Validate           *validator.Validate
+	TokenManager       *helper.TokenManager
+	RefreshTokenExpiry time.Duration
+}
+
+func NewAuthService(userRepository repository.UserRepository, sessionRepository repository.SessionRepository, validate *validator.Validate, tokenManager *helper.TokenManager, refreshTokenExpiry time.Duration) AuthService {
+	return &AuthServiceImpl{
+		UserRepository:     userRepository,
+		SessionRepository:  sessionRepository,
+		Validate:           validate,
+		TokenManager:       tokenManager,
+		RefreshTokenExpiry: refreshTokenExpiry,
+	}
+}
+
+func (service *AuthServiceImpl) Login(ctx context.Context, request web.LoginRequest) (web.TokenResponse, error) {
+	err := service.Validate.Struct(request)
+	if err != nil {
+		return web.TokenResponse{}, exception.NewValidationErrors(err)
+	}
+
+	user, found, err := service.UserRepository.FindByEmail(ctx, strings.ToLower(request.Email))
+	if err != nil {
+		return web.TokenResponse{}, err
+	}
+	passwordHash := user.Password
+	if !found {
+		passwordHash = dummyPasswordHash
+	}
+
+	err = bcrypt.CompareHashAndPassword([]byte(passwordHash), []byte(request.Password))
+	if !found || err != nil {
+		return web.TokenResponse{}, exception.NewUnauthorizedError("invalid email or password")
+	}
+
+	familyId, err := uuid.NewRandom()
+	if err != nil {
+		return web.TokenResponse{}, err
+	}
+
+	return service.issueTokens(ctx, domain.Session{
+		FamilyId:  familyId.String(),
+		UserId:    user.Id,
+		UserAgent: request.UserAgent,
+		StartedAt: time.Now().UnixMilli(),
+	})
+}
+
+func (service *AuthServiceImpl) Refresh(ctx context.Context, request web.RefreshTokenRequest) (web.TokenResponse, error) {
+	err := service.Validate.Struct(request)
+	if err != nil {
+		return web.TokenResponse{}, exception.NewValidationErrors(err)
+	}
+
+	session, err := service.findSession(ctx, request.RefreshToken)
+	if err != nil {
+		return web.TokenResponse{}, err
+	}
+	if session.RotatedAt != 0 {
+		// A refresh token that has already been exchanged is presented
+		// again, so it may have been stolen. Revoke the whole family to log
+		// out both the legitimate client and the attacker.
+		if err := service.SessionRepository.RevokeFamily(ctx, session.UserId, session.FamilyId); err != nil {
+			return web.TokenResponse{}, err
+		}
+		return web.TokenResponse{}, exception.NewUnauthorizedError("refresh token has already been used")
+	}
+
+	now := time.Now().UnixMilli()
+	if !session.Active(now) {
+		return web.TokenResponse{}, exception.NewUnauthorizedError("refresh token is expired or revoked")
+	}
+
+	session.RotatedAt = now
+	rotated, err := service.SessionRepository.Rotate(ctx, session)
+	if err != nil {
+		return web.TokenResponse{}, err
+	}
+	if !rotated {
+		// Another request has used the same refresh token at the same time.
+		if err := service.SessionRepository.RevokeFamily(ctx, session.UserId, session.FamilyId); err != nil {
+			return web.TokenResponse{}, err
+		}
+		return web.TokenResponse{}, exception.NewUnauthorizedError("refresh token has already been used")
+	}
+
+	return service.issueTokens(ctx, session)
+}
+
+func (service *AuthServiceImpl) Logout(ctx context.Context, request web.RefreshTokenRequest) error {
+	err := service.Validate.Struct(request)
+	if err != nil {
+		return exception.NewValidationErrors(err)
+	}
+
+	session, err := service.findSession(ctx, request.RefreshToken)
+	if err != nil {
+		return err
+	}
+	return service.SessionRepository.RevokeFamily(ctx, session.UserId, session.FamilyId)
+}
+
+// findSession returns the session of the given refresh token, or an
+// unauthorized error when the token is unknown or its secret does not match.
+func (service *AuthServiceImpl) findSession(ctx context.Context, refreshToken string) (domain.Session, error) {
+	sessionId, secret, ok := helper.ParseRefreshToken(refreshToken)
+	if !ok {
+		return domain.Session{}, exception.NewUnauthorizedError("invalid refresh token")
+	}
+
+	session, found, err := service.SessionRepository.FindById(ctx, sessionId)
+	if err != nil {
+		return domain.Session{}, err
+	}
+	if !found || !helper.VerifyRefreshTokenSecret(secret, session.TokenHash) {
+		return domain.Session{}, exception.NewUnauthorizedError("invalid refresh token")
+	}
+	return session, nil
+}
+
+// issueTokens issues a new access token for the user of the given session
+// family, and saves a new session in the family for the issued refresh token.
+func (service *AuthServiceImpl) issueTokens(ctx context.Context, family domain.Session) (web.TokenResponse, error) {
+	sessionId, err := uuid.NewRandom()
+	if err != nil {
+		return web.TokenResponse{}, err
+	}
+
+	refreshToken, tokenHash := helper.NewRefreshToken(sessionId.String())
+	now := time.Now()
+	_, err = service.SessionRepository.Save(ctx, domain.Session{
+		Id:        sessionId.String(),
+		FamilyId:  family.FamilyId,
+		UserId:    family.UserId,
+		TokenHash: tokenHash,
+		UserAgent: family.UserAgent,
+		StartedAt: family.StartedAt,
+		CreatedAt: now.UnixMilli(),
+		ExpiresAt: now.Add(service.RefreshTokenExpiry).UnixMilli(),
+	})
+	if err != nil {
+		return web.TokenResponse{}, err
+	}
+
+	accessToken, expiresAt := service.TokenManager.Issue(family.UserId)
+	return web.TokenResponse{
+		AccessToken:  accessToken,
+		TokenType:    "Bearer",
+		ExpiresIn:    int64(time.Until(expiresAt).Seconds()),
+		RefreshToken: refreshToken,
+	}, nil
+}
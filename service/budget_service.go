@@ -0,0 +1,15 @@
+package service
+
+import (
+	"context"
+	"github.com/refandas/duit-api/model/web"
+)
+
+type BudgetService interface {
+	Create(ctx context.Context, request web.BudgetCreateRequest) (web.BudgetResponse, error)
+	Update(ctx context.Context, request web.BudgetUpdateRequest) (web.BudgetResponse, error)
+	Delete(ctx context.Context, userId string, budgetId string) error
+	FindById(ctx context.Context, userId string, budgetId string) (web.BudgetResponse, error)
+	FindByUserId(ctx context.Context, userId string) ([]web.BudgetResponse, error)
+	Status(ctx context.Context, userId string, budgetId string) (web.BudgetStatusResponse, error)
+}
@@ -0,0 +1,233 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/go-playground/validator/v10"
+	"github.com/refandas/duit-api/exception"
+	"github.com/refandas/duit-api/helper"
+	"github.com/refandas/duit-api/model/domain"
+	"github.com/refandas/duit-api/model/web"
+	"github.com/refandas/duit-api/repository"
+	"math/big"
+	"time"
+)
+
+type BudgetServiceImpl struct {
+	BudgetRepository       repository.BudgetRepository
+	SpendingRepository     repository.SpendingRepository
+	UserRepository         repository.UserRepository
+	ExchangeRateRepository repository.ExchangeRateRepository
+	Validate               *validator.Validate
+	Policy                 OwnershipPolicy
+}
+
+func NewBudgetService(budgetRepository repository.BudgetRepository, spendingRepository repository.SpendingRepository, userRepository repository.UserRepository, exchangeRateRepository repository.ExchangeRateRepository, validate *validator.Validate, policy OwnershipPolicy) BudgetService {
+	return &BudgetServiceImpl{
+		BudgetRepository:       budgetRepository,
+		SpendingRepository:     spendingRepository,
+		UserRepository:         userRepository,
+		ExchangeRateRepository: exchangeRateRepository,
+		Validate:               validate,
+		Policy:                 policy,
+	}
+}
+
+func (service *BudgetServiceImpl) Create(ctx context.Context, request web.BudgetCreateRequest) (web.BudgetResponse, error) {
+	err := service.Validate.Struct(request)
+	if err != nil {
+		return web.BudgetResponse{}, exception.NewValidationErrors(err)
+	}
+	if err := service.Policy.checkOwnership(ctx, request.UserId, "user not found"); err != nil {
+		return web.BudgetResponse{}, err
+	}
+	amount, err := service.parseAmount(ctx, request.UserId, request.Amount)
+	if err != nil {
+		return web.BudgetResponse{}, err
+	}
+
+	budget := domain.Budget{
+		Id:        request.Id,
+		UserId:    request.UserId,
+		Category:  request.Category,
+		Amount:    amount,
+		Period:    request.Period,
+		CreatedAt: request.CreatedAt,
+	}
+	if budget.Period == "" {
+		budget.Period = "month"
+	}
+	if err := service.checkDuplicate(ctx, budget); err != nil {
+		return web.BudgetResponse{}, err
+	}
+
+	response, err := service.BudgetRepository.Save(ctx, budget)
+	if err != nil {
+		return web.BudgetResponse{}, err
+	}
+	return helper.ToBudgetResponse(response), nil
+}
+
+func (service *BudgetServiceImpl) Update(ctx context.Context, request web.BudgetUpdateRequest) (web.BudgetResponse, error) {
+	err := service.Validate.Struct(request)
+	if err != nil {
+		return web.BudgetResponse{}, exception.NewValidationErrors(err)
+	}
+
+	budget, err := service.findBudget(ctx, request.UserId, request.Id)
+	if err != nil {
+		return web.BudgetResponse{}, err
+	}
+	amount, err := service.parseAmount(ctx, request.UserId, request.Amount)
+	if err != nil {
+		return web.BudgetResponse{}, err
+	}
+	budget.Category = request.Category
+	budget.Amount = amount
+	if request.Period != "" {
+		budget.Period = request.Period
+	}
+	if err := service.checkDuplicate(ctx, budget); err != nil {
+		return web.BudgetResponse{}, err
+	}
+
+	response, err := service.BudgetRepository.Update(ctx, budget)
+	if err != nil {
+		return web.BudgetResponse{}, err
+	}
+	return helper.ToBudgetResponse(response), nil
+}
+
+func (service *BudgetServiceImpl) Delete(ctx context.Context, userId string, budgetId string) error {
+	budget, err := service.findBudget(ctx, userId, budgetId)
+	if err != nil {
+		return err
+	}
+	return service.BudgetRepository.Delete(ctx, budget)
+}
+
+func (service *BudgetServiceImpl) FindById(ctx context.Context, userId string, budgetId string) (web.BudgetResponse, error) {
+	budget, err := service.findBudget(ctx, userId, budgetId)
+	if err != nil {
+		return web.BudgetResponse{}, err
+	}
+	return helper.ToBudgetResponse(budget), nil
+}
+
+func (service *BudgetServiceImpl) FindByUserId(ctx context.Context, userId string) ([]web.BudgetResponse, error) {
+	if err := service.Policy.checkOwnership(ctx, userId, "user not found"); err != nil {
+		return nil, err
+	}
+	budgets, err := service.BudgetRepository.FindByUserId(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+	return helper.ToBudgetResponses(budgets), nil
+}
+
+// Status computes how much of the budget is spent in the current period,
+// whose boundaries are in the time zone of the user. The spendings are
+// converted into the currency of the budget and summed exactly.
+func (service *BudgetServiceImpl) Status(ctx context.Context, userId string, budgetId string) (web.BudgetStatusResponse, error) {
+	budget, err := service.findBudget(ctx, userId, budgetId)
+	if err != nil {
+		return web.BudgetStatusResponse{}, err
+	}
+	user, err := service.UserRepository.FindById(ctx, userId)
+	if err != nil {
+		return web.BudgetStatusResponse{}, err
+	}
+	location, err := loadLocation(user.TimeZone)
+	if err != nil {
+		return web.BudgetStatusResponse{}, err
+	}
+
+	now := time.Now().In(location)
+	start := periodStart(budget.Period, now)
+	end := addPeriods(budget.Period, start, 1)
+
+	from := start.UnixMilli()
+	to := now.UnixMilli()
+	total := new(big.Rat)
+	unconverted := 0
+	converter := newCurrencyConverter(service.ExchangeRateRepository, budget.Amount.Currency)
+	err = eachSpending(ctx, service.SpendingRepository, domain.SpendingQuery{
+		UserId:   userId,
+		From:     &from,
+		To:       &to,
+		Category: budget.Category,
+		Type:     domain.SpendingTypeExpense,
+	}, func(spending domain.Spending) error {
+		amount, ok, err := converter.convert(ctx, spending.Amount, spending.Date)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			unconverted++
+			return nil
+		}
+		total.Add(total, amount.Rat())
+		return nil
+	})
+	if err != nil {
+		return web.BudgetStatusResponse{}, err
+	}
+	limit := budget.Amount.Rat()
+	percent, _ := new(big.Rat).Quo(new(big.Rat).Mul(total, big.NewRat(100, 1)), limit).Float64()
+
+	return web.BudgetStatusResponse{
+		BudgetId:    budget.Id,
+		Category:    budget.Category,
+		Period:      budget.Period,
+		Start:       start.UnixMilli(),
+		End:         end.UnixMilli() - 1,
+		Amount:      decimalAmount(limit, budget.Amount.Currency),
+		Spent:       decimalAmount(total, budget.Amount.Currency),
+		Remaining:   decimalAmount(new(big.Rat).Sub(limit, total), budget.Amount.Currency),
+		Percent:     percent,
+		Overspent:   total.Cmp(limit) > 0,
+		Currency:    budget.Amount.Currency,
+		Unconverted: unconverted,
+	}, nil
+}
+
+// parseAmount returns the money of the requested amount of a budget, which
+// is in the home currency of the user.
+func (service *BudgetServiceImpl) parseAmount(ctx context.Context, userId string, amount json.Number) (domain.Money, error) {
+	user, err := service.UserRepository.FindById(ctx, userId)
+	if err != nil {
+		return domain.Money{}, err
+	}
+	return parseAmount(amount, user.Currency())
+}
+
+// findBudget returns the budget of the user with the given id. A budget of
+// another user is reported as not found.
+func (service *BudgetServiceImpl) findBudget(ctx context.Context, userId string, budgetId string) (domain.Budget, error) {
+	if err := service.Policy.checkOwnership(ctx, userId, "user not found"); err != nil {
+		return domain.Budget{}, err
+	}
+	budget, err := service.BudgetRepository.FindById(ctx, budgetId)
+	if err != nil {
+		return domain.Budget{}, err
+	}
+	if budget.UserId != userId {
+		return domain.Budget{}, exception.NewNotFoundError("budget not found")
+	}
+	return budget, nil
+}
+
+// checkDuplicate returns a conflict error when the user already has another
+// budget for the category of the budget in the same period.
+func (service *BudgetServiceImpl) checkDuplicate(ctx context.Context, budget domain.Budget) error {
+	budgets, err := service.BudgetRepository.FindByUserId(ctx, budget.UserId)
+	if err != nil {
+		return err
+	}
+	for _, existingBudget := range budgets {
+		if existingBudget.Id != budget.Id && existingBudget.Category == budget.Category && existingBudget.Period == budget.Period {
+			return exception.NewConflictError("budget for the category already exists")
+		}
+	}
+	return nil
+}
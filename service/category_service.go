@@ -0,0 +1,15 @@
+package service
+
+import (
+	"context"
+	"github.com/refandas/duit-api/model/web"
+)
+
+type CategoryService interface {
+	Create(ctx context.Context, request web.CategoryCreateRequest) (web.CategoryResponse, error)
+	Update(ctx context.Context, request web.CategoryUpdateRequest) (web.CategoryResponse, error)
+	Merge(ctx context.Context, request web.CategoryMergeRequest) (web.CategoryResponse, error)
+	Delete(ctx context.Context, userId string, categoryId string) error
+	FindById(ctx context.Context, userId string, categoryId string) (web.CategoryResponse, error)
+	FindByUserId(ctx context.Context, userId string) ([]web.CategoryResponse, error)
+}
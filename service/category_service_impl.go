@@ -0,0 +1,385 @@
+package service
+
+import (
+	"context"
+	"github.com/go-playground/validator/v10"
+	"github.com/refandas/duit-api/exception"
+	"github.com/refandas/duit-api/helper"
+	"github.com/refandas/duit-api/model/domain"
+	"github.com/refandas/duit-api/model/web"
+	"github.com/refandas/duit-api/repository"
+)
+
+type CategoryServiceImpl struct {
+	CategoryRepository          repository.CategoryRepository
+	SpendingRepository          repository.SpendingRepository
+	BudgetRepository            repository.BudgetRepository
+	RecurringSpendingRepository repository.RecurringSpendingRepository
+	Validate                    *validator.Validate
+	Policy                      OwnershipPolicy
+}
+
+func NewCategoryService(categoryRepository repository.CategoryRepository, spendingRepository repository.SpendingRepository, budgetRepository repository.BudgetRepository, recurringSpendingRepository repository.RecurringSpendingRepository, validate *validator.Validate, policy OwnershipPolicy) CategoryService {
+	return &CategoryServiceImpl{
+		CategoryRepository:          categoryRepository,
+		SpendingRepository:          spendingRepository,
+		BudgetRepository:            budgetRepository,
+		RecurringSpendingRepository: recurringSpendingRepository,
+		Validate:                    validate,
+		Policy:                      policy,
+	}
+}
+
+func (service *CategoryServiceImpl) Create(ctx context.Context, request web.CategoryCreateRequest) (web.CategoryResponse, error) {
+	err := service.Validate.Struct(request)
+	if err != nil {
+		return web.CategoryResponse{}, exception.NewValidationErrors(err)
+	}
+	if err := service.Policy.checkOwnership(ctx, request.UserId, "user not found"); err != nil {
+		return web.CategoryResponse{}, err
+	}
+
+	_, found, err := service.CategoryRepository.FindByName(ctx, request.UserId, request.Name)
+	if err != nil {
+		return web.CategoryResponse{}, err
+	}
+	if found {
+		return web.CategoryResponse{}, exception.NewConflictError("category already exists")
+	}
+
+	category := domain.Category{
+		Id:        request.Id,
+		UserId:    request.UserId,
+		Name:      request.Name,
+		Color:     request.Color,
+		Icon:      request.Icon,
+		ParentId:  request.ParentId,
+		CreatedAt: request.CreatedAt,
+	}
+	if err := service.checkParent(ctx, category); err != nil {
+		return web.CategoryResponse{}, err
+	}
+
+	response, err := service.CategoryRepository.Save(ctx, category)
+	if err != nil {
+		return web.CategoryResponse{}, err
+	}
+	return helper.ToCategoryResponse(response), nil
+}
+
+// Update updates the category. Renaming the category also renames the
+// category of its spendings, budgets and recurring spendings.
+func (service *CategoryServiceImpl) Update(ctx context.Context, request web.CategoryUpdateRequest) (web.CategoryResponse, error) {
+	err := service.Validate.Struct(request)
+	if err != nil {
+		return web.CategoryResponse{}, exception.NewValidationErrors(err)
+	}
+
+	category, err := service.findCategory(ctx, request.UserId, request.Id)
+	if err != nil {
+		return web.CategoryResponse{}, err
+	}
+	oldName := category.Name
+	if request.Name != oldName {
+		_, found, err := service.CategoryRepository.FindByName(ctx, request.UserId, request.Name)
+		if err != nil {
+			return web.CategoryResponse{}, err
+		}
+		if found {
+			return web.CategoryResponse{}, exception.NewConflictError("category already exists")
+		}
+	}
+
+	category.Name = request.Name
+	category.Color = request.Color
+	category.Icon = request.Icon
+	category.ParentId = request.ParentId
+	if err := service.checkParent(ctx, category); err != nil {
+		return web.CategoryResponse{}, err
+	}
+
+	response, err := service.CategoryRepository.Update(ctx, category)
+	if err != nil {
+		return web.CategoryResponse{}, err
+	}
+	if category.Name != oldName {
+		if err := service.moveSpendings(ctx, category.UserId, oldName, category.Name); err != nil {
+			return web.CategoryResponse{}, err
+		}
+		if err := service.moveBudgets(ctx, category.UserId, oldName, category.Name); err != nil {
+			return web.CategoryResponse{}, err
+		}
+		if err := service.moveRecurringSpendings(ctx, category.UserId, oldName, category.Name); err != nil {
+			return web.CategoryResponse{}, err
+		}
+	}
+	return helper.ToCategoryResponse(response), nil
+}
+
+// Merge moves the spendings, budgets, recurring spendings and subcategories
+// of the category into the target category, then deletes the category.
+func (service *CategoryServiceImpl) Merge(ctx context.Context, request web.CategoryMergeRequest) (web.CategoryResponse, error) {
+	err := service.Validate.Struct(request)
+	if err != nil {
+		return web.CategoryResponse{}, exception.NewValidationErrors(err)
+	}
+
+	category, err := service.findCategory(ctx, request.UserId, request.Id)
+	if err != nil {
+		return web.CategoryResponse{}, err
+	}
+	target, err := service.findCategory(ctx, request.UserId, request.TargetId)
+	if err != nil {
+		return web.CategoryResponse{}, err
+	}
+
+	if err := service.moveSpendings(ctx, category.UserId, category.Name, target.Name); err != nil {
+		return web.CategoryResponse{}, err
+	}
+	if err := service.moveBudgets(ctx, category.UserId, category.Name, target.Name); err != nil {
+		return web.CategoryResponse{}, err
+	}
+	if err := service.moveRecurringSpendings(ctx, category.UserId, category.Name, target.Name); err != nil {
+		return web.CategoryResponse{}, err
+	}
+
+	categories, err := service.userCategories(ctx, category.UserId)
+	if err != nil {
+		return web.CategoryResponse{}, err
+	}
+
+	// A target under the category takes the place of the category, so the
+	// subcategories moved into the target do not become its ancestors.
+	for parentId, i := target.ParentId, 0; parentId != "" && i <= len(categories); i++ {
+		if parentId == category.Id {
+			target.ParentId = category.ParentId
+			if _, err := service.CategoryRepository.Update(ctx, target); err != nil {
+				return web.CategoryResponse{}, err
+			}
+			break
+		}
+		parentId = categories[parentId].ParentId
+	}
+
+	for _, subcategory := range categories {
+		if subcategory.ParentId == category.Id && subcategory.Id != target.Id {
+			subcategory.ParentId = target.Id
+			if _, err := service.CategoryRepository.Update(ctx, subcategory); err != nil {
+				return web.CategoryResponse{}, err
+			}
+		}
+	}
+
+	if err := service.CategoryRepository.Delete(ctx, category); err != nil {
+		return web.CategoryResponse{}, err
+	}
+	return helper.ToCategoryResponse(target), nil
+}
+
+// Delete deletes the category and its budgets. A category with spendings,
+// recurring spendings or subcategories can not be deleted, but it can be
+// merged into another category.
+func (service *CategoryServiceImpl) Delete(ctx context.Context, userId string, categoryId string) error {
+	category, err := service.findCategory(ctx, userId, categoryId)
+	if err != nil {
+		return err
+	}
+
+	subcategories, err := service.CategoryRepository.FindByUserId(ctx, userId)
+	if err != nil {
+		return err
+	}
+	for _, subcategory := range subcategories {
+		if subcategory.ParentId == category.Id {
+			return exception.NewConflictError("category has subcategories")
+		}
+	}
+	page, err := service.SpendingRepository.FindByUserId(ctx, domain.SpendingQuery{
+		UserId:   userId,
+		Limit:    1,
+		Category: category.Name,
+	})
+	if err != nil {
+		return err
+	}
+	if len(page.Spendings) > 0 {
+		return exception.NewConflictError("category is used by spendings, merge it into another category instead")
+	}
+	recurrings, err := service.RecurringSpendingRepository.FindByUserId(ctx, userId)
+	if err != nil {
+		return err
+	}
+	for _, recurring := range recurrings {
+		if recurring.Category == category.Name {
+			return exception.NewConflictError("category is used by recurring spendings, merge it into another category instead")
+		}
+	}
+
+	budgets, err := service.BudgetRepository.FindByUserId(ctx, userId)
+	if err != nil {
+		return err
+	}
+	for _, budget := range budgets {
+		if budget.Category == category.Name {
+			if err := service.BudgetRepository.Delete(ctx, budget); err != nil {
+				return err
+			}
+		}
+	}
+	return service.CategoryRepository.Delete(ctx, category)
+}
+
+func (service *CategoryServiceImpl) FindById(ctx context.Context, userId string, categoryId string) (web.CategoryResponse, error) {
+	category, err := service.findCategory(ctx, userId, categoryId)
+	if err != nil {
+		return web.CategoryResponse{}, err
+	}
+	return helper.ToCategoryResponse(category), nil
+}
+
+func (service *CategoryServiceImpl) FindByUserId(ctx context.Context, userId string) ([]web.CategoryResponse, error) {
+	if err := service.Policy.checkOwnership(ctx, userId, "user not found"); err != nil {
+		return nil, err
+	}
+	categories, err := service.CategoryRepository.FindByUserId(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+	return helper.ToCategoryResponses(categories), nil
+}
+
+// findCategory returns the category of the user with the given id. A
+// category of another user is reported as not found.
+func (service *CategoryServiceImpl) findCategory(ctx context.Context, userId string, categoryId string) (domain.Category, error) {
+	if err := service.Policy.checkOwnership(ctx, userId, "user not found"); err != nil {
+		return domain.Category{}, err
+	}
+	category, err := service.CategoryRepository.FindById(ctx, categoryId)
+	if err != nil {
+		return domain.Category{}, err
+	}
+	if category.UserId != userId {
+		return domain.Category{}, exception.NewNotFoundError("category not found")
+	}
+	return category, nil
+}
+
+// userCategories returns the categories of the user by their id.
+func (service *CategoryServiceImpl) userCategories(ctx context.Context, userId string) (map[string]domain.Category, error) {
+	userCategories, err := service.CategoryRepository.FindByUserId(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	categories := map[string]domain.Category{}
+	for _, userCategory := range userCategories {
+		categories[userCategory.Id] = userCategory
+	}
+	return categories, nil
+}
+
+// checkParent returns a validation error when the parent of the category is
+// not a category of the user, or when the category would become its own
+// ancestor.
+func (service *CategoryServiceImpl) checkParent(ctx context.Context, category domain.Category) error {
+	if category.ParentId == "" {
+		return nil
+	}
+
+	categories, err := service.userCategories(ctx, category.UserId)
+	if err != nil {
+		return err
+	}
+
+	parentId := category.ParentId
+	for i := 0; parentId != "" && i <= len(categories); i++ {
+		if parentId == category.Id {
+			return exception.NewValidationError("category can not be a subcategory of itself")
+		}
+		parent, found := categories[parentId]
+		if !found {
+			return exception.NewValidationError("parent category not found")
+		}
+		parentId = parent.ParentId
+	}
+	return nil
+}
+
+// moveSpendings changes the category of the user's spendings in the
+// category named from to the category named to.
+func (service *CategoryServiceImpl) moveSpendings(ctx context.Context, userId string, from string, to string) error {
+	var spendings []domain.Spending
+	err := eachSpending(ctx, service.SpendingRepository, domain.SpendingQuery{
+		UserId:   userId,
+		Category: from,
+	}, func(spending domain.Spending) error {
+		spendings = append(spendings, spending)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, spending := range spendings {
+		spending.Category = to
+		if _, err := service.SpendingRepository.Update(ctx, spending); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// moveBudgets changes the category of the user's budgets in the category
+// named from to the category named to. A budget is deleted instead when
+// the category named to already has a budget for the same period.
+func (service *CategoryServiceImpl) moveBudgets(ctx context.Context, userId string, from string, to string) error {
+	budgets, err := service.BudgetRepository.FindByUserId(ctx, userId)
+	if err != nil {
+		return err
+	}
+
+	periods := map[string]bool{}
+	for _, budget := range budgets {
+		if budget.Category == to {
+			periods[budget.Period] = true
+		}
+	}
+
+	for _, budget := range budgets {
+		if budget.Category != from {
+			continue
+		}
+		if periods[budget.Period] {
+			if err := service.BudgetRepository.Delete(ctx, budget); err != nil {
+				return err
+			}
+			continue
+		}
+		budget.Category = to
+		if _, err := service.BudgetRepository.Update(ctx, budget); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// moveRecurringSpendings changes the category of the user's recurring
+// spendings in the category named from to the category named to, so their
+// next occurrences are created in the category named to.
+func (service *CategoryServiceImpl) moveRecurringSpendings(ctx context.Context, userId string, from string, to string) error {
+	recurrings, err := service.RecurringSpendingRepository.FindByUserId(ctx, userId)
+	if err != nil {
+		return err
+	}
+
+	for _, recurring := range recurrings {
+		if recurring.Category != from {
+			continue
+		}
+		recurring.Category = to
+		if _, err := service.RecurringSpendingRepository.Update(ctx, recurring); err != nil {
+			return err
+		}
+	}
+	return nil
+}
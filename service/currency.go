@@ -0,0 +1,118 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/refandas/duit-api/helper"
+	"github.com/refandas/duit-api/model/domain"
+	"github.com/refandas/duit-api/model/web"
+	"github.com/refandas/duit-api/repository"
+	"math/big"
+	"sort"
+)
+
+// effectiveRate is the rate converting a currency into the home currency
+// since a time in Unix time format.
+type effectiveRate struct {
+	from int64
+	rate *big.Rat
+}
+
+// currencyConverter converts amounts into the home currency of a user by
+// the exchange rates effective at the dates of the amounts. The rates of a
+// currency are read once, when the first amount in the currency is
+// converted.
+type currencyConverter struct {
+	repository repository.ExchangeRateRepository
+	home       string
+	rates      map[string][]effectiveRate
+}
+
+func newCurrencyConverter(exchangeRateRepository repository.ExchangeRateRepository, home string) *currencyConverter {
+	return &currencyConverter{
+		repository: exchangeRateRepository,
+		home:       home,
+		rates:      map[string][]effectiveRate{},
+	}
+}
+
+// convert returns the amount converted into the home currency by the rate
+// effective at the given date. It reports false when no rate of the
+// currency of the amount is effective at the date.
+func (converter *currencyConverter) convert(ctx context.Context, amount domain.Money, date int64) (domain.Money, bool, error) {
+	if amount.Currency == converter.home {
+		return amount, true, nil
+	}
+
+	rates, found := converter.rates[amount.Currency]
+	if !found {
+		var err error
+		rates, err = converter.loadRates(ctx, amount.Currency)
+		if err != nil {
+			return domain.Money{}, false, err
+		}
+		converter.rates[amount.Currency] = rates
+	}
+
+	index := sort.Search(len(rates), func(i int) bool {
+		return rates[i].from > date
+	})
+	if index == 0 {
+		return domain.Money{}, false, nil
+	}
+	return amount.Convert(rates[index-1].rate, converter.home), true, nil
+}
+
+// loadRates returns the rates converting the currency into the home
+// currency ordered by the time since they are effective. A rate converting
+// the home currency into the currency is used inverted, unless a rate of
+// the currency is effective since the same time.
+func (converter *currencyConverter) loadRates(ctx context.Context, currency string) ([]effectiveRate, error) {
+	direct, err := converter.repository.FindByPair(ctx, currency, converter.home)
+	if err != nil {
+		return nil, err
+	}
+	inverse, err := converter.repository.FindByPair(ctx, converter.home, currency)
+	if err != nil {
+		return nil, err
+	}
+
+	rates := map[int64]*big.Rat{}
+	for _, rate := range inverse {
+		if value := rate.Value(); value.Sign() > 0 {
+			rates[rate.EffectiveFrom] = value.Inv(value)
+		}
+	}
+	for _, rate := range direct {
+		if value := rate.Value(); value.Sign() > 0 {
+			rates[rate.EffectiveFrom] = value
+		}
+	}
+
+	effectiveRates := make([]effectiveRate, 0, len(rates))
+	for from, rate := range rates {
+		effectiveRates = append(effectiveRates, effectiveRate{from: from, rate: rate})
+	}
+	sort.Slice(effectiveRates, func(i, j int) bool {
+		return effectiveRates[i].from < effectiveRates[j].from
+	})
+	return effectiveRates, nil
+}
+
+// spendingResponses returns the responses of the spendings, with their
+// amount converted into the home currency when a rate is effective at their
+// date.
+func (converter *currencyConverter) spendingResponses(ctx context.Context, spendings []domain.Spending) ([]web.SpendingResponse, error) {
+	responses := helper.ToSpendingResponses(spendings)
+	for i, spending := range spendings {
+		converted, ok, err := converter.convert(ctx, spending.Amount, spending.Date)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			responses[i].ConvertedAmount = json.Number(converted.String())
+			responses[i].ConvertedCurrency = converted.Currency
+		}
+	}
+	return responses, nil
+}
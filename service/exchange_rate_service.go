@@ -0,0 +1,13 @@
+package service
+
+import (
+	"context"
+	"github.com/refandas/duit-api/model/web"
+	"io"
+)
+
+type ExchangeRateService interface {
+	Create(ctx context.Context, request web.ExchangeRateBatchRequest) ([]web.ExchangeRateResponse, error)
+	Import(ctx context.Context, reader io.Reader) ([]web.ExchangeRateResponse, error)
+	FindByPair(ctx context.Context, request web.ExchangeRateListRequest) ([]web.ExchangeRateResponse, error)
+}
@@ -0,0 +1,151 @@
+package service
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"github.com/go-playground/validator/v10"
+	"github.com/refandas/duit-api/exception"
+	"github.com/refandas/duit-api/helper"
+	"github.com/refandas/duit-api/model/domain"
+	"github.com/refandas/duit-api/model/web"
+	"github.com/refandas/duit-api/repository"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// exchangeRateColumns are the columns of a CSV file of exchange rates, in
+// any order.
+var exchangeRateColumns = []string{"base", "quote", "rate", "effective_from"}
+
+type ExchangeRateServiceImpl struct {
+	ExchangeRateRepository repository.ExchangeRateRepository
+	Validate               *validator.Validate
+	AdminPolicy            AdminPolicy
+}
+
+func NewExchangeRateService(exchangeRateRepository repository.ExchangeRateRepository, validate *validator.Validate, adminPolicy AdminPolicy) ExchangeRateService {
+	return &ExchangeRateServiceImpl{
+		ExchangeRateRepository: exchangeRateRepository,
+		Validate:               validate,
+		AdminPolicy:            adminPolicy,
+	}
+}
+
+// Create saves the requested rates, replacing a rate of the same currencies
+// effective from the same time. No rate is saved when any of them is
+// invalid. The rates convert the spendings of every user, so only the
+// administrators can save them.
+func (service *ExchangeRateServiceImpl) Create(ctx context.Context, request web.ExchangeRateBatchRequest) ([]web.ExchangeRateResponse, error) {
+	if err := service.AdminPolicy.checkAdmin(ctx); err != nil {
+		return nil, err
+	}
+	err := service.Validate.Struct(request)
+	if err != nil {
+		return nil, exception.NewValidationErrors(err)
+	}
+
+	now := time.Now().UnixMilli()
+	rates := make([]domain.ExchangeRate, 0, len(request.Rates))
+	for _, rateRequest := range request.Rates {
+		rate, err := domain.ParseRate(rateRequest.Rate.String())
+		if err != nil {
+			return nil, exception.NewValidationError("{0} must be a positive decimal number", "rate")
+		}
+		// The dates of the rates are not of any user, so they are in UTC.
+		effectiveFrom, err := helper.ParseTime(rateRequest.EffectiveFrom, time.UTC, false)
+		if err != nil {
+			return nil, exception.NewValidationError("{0} must be a date", "effective_from")
+		}
+
+		rates = append(rates, domain.ExchangeRate{
+			Pair:          domain.CurrencyPair(rateRequest.Base, rateRequest.Quote),
+			Base:          rateRequest.Base,
+			Quote:         rateRequest.Quote,
+			Rate:          rate,
+			EffectiveFrom: effectiveFrom,
+			CreatedAt:     now,
+		})
+	}
+
+	for _, rate := range rates {
+		if _, err := service.ExchangeRateRepository.Save(ctx, rate); err != nil {
+			return nil, err
+		}
+	}
+	return helper.ToExchangeRateResponses(rates), nil
+}
+
+// Import saves the rates of a CSV file with a header naming the columns
+// base, quote, rate and effective_from, in any order.
+func (service *ExchangeRateServiceImpl) Import(ctx context.Context, reader io.Reader) ([]web.ExchangeRateResponse, error) {
+	if err := service.AdminPolicy.checkAdmin(ctx); err != nil {
+		return nil, err
+	}
+	request, err := parseExchangeRates(reader)
+	if err != nil {
+		return nil, err
+	}
+	return service.Create(ctx, request)
+}
+
+func (service *ExchangeRateServiceImpl) FindByPair(ctx context.Context, request web.ExchangeRateListRequest) ([]web.ExchangeRateResponse, error) {
+	err := service.Validate.Struct(request)
+	if err != nil {
+		return nil, exception.NewValidationErrors(err)
+	}
+
+	rates, err := service.ExchangeRateRepository.FindByPair(ctx, request.Base, request.Quote)
+	if err != nil {
+		return nil, err
+	}
+	return helper.ToExchangeRateResponses(rates), nil
+}
+
+// parseExchangeRates reads the rates of a CSV file into the request to
+// create them.
+func parseExchangeRates(reader io.Reader) (web.ExchangeRateBatchRequest, error) {
+	csvReader := csv.NewReader(reader)
+	csvReader.TrimLeadingSpace = true
+
+	header, err := csvReader.Read()
+	if err != nil {
+		return web.ExchangeRateBatchRequest{}, exception.NewValidationError("the CSV file must have a header with the columns {0}",
+			strings.Join(exchangeRateColumns, ", "))
+	}
+	columns := map[string]int{}
+	for index, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = index
+	}
+	for _, name := range exchangeRateColumns {
+		if _, found := columns[name]; !found {
+			return web.ExchangeRateBatchRequest{}, exception.NewValidationError("the CSV file must have a header with the columns {0}",
+				strings.Join(exchangeRateColumns, ", "))
+		}
+	}
+
+	request := web.ExchangeRateBatchRequest{}
+	for {
+		record, err := csvReader.Read()
+		if errors.Is(err, io.EOF) {
+			return request, nil
+		}
+		var parseError *csv.ParseError
+		if errors.As(err, &parseError) {
+			return web.ExchangeRateBatchRequest{}, exception.NewValidationError("line {0} of the CSV file is invalid", strconv.Itoa(parseError.Line))
+		}
+		if err != nil {
+			return web.ExchangeRateBatchRequest{}, err
+		}
+
+		request.Rates = append(request.Rates, web.ExchangeRateCreateRequest{
+			Base:          strings.ToUpper(strings.TrimSpace(record[columns["base"]])),
+			Quote:         strings.ToUpper(strings.TrimSpace(record[columns["quote"]])),
+			Rate:          json.Number(strings.TrimSpace(record[columns["rate"]])),
+			EffectiveFrom: strings.TrimSpace(record[columns["effective_from"]]),
+		})
+	}
+}
@@ -0,0 +1,52 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"github.com/refandas/duit-api/exception"
+	"github.com/refandas/duit-api/helper"
+)
+
+// OwnershipPolicy decides how the services respond when the caller tries
+// to access a resource owned by another user.
+type OwnershipPolicy int
+
+const (
+	// OwnershipPolicyNotFound responds as if the resource does not exist,
+	// so the existence of other users' resources is not revealed.
+	OwnershipPolicyNotFound OwnershipPolicy = iota
+
+	// OwnershipPolicyForbidden responds with a forbidden error.
+	OwnershipPolicyForbidden
+)
+
+// ParseOwnershipPolicy returns the OwnershipPolicy named by the given
+// value, either `not_found` or `forbidden`.
+func ParseOwnershipPolicy(value string) OwnershipPolicy {
+	switch value {
+	case "not_found":
+		return OwnershipPolicyNotFound
+	case "forbidden":
+		return OwnershipPolicyForbidden
+	default:
+		panic(fmt.Sprintf("unsupported ownership policy %q", value))
+	}
+}
+
+// checkOwnership returns an error unless the authenticated user stored in
+// the context is the given owner of a resource. The notFoundMessage is used
+// when the policy hides the resource from the caller.
+func (policy OwnershipPolicy) checkOwnership(ctx context.Context, ownerId string, notFoundMessage string) error {
+	callerId, ok := helper.UserIdFromContext(ctx)
+	if !ok {
+		return exception.NewUnauthorizedError("missing access token")
+	}
+	if callerId == ownerId {
+		return nil
+	}
+
+	if policy == OwnershipPolicyForbidden {
+		return exception.NewForbiddenError("access to the resource is forbidden")
+	}
+	return exception.NewNotFoundError(notFoundMessage)
+}
@@ -0,0 +1,86 @@
+package service
+
+import (
+	"context"
+	"github.com/refandas/duit-api/exception"
+	"github.com/refandas/duit-api/model/domain"
+	"github.com/refandas/duit-api/repository"
+	"time"
+)
+
+// spendingPageLimit is the number of spendings read from the repository at
+// a time while iterating over the spendings of a user.
+const spendingPageLimit = 100
+
+// periodStart returns the start of the period of the given kind containing
+// the time, in the location of the time. Weeks start on Monday.
+func periodStart(period string, t time.Time) time.Time {
+	year, month, day := t.Date()
+	switch period {
+	case "day":
+		return time.Date(year, month, day, 0, 0, 0, 0, t.Location())
+	case "week":
+		offset := (int(t.Weekday()) + 6) % 7
+		return time.Date(year, month, day-offset, 0, 0, 0, 0, t.Location())
+	case "year":
+		return time.Date(year, time.January, 1, 0, 0, 0, 0, t.Location())
+	default:
+		return time.Date(year, month, 1, 0, 0, 0, 0, t.Location())
+	}
+}
+
+// addPeriods returns the start of the period the given number of periods
+// after the period starting at the given time.
+func addPeriods(period string, start time.Time, periods int) time.Time {
+	switch period {
+	case "day":
+		return start.AddDate(0, 0, periods)
+	case "week":
+		return start.AddDate(0, 0, 7*periods)
+	case "year":
+		return start.AddDate(periods, 0, 0)
+	default:
+		return start.AddDate(0, periods, 0)
+	}
+}
+
+// loadLocation returns the location of the first time zone that is set,
+// or UTC when none of them is set. It returns a validation error when the
+// time zone is not known.
+func loadLocation(timeZones ...string) (*time.Location, error) {
+	for _, timeZone := range timeZones {
+		if timeZone == "" {
+			continue
+		}
+		location, err := time.LoadLocation(timeZone)
+		if err != nil {
+			return nil, exception.NewValidationError("invalid time zone")
+		}
+		return location, nil
+	}
+	return time.UTC, nil
+}
+
+// eachSpending calls the function with every spending matching the query,
+// reading the spendings from the repository a page at a time, and stops at
+// the first error returned by the function. The limit and the start of the
+// query are managed by eachSpending.
+func eachSpending(ctx context.Context, spendingRepository repository.SpendingRepository, query domain.SpendingQuery, fn func(spending domain.Spending) error) error {
+	query.Limit = spendingPageLimit
+	query.After = nil
+	for {
+		page, err := spendingRepository.FindByUserId(ctx, query)
+		if err != nil {
+			return err
+		}
+		for _, spending := range page.Spendings {
+			if err := fn(spending); err != nil {
+				return err
+			}
+		}
+		if page.Next == nil {
+			return nil
+		}
+		query.After = page.Next
+	}
+}
@@ -0,0 +1,15 @@
+package service
+
+import (
+	"context"
+	"github.com/refandas/duit-api/model/web"
+)
+
+type RecurringSpendingService interface {
+	Create(ctx context.Context, request web.RecurringSpendingCreateRequest) (web.RecurringSpendingResponse, error)
+	Update(ctx context.Context, request web.RecurringSpendingUpdateRequest) (web.RecurringSpendingResponse, error)
+	Delete(ctx context.Context, userId string, recurringId string) error
+	FindById(ctx context.Context, userId string, recurringId string) (web.RecurringSpendingResponse, error)
+	FindByUserId(ctx context.Context, userId string) ([]web.RecurringSpendingResponse, error)
+	Materialize(ctx context.Context, date int64) (int, error)
+}
@@ -0,0 +1,247 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/go-playground/validator/v10"
+	"github.com/refandas/duit-api/exception"
+	"github.com/refandas/duit-api/helper"
+	"github.com/refandas/duit-api/model/domain"
+	"github.com/refandas/duit-api/model/web"
+	"github.com/refandas/duit-api/repository"
+	"time"
+)
+
+type RecurringSpendingServiceImpl struct {
+	RecurringSpendingRepository repository.RecurringSpendingRepository
+	SpendingRepository          repository.SpendingRepository
+	CategoryRepository          repository.CategoryRepository
+	UserRepository              repository.UserRepository
+	Validate                    *validator.Validate
+	Policy                      OwnershipPolicy
+}
+
+func NewRecurringSpendingService(recurringSpendingRepository repository.RecurringSpendingRepository, spendingRepository repository.SpendingRepository, categoryRepository repository.CategoryRepository, userRepository repository.UserRepository, validate *validator.Validate, policy OwnershipPolicy) RecurringSpendingService {
+	return &RecurringSpendingServiceImpl{
+		RecurringSpendingRepository: recurringSpendingRepository,
+		SpendingRepository:          spendingRepository,
+		CategoryRepository:          categoryRepository,
+		UserRepository:              userRepository,
+		Validate:                    validate,
+		Policy:                      policy,
+	}
+}
+
+func (service *RecurringSpendingServiceImpl) Create(ctx context.Context, request web.RecurringSpendingCreateRequest) (web.RecurringSpendingResponse, error) {
+	err := service.Validate.Struct(request)
+	if err != nil {
+		return web.RecurringSpendingResponse{}, exception.NewValidationErrors(err)
+	}
+	if err := service.Policy.checkOwnership(ctx, request.UserId, "user not found"); err != nil {
+		return web.RecurringSpendingResponse{}, err
+	}
+
+	recurring := domain.RecurringSpending{
+		Id:          request.Id,
+		UserId:      request.UserId,
+		Title:       request.Title,
+		Description: request.Description,
+		Category:    request.Category,
+		Rule: domain.RecurrenceRule{
+			Frequency: request.Frequency,
+			Interval:  request.Interval,
+			ByDay:     request.ByDay,
+			Start:     request.Start,
+			Until:     request.Until,
+			Count:     request.Count,
+		},
+		CreatedAt: request.CreatedAt,
+	}
+	if err := service.prepare(ctx, &recurring, request.Amount, request.Currency); err != nil {
+		return web.RecurringSpendingResponse{}, err
+	}
+
+	response, err := service.RecurringSpendingRepository.Save(ctx, recurring)
+	if err != nil {
+		return web.RecurringSpendingResponse{}, err
+	}
+	return helper.ToRecurringSpendingResponse(response), nil
+}
+
+// Update changes the recurring spending and its schedule. The spendings of
+// the occurrences created before are kept, and the next occurrence is the
+// first occurrence of the new schedule after the last created one.
+func (service *RecurringSpendingServiceImpl) Update(ctx context.Context, request web.RecurringSpendingUpdateRequest) (web.RecurringSpendingResponse, error) {
+	err := service.Validate.Struct(request)
+	if err != nil {
+		return web.RecurringSpendingResponse{}, exception.NewValidationErrors(err)
+	}
+
+	recurring, err := service.findRecurringSpending(ctx, request.UserId, request.Id)
+	if err != nil {
+		return web.RecurringSpendingResponse{}, err
+	}
+	recurring.Title = request.Title
+	recurring.Description = request.Description
+	recurring.Category = request.Category
+	recurring.Rule = domain.RecurrenceRule{
+		Frequency: request.Frequency,
+		Interval:  request.Interval,
+		ByDay:     request.ByDay,
+		Start:     request.Start,
+		Until:     request.Until,
+		Count:     request.Count,
+	}
+	if err := service.prepare(ctx, &recurring, request.Amount, request.Currency); err != nil {
+		return web.RecurringSpendingResponse{}, err
+	}
+
+	response, err := service.RecurringSpendingRepository.Update(ctx, recurring)
+	if err != nil {
+		return web.RecurringSpendingResponse{}, err
+	}
+	return helper.ToRecurringSpendingResponse(response), nil
+}
+
+// Delete deletes the recurring spending. The spendings of the occurrences
+// created before are kept.
+func (service *RecurringSpendingServiceImpl) Delete(ctx context.Context, userId string, recurringId string) error {
+	recurring, err := service.findRecurringSpending(ctx, userId, recurringId)
+	if err != nil {
+		return err
+	}
+	return service.RecurringSpendingRepository.Delete(ctx, recurring)
+}
+
+func (service *RecurringSpendingServiceImpl) FindById(ctx context.Context, userId string, recurringId string) (web.RecurringSpendingResponse, error) {
+	recurring, err := service.findRecurringSpending(ctx, userId, recurringId)
+	if err != nil {
+		return web.RecurringSpendingResponse{}, err
+	}
+	return helper.ToRecurringSpendingResponse(recurring), nil
+}
+
+func (service *RecurringSpendingServiceImpl) FindByUserId(ctx context.Context, userId string) ([]web.RecurringSpendingResponse, error) {
+	if err := service.Policy.checkOwnership(ctx, userId, "user not found"); err != nil {
+		return nil, err
+	}
+	recurringSpendings, err := service.RecurringSpendingRepository.FindByUserId(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+	return helper.ToRecurringSpendingResponses(recurringSpendings), nil
+}
+
+// Materialize creates the spendings of the occurrences of every recurring
+// spending due at the given date, stored in Unix time format, and returns
+// the number of created spendings.
+//
+// The spending of an occurrence has an id derived from the occurrence, and
+// is only created when no spending has the id, so an occurrence is never
+// created twice, even when the server stops before the recurring spending
+// is updated, or when several servers materialize at the same time. The
+// recurring spendings failing to materialize are tried again the next time.
+func (service *RecurringSpendingServiceImpl) Materialize(ctx context.Context, date int64) (int, error) {
+	recurringSpendings, err := service.RecurringSpendingRepository.FindDue(ctx, date)
+	if err != nil {
+		return 0, err
+	}
+
+	created := 0
+	var errs []error
+	for _, recurring := range recurringSpendings {
+		materialized, err := service.materialize(ctx, recurring, date)
+		created += materialized
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return created, errors.Join(errs...)
+}
+
+// materialize creates the spendings of the occurrences of the recurring
+// spending due at the given date, and returns the number of created
+// spendings.
+func (service *RecurringSpendingServiceImpl) materialize(ctx context.Context, recurring domain.RecurringSpending, date int64) (int, error) {
+	location, err := service.location(ctx, recurring.UserId)
+	if err != nil {
+		return 0, err
+	}
+
+	created := 0
+	expectedLast := recurring.LastOccurrence
+	for recurring.NextOccurrence != 0 && recurring.NextOccurrence <= date {
+		saved, err := service.SpendingRepository.SaveIfAbsent(ctx, recurring.Occurrence(recurring.NextOccurrence, time.Now().UnixMilli()))
+		if err != nil {
+			return created, err
+		}
+		if saved {
+			created++
+		}
+		recurring.LastOccurrence = recurring.NextOccurrence
+		recurring.Schedule(location)
+	}
+	// Only the occurrences are written back, and only when no other server
+	// has advanced the recurring spending and no one has deleted it since it
+	// was read, so neither a deleted recurring spending comes back nor a
+	// concurrent change of its details is overwritten.
+	_, err = service.RecurringSpendingRepository.Advance(ctx, recurring.Id, expectedLast, recurring.LastOccurrence, recurring.NextOccurrence)
+	return created, err
+}
+
+// prepare validates the amount, the category and the rule of the recurring
+// spending, and schedules its next occurrence.
+func (service *RecurringSpendingServiceImpl) prepare(ctx context.Context, recurring *domain.RecurringSpending, amount json.Number, currency string) error {
+	money, err := parseAmount(amount, currency)
+	if err != nil {
+		return err
+	}
+	recurring.Amount = money
+	if err := checkCategory(ctx, service.CategoryRepository, recurring.UserId, recurring.Category); err != nil {
+		return err
+	}
+
+	if recurring.Rule.Interval == 0 {
+		recurring.Rule.Interval = 1
+	}
+	if len(recurring.Rule.ByDay) > 0 && recurring.Rule.Frequency != domain.FrequencyWeekly {
+		return exception.NewValidationError("by_day is only allowed with the weekly frequency")
+	}
+	if recurring.Rule.Until != 0 && recurring.Rule.Count != 0 {
+		return exception.NewValidationError("until and count must not both be set")
+	}
+
+	location, err := service.location(ctx, recurring.UserId)
+	if err != nil {
+		return err
+	}
+	recurring.Schedule(location)
+	return nil
+}
+
+// location returns the location of the time zone of the user, in which the
+// days of the recurring spendings of the user are.
+func (service *RecurringSpendingServiceImpl) location(ctx context.Context, userId string) (*time.Location, error) {
+	user, err := service.UserRepository.FindById(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+	return loadLocation(user.TimeZone)
+}
+
+// findRecurringSpending returns the recurring spending of the user with the
+// given id. A recurring spending of another user is reported as not found.
+func (service *RecurringSpendingServiceImpl) findRecurringSpending(ctx context.Context, userId string, recurringId string) (domain.RecurringSpending, error) {
+	if err := service.Policy.checkOwnership(ctx, userId, "user not found"); err != nil {
+		return domain.RecurringSpending{}, err
+	}
+	recurring, err := service.RecurringSpendingRepository.FindById(ctx, recurringId)
+	if err != nil {
+		return domain.RecurringSpending{}, err
+	}
+	if recurring.UserId != userId {
+		return domain.RecurringSpending{}, exception.NewNotFoundError("recurring spending not found")
+	}
+	return recurring, nil
+}
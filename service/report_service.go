@@ -0,0 +1,11 @@
+package service
+
+import (
+	"context"
+	"github.com/refandas/duit-api/model/web"
+)
+
+type ReportService interface {
+	Summary(ctx context.Context, request web.ReportSummaryRequest) (web.ReportSummaryResponse, error)
+	Balance(ctx context.Context, request web.BalanceRequest) (web.BalanceResponse, error)
+}
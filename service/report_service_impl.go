@@ -0,0 +1,356 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/go-playground/validator/v10"
+	"github.com/refandas/duit-api/exception"
+	"github.com/refandas/duit-api/helper"
+	"github.com/refandas/duit-api/model/domain"
+	"github.com/refandas/duit-api/model/web"
+	"github.com/refandas/duit-api/repository"
+	"math/big"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// defaultReportPeriods is the number of periods summarized when the
+// beginning of the report is not requested.
+const defaultReportPeriods = 12
+
+// maxReportPeriods limits the number of periods of a single report.
+const maxReportPeriods = 1000
+
+type ReportServiceImpl struct {
+	SpendingRepository     repository.SpendingRepository
+	UserRepository         repository.UserRepository
+	ExchangeRateRepository repository.ExchangeRateRepository
+	Validate               *validator.Validate
+	Policy                 OwnershipPolicy
+}
+
+func NewReportService(spendingRepository repository.SpendingRepository, userRepository repository.UserRepository, exchangeRateRepository repository.ExchangeRateRepository, validate *validator.Validate, policy OwnershipPolicy) ReportService {
+	return &ReportServiceImpl{
+		SpendingRepository:     spendingRepository,
+		UserRepository:         userRepository,
+		ExchangeRateRepository: exchangeRateRepository,
+		Validate:               validate,
+		Policy:                 policy,
+	}
+}
+
+// spendingStatistics accumulates the amounts of a group of spendings. The
+// amounts are accumulated exactly and only rounded in the response.
+type spendingStatistics struct {
+	total big.Rat
+	count int
+	min   big.Rat
+	max   big.Rat
+}
+
+func (statistics *spendingStatistics) add(amount domain.Money) {
+	value := amount.Rat()
+	if statistics.count == 0 || value.Cmp(&statistics.min) < 0 {
+		statistics.min.Set(value)
+	}
+	if statistics.count == 0 || value.Cmp(&statistics.max) > 0 {
+		statistics.max.Set(value)
+	}
+	statistics.total.Add(&statistics.total, value)
+	statistics.count++
+}
+
+// response returns the statistics in the given currency, with the amounts
+// rounded to its minor unit.
+func (statistics *spendingStatistics) response(currency string) web.SpendingStatisticsResponse {
+	response := web.SpendingStatisticsResponse{
+		Total:   decimalAmount(&statistics.total, currency),
+		Count:   statistics.count,
+		Average: decimalAmount(new(big.Rat), currency),
+		Min:     decimalAmount(&statistics.min, currency),
+		Max:     decimalAmount(&statistics.max, currency),
+	}
+	if statistics.count > 0 {
+		average := new(big.Rat).Quo(&statistics.total, big.NewRat(int64(statistics.count), 1))
+		response.Average = decimalAmount(average, currency)
+	}
+	return response
+}
+
+// decimalAmount returns the exact amount as a decimal number with the
+// decimal places of the minor unit of the currency, like the amounts of the
+// spendings, so it is not rounded to a floating point number.
+func decimalAmount(amount *big.Rat, currency string) json.Number {
+	return json.Number(amount.FloatString(domain.CurrencyExponent(currency)))
+}
+
+// categoryStatistics accumulates the amounts of spendings per category.
+type categoryStatistics map[string]*spendingStatistics
+
+func (statistics categoryStatistics) add(category string, amount domain.Money) {
+	if statistics[category] == nil {
+		statistics[category] = &spendingStatistics{}
+	}
+	statistics[category].add(amount)
+}
+
+// response returns the statistics of every category sorted by the name of
+// the category.
+func (statistics categoryStatistics) response(currency string) []web.ReportCategoryResponse {
+	responses := make([]web.ReportCategoryResponse, 0, len(statistics))
+	for category, categoryStatistics := range statistics {
+		responses = append(responses, web.ReportCategoryResponse{
+			Category:                   category,
+			SpendingStatisticsResponse: categoryStatistics.response(currency),
+		})
+	}
+	sort.Slice(responses, func(i, j int) bool {
+		return responses[i].Category < responses[j].Category
+	})
+	return responses
+}
+
+// reportPeriod is a period of a report, from its start up to but excluding
+// its end.
+type reportPeriod struct {
+	start      time.Time
+	end        time.Time
+	statistics spendingStatistics
+	categories categoryStatistics
+}
+
+func (service *ReportServiceImpl) Summary(ctx context.Context, request web.ReportSummaryRequest) (web.ReportSummaryResponse, error) {
+	err := service.Validate.Struct(request)
+	if err != nil {
+		return web.ReportSummaryResponse{}, exception.NewValidationErrors(err)
+	}
+	if err := service.Policy.checkOwnership(ctx, request.UserId, "user not found"); err != nil {
+		return web.ReportSummaryResponse{}, err
+	}
+
+	user, err := service.UserRepository.FindById(ctx, request.UserId)
+	if err != nil {
+		return web.ReportSummaryResponse{}, err
+	}
+
+	// The requested time zone takes precedence over the user's preference.
+	location, err := loadLocation(request.TimeZone, user.TimeZone)
+	if err != nil {
+		return web.ReportSummaryResponse{}, err
+	}
+
+	from, to, bounds, err := reportRange(request.Period, request.From, request.To, location)
+	if err != nil {
+		return web.ReportSummaryResponse{}, err
+	}
+	periods := make([]*reportPeriod, 0, len(bounds)-1)
+	for i := 1; i < len(bounds); i++ {
+		periods = append(periods, &reportPeriod{
+			start:      bounds[i-1],
+			end:        bounds[i],
+			categories: categoryStatistics{},
+		})
+	}
+
+	fromMilliseconds := from.UnixMilli()
+	toMilliseconds := to.UnixMilli()
+	summary := spendingStatistics{}
+	categories := categoryStatistics{}
+	unconverted := 0
+	converter := newCurrencyConverter(service.ExchangeRateRepository, user.Currency())
+	err = eachSpending(ctx, service.SpendingRepository, domain.SpendingQuery{
+		UserId: request.UserId,
+		From:   &fromMilliseconds,
+		To:     &toMilliseconds,
+		Type:   domain.SpendingTypeExpense,
+	}, func(spending domain.Spending) error {
+		index := sort.Search(len(periods), func(i int) bool {
+			return periods[i].end.UnixMilli() > spending.Date
+		})
+		if index == len(periods) {
+			return nil
+		}
+
+		// The spendings in a currency without a rate effective at their
+		// date can not be summed, so they are only counted.
+		amount, ok, err := converter.convert(ctx, spending.Amount, spending.Date)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			unconverted++
+			return nil
+		}
+		periods[index].statistics.add(amount)
+		periods[index].categories.add(spending.Category, amount)
+		summary.add(amount)
+		categories.add(spending.Category, amount)
+		return nil
+	})
+	if err != nil {
+		return web.ReportSummaryResponse{}, err
+	}
+
+	periodResponses := make([]web.ReportPeriodResponse, 0, len(periods))
+	for _, period := range periods {
+		periodResponses = append(periodResponses, web.ReportPeriodResponse{
+			Start:                      period.start.UnixMilli(),
+			End:                        period.end.UnixMilli() - 1,
+			SpendingStatisticsResponse: period.statistics.response(user.Currency()),
+			Categories:                 period.categories.response(user.Currency()),
+		})
+	}
+
+	return web.ReportSummaryResponse{
+		Period:                     request.Period,
+		TimeZone:                   location.String(),
+		Currency:                   user.Currency(),
+		Unconverted:                unconverted,
+		From:                       from.UnixMilli(),
+		To:                         to.UnixMilli(),
+		SpendingStatisticsResponse: summary.response(user.Currency()),
+		Periods:                    periodResponses,
+		Categories:                 categories.response(user.Currency()),
+	}, nil
+}
+
+// Balance returns the incomes, the expenses and the net cash flow of the
+// user in every period of the requested range, converted into the home
+// currency of the user.
+func (service *ReportServiceImpl) Balance(ctx context.Context, request web.BalanceRequest) (web.BalanceResponse, error) {
+	err := service.Validate.Struct(request)
+	if err != nil {
+		return web.BalanceResponse{}, exception.NewValidationErrors(err)
+	}
+	if err := service.Policy.checkOwnership(ctx, request.UserId, "user not found"); err != nil {
+		return web.BalanceResponse{}, err
+	}
+
+	user, err := service.UserRepository.FindById(ctx, request.UserId)
+	if err != nil {
+		return web.BalanceResponse{}, err
+	}
+	location, err := loadLocation(request.TimeZone, user.TimeZone)
+	if err != nil {
+		return web.BalanceResponse{}, err
+	}
+	from, to, bounds, err := reportRange(request.Period, request.From, request.To, location)
+	if err != nil {
+		return web.BalanceResponse{}, err
+	}
+	periods := make([]cashFlow, len(bounds)-1)
+
+	fromMilliseconds := from.UnixMilli()
+	toMilliseconds := to.UnixMilli()
+	total := cashFlow{}
+	unconverted := 0
+	converter := newCurrencyConverter(service.ExchangeRateRepository, user.Currency())
+	err = eachSpending(ctx, service.SpendingRepository, domain.SpendingQuery{
+		UserId: request.UserId,
+		From:   &fromMilliseconds,
+		To:     &toMilliseconds,
+	}, func(spending domain.Spending) error {
+		index := sort.Search(len(periods), func(i int) bool {
+			return bounds[i+1].UnixMilli() > spending.Date
+		})
+		if index == len(periods) {
+			return nil
+		}
+
+		amount, ok, err := converter.convert(ctx, spending.Amount, spending.Date)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			unconverted++
+			return nil
+		}
+		periods[index].add(spending.Kind(), amount)
+		total.add(spending.Kind(), amount)
+		return nil
+	})
+	if err != nil {
+		return web.BalanceResponse{}, err
+	}
+
+	periodResponses := make([]web.BalancePeriodResponse, 0, len(periods))
+	for i, period := range periods {
+		periodResponses = append(periodResponses, web.BalancePeriodResponse{
+			Start:            bounds[i].UnixMilli(),
+			End:              bounds[i+1].UnixMilli() - 1,
+			CashFlowResponse: period.response(user.Currency()),
+		})
+	}
+
+	return web.BalanceResponse{
+		Period:           request.Period,
+		TimeZone:         location.String(),
+		Currency:         user.Currency(),
+		Unconverted:      unconverted,
+		From:             from.UnixMilli(),
+		To:               to.UnixMilli(),
+		CashFlowResponse: total.response(user.Currency()),
+		Periods:          periodResponses,
+	}, nil
+}
+
+// cashFlow accumulates the incomes and the expenses of a period. The amounts
+// are accumulated exactly and only rounded in the response.
+type cashFlow struct {
+	income   big.Rat
+	expenses big.Rat
+}
+
+func (flow *cashFlow) add(kind string, amount domain.Money) {
+	if kind == domain.SpendingTypeIncome {
+		flow.income.Add(&flow.income, amount.Rat())
+		return
+	}
+	flow.expenses.Add(&flow.expenses, amount.Rat())
+}
+
+// response returns the cash flow in the given currency, with the amounts
+// rounded to its minor unit.
+func (flow *cashFlow) response(currency string) web.CashFlowResponse {
+	return web.CashFlowResponse{
+		Income:   decimalAmount(&flow.income, currency),
+		Expenses: decimalAmount(&flow.expenses, currency),
+		Net:      decimalAmount(new(big.Rat).Sub(&flow.income, &flow.expenses), currency),
+	}
+}
+
+// reportRange returns the beginning and the end of a report of the requested
+// range in the location, with the starts of its periods followed by the end
+// of the last period. The report ends now when no end is requested, and
+// begins defaultReportPeriods periods before when no beginning is.
+func reportRange(period string, fromValue string, toValue string, location *time.Location) (time.Time, time.Time, []time.Time, error) {
+	to := time.Now().In(location)
+	if toValue != "" {
+		milliseconds, err := helper.ParseTime(toValue, location, true)
+		if err != nil {
+			return time.Time{}, time.Time{}, nil, exception.NewValidationError("{0} must be a date", "to")
+		}
+		to = time.UnixMilli(milliseconds).In(location)
+	}
+	from := addPeriods(period, periodStart(period, to), 1-defaultReportPeriods)
+	if fromValue != "" {
+		milliseconds, err := helper.ParseTime(fromValue, location, false)
+		if err != nil {
+			return time.Time{}, time.Time{}, nil, exception.NewValidationError("{0} must be a date", "from")
+		}
+		from = time.UnixMilli(milliseconds).In(location)
+	}
+	if from.After(to) {
+		return time.Time{}, time.Time{}, nil, exception.NewValidationError("from must not be after to")
+	}
+
+	bounds := []time.Time{periodStart(period, from)}
+	for !bounds[len(bounds)-1].After(to) {
+		if len(bounds) > maxReportPeriods {
+			return time.Time{}, time.Time{}, nil, exception.NewValidationError("a report must not have more than {0} periods", strconv.Itoa(maxReportPeriods))
+		}
+		bounds = append(bounds, addPeriods(period, bounds[len(bounds)-1], 1))
+	}
+	return from, to, bounds, nil
+}
@@ -0,0 +1,12 @@
+package service
+
+import (
+	"context"
+	"github.com/refandas/duit-api/model/web"
+)
+
+type SessionService interface {
+	Delete(ctx context.Context, userId string, sessionId string) error
+	DeleteByUserId(ctx context.Context, userId string) error
+	FindByUserId(ctx context.Context, userId string) ([]web.SessionResponse, error)
+}
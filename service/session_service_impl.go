@@ -0,0 +1,87 @@
+package service
+
+import (
+	"context"
+	"github.com/refandas/duit-api/exception"
+	"github.com/refandas/duit-api/helper"
+	"github.com/refandas/duit-api/model/domain"
+	"github.com/refandas/duit-api/model/web"
+	"github.com/refandas/duit-api/repository"
+	"time"
+)
+
+type SessionServiceImpl struct {
+	SessionRepository repository.SessionRepository
+	Policy            OwnershipPolicy
+}
+
+func NewSessionService(sessionRepository repository.SessionRepository, policy OwnershipPolicy) SessionService {
+	return &SessionServiceImpl{
+		SessionRepository: sessionRepository,
+		Policy:            policy,
+	}
+}
+
+func (service *SessionServiceImpl) Delete(ctx context.Context, userId string, sessionId string) error {
+	if err := service.Policy.checkOwnership(ctx, userId, "user not found"); err != nil {
+		return err
+	}
+
+	sessions, err := service.activeSessions(ctx, userId)
+	if err != nil {
+		return err
+	}
+	for _, session := range sessions {
+		if session.FamilyId == sessionId {
+			return service.SessionRepository.RevokeFamily(ctx, userId, sessionId)
+		}
+	}
+	return exception.NewNotFoundError("session not found")
+}
+
+func (service *SessionServiceImpl) DeleteByUserId(ctx context.Context, userId string) error {
+	if err := service.Policy.checkOwnership(ctx, userId, "user not found"); err != nil {
+		return err
+	}
+
+	sessions, err := service.activeSessions(ctx, userId)
+	if err != nil {
+		return err
+	}
+	for _, session := range sessions {
+		if err := service.SessionRepository.RevokeFamily(ctx, userId, session.FamilyId); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (service *SessionServiceImpl) FindByUserId(ctx context.Context, userId string) ([]web.SessionResponse, error) {
+	if err := service.Policy.checkOwnership(ctx, userId, "user not found"); err != nil {
+		return nil, err
+	}
+
+	sessions, err := service.activeSessions(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+	return helper.ToSessionResponses(sessions), nil
+}
+
+// activeSessions returns the sessions of the user whose refresh token can
+// still be used, which is one session for each logged in device.
+func (service *SessionServiceImpl) activeSessions(ctx context.Context, userId string) ([]domain.Session, error) {
+	userSessions, err := service.SessionRepository.FindByUserId(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	var sessions []domain.Session
+	now := time.Now().UnixMilli()
+	for _, session := range userSessions {
+		if session.Active(now) {
+			sessions = append(sessions, session)
+		}
+	}
+	return sessions, nil
+}
@@ -0,0 +1,164 @@
+package service
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"github.com/refandas/duit-api/exception"
+	"github.com/refandas/duit-api/helper"
+	"github.com/refandas/duit-api/model/domain"
+	"github.com/refandas/duit-api/model/web"
+	"io"
+	"strings"
+	"time"
+)
+
+// spendingExportColumns lists the columns of the CSV and XLSX exports of
+// spendings.
+var spendingExportColumns = []string{
+	"id", "date", "title", "description", "category", "type", "amount", "currency", "converted_amount", "converted_currency", "status",
+}
+
+// spendingExportFormats maps the formats of the exports of spendings to
+// their content types.
+var spendingExportFormats = map[string]string{
+	"csv":   "text/csv; charset=utf-8",
+	"jsonl": "application/jsonl; charset=utf-8",
+	"xlsx":  "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+}
+
+// SpendingExport is an export of spendings into a file. The export is
+// validated before the file is written, so the errors of the request can be
+// responded before the file is.
+type SpendingExport struct {
+	ContentType string
+	FileName    string
+
+	format   string
+	location *time.Location
+	each     func(ctx context.Context, fn func(response web.SpendingResponse) error) error
+}
+
+// Export returns the export of the spendings matching the listing criteria
+// of the request. The spendings are read as the export is written, so
+// the cursor and the limit of the request are ignored.
+func (service *SpendingServiceImpl) Export(ctx context.Context, request web.SpendingExportRequest) (*SpendingExport, error) {
+	err := service.Validator.Struct(request)
+	if err != nil {
+		return nil, exception.NewValidationErrors(err)
+	}
+	if err := service.Policy.checkOwnership(ctx, request.UserId, "user not found"); err != nil {
+		return nil, err
+	}
+	user, err := service.UserRepository.FindById(ctx, request.UserId)
+	if err != nil {
+		return nil, err
+	}
+	location, err := loadLocation(request.TimeZone, user.TimeZone)
+	if err != nil {
+		return nil, err
+	}
+	query, found, err := spendingQuery(request.SpendingListRequest)
+	if err != nil {
+		return nil, err
+	}
+
+	converter := newCurrencyConverter(service.ExchangeRateRepository, user.Currency())
+	return &SpendingExport{
+		ContentType: spendingExportFormats[request.Format],
+		FileName:    "spendings." + request.Format,
+		format:      request.Format,
+		location:    location,
+		each: func(ctx context.Context, fn func(response web.SpendingResponse) error) error {
+			if !found {
+				return nil
+			}
+			return eachSpending(ctx, service.SpendingRepository, query, func(spending domain.Spending) error {
+				responses, err := converter.spendingResponses(ctx, []domain.Spending{spending})
+				if err != nil {
+					return err
+				}
+				return fn(responses[0])
+			})
+		},
+	}, nil
+}
+
+// Write writes the spendings into the file, reading them a page at a time.
+// The dates are written in the time zone of the export, as RFC 3339
+// timestamps in JSON Lines and as `YYYY-MM-DD HH:mm:ss` in the other
+// formats. The texts entered by the user are escaped in the CSV and XLSX
+// formats, so a spreadsheet opening the file never runs them as formulas.
+func (export *SpendingExport) Write(ctx context.Context, writer io.Writer) error {
+	switch export.format {
+	case "jsonl":
+		encoder := json.NewEncoder(writer)
+		return export.each(ctx, func(response web.SpendingResponse) error {
+			return encoder.Encode(export.response(response, time.RFC3339))
+		})
+	case "xlsx":
+		xlsxWriter, err := helper.NewXLSXWriter(writer, "Spendings")
+		if err != nil {
+			return err
+		}
+		header := make([]interface{}, len(spendingExportColumns))
+		for i, column := range spendingExportColumns {
+			header[i] = column
+		}
+		if err := xlsxWriter.WriteRow(header...); err != nil {
+			return err
+		}
+		err = export.each(ctx, func(response web.SpendingResponse) error {
+			row := export.response(response, time.DateTime)
+			return xlsxWriter.WriteRow(row.Id, row.Date, spreadsheetText(row.Title), spreadsheetText(row.Description), spreadsheetText(row.Category), row.Type,
+				row.Amount, row.Currency, row.ConvertedAmount, row.ConvertedCurrency, row.Status)
+		})
+		if err != nil {
+			return err
+		}
+		return xlsxWriter.Close()
+	default:
+		csvWriter := csv.NewWriter(writer)
+		if err := csvWriter.Write(spendingExportColumns); err != nil {
+			return err
+		}
+		err := export.each(ctx, func(response web.SpendingResponse) error {
+			row := export.response(response, time.DateTime)
+			return csvWriter.Write([]string{row.Id, row.Date, spreadsheetText(row.Title), spreadsheetText(row.Description), spreadsheetText(row.Category), row.Type,
+				row.Amount.String(), row.Currency, row.ConvertedAmount.String(), row.ConvertedCurrency, row.Status})
+		})
+		if err != nil {
+			return err
+		}
+		csvWriter.Flush()
+		return csvWriter.Error()
+	}
+}
+
+// response returns the exported spending of the response, dated in the
+// time zone of the export by the given layout.
+func (export *SpendingExport) response(response web.SpendingResponse, layout string) web.SpendingExportResponse {
+	return web.SpendingExportResponse{
+		Id:                response.Id,
+		Date:              time.UnixMilli(response.Date).In(export.location).Format(layout),
+		Title:             response.Title,
+		Description:       response.Description,
+		Category:          response.Category,
+		Type:              response.Type,
+		Amount:            response.Amount,
+		Currency:          response.Currency,
+		ConvertedAmount:   response.ConvertedAmount,
+		ConvertedCurrency: response.ConvertedCurrency,
+		Status:            response.Status,
+	}
+}
+
+// spreadsheetText returns the text escaped for a cell of a spreadsheet. A
+// text starting like a formula is prefixed by a `'`, which spreadsheets take
+// as the mark of a text cell.
+func spreadsheetText(text string) string {
+	if text != "" && strings.ContainsRune("=+-@\t\r", rune(text[0])) {
+		return "'" + text
+	}
+	return text
+}
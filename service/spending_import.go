@@ -0,0 +1,359 @@
+package service
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"github.com/google/uuid"
+	"github.com/refandas/duit-api/exception"
+	"github.com/refandas/duit-api/helper"
+	"github.com/refandas/duit-api/model/domain"
+	"github.com/refandas/duit-api/model/web"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// spendingImportLimit limits the number of rows of a CSV file of spendings.
+const spendingImportLimit = 10000
+
+// The statuses of the rows of a CSV file of spendings.
+const (
+	spendingImportCreated   = "created"
+	spendingImportValid     = "valid"
+	spendingImportDuplicate = "duplicate"
+	spendingImportInvalid   = "invalid"
+)
+
+// dateFormatReplacer translates a date format such as `DD/MM/YYYY` into a
+// layout of the time package.
+var dateFormatReplacer = strings.NewReplacer(
+	"YYYY", "2006", "YY", "06", "MM", "01", "DD", "02", "HH", "15", "mm", "04", "ss", "05",
+)
+
+// importDelimiter returns the delimiter of the fields of a CSV file of
+// spendings, a comma unless another character is requested. A quote or a
+// line break can not delimit the fields.
+func importDelimiter(delimiter string) (rune, error) {
+	if delimiter == "" {
+		return ',', nil
+	}
+	comma := []rune(delimiter)[0]
+	if comma == '"' || comma == '\r' || comma == '\n' || comma == utf8.RuneError {
+		return 0, exception.NewValidationError("{0} is invalid", "delimiter")
+	}
+	return comma, nil
+}
+
+// spendingImport is the state of an import of a CSV file of spendings.
+type spendingImport struct {
+	request    web.SpendingImportRequest
+	location   *time.Location
+	currency   string
+	columns    map[string]int
+	categories map[string]error
+	createdAt  int64
+}
+
+// Import creates the spendings of the rows of a CSV file. Every row is
+// validated like a created spending, and the invalid rows are reported
+// instead of created. A row with the same date, amount and title as a
+// spending of the user, or as a previous row, is reported as a duplicate, so
+// a file can be imported again after its invalid rows are fixed. Nothing is
+// created in a dry run.
+func (service *SpendingServiceImpl) Import(ctx context.Context, request web.SpendingImportRequest, reader io.Reader) (web.SpendingImportResponse, error) {
+	err := service.Validator.Struct(request)
+	if err != nil {
+		return web.SpendingImportResponse{}, exception.NewValidationErrors(err)
+	}
+	delimiter, err := importDelimiter(request.Delimiter)
+	if err != nil {
+		return web.SpendingImportResponse{}, err
+	}
+	if err := service.Policy.checkOwnership(ctx, request.UserId, "user not found"); err != nil {
+		return web.SpendingImportResponse{}, err
+	}
+	user, err := service.UserRepository.FindById(ctx, request.UserId)
+	if err != nil {
+		return web.SpendingImportResponse{}, err
+	}
+	location, err := loadLocation(user.TimeZone)
+	if err != nil {
+		return web.SpendingImportResponse{}, err
+	}
+
+	state := &spendingImport{
+		request:    request,
+		location:   location,
+		currency:   user.Currency(),
+		categories: map[string]error{},
+		createdAt:  time.Now().UnixMilli(),
+	}
+	csvReader := csv.NewReader(reader)
+	csvReader.TrimLeadingSpace = true
+	csvReader.FieldsPerRecord = -1
+	csvReader.Comma = delimiter
+	if err := state.readHeader(csvReader); err != nil {
+		return web.SpendingImportResponse{}, err
+	}
+
+	response := web.SpendingImportResponse{DryRun: request.DryRun, Rows: []web.SpendingImportRowResponse{}}
+	var spendings []domain.Spending
+	var rows []int
+	for {
+		record, err := csvReader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		var parseError *csv.ParseError
+		if errors.As(err, &parseError) {
+			return web.SpendingImportResponse{}, exception.NewValidationError("line {0} of the CSV file is invalid", strconv.Itoa(parseError.Line))
+		}
+		if err != nil {
+			return web.SpendingImportResponse{}, err
+		}
+		if len(response.Rows) == spendingImportLimit {
+			return web.SpendingImportResponse{}, exception.NewValidationError("the CSV file must not have more than {0} rows",
+				strconv.Itoa(spendingImportLimit))
+		}
+
+		line, _ := csvReader.FieldPos(0)
+		row := web.SpendingImportRowResponse{Line: line, Status: spendingImportInvalid}
+		spending, fieldErrors, err := service.readSpending(ctx, state, record)
+		if err != nil {
+			return web.SpendingImportResponse{}, err
+		}
+		if len(fieldErrors) > 0 {
+			row.Errors = fieldErrors
+		} else {
+			spendings = append(spendings, spending)
+			rows = append(rows, len(response.Rows))
+		}
+		response.Rows = append(response.Rows, row)
+	}
+	response.Total = len(response.Rows)
+	response.Invalid = response.Total - len(spendings)
+
+	existing, err := service.importedKeys(ctx, request.UserId, spendings)
+	if err != nil {
+		return web.SpendingImportResponse{}, err
+	}
+	var created []domain.Spending
+	for index, spending := range spendings {
+		row := &response.Rows[rows[index]]
+		key := importKey(spending)
+		if existing[key] {
+			row.Status = spendingImportDuplicate
+			response.Duplicates++
+			continue
+		}
+		existing[key] = true
+
+		row.Id = spending.Id
+		row.Status = spendingImportValid
+		response.Valid++
+		created = append(created, spending)
+	}
+	if request.DryRun || len(created) == 0 {
+		return response, nil
+	}
+
+	if err := service.SpendingRepository.SaveAll(ctx, created); err != nil {
+		return web.SpendingImportResponse{}, err
+	}
+	for index := range response.Rows {
+		if response.Rows[index].Status == spendingImportValid {
+			response.Rows[index].Status = spendingImportCreated
+		}
+	}
+	response.Created = len(created)
+	return response, nil
+}
+
+// readHeader reads the header of the CSV file and maps the columns of the
+// spendings to their positions. The title, amount and date columns are
+// required.
+func (state *spendingImport) readHeader(csvReader *csv.Reader) error {
+	required := []string{
+		columnName(state.request.TitleColumn, "title"),
+		columnName(state.request.AmountColumn, "amount"),
+		columnName(state.request.DateColumn, "date"),
+	}
+	header, err := csvReader.Read()
+	if err != nil {
+		return exception.NewValidationError("the CSV file must have a header with the columns {0}", strings.Join(required, ", "))
+	}
+
+	positions := map[string]int{}
+	for index, name := range header {
+		positions[strings.ToLower(strings.TrimSpace(name))] = index
+	}
+	for _, name := range required {
+		if _, found := positions[name]; !found {
+			return exception.NewValidationError("the CSV file must have a header with the columns {0}", strings.Join(required, ", "))
+		}
+	}
+
+	state.columns = map[string]int{}
+	for field, name := range map[string]string{
+		"title":       columnName(state.request.TitleColumn, "title"),
+		"amount":      columnName(state.request.AmountColumn, "amount"),
+		"currency":    columnName(state.request.CurrencyColumn, "currency"),
+		"date":        columnName(state.request.DateColumn, "date"),
+		"category":    columnName(state.request.CategoryColumn, "category"),
+		"description": columnName(state.request.DescriptionColumn, "description"),
+	} {
+		if index, found := positions[name]; found {
+			state.columns[field] = index
+		}
+	}
+	return nil
+}
+
+// value returns the trimmed value of the field in the record, or an empty
+// string when the file has no column for the field or the record is short.
+func (state *spendingImport) value(record []string, field string) string {
+	index, found := state.columns[field]
+	if !found || index >= len(record) {
+		return ""
+	}
+	return strings.TrimSpace(record[index])
+}
+
+// readSpending returns the spending of a record of the CSV file, or the
+// errors of the fields of the record when the spending is not valid.
+func (service *SpendingServiceImpl) readSpending(ctx context.Context, state *spendingImport, record []string) (domain.Spending, []web.FieldErrorResponse, error) {
+	var fieldErrors []web.FieldErrorResponse
+
+	currency := strings.ToUpper(state.value(record, "currency"))
+	if currency == "" {
+		currency = state.currency
+	}
+	date, dateErr := state.parseDate(state.value(record, "date"))
+	request := web.SpendingCreateRequest{
+		Id:          uuid.NewString(),
+		UserId:      state.request.UserId,
+		Title:       state.value(record, "title"),
+		Description: state.value(record, "description"),
+		Amount:      json.Number(state.normalizeAmount(state.value(record, "amount"))),
+		Currency:    currency,
+		Date:        date,
+		Category:    strings.ToLower(state.value(record, "category")),
+		CreatedAt:   state.createdAt,
+	}
+	if err := service.Validator.Struct(request); err != nil {
+		for _, fieldError := range exception.FieldErrors(ctx, "", err) {
+			// An invalid date is reported below rather than as a missing date.
+			if fieldError.Field != "date" || dateErr == nil {
+				fieldErrors = append(fieldErrors, fieldError)
+			}
+		}
+	}
+	if dateErr != nil {
+		fieldErrors = append(fieldErrors, exception.FieldErrors(ctx, "date", exception.NewValidationError("{0} must be a date", "date"))...)
+	}
+	if len(fieldErrors) > 0 {
+		return domain.Spending{}, fieldErrors, nil
+	}
+
+	amount, err := parseAmount(request.Amount, request.Currency)
+	if err != nil {
+		fieldErrors = append(fieldErrors, exception.FieldErrors(ctx, "amount", err)...)
+	}
+	categoryErr, checked := state.categories[request.Category]
+	if !checked {
+		categoryErr = service.checkCategory(ctx, request.UserId, request.Category)
+		var apiError *exception.Error
+		if categoryErr != nil && !errors.As(categoryErr, &apiError) {
+			return domain.Spending{}, nil, categoryErr
+		}
+		state.categories[request.Category] = categoryErr
+	}
+	if categoryErr != nil {
+		fieldErrors = append(fieldErrors, exception.FieldErrors(ctx, "category", categoryErr)...)
+	}
+	if len(fieldErrors) > 0 {
+		return domain.Spending{}, fieldErrors, nil
+	}
+
+	return domain.Spending{
+		Id:          request.Id,
+		UserId:      request.UserId,
+		Title:       request.Title,
+		Description: request.Description,
+		Category:    request.Category,
+		Type:        domain.SpendingTypeExpense,
+		Date:        request.Date,
+		Amount:      amount,
+		CreatedAt:   request.CreatedAt,
+	}, nil, nil
+}
+
+// parseDate returns the date in Unix milliseconds, parsed by the requested
+// date format in the time zone of the user, or like a date filter when no
+// format is requested.
+func (state *spendingImport) parseDate(value string) (int64, error) {
+	if state.request.DateFormat == "" {
+		return helper.ParseTime(value, state.location, false)
+	}
+	date, err := time.ParseInLocation(dateFormatReplacer.Replace(state.request.DateFormat), value, state.location)
+	if err != nil {
+		return 0, err
+	}
+	return date.UnixMilli(), nil
+}
+
+// normalizeAmount returns the amount with a dot as the decimal separator and
+// without the thousands separators, so `1.234,56` becomes `1234.56` when the
+// decimal separator is a comma.
+func (state *spendingImport) normalizeAmount(amount string) string {
+	amount = strings.ReplaceAll(amount, " ", "")
+	if state.request.DecimalSeparator == "," {
+		return strings.ReplaceAll(strings.ReplaceAll(amount, ".", ""), ",", ".")
+	}
+	return strings.ReplaceAll(amount, ",", "")
+}
+
+// importedKeys returns the duplicate detection keys of the spendings of the
+// user dated within the dates of the imported spendings.
+func (service *SpendingServiceImpl) importedKeys(ctx context.Context, userId string, spendings []domain.Spending) (map[string]bool, error) {
+	keys := map[string]bool{}
+	if len(spendings) == 0 {
+		return keys, nil
+	}
+
+	from, to := spendings[0].Date, spendings[0].Date
+	for _, spending := range spendings {
+		from = min(from, spending.Date)
+		to = max(to, spending.Date)
+	}
+	query := domain.SpendingQuery{UserId: userId, From: &from, To: &to}
+	err := eachSpending(ctx, service.SpendingRepository, query, func(spending domain.Spending) error {
+		keys[importKey(spending)] = true
+		return nil
+	})
+	return keys, err
+}
+
+// importKey returns the key by which an imported spending is a duplicate of
+// another spending: the same date, amount and title.
+func importKey(spending domain.Spending) string {
+	return strings.Join([]string{
+		strconv.FormatInt(spending.Date, 10),
+		strconv.FormatInt(spending.Amount.Minor, 10),
+		spending.Amount.Currency,
+		strings.ToLower(strings.TrimSpace(spending.Title)),
+	}, "|")
+}
+
+// columnName returns the requested name of a column of the CSV file, or the
+// default name when none is requested.
+func columnName(name string, defaultName string) string {
+	if name == "" {
+		return defaultName
+	}
+	return strings.ToLower(strings.TrimSpace(name))
+}
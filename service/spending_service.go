@@ -3,12 +3,17 @@ package service
 import (
 	"context"
 	"github.com/refandas/duit-api/model/web"
+	"io"
 )
 
 type SpendingService interface {
-	Create(ctx context.Context, request web.SpendingCreateRequest) web.SpendingResponse
-	Update(ctx context.Context, request web.SpendingUpdateRequest) web.SpendingResponse
-	Delete(ctx context.Context, spendingId string)
-	FindById(ctx context.Context, spendingId string) web.SpendingResponse
-	FindByUserId(ctx context.Context, userId string) []web.SpendingResponse
+	Create(ctx context.Context, request web.SpendingCreateRequest) (web.SpendingResponse, error)
+	Update(ctx context.Context, request web.SpendingUpdateRequest) (web.SpendingResponse, error)
+	Delete(ctx context.Context, spendingId string) error
+	FindById(ctx context.Context, spendingId string) (web.SpendingResponse, error)
+	FindByUserId(ctx context.Context, request web.SpendingListRequest) ([]web.SpendingResponse, web.Pagination, error)
+	Export(ctx context.Context, request web.SpendingExportRequest) (*SpendingExport, error)
+	Import(ctx context.Context, request web.SpendingImportRequest, reader io.Reader) (web.SpendingImportResponse, error)
+	FindTags(ctx context.Context, userId string) ([]web.TagResponse, error)
+	UpdateTags(ctx context.Context, request web.SpendingTagsRequest) (web.SpendingTagsResponse, error)
 }
@@ -2,31 +2,68 @@ package service
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"github.com/go-playground/validator/v10"
+	"github.com/refandas/duit-api/exception"
 	"github.com/refandas/duit-api/helper"
 	"github.com/refandas/duit-api/model/domain"
 	"github.com/refandas/duit-api/model/web"
 	"github.com/refandas/duit-api/repository"
+	"strconv"
+	"time"
 )
 
 type SpendingServiceImpl struct {
-	SpendingRepository repository.SpendingRepository
-	DB                 *helper.DynamoDB
-	Validator          *validator.Validate
+	SpendingRepository     repository.SpendingRepository
+	CategoryRepository     repository.CategoryRepository
+	UserRepository         repository.UserRepository
+	ExchangeRateRepository repository.ExchangeRateRepository
+	AccountRepository      repository.AccountRepository
+	AttachmentRepository   repository.AttachmentRepository
+	BlobStore              repository.BlobStore
+	Validator              *validator.Validate
+	Policy                 OwnershipPolicy
+	CursorSecret           []byte
 }
 
-func NewSpendingService(spendingRepository repository.SpendingRepository, DB *helper.DynamoDB, validator *validator.Validate) SpendingService {
+func NewSpendingService(spendingRepository repository.SpendingRepository, categoryRepository repository.CategoryRepository, userRepository repository.UserRepository, exchangeRateRepository repository.ExchangeRateRepository, accountRepository repository.AccountRepository, attachmentRepository repository.AttachmentRepository, blobStore repository.BlobStore, validator *validator.Validate, policy OwnershipPolicy, cursorSecret []byte) SpendingService {
 	return &SpendingServiceImpl{
-		SpendingRepository: spendingRepository,
-		DB:                 DB,
-		Validator:          validator,
+		SpendingRepository:     spendingRepository,
+		CategoryRepository:     categoryRepository,
+		UserRepository:         userRepository,
+		ExchangeRateRepository: exchangeRateRepository,
+		AccountRepository:      accountRepository,
+		AttachmentRepository:   attachmentRepository,
+		BlobStore:              blobStore,
+		Validator:              validator,
+		Policy:                 policy,
+		CursorSecret:           cursorSecret,
 	}
 }
 
-func (service *SpendingServiceImpl) Create(ctx context.Context, request web.SpendingCreateRequest) web.SpendingResponse {
+// spendingCursor is the content of the pagination cursor of a user's
+// spending history. It carries the user id so a cursor can not be used to
+// list the history of another user.
+type spendingCursor struct {
+	UserId string             `json:"user_id"`
+	After  domain.SpendingKey `json:"after"`
+}
+
+func (service *SpendingServiceImpl) Create(ctx context.Context, request web.SpendingCreateRequest) (web.SpendingResponse, error) {
 	err := service.Validator.Struct(request)
 	if err != nil {
-		panic(err)
+		return web.SpendingResponse{}, exception.NewValidationErrors(err)
+	}
+	if err := service.Policy.checkOwnership(ctx, request.UserId, "user not found"); err != nil {
+		return web.SpendingResponse{}, err
+	}
+	if err := service.checkCategory(ctx, request.UserId, request.Category); err != nil {
+		return web.SpendingResponse{}, err
+	}
+	amount, err := service.parseAccountAmount(ctx, request.UserId, request.AccountId, request.Amount, request.Currency)
+	if err != nil {
+		return web.SpendingResponse{}, err
 	}
 
 	spending := domain.Spending{
@@ -35,53 +72,313 @@ func (service *SpendingServiceImpl) Create(ctx context.Context, request web.Spen
 		Title:       request.Title,
 		Description: request.Description,
 		Category:    request.Category,
+		Type:        request.Type,
+		AccountId:   request.AccountId,
+		Tags:        domain.NormalizeTags(request.Tags),
 		Date:        request.Date,
-		Amount:      request.Amount,
+		Amount:      amount,
 		CreatedAt:   request.CreatedAt,
 	}
+	if spending.Type == "" {
+		spending.Type = domain.SpendingTypeExpense
+	}
 
-	spendingResponse := service.SpendingRepository.Save(ctx, service.DB, spending)
-	return helper.ToSpendingResponse(spendingResponse)
+	spendingResponse, err := service.SpendingRepository.Save(ctx, spending)
+	if err != nil {
+		return web.SpendingResponse{}, err
+	}
+	return helper.ToSpendingResponse(spendingResponse), nil
 }
 
-func (service *SpendingServiceImpl) Update(ctx context.Context, request web.SpendingUpdateRequest) web.SpendingResponse {
+func (service *SpendingServiceImpl) Update(ctx context.Context, request web.SpendingUpdateRequest) (web.SpendingResponse, error) {
 	err := service.Validator.Struct(request)
 	if err != nil {
-		panic(err)
+		return web.SpendingResponse{}, exception.NewValidationErrors(err)
 	}
 
-	spending, err := service.SpendingRepository.FindById(ctx, service.DB, request.Id)
+	spending, err := service.SpendingRepository.FindById(ctx, request.Id)
+	if err != nil {
+		return web.SpendingResponse{}, err
+	}
+	if err := service.Policy.checkOwnership(ctx, spending.UserId, "item not found"); err != nil {
+		return web.SpendingResponse{}, err
+	}
+	if request.Category != spending.Category {
+		if err := service.checkCategory(ctx, spending.UserId, request.Category); err != nil {
+			return web.SpendingResponse{}, err
+		}
+	}
+	// A spending keeps its account unless another account, or none, is
+	// requested.
+	if request.AccountId != nil {
+		spending.AccountId = *request.AccountId
+	}
+	amount, err := service.parseAccountAmount(ctx, spending.UserId, spending.AccountId, request.Amount, request.Currency)
 	if err != nil {
-		panic(err)
+		return web.SpendingResponse{}, err
 	}
 
 	spending.Title = request.Title
 	spending.Date = request.Date
 	spending.Description = request.Description
-	spending.Amount = request.Amount
+	spending.Amount = amount
 	spending.Category = request.Category
+	// A spending keeps its type unless another type is requested.
+	if request.Type != "" {
+		spending.Type = request.Type
+	}
+	// A spending keeps its tags unless other tags, or none, are requested.
+	if request.Tags != nil {
+		spending.Tags = domain.NormalizeTags(request.Tags)
+	}
 
-	response := service.SpendingRepository.Update(ctx, service.DB, spending)
-	return helper.ToSpendingResponse(response)
+	response, err := service.SpendingRepository.Update(ctx, spending)
+	if err != nil {
+		return web.SpendingResponse{}, err
+	}
+	return helper.ToSpendingResponse(response), nil
 }
 
-func (service *SpendingServiceImpl) Delete(ctx context.Context, spendingId string) {
-	spending, err := service.SpendingRepository.FindById(ctx, service.DB, spendingId)
+func (service *SpendingServiceImpl) Delete(ctx context.Context, spendingId string) error {
+	spending, err := service.SpendingRepository.FindById(ctx, spendingId)
 	if err != nil {
-		panic(err)
+		return err
 	}
-	service.SpendingRepository.Delete(ctx, service.DB, spending)
+	if err := service.Policy.checkOwnership(ctx, spending.UserId, "item not found"); err != nil {
+		return err
+	}
+
+	// The files attached to the spending are removed with it.
+	attachments, err := service.AttachmentRepository.FindBySpendingId(ctx, spending.Id)
+	if err != nil {
+		return err
+	}
+	for _, attachment := range attachments {
+		if err := deleteAttachment(ctx, service.AttachmentRepository, service.BlobStore, attachment); err != nil {
+			return err
+		}
+	}
+	return service.SpendingRepository.Delete(ctx, spending)
 }
 
-func (service *SpendingServiceImpl) FindById(ctx context.Context, spendingId string) web.SpendingResponse {
-	spending, err := service.SpendingRepository.FindById(ctx, service.DB, spendingId)
+func (service *SpendingServiceImpl) FindById(ctx context.Context, spendingId string) (web.SpendingResponse, error) {
+	spending, err := service.SpendingRepository.FindById(ctx, spendingId)
 	if err != nil {
-		panic(err)
+		return web.SpendingResponse{}, err
+	}
+	if err := service.Policy.checkOwnership(ctx, spending.UserId, "item not found"); err != nil {
+		return web.SpendingResponse{}, err
 	}
-	return helper.ToSpendingResponse(spending)
+
+	converter, err := service.converter(ctx, spending.UserId)
+	if err != nil {
+		return web.SpendingResponse{}, err
+	}
+	responses, err := converter.spendingResponses(ctx, []domain.Spending{spending})
+	if err != nil {
+		return web.SpendingResponse{}, err
+	}
+	return responses[0], nil
 }
 
-func (service *SpendingServiceImpl) FindByUserId(ctx context.Context, userId string) []web.SpendingResponse {
-	spendings := service.SpendingRepository.FindByUserId(ctx, service.DB, userId)
-	return helper.ToSpendingResponses(spendings)
+func (service *SpendingServiceImpl) FindByUserId(ctx context.Context, request web.SpendingListRequest) ([]web.SpendingResponse, web.Pagination, error) {
+	err := service.Validator.Struct(request)
+	if err != nil {
+		return nil, web.Pagination{}, exception.NewValidationErrors(err)
+	}
+	if err := service.Policy.checkOwnership(ctx, request.UserId, "user not found"); err != nil {
+		return nil, web.Pagination{}, err
+	}
+
+	query, found, err := spendingQuery(request)
+	if err != nil {
+		return nil, web.Pagination{}, err
+	}
+	if !found {
+		return helper.ToSpendingResponses(nil), web.Pagination{Limit: request.Limit}, nil
+	}
+	if request.Cursor != "" {
+		cursor := spendingCursor{}
+		err := helper.DecodeCursor(service.CursorSecret, request.Cursor, &cursor)
+		if err != nil || cursor.UserId != request.UserId {
+			return nil, web.Pagination{}, exception.NewValidationError("invalid cursor")
+		}
+		query.After = &cursor.After
+	}
+
+	page, err := service.SpendingRepository.FindByUserId(ctx, query)
+	if err != nil {
+		return nil, web.Pagination{}, err
+	}
+	if query.After == nil && !query.Filtered() && len(page.Spendings) == 0 {
+		// A user without any spending history is reported as not found.
+		return nil, web.Pagination{}, exception.NewNotFoundError("user not found")
+	}
+
+	pagination := web.Pagination{
+		Limit:   request.Limit,
+		HasMore: page.Next != nil,
+	}
+	if page.Next != nil {
+		pagination.NextCursor = helper.EncodeCursor(service.CursorSecret, spendingCursor{
+			UserId: request.UserId,
+			After:  *page.Next,
+		})
+	}
+
+	converter, err := service.converter(ctx, request.UserId)
+	if err != nil {
+		return nil, web.Pagination{}, err
+	}
+	responses, err := converter.spendingResponses(ctx, page.Spendings)
+	if err != nil {
+		return nil, web.Pagination{}, err
+	}
+	return responses, pagination, nil
+}
+
+// spendingQuery returns the query of the spendings matching the listing
+// criteria of the request, and whether any spending can match them.
+func spendingQuery(request web.SpendingListRequest) (domain.SpendingQuery, bool, error) {
+	if request.From != nil && request.To != nil && *request.From > *request.To {
+		return domain.SpendingQuery{}, false, exception.NewValidationError("from must not be after to")
+	}
+	if request.MinAmount != nil && request.MaxAmount != nil && *request.MinAmount > *request.MaxAmount {
+		return domain.SpendingQuery{}, false, exception.NewValidationError("min_amount must not be greater than max_amount")
+	}
+
+	// The amounts of spendings in different currencies are not comparable,
+	// so the amount filters only match the spendings in their currency.
+	currency := request.Currency
+	if currency == "" && (request.MinAmount != nil || request.MaxAmount != nil) {
+		currency = domain.DefaultCurrency
+	}
+
+	query := domain.SpendingQuery{
+		UserId:     request.UserId,
+		Limit:      request.Limit,
+		From:       request.From,
+		To:         request.To,
+		Category:   request.Category,
+		Type:       request.Type,
+		AccountId:  request.AccountId,
+		Currency:   currency,
+		MinAmount:  minorAmount(request.MinAmount, currency),
+		MaxAmount:  minorAmount(request.MaxAmount, currency),
+		Search:     request.Query,
+		Descending: request.Order == "desc",
+	}
+
+	if request.TagMatch == "all" {
+		query.AllTags = domain.NormalizeTags(request.Tags)
+	} else {
+		query.AnyTags = domain.NormalizeTags(request.Tags)
+	}
+
+	// Planned spendings are dated in the future, so they are only listed
+	// when requested.
+	now := time.Now().UnixMilli()
+	switch {
+	case request.Status == domain.SpendingStatusPlanned:
+		afterNow := now + 1
+		if query.From == nil || *query.From < afterNow {
+			query.From = &afterNow
+		}
+	case request.Status == domain.SpendingStatusPosted || !request.IncludeFuture:
+		if query.To == nil || *query.To > now {
+			query.To = &now
+		}
+	}
+	if query.From != nil && query.To != nil && *query.From > *query.To {
+		// No spending of the requested status is in the requested range.
+		return query, false, nil
+	}
+	return query, true, nil
+}
+
+// converter returns the converter of amounts into the home currency of the
+// user.
+func (service *SpendingServiceImpl) converter(ctx context.Context, userId string) (*currencyConverter, error) {
+	user, err := service.UserRepository.FindById(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+	return newCurrencyConverter(service.ExchangeRateRepository, user.Currency()), nil
+}
+
+// checkCategory returns a validation error when the user has no category
+// with the given name. A spending may have no category.
+func (service *SpendingServiceImpl) checkCategory(ctx context.Context, userId string, category string) error {
+	return checkCategory(ctx, service.CategoryRepository, userId, category)
+}
+
+// checkCategory returns a validation error when the user has no category
+// with the given name in the repository. An empty name is no category.
+func checkCategory(ctx context.Context, categoryRepository repository.CategoryRepository, userId string, category string) error {
+	if category == "" {
+		return nil
+	}
+	_, found, err := categoryRepository.FindByName(ctx, userId, category)
+	if err != nil {
+		return err
+	}
+	if !found {
+		return exception.NewValidationError("category not found")
+	}
+	return nil
+}
+
+// parseAccountAmount returns the money of the requested amount of a
+// spending of the account with the given id, in the currency of the account
+// when no currency is requested. The account must be an account of the user
+// in the same currency. A spending may have no account.
+func (service *SpendingServiceImpl) parseAccountAmount(ctx context.Context, userId string, accountId string, amount json.Number, currency string) (domain.Money, error) {
+	if accountId == "" {
+		return parseAmount(amount, currency)
+	}
+	account, err := service.AccountRepository.FindById(ctx, accountId)
+	if errors.Is(err, exception.ErrNotFound) || (err == nil && account.UserId != userId) {
+		return domain.Money{}, exception.NewValidationError("account not found")
+	}
+	if err != nil {
+		return domain.Money{}, err
+	}
+
+	if currency == "" {
+		currency = account.Currency()
+	}
+	money, err := parseAmount(amount, currency)
+	if err != nil {
+		return domain.Money{}, err
+	}
+	if money.Currency != account.Currency() {
+		return domain.Money{}, exception.NewValidationError("currency must be the currency of the account {0}", account.Currency())
+	}
+	return money, nil
+}
+
+// parseAmount returns the money of the requested amount in the requested
+// currency, or in the default currency when none is requested. The amount
+// must be positive and must not be more precise than the minor unit of the
+// currency.
+func parseAmount(amount json.Number, currency string) (domain.Money, error) {
+	if currency == "" {
+		currency = domain.DefaultCurrency
+	}
+	money, err := domain.ParseMoney(amount.String(), currency)
+	if err != nil || money.Minor == 0 {
+		return domain.Money{}, exception.NewValidationError("{0} must be a positive amount with at most {1} decimal places",
+			"amount", strconv.Itoa(domain.CurrencyExponent(currency)))
+	}
+	return money, nil
+}
+
+// minorAmount returns the minor units of an amount filter, which is given in
+// the major unit of the currency.
+func minorAmount(amount *float64, currency string) *int64 {
+	if amount == nil {
+		return nil
+	}
+	minor := domain.MoneyFromFloat(*amount, currency).Minor
+	return &minor
 }
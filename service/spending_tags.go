@@ -0,0 +1,95 @@
+package service
+
+import (
+	"context"
+	"github.com/refandas/duit-api/exception"
+	"github.com/refandas/duit-api/model/domain"
+	"github.com/refandas/duit-api/model/web"
+	"slices"
+	"sort"
+	"strconv"
+)
+
+// maxSpendingTags is the maximum number of tags of a spending.
+const maxSpendingTags = 20
+
+// FindTags returns the tags of the spendings of the user with the number of
+// spendings having each of them, the most used tags first.
+func (service *SpendingServiceImpl) FindTags(ctx context.Context, userId string) ([]web.TagResponse, error) {
+	if err := service.Policy.checkOwnership(ctx, userId, "user not found"); err != nil {
+		return nil, err
+	}
+
+	counts := map[string]int{}
+	err := eachSpending(ctx, service.SpendingRepository, domain.SpendingQuery{UserId: userId}, func(spending domain.Spending) error {
+		for _, tag := range spending.Tags {
+			counts[tag]++
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	tagResponses := make([]web.TagResponse, 0, len(counts))
+	for tag, count := range counts {
+		tagResponses = append(tagResponses, web.TagResponse{Tag: tag, Count: count})
+	}
+	sort.Slice(tagResponses, func(i, j int) bool {
+		if tagResponses[i].Count != tagResponses[j].Count {
+			return tagResponses[i].Count > tagResponses[j].Count
+		}
+		return tagResponses[i].Tag < tagResponses[j].Tag
+	})
+	return tagResponses, nil
+}
+
+// UpdateTags adds the tags to and removes the tags from every spending of
+// the request. Every spending is checked before any of them is changed, so
+// a spending which is not found changes none of them. A tag both added and
+// removed is removed.
+func (service *SpendingServiceImpl) UpdateTags(ctx context.Context, request web.SpendingTagsRequest) (web.SpendingTagsResponse, error) {
+	err := service.Validator.Struct(request)
+	if err != nil {
+		return web.SpendingTagsResponse{}, exception.NewValidationErrors(err)
+	}
+	if err := service.Policy.checkOwnership(ctx, request.UserId, "user not found"); err != nil {
+		return web.SpendingTagsResponse{}, err
+	}
+	add := domain.NormalizeTags(request.Add)
+	remove := domain.NormalizeTags(request.Remove)
+	if len(add) == 0 && len(remove) == 0 {
+		return web.SpendingTagsResponse{}, exception.NewValidationError("add or remove must have a tag")
+	}
+
+	spendingIds := slices.Clone(request.SpendingIds)
+	slices.Sort(spendingIds)
+	var spendings []domain.Spending
+	for _, spendingId := range slices.Compact(spendingIds) {
+		spending, err := service.SpendingRepository.FindById(ctx, spendingId)
+		if err != nil {
+			return web.SpendingTagsResponse{}, err
+		}
+		if spending.UserId != request.UserId {
+			return web.SpendingTagsResponse{}, exception.NewNotFoundError("item not found")
+		}
+
+		tags := domain.NormalizeTags(append(slices.Clone(spending.Tags), add...))
+		tags = slices.DeleteFunc(tags, func(tag string) bool { return slices.Contains(remove, tag) })
+		if len(tags) > maxSpendingTags {
+			return web.SpendingTagsResponse{}, exception.NewValidationError("a spending must not have more than {0} tags",
+				strconv.Itoa(maxSpendingTags))
+		}
+		if !slices.Equal(tags, spending.Tags) {
+			spending.Tags = tags
+			spendings = append(spendings, spending)
+		}
+	}
+
+	for _, spending := range spendings {
+		if _, err := service.SpendingRepository.Update(ctx, spending); err != nil {
+			return web.SpendingTagsResponse{}, err
+		}
+	}
+	return web.SpendingTagsResponse{Updated: len(spendings)}, nil
+}
@@ -6,8 +6,8 @@ import (
 )
 
 type UserService interface {
-	Create(ctx context.Context, request web.UserCreateRequest) web.UserResponse
-	Update(ctx context.Context, request web.UserUpdateRequest) web.UserResponse
-	Delete(ctx context.Context, userId string)
-	FindById(ctx context.Context, userId string) web.UserResponse
+	Create(ctx context.Context, request web.UserCreateRequest) (web.UserResponse, error)
+	Update(ctx context.Context, request web.UserUpdateRequest) (web.UserResponse, error)
+	Delete(ctx context.Context, userId string) error
+	FindById(ctx context.Context, userId string) (web.UserResponse, error)
 }
@@ -3,53 +3,98 @@ package service
 import (
 	"context"
 	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/refandas/duit-api/exception"
 	"github.com/refandas/duit-api/helper"
 	"github.com/refandas/duit-api/model/domain"
 	"github.com/refandas/duit-api/model/web"
 	"github.com/refandas/duit-api/repository"
+	"strings"
 )
 
 type UserServiceImpl struct {
-	UserRepository repository.UserRepository
-	DB             *helper.DynamoDB
-	Validate       *validator.Validate
+	UserRepository     repository.UserRepository
+	CategoryRepository repository.CategoryRepository
+	Validate           *validator.Validate
+	Policy             OwnershipPolicy
 }
 
-func NewUserService(userRepository repository.UserRepository, DB *helper.DynamoDB, validate *validator.Validate) UserService {
+func NewUserService(userRepository repository.UserRepository, categoryRepository repository.CategoryRepository, validate *validator.Validate, policy OwnershipPolicy) UserService {
 	return &UserServiceImpl{
-		UserRepository: userRepository,
-		DB:             DB,
-		Validate:       validate,
+		UserRepository:     userRepository,
+		CategoryRepository: categoryRepository,
+		Validate:           validate,
+		Policy:             policy,
 	}
 }
 
-func (service *UserServiceImpl) Create(ctx context.Context, request web.UserCreateRequest) web.UserResponse {
+func (service *UserServiceImpl) Create(ctx context.Context, request web.UserCreateRequest) (web.UserResponse, error) {
 	err := service.Validate.Struct(request)
 	if err != nil {
-		panic(err)
+		return web.UserResponse{}, exception.NewValidationErrors(err)
+	}
+
+	// The repository keeps the emails unique, so a registered email is
+	// reported as a conflict by Save rather than looked up beforehand.
+	email := strings.ToLower(request.Email)
+
+	timeZone := request.TimeZone
+	if timeZone == "" {
+		timeZone = "UTC"
+	}
+	language := request.Language
+	if language == "" {
+		language = "en"
+	}
+	homeCurrency := request.HomeCurrency
+	if homeCurrency == "" {
+		homeCurrency = domain.DefaultCurrency
 	}
 
 	user := domain.User{
-		Id:        request.Id,
-		Name:      request.Name,
-		Email:     request.Email,
-		Password:  request.Password,
-		CreatedAt: request.CreatedAt,
+		Id:           request.Id,
+		Name:         request.Name,
+		Email:        email,
+		Password:     request.Password,
+		TimeZone:     timeZone,
+		Language:     language,
+		HomeCurrency: homeCurrency,
+		CreatedAt:    request.CreatedAt,
 	}
 
-	userResponse := service.UserRepository.Save(ctx, service.DB, user)
-	return helper.ToUserResponse(userResponse)
+	userResponse, err := service.UserRepository.Save(ctx, user)
+	if err != nil {
+		return web.UserResponse{}, err
+	}
+
+	// Every user starts with the default set of categories.
+	for _, category := range domain.DefaultCategories() {
+		categoryId, err := uuid.NewRandom()
+		if err != nil {
+			return web.UserResponse{}, err
+		}
+		category.Id = categoryId.String()
+		category.UserId = user.Id
+		category.CreatedAt = user.CreatedAt
+		if _, err := service.CategoryRepository.Save(ctx, category); err != nil {
+			return web.UserResponse{}, err
+		}
+	}
+	return helper.ToUserResponse(userResponse), nil
 }
 
-func (service *UserServiceImpl) Update(ctx context.Context, request web.UserUpdateRequest) web.UserResponse {
+func (service *UserServiceImpl) Update(ctx context.Context, request web.UserUpdateRequest) (web.UserResponse, error) {
 	err := service.Validate.Struct(request)
 	if err != nil {
-		panic(err)
+		return web.UserResponse{}, exception.NewValidationErrors(err)
 	}
 
-	user, err := service.UserRepository.FindById(ctx, service.DB, request.Id)
+	if err := service.Policy.checkOwnership(ctx, request.Id, "item not found"); err != nil {
+		return web.UserResponse{}, err
+	}
+	user, err := service.UserRepository.FindById(ctx, request.Id)
 	if err != nil {
-		panic(err)
+		return web.UserResponse{}, err
 	}
 
 	// update field
@@ -57,28 +102,46 @@ func (service *UserServiceImpl) Update(ctx context.Context, request web.UserUpda
 		user.Name = request.Name
 	}
 	if request.Email != "" {
-		user.Email = request.Email
+		user.Email = strings.ToLower(request.Email)
 	}
 	if request.Password != "" {
 		user.Password = request.Password
 	}
+	if request.TimeZone != "" {
+		user.TimeZone = request.TimeZone
+	}
+	if request.Language != "" {
+		user.Language = request.Language
+	}
+	if request.HomeCurrency != "" {
+		user.HomeCurrency = request.HomeCurrency
+	}
 
-	response := service.UserRepository.Update(ctx, service.DB, user)
-	return helper.ToUserResponse(response)
+	response, err := service.UserRepository.Update(ctx, user)
+	if err != nil {
+		return web.UserResponse{}, err
+	}
+	return helper.ToUserResponse(response), nil
 }
 
-func (service *UserServiceImpl) Delete(ctx context.Context, userId string) {
-	user, err := service.UserRepository.FindById(ctx, service.DB, userId)
+func (service *UserServiceImpl) Delete(ctx context.Context, userId string) error {
+	if err := service.Policy.checkOwnership(ctx, userId, "item not found"); err != nil {
+		return err
+	}
+	user, err := service.UserRepository.FindById(ctx, userId)
 	if err != nil {
-		panic(err)
+		return err
 	}
-	service.UserRepository.Delete(ctx, service.DB, user)
+	return service.UserRepository.Delete(ctx, user)
 }
 
-func (service *UserServiceImpl) FindById(ctx context.Context, userId string) web.UserResponse {
-	user, err := service.UserRepository.FindById(ctx, service.DB, userId)
+func (service *UserServiceImpl) FindById(ctx context.Context, userId string) (web.UserResponse, error) {
+	if err := service.Policy.checkOwnership(ctx, userId, "item not found"); err != nil {
+		return web.UserResponse{}, err
+	}
+	user, err := service.UserRepository.FindById(ctx, userId)
 	if err != nil {
-		panic(err)
+		return web.UserResponse{}, err
 	}
-	return helper.ToUserResponse(user)
+	return helper.ToUserResponse(user), nil
 }
@@ -0,0 +1,229 @@
+package test
+
+import (
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// sendAccountRequest sends a request with the given JSON body to the path of
+// the API as the user then return the status code and the data of the
+// response.
+func sendAccountRequest(router http.Handler, method string, userId string, path string, jsonData string) (int, map[string]interface{}) {
+	request := httptest.NewRequest(method, "http://localhost:8000/api/v1"+path, strings.NewReader(jsonData))
+	authorize(request, userId)
+	request.Header.Add("Content-Type", "application/json")
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	var responseBody map[string]interface{}
+	err := json.Unmarshal(recorder.Body.Bytes(), &responseBody)
+	if err != nil {
+		panic(err)
+	}
+	// A successful response carries its status code in the body, while a
+	// problem is responded with its status code.
+	if code, ok := responseBody["code"].(float64); ok {
+		return int(code), responseBody
+	}
+	return recorder.Code, responseBody
+}
+
+// createAccount creates an account for the user through the API then return
+// the id of the account.
+func createAccount(router http.Handler, userId string, jsonData string) string {
+	code, responseBody := sendAccountRequest(router, http.MethodPost, userId, "/users/"+userId+"/accounts", jsonData)
+	if code != http.StatusCreated {
+		panic(responseBody)
+	}
+	return responseBody["data"].(map[string]interface{})["id"].(string)
+}
+
+func TestCreateAccountSuccess(t *testing.T) {
+	router := setupRouter()
+
+	user := createUser()
+	defer clearUserDataAfterTest(user.Id)
+
+	code, responseBody := sendAccountRequest(router, http.MethodPost, user.Id, "/users/"+user.Id+"/accounts",
+		`{"name": "Kartu kredit", "type": "bank", "currency": "USD", "opening_balance": "-120.50"}`)
+	data := responseBody["data"].(map[string]interface{})
+	defer clearAccountDataAfterTest(data["id"].(string))
+
+	assert.Equal(t, http.StatusCreated, code)
+	assert.Equal(t, user.Id, data["user_id"])
+	assert.Equal(t, "Kartu kredit", data["name"])
+	assert.Equal(t, "bank", data["type"])
+	assert.Equal(t, "USD", data["currency"])
+	assert.Equal(t, -120.5, data["opening_balance"])
+
+	// The currency of an account is kept on update.
+	code, responseBody = sendAccountRequest(router, http.MethodPut, user.Id, "/users/"+user.Id+"/accounts/"+data["id"].(string),
+		`{"name": "Kartu kredit", "type": "bank", "currency": "IDR", "opening_balance": 0}`)
+	assert.Equal(t, http.StatusOK, code)
+	data = responseBody["data"].(map[string]interface{})
+	assert.Equal(t, "USD", data["currency"])
+	assert.Equal(t, 0.0, data["opening_balance"])
+
+	code, responseBody = sendAccountRequest(router, http.MethodGet, user.Id, "/users/"+user.Id+"/accounts", "")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, 1, len(responseBody["data"].([]interface{})))
+}
+
+func TestCreateAccountFailed(t *testing.T) {
+	router := setupRouter()
+
+	user := createUser()
+	defer clearUserDataAfterTest(user.Id)
+
+	tests := []struct {
+		body   string
+		detail string
+	}{
+		{`{"name": "Dompet", "type": "wallet"}`, "the request has invalid fields"},
+		{`{"name": "Dompet", "type": "cash", "currency": "XYZ"}`, "the request has invalid fields"},
+		{`{"name": "Dompet", "type": "cash", "opening_balance": "10.555"}`, "opening_balance must be an amount with at most 2 decimal places"},
+	}
+	for _, test := range tests {
+		code, responseBody := sendAccountRequest(router, http.MethodPost, user.Id, "/users/"+user.Id+"/accounts", test.body)
+		assert.Equal(t, http.StatusBadRequest, code)
+		assert.Contains(t, responseBody["detail"], test.detail)
+	}
+}
+
+func TestGetAccountOfAnotherUserFailed(t *testing.T) {
+	router := setupRouter()
+
+	users := createUsers()
+	for _, user := range users {
+		defer clearUserDataAfterTest(user.Id)
+	}
+
+	accountId := createAccount(router, users[0].Id, `{"name": "Dompet", "type": "cash"}`)
+	defer clearAccountDataAfterTest(accountId)
+
+	code, _ := sendAccountRequest(router, http.MethodGet, users[1].Id, "/users/"+users[1].Id+"/accounts/"+accountId, "")
+	assert.Equal(t, http.StatusNotFound, code)
+
+	// An account of another user can neither be used by spendings nor by
+	// transfers.
+	code, responseBody := sendAccountRequest(router, http.MethodPost, users[1].Id, "/spendings",
+		`{"title": "Kopi", "amount": 20000, "date": 1701795600000, "category": "food", "account_id": "`+accountId+`"}`)
+	assert.Equal(t, http.StatusBadRequest, code)
+	assert.Equal(t, "account not found", responseBody["detail"])
+
+	ownAccountId := createAccount(router, users[1].Id, `{"name": "Dompet", "type": "cash"}`)
+	defer clearAccountDataAfterTest(ownAccountId)
+	code, _ = sendAccountRequest(router, http.MethodPost, users[1].Id, "/users/"+users[1].Id+"/transfers",
+		`{"from_account_id": "`+accountId+`", "to_account_id": "`+ownAccountId+`", "amount": 10000, "date": 1701795600000}`)
+	assert.Equal(t, http.StatusNotFound, code)
+}
+
+func TestAccountBalanceSuccess(t *testing.T) {
+	router := setupRouter()
+
+	user := createUser()
+	defer clearUserDataAfterTest(user.Id)
+
+	bankId := createAccount(router, user.Id, `{"name": "Tabungan", "type": "bank", "opening_balance": 1000000}`)
+	defer clearAccountDataAfterTest(bankId)
+	walletId := createAccount(router, user.Id, `{"name": "Dompet", "type": "cash"}`)
+	defer clearAccountDataAfterTest(walletId)
+
+	spendings := []string{
+		`{"title": "Gaji", "amount": 5000000, "date": 1701388800000, "category": "other", "type": "income", "account_id": "` + bankId + `"}`,
+		`{"title": "Makan malam", "amount": 50000, "date": 1701795600000, "category": "food", "account_id": "` + walletId + `"}`,
+		`{"title": "Listrik", "amount": 300000, "date": 1701882000000, "category": "bills", "account_id": "` + bankId + `"}`,
+		`{"title": "Kopi", "amount": 20000, "date": 1701795600000, "category": "food"}`,
+	}
+	for _, spending := range spendings {
+		code, responseBody := sendAccountRequest(router, http.MethodPost, user.Id, "/spendings", spending)
+		assert.Equal(t, http.StatusCreated, code)
+		defer clearSpendingDataAfterTest(responseBody["data"].(map[string]interface{})["id"].(string))
+	}
+
+	code, responseBody := sendAccountRequest(router, http.MethodPost, user.Id, "/users/"+user.Id+"/transfers",
+		`{"from_account_id": "`+bankId+`", "to_account_id": "`+walletId+`", "amount": 200000, "description": "Tarik tunai", "date": 1701709200000}`)
+	assert.Equal(t, http.StatusCreated, code)
+	transfer := responseBody["data"].(map[string]interface{})
+	defer clearTransferDataAfterTest(transfer["id"].(string))
+	assert.Equal(t, 200000.0, transfer["received_amount"])
+	assert.Equal(t, "IDR", transfer["received_currency"])
+
+	code, responseBody = sendAccountRequest(router, http.MethodGet, user.Id, "/users/"+user.Id+"/accounts/"+bankId+"/balance", "")
+	assert.Equal(t, http.StatusOK, code)
+	data := responseBody["data"].(map[string]interface{})
+	assert.Equal(t, 1000000.0, data["opening_balance"])
+	assert.Equal(t, 5500000.0, data["balance"])
+	balances := []float64{}
+	for _, entry := range data["entries"].([]interface{}) {
+		balances = append(balances, entry.(map[string]interface{})["balance"].(float64))
+	}
+	assert.Equal(t, []float64{6000000, 5800000, 5500000}, balances)
+	assert.Equal(t, "transfer", data["entries"].([]interface{})[1].(map[string]interface{})["type"])
+	assert.Equal(t, -200000.0, data["entries"].([]interface{})[1].(map[string]interface{})["amount"])
+
+	code, responseBody = sendAccountRequest(router, http.MethodGet, user.Id, "/users/"+user.Id+"/accounts/"+walletId+"/balance?from=2023-12-05T17:00:00Z", "")
+	assert.Equal(t, http.StatusOK, code)
+	data = responseBody["data"].(map[string]interface{})
+	assert.Equal(t, 200000.0, data["start_balance"])
+	assert.Equal(t, 150000.0, data["balance"])
+	assert.Equal(t, 1, len(data["entries"].([]interface{})))
+
+	// The spendings of an account are listed by the account filter.
+	code, responseBody = sendAccountRequest(router, http.MethodGet, user.Id, "/users/"+user.Id+"/spendings?account_id="+walletId, "")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, 1, len(responseBody["data"].([]interface{})))
+
+	// An account in use can not be deleted until its transfer is.
+	code, _ = sendAccountRequest(router, http.MethodDelete, user.Id, "/users/"+user.Id+"/accounts/"+bankId, "")
+	assert.Equal(t, http.StatusConflict, code)
+	code, _ = sendAccountRequest(router, http.MethodDelete, user.Id, "/users/"+user.Id+"/transfers/"+transfer["id"].(string), "")
+	assert.Equal(t, http.StatusNoContent, code)
+	code, _ = sendAccountRequest(router, http.MethodGet, user.Id, "/users/"+user.Id+"/transfers/"+transfer["id"].(string), "")
+	assert.Equal(t, http.StatusNotFound, code)
+}
+
+func TestTransferFailed(t *testing.T) {
+	router := setupRouter()
+
+	user := createUser()
+	defer clearUserDataAfterTest(user.Id)
+
+	idrId := createAccount(router, user.Id, `{"name": "Tabungan", "type": "bank"}`)
+	defer clearAccountDataAfterTest(idrId)
+	usdId := createAccount(router, user.Id, `{"name": "Dollar", "type": "bank", "currency": "USD"}`)
+	defer clearAccountDataAfterTest(usdId)
+
+	tests := []struct {
+		body   string
+		detail string
+	}{
+		{`{"from_account_id": "` + idrId + `", "to_account_id": "` + idrId + `", "amount": 10000, "date": 1701795600000}`, "the request has invalid fields"},
+		{`{"from_account_id": "` + idrId + `", "to_account_id": "` + usdId + `", "amount": 10000, "date": 1701795600000}`, "received_amount is required between accounts of different currencies"},
+		{`{"from_account_id": "` + idrId + `", "to_account_id": "` + usdId + `", "amount": 10000, "received_amount": "0.001", "date": 1701795600000}`, "amount must be a positive amount with at most 2 decimal places"},
+	}
+	for _, test := range tests {
+		code, responseBody := sendAccountRequest(router, http.MethodPost, user.Id, "/users/"+user.Id+"/transfers", test.body)
+		assert.Equal(t, http.StatusBadRequest, code)
+		assert.Contains(t, responseBody["detail"], test.detail)
+	}
+
+	// A spending of an account is in the currency of the account.
+	code, responseBody := sendAccountRequest(router, http.MethodPost, user.Id, "/spendings",
+		`{"title": "Buku", "amount": 10, "currency": "IDR", "date": 1701795600000, "category": "education", "account_id": "`+usdId+`"}`)
+	assert.Equal(t, http.StatusBadRequest, code)
+	assert.Equal(t, "currency must be the currency of the account USD", responseBody["detail"])
+
+	code, responseBody = sendAccountRequest(router, http.MethodPost, user.Id, "/users/"+user.Id+"/transfers",
+		`{"from_account_id": "`+idrId+`", "to_account_id": "`+usdId+`", "amount": 160000, "received_amount": 10, "date": 1701795600000}`)
+	assert.Equal(t, http.StatusCreated, code)
+	transfer := responseBody["data"].(map[string]interface{})
+	defer clearTransferDataAfterTest(transfer["id"].(string))
+	assert.Equal(t, 10.0, transfer["received_amount"])
+	assert.Equal(t, "USD", transfer["received_currency"])
+}
@@ -0,0 +1,194 @@
+package test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"github.com/refandas/duit-api/model/domain"
+	"github.com/stretchr/testify/assert"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// pngContent is the content of a PNG image, which is detected by its
+// signature.
+var pngContent = append([]byte("\x89PNG\r\n\x1a\n"), bytes.Repeat([]byte{0}, 100)...)
+
+// uploadAttachment uploads the content as a file named by the file name in
+// the given field of a multipart form as the user then return the status
+// code and the body of the response.
+func uploadAttachment(router http.Handler, userId string, spendingId string, field string, fileName string, content []byte) (int, map[string]interface{}) {
+	body := &bytes.Buffer{}
+	form := multipart.NewWriter(body)
+	if err := form.WriteField("note", "struk belanja"); err != nil {
+		panic(err)
+	}
+	part, err := form.CreateFormFile(field, fileName)
+	if err != nil {
+		panic(err)
+	}
+	if _, err := part.Write(content); err != nil {
+		panic(err)
+	}
+	if err := form.Close(); err != nil {
+		panic(err)
+	}
+
+	request := httptest.NewRequest(http.MethodPost, "http://localhost:8000/api/v1/spendings/"+spendingId+"/attachments", body)
+	authorize(request, userId)
+	request.Header.Add("Content-Type", form.FormDataContentType())
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	var responseBody map[string]interface{}
+	err = json.Unmarshal(recorder.Body.Bytes(), &responseBody)
+	if err != nil {
+		panic(err)
+	}
+	return recorder.Code, responseBody
+}
+
+// findAttachment returns the attachment of the given id from the repository.
+func findAttachment(id string) domain.Attachment {
+	attachment, err := testRepositories.Attachment.FindById(context.Background(), id)
+	if err != nil {
+		panic(err)
+	}
+	return attachment
+}
+
+func TestAttachmentSuccess(t *testing.T) {
+	router := setupRouter()
+
+	user := createUser()
+	defer clearUserDataAfterTest(user.Id)
+	spending := createSpending(user.Id)
+	defer clearSpendingDataAfterTest(spending.Id)
+
+	// The type claimed by the file name is ignored, and the directories of
+	// the file name are dropped.
+	code, responseBody := uploadAttachment(router, user.Id, spending.Id, "file", `C:\struk\kopi.pdf`, pngContent)
+	assert.Equal(t, http.StatusOK, code)
+	data := responseBody["data"].(map[string]interface{})
+	attachment := findAttachment(data["id"].(string))
+	defer clearAttachmentDataAfterTest(attachment)
+	assert.Equal(t, 201.0, responseBody["code"])
+	assert.Equal(t, spending.Id, data["spending_id"])
+	assert.Equal(t, "kopi.pdf", data["file_name"])
+	assert.Equal(t, "image/png", data["content_type"])
+	assert.Equal(t, float64(len(pngContent)), data["size"])
+
+	code, responseBody = sendSpendingRequest(router, http.MethodGet, user.Id, "/spendings/"+spending.Id+"/attachments", "")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, []interface{}{data}, responseBody["data"])
+
+	request := httptest.NewRequest(http.MethodGet, "http://localhost:8000/api/v1/spendings/"+spending.Id+"/attachments/"+attachment.Id, nil)
+	authorize(request, user.Id)
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, "image/png", recorder.Header().Get("Content-Type"))
+	assert.Equal(t, `attachment; filename=kopi.pdf`, recorder.Header().Get("Content-Disposition"))
+	assert.Equal(t, pngContent, recorder.Body.Bytes())
+
+	code, _ = sendSpendingRequest(router, http.MethodDelete, user.Id, "/spendings/"+spending.Id+"/attachments/"+attachment.Id, "")
+	assert.Equal(t, http.StatusOK, code)
+	code, _ = sendSpendingRequest(router, http.MethodGet, user.Id, "/spendings/"+spending.Id+"/attachments/"+attachment.Id, "")
+	assert.Equal(t, http.StatusNotFound, code)
+	_, err := testBlobStore.Get(context.Background(), attachment.BlobKey())
+	assert.Error(t, err)
+}
+
+func TestCreateAttachmentFailed(t *testing.T) {
+	router := setupRouter()
+
+	users := createUsers()
+	for _, user := range users {
+		defer clearUserDataAfterTest(user.Id)
+	}
+	spending := createSpending(users[0].Id)
+	defer clearSpendingDataAfterTest(spending.Id)
+
+	tests := []struct {
+		userId   string
+		field    string
+		fileName string
+		content  []byte
+		code     int
+		detail   string
+	}{
+		{users[0].Id, "file", "struk.png", []byte("bukan gambar"), http.StatusBadRequest, "file must be a JPEG, PNG, WebP or HEIC image or a PDF document"},
+		{users[0].Id, "file", "struk.png", []byte{}, http.StatusBadRequest, "file must not be empty"},
+		{users[0].Id, "file", "struk.pdf", append([]byte("%PDF-1.4\n"), bytes.Repeat([]byte{0}, testAttachmentMaxSize)...), http.StatusBadRequest, "file must not be larger than 65536 bytes"},
+		{users[0].Id, "receipt", "struk.png", pngContent, http.StatusBadRequest, "file is required"},
+		{users[1].Id, "file", "struk.png", pngContent, http.StatusNotFound, "item not found"},
+	}
+	for _, test := range tests {
+		code, responseBody := uploadAttachment(router, test.userId, spending.Id, test.field, test.fileName, test.content)
+		assert.Equal(t, test.code, code)
+		assert.Equal(t, test.detail, responseBody["detail"])
+	}
+
+	// No file is attached by a failed upload.
+	_, responseBody := sendSpendingRequest(router, http.MethodGet, users[0].Id, "/spendings/"+spending.Id+"/attachments", "")
+	assert.Equal(t, []interface{}{}, responseBody["data"])
+
+	// A request which is not a multipart form is refused.
+	code, responseBody := sendSpendingRequest(router, http.MethodPost, users[0].Id, "/spendings/"+spending.Id+"/attachments", `{"file": "struk.png"}`)
+	assert.Equal(t, http.StatusBadRequest, code)
+	assert.Equal(t, "invalid request body", responseBody["detail"])
+}
+
+func TestDeleteSpendingWithAttachmentsSuccess(t *testing.T) {
+	router := setupRouter()
+
+	user := createUser()
+	defer clearUserDataAfterTest(user.Id)
+	spending := createSpending(user.Id)
+	defer clearSpendingDataAfterTest(spending.Id)
+	otherSpending := createSpending(user.Id)
+	defer clearSpendingDataAfterTest(otherSpending.Id)
+
+	var attachments []domain.Attachment
+	for _, spendingId := range []string{spending.Id, spending.Id, otherSpending.Id} {
+		_, responseBody := uploadAttachment(router, user.Id, spendingId, "file", "struk.png", pngContent)
+		attachment := findAttachment(responseBody["data"].(map[string]interface{})["id"].(string))
+		defer clearAttachmentDataAfterTest(attachment)
+		attachments = append(attachments, attachment)
+	}
+
+	// An attachment is only found through its own spending.
+	code, _ := sendSpendingRequest(router, http.MethodGet, user.Id, "/spendings/"+otherSpending.Id+"/attachments/"+attachments[0].Id, "")
+	assert.Equal(t, http.StatusNotFound, code)
+
+	code, _ = sendSpendingRequest(router, http.MethodDelete, user.Id, "/spendings/"+spending.Id, "")
+	assert.Equal(t, http.StatusOK, code)
+
+	for i, attachment := range attachments {
+		_, err := testRepositories.Attachment.FindById(context.Background(), attachment.Id)
+		content, blobErr := testBlobStore.Get(context.Background(), attachment.BlobKey())
+		if blobErr == nil {
+			content.Close()
+		}
+		if i < 2 {
+			assert.Error(t, err)
+			assert.Error(t, blobErr)
+		} else {
+			assert.NoError(t, err)
+			assert.NoError(t, blobErr)
+		}
+	}
+	_, responseBody := sendSpendingRequest(router, http.MethodGet, user.Id, "/spendings/"+otherSpending.Id+"/attachments", "")
+	assert.Equal(t, 1, len(responseBody["data"].([]interface{})))
+}
+
+func TestBlobStoreLocalRefusesKeysOutsideItsDirectory(t *testing.T) {
+	for _, key := range []string{"../kunci", "/etc/passwd", ""} {
+		err := testBlobStore.Put(context.Background(), key, strings.NewReader("isi"))
+		assert.Error(t, err, key)
+	}
+}
@@ -0,0 +1,231 @@
+package test
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestLoginSuccess(t *testing.T) {
+	router := setupRouter()
+
+	user := createUser()
+	defer clearUserDataAfterTest(user.Id)
+
+	jsonData := `
+	{
+		"email": "test@example.com",
+		"password": "secret"
+	}
+`
+	requestBody := strings.NewReader(jsonData)
+	request := httptest.NewRequest(http.MethodPost, "http://localhost:8000/api/v1/auth/login", requestBody)
+	request.Header.Add("Content-Type", "application/json")
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	response := recorder.Result()
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+
+	body, err := io.ReadAll(response.Body)
+	if err != nil {
+		panic(err)
+	}
+
+	var responseBody map[string]interface{}
+	err = json.Unmarshal(body, &responseBody)
+	if err != nil {
+		panic(err)
+	}
+
+	assert.Equal(t, http.StatusOK, int(responseBody["code"].(float64)))
+	assert.Equal(t, "OK", responseBody["status"])
+	assert.Equal(t, "Bearer", responseBody["data"].(map[string]interface{})["token_type"])
+
+	accessToken := responseBody["data"].(map[string]interface{})["access_token"].(string)
+	userId, err := testTokenManager.Verify(accessToken)
+	assert.Nil(t, err)
+	assert.Equal(t, user.Id, userId)
+}
+
+func TestLoginFailed(t *testing.T) {
+	router := setupRouter()
+
+	user := createUser()
+	defer clearUserDataAfterTest(user.Id)
+
+	jsonData := `
+	{
+		"email": "test@example.com",
+		"password": "wrong password"
+	}
+`
+	requestBody := strings.NewReader(jsonData)
+	request := httptest.NewRequest(http.MethodPost, "http://localhost:8000/api/v1/auth/login", requestBody)
+	request.Header.Add("Content-Type", "application/json")
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	response := recorder.Result()
+	assert.Equal(t, http.StatusUnauthorized, response.StatusCode)
+
+	body, err := io.ReadAll(response.Body)
+	if err != nil {
+		panic(err)
+	}
+
+	var responseBody map[string]interface{}
+	err = json.Unmarshal(body, &responseBody)
+	if err != nil {
+		panic(err)
+	}
+
+	assert.Equal(t, http.StatusUnauthorized, int(responseBody["status"].(float64)))
+	assert.Equal(t, "unauthorized", responseBody["code"])
+}
+
+func TestAccessWithoutTokenFailed(t *testing.T) {
+	router := setupRouter()
+
+	user := createUser()
+	defer clearUserDataAfterTest(user.Id)
+
+	request := httptest.NewRequest(http.MethodGet, "http://localhost:8000/api/v1/users/"+user.Id, nil)
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	response := recorder.Result()
+	assert.Equal(t, http.StatusUnauthorized, response.StatusCode)
+
+	body, err := io.ReadAll(response.Body)
+	if err != nil {
+		panic(err)
+	}
+
+	var responseBody map[string]interface{}
+	err = json.Unmarshal(body, &responseBody)
+	if err != nil {
+		panic(err)
+	}
+
+	assert.Equal(t, http.StatusUnauthorized, int(responseBody["status"].(float64)))
+	assert.Equal(t, "unauthorized", responseBody["code"])
+}
+
+// login logs in as the user created by createUser and returns the data of
+// the response.
+func login(router http.Handler) map[string]interface{} {
+	jsonData := `
+	{
+		"email": "test@example.com",
+		"password": "secret"
+	}
+`
+	requestBody := strings.NewReader(jsonData)
+	request := httptest.NewRequest(http.MethodPost, "http://localhost:8000/api/v1/auth/login", requestBody)
+	request.Header.Add("Content-Type", "application/json")
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	var responseBody map[string]interface{}
+	err := json.Unmarshal(recorder.Body.Bytes(), &responseBody)
+	if err != nil {
+		panic(err)
+	}
+	return responseBody["data"].(map[string]interface{})
+}
+
+// postRefreshToken sends the refresh token to the given authentication
+// route and returns the recorded response.
+func postRefreshToken(router http.Handler, route string, refreshToken string) *http.Response {
+	jsonData := fmt.Sprintf(`{"refresh_token": "%s"}`, refreshToken)
+
+	requestBody := strings.NewReader(jsonData)
+	request := httptest.NewRequest(http.MethodPost, "http://localhost:8000/api/v1/auth/"+route, requestBody)
+	request.Header.Add("Content-Type", "application/json")
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+	return recorder.Result()
+}
+
+func TestRefreshTokenSuccess(t *testing.T) {
+	router := setupRouter()
+
+	user := createUser()
+	defer clearUserDataAfterTest(user.Id)
+
+	refreshToken := login(router)["refresh_token"].(string)
+
+	response := postRefreshToken(router, "refresh", refreshToken)
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+
+	body, err := io.ReadAll(response.Body)
+	if err != nil {
+		panic(err)
+	}
+
+	var responseBody map[string]interface{}
+	err = json.Unmarshal(body, &responseBody)
+	if err != nil {
+		panic(err)
+	}
+
+	data := responseBody["data"].(map[string]interface{})
+	assert.Equal(t, http.StatusOK, int(responseBody["code"].(float64)))
+	assert.Equal(t, "OK", responseBody["status"])
+	assert.NotEqual(t, refreshToken, data["refresh_token"])
+
+	userId, err := testTokenManager.Verify(data["access_token"].(string))
+	assert.Nil(t, err)
+	assert.Equal(t, user.Id, userId)
+}
+
+func TestRefreshTokenReuseFailed(t *testing.T) {
+	router := setupRouter()
+
+	user := createUser()
+	defer clearUserDataAfterTest(user.Id)
+
+	refreshToken := login(router)["refresh_token"].(string)
+
+	response := postRefreshToken(router, "refresh", refreshToken)
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+
+	var responseBody map[string]interface{}
+	err := json.NewDecoder(response.Body).Decode(&responseBody)
+	if err != nil {
+		panic(err)
+	}
+	rotatedRefreshToken := responseBody["data"].(map[string]interface{})["refresh_token"].(string)
+
+	// Reusing the first refresh token revokes the rotated refresh token too.
+	response = postRefreshToken(router, "refresh", refreshToken)
+	assert.Equal(t, http.StatusUnauthorized, response.StatusCode)
+
+	response = postRefreshToken(router, "refresh", rotatedRefreshToken)
+	assert.Equal(t, http.StatusUnauthorized, response.StatusCode)
+}
+
+func TestLogoutSuccess(t *testing.T) {
+	router := setupRouter()
+
+	user := createUser()
+	defer clearUserDataAfterTest(user.Id)
+
+	refreshToken := login(router)["refresh_token"].(string)
+
+	response := postRefreshToken(router, "logout", refreshToken)
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+
+	response = postRefreshToken(router, "refresh", refreshToken)
+	assert.Equal(t, http.StatusUnauthorized, response.StatusCode)
+}
@@ -0,0 +1,192 @@
+package test
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/google/uuid"
+	"github.com/refandas/duit-api/model/domain"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// createBudget creates a budget for the user through the API then return
+// the data of the response.
+func createBudget(router http.Handler, userId string, jsonData string) (int, map[string]interface{}) {
+	requestBody := strings.NewReader(jsonData)
+	request := httptest.NewRequest(http.MethodPost, "http://localhost:8000/api/v1/users/"+userId+"/budgets", requestBody)
+	authorize(request, userId)
+	request.Header.Add("Content-Type", "application/json")
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	var responseBody map[string]interface{}
+	err := json.Unmarshal(recorder.Body.Bytes(), &responseBody)
+	if err != nil {
+		panic(err)
+	}
+	// A successful response carries its status code in the body, while a
+	// problem is responded with its status code.
+	if code, ok := responseBody["code"].(float64); ok {
+		return int(code), responseBody
+	}
+	return recorder.Code, responseBody
+}
+
+func TestCreateBudgetSuccess(t *testing.T) {
+	router := setupRouter()
+
+	user := createUser()
+	defer clearUserDataAfterTest(user.Id)
+
+	code, responseBody := createBudget(router, user.Id, `{"category": "food", "amount": 1000000}`)
+	data := responseBody["data"].(map[string]interface{})
+	defer clearBudgetDataAfterTest(data["id"].(string))
+
+	assert.Equal(t, http.StatusCreated, code)
+	assert.Equal(t, "CREATED", responseBody["status"])
+	assert.Equal(t, user.Id, data["user_id"])
+	assert.Equal(t, "food", data["category"])
+	assert.Equal(t, float64(1000000), data["amount"])
+	assert.Equal(t, "IDR", data["currency"])
+	assert.Equal(t, "month", data["period"])
+}
+
+func TestCreateBudgetDuplicateFailed(t *testing.T) {
+	router := setupRouter()
+
+	user := createUser()
+	defer clearUserDataAfterTest(user.Id)
+
+	_, responseBody := createBudget(router, user.Id, `{"category": "food", "amount": 1000000}`)
+	defer clearBudgetDataAfterTest(responseBody["data"].(map[string]interface{})["id"].(string))
+
+	code, responseBody := createBudget(router, user.Id, `{"category": "food", "amount": 500000, "period": "month"}`)
+	assert.Equal(t, http.StatusConflict, code)
+	assert.Equal(t, "conflict", responseBody["code"])
+}
+
+func TestGetBudgetOfAnotherUserFailed(t *testing.T) {
+	router := setupRouter()
+
+	users := createUsers()
+	for _, user := range users {
+		defer clearUserDataAfterTest(user.Id)
+	}
+
+	_, responseBody := createBudget(router, users[0].Id, `{"category": "food", "amount": 1000000}`)
+	budgetId := responseBody["data"].(map[string]interface{})["id"].(string)
+	defer clearBudgetDataAfterTest(budgetId)
+
+	// The budget is neither found under its owner's route by another user,
+	// nor under the route of another user.
+	routes := []struct {
+		userId string
+		owner  string
+	}{
+		{users[1].Id, users[0].Id},
+		{users[1].Id, users[1].Id},
+	}
+	for _, route := range routes {
+		request := httptest.NewRequest(http.MethodGet, "http://localhost:8000/api/v1/users/"+route.owner+"/budgets/"+budgetId, nil)
+		authorize(request, route.userId)
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, request)
+
+		assert.Equal(t, http.StatusNotFound, recorder.Result().StatusCode)
+	}
+}
+
+func TestGetBudgetStatusSuccess(t *testing.T) {
+	router := setupRouter()
+
+	user := createUser()
+	defer clearUserDataAfterTest(user.Id)
+
+	spending := createSpendingOn(user.Id, time.Now().UnixMilli())
+	defer clearSpendingDataAfterTest(spending.Id)
+
+	_, responseBody := createBudget(router, user.Id, `{"category": "food", "amount": 40000}`)
+	budgetId := responseBody["data"].(map[string]interface{})["id"].(string)
+	defer clearBudgetDataAfterTest(budgetId)
+
+	request := httptest.NewRequest(http.MethodGet, "http://localhost:8000/api/v1/users/"+user.Id+"/budgets/"+budgetId+"/status", nil)
+	authorize(request, user.Id)
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	response := recorder.Result()
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+
+	err := json.NewDecoder(response.Body).Decode(&responseBody)
+	if err != nil {
+		panic(err)
+	}
+
+	data := responseBody["data"].(map[string]interface{})
+	assert.Equal(t, float64(50000), data["spent"])
+	assert.Equal(t, float64(-10000), data["remaining"])
+	assert.Equal(t, float64(125), data["percent"])
+	assert.Equal(t, true, data["overspent"])
+}
+
+// TestGetBudgetStatusExactAmounts test that the budget and its spendings are
+// summed exactly in the home currency of the user, so spendings adding up
+// to the budget exactly do not overspend it.
+func TestGetBudgetStatusExactAmounts(t *testing.T) {
+	router := setupRouter()
+
+	user := createUser()
+	defer clearUserDataAfterTest(user.Id)
+	user.HomeCurrency = "USD"
+	if _, err := testRepositories.User.Update(context.Background(), user); err != nil {
+		panic(err)
+	}
+
+	for _, minor := range []int64{10, 20} {
+		spending, _ := testRepositories.Spending.Save(context.Background(), domain.Spending{
+			Id:        uuid.NewString(),
+			UserId:    user.Id,
+			Title:     "Permen",
+			Date:      time.Now().UnixMilli(),
+			Amount:    domain.Money{Minor: minor, Currency: "USD"},
+			Category:  "food",
+			Type:      domain.SpendingTypeExpense,
+			CreatedAt: time.Now().UnixMilli(),
+		})
+		defer clearSpendingDataAfterTest(spending.Id)
+	}
+
+	code, _ := createBudget(router, user.Id, `{"category": "food", "amount": 0.301}`)
+	assert.Equal(t, http.StatusBadRequest, code)
+
+	code, responseBody := createBudget(router, user.Id, `{"category": "food", "amount": 0.3}`)
+	assert.Equal(t, http.StatusCreated, code)
+	data := responseBody["data"].(map[string]interface{})
+	budgetId := data["id"].(string)
+	defer clearBudgetDataAfterTest(budgetId)
+	assert.Equal(t, "USD", data["currency"])
+
+	request := httptest.NewRequest(http.MethodGet, "http://localhost:8000/api/v1/users/"+user.Id+"/budgets/"+budgetId+"/status", nil)
+	authorize(request, user.Id)
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+
+	decoder := json.NewDecoder(recorder.Body)
+	decoder.UseNumber()
+	if err := decoder.Decode(&responseBody); err != nil {
+		panic(err)
+	}
+	data = responseBody["data"].(map[string]interface{})
+	assert.Equal(t, json.Number("0.30"), data["amount"])
+	assert.Equal(t, json.Number("0.30"), data["spent"])
+	assert.Equal(t, json.Number("0.00"), data["remaining"])
+	assert.Equal(t, json.Number("100"), data["percent"])
+	assert.Equal(t, false, data["overspent"])
+	assert.Equal(t, "USD", data["currency"])
+}
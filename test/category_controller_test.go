@@ -0,0 +1,197 @@
+package test
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/refandas/duit-api/model/domain"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// findCategoryByName returns the user's category with the given name.
+func findCategoryByName(userId string, name string) domain.Category {
+	category, _, _ := testRepositories.Category.FindByName(context.Background(), userId, name)
+	return category
+}
+
+// getSpendingCategory returns the category of the spending responded by
+// the API.
+func getSpendingCategory(router http.Handler, userId string, spendingId string) string {
+	request := httptest.NewRequest(http.MethodGet, "http://localhost:8000/api/v1/spendings/"+spendingId, nil)
+	authorize(request, userId)
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	var responseBody map[string]interface{}
+	err := json.Unmarshal(recorder.Body.Bytes(), &responseBody)
+	if err != nil {
+		panic(err)
+	}
+	return responseBody["data"].(map[string]interface{})["category"].(string)
+}
+
+// createRecurringSpendingIn creates a recurring spending of the user in the
+// category then returns its id.
+func createRecurringSpendingIn(userId string, category string) string {
+	_, responseBody := sendRecurringSpending(http.MethodPost, userId, "", `{
+		"title": "Langganan katering", "amount": 750000, "category": "`+category+`",
+		"frequency": "monthly", "start": 1698710400000
+	}`)
+	return responseBody["data"].(map[string]interface{})["id"].(string)
+}
+
+// getRecurringSpendingCategory returns the category of the recurring
+// spending in the repository.
+func getRecurringSpendingCategory(recurringId string) string {
+	recurring, err := testRepositories.RecurringSpending.FindById(context.Background(), recurringId)
+	if err != nil {
+		panic(err)
+	}
+	return recurring.Category
+}
+
+func TestGetListOfUserCategorySuccess(t *testing.T) {
+	router := setupRouter()
+
+	user := createUser()
+	defer clearUserDataAfterTest(user.Id)
+
+	request := httptest.NewRequest(http.MethodGet, "http://localhost:8000/api/v1/users/"+user.Id+"/categories", nil)
+	authorize(request, user.Id)
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	response := recorder.Result()
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+
+	var responseBody map[string]interface{}
+	err := json.NewDecoder(response.Body).Decode(&responseBody)
+	if err != nil {
+		panic(err)
+	}
+
+	names := []string{}
+	for _, category := range responseBody["data"].([]interface{}) {
+		names = append(names, category.(map[string]interface{})["name"].(string))
+	}
+	assert.Equal(t, []string{"bills", "education", "entertainment", "food", "health", "other", "shopping", "transportation"}, names)
+}
+
+func TestCreateCategoryDuplicateFailed(t *testing.T) {
+	router := setupRouter()
+
+	user := createUser()
+	defer clearUserDataAfterTest(user.Id)
+
+	requestBody := strings.NewReader(`{"name": "food", "color": "#FFFFFF"}`)
+	request := httptest.NewRequest(http.MethodPost, "http://localhost:8000/api/v1/users/"+user.Id+"/categories", requestBody)
+	authorize(request, user.Id)
+	request.Header.Add("Content-Type", "application/json")
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	response := recorder.Result()
+	assert.Equal(t, http.StatusConflict, response.StatusCode)
+}
+
+// TestRenameCategorySuccess test that renaming a category renames the
+// category of its spendings and recurring spendings too.
+func TestRenameCategorySuccess(t *testing.T) {
+	router := setupRouter()
+
+	user := createUser()
+	defer clearUserDataAfterTest(user.Id)
+
+	spending := createSpending(user.Id)
+	defer clearSpendingDataAfterTest(spending.Id)
+	recurringId := createRecurringSpendingIn(user.Id, "food")
+	defer clearRecurringSpendingDataAfterTest(recurringId)
+
+	category := findCategoryByName(user.Id, "food")
+	requestBody := strings.NewReader(`{"name": "meals", "color": "#FF7043", "icon": "utensils"}`)
+	request := httptest.NewRequest(http.MethodPut, "http://localhost:8000/api/v1/users/"+user.Id+"/categories/"+category.Id, requestBody)
+	authorize(request, user.Id)
+	request.Header.Add("Content-Type", "application/json")
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	response := recorder.Result()
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+	assert.Equal(t, "meals", getSpendingCategory(router, user.Id, spending.Id))
+	assert.Equal(t, "meals", getRecurringSpendingCategory(recurringId))
+}
+
+// TestMergeCategorySuccess test that merging a category moves its
+// spendings and recurring spendings into the target category and deletes
+// the category.
+func TestMergeCategorySuccess(t *testing.T) {
+	router := setupRouter()
+
+	user := createUser()
+	defer clearUserDataAfterTest(user.Id)
+
+	spending := createSpending(user.Id)
+	defer clearSpendingDataAfterTest(spending.Id)
+	recurringId := createRecurringSpendingIn(user.Id, "food")
+	defer clearRecurringSpendingDataAfterTest(recurringId)
+
+	category := findCategoryByName(user.Id, "food")
+	target := findCategoryByName(user.Id, "other")
+	requestBody := strings.NewReader(`{"target_id": "` + target.Id + `"}`)
+	request := httptest.NewRequest(http.MethodPost, "http://localhost:8000/api/v1/users/"+user.Id+"/categories/"+category.Id+"/merge", requestBody)
+	authorize(request, user.Id)
+	request.Header.Add("Content-Type", "application/json")
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	response := recorder.Result()
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+	assert.Equal(t, "other", getSpendingCategory(router, user.Id, spending.Id))
+	assert.Equal(t, "other", getRecurringSpendingCategory(recurringId))
+
+	request = httptest.NewRequest(http.MethodGet, "http://localhost:8000/api/v1/users/"+user.Id+"/categories/"+category.Id, nil)
+	authorize(request, user.Id)
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+	assert.Equal(t, http.StatusNotFound, recorder.Result().StatusCode)
+}
+
+func TestDeleteCategoryInUseFailed(t *testing.T) {
+	router := setupRouter()
+
+	user := createUser()
+	defer clearUserDataAfterTest(user.Id)
+
+	spending := createSpending(user.Id)
+	defer clearSpendingDataAfterTest(spending.Id)
+
+	category := findCategoryByName(user.Id, "food")
+	request := httptest.NewRequest(http.MethodDelete, "http://localhost:8000/api/v1/users/"+user.Id+"/categories/"+category.Id, nil)
+	authorize(request, user.Id)
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	assert.Equal(t, http.StatusConflict, recorder.Result().StatusCode)
+}
+
+func TestDeleteCategoryUsedByRecurringSpendingFailed(t *testing.T) {
+	router := setupRouter()
+
+	user := createUser()
+	defer clearUserDataAfterTest(user.Id)
+
+	recurringId := createRecurringSpendingIn(user.Id, "bills")
+	defer clearRecurringSpendingDataAfterTest(recurringId)
+
+	category := findCategoryByName(user.Id, "bills")
+	request := httptest.NewRequest(http.MethodDelete, "http://localhost:8000/api/v1/users/"+user.Id+"/categories/"+category.Id, nil)
+	authorize(request, user.Id)
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	assert.Equal(t, http.StatusConflict, recorder.Result().StatusCode)
+	assert.Equal(t, "bills", findCategoryByName(user.Id, "bills").Name)
+}
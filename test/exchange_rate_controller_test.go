@@ -0,0 +1,184 @@
+package test
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/google/uuid"
+	"github.com/refandas/duit-api/model/domain"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// sendExchangeRates posts the body of the given content type to the
+// exchange rates and returns the response recorder.
+func sendExchangeRates(userId string, contentType string, body string) *httptest.ResponseRecorder {
+	router := setupRouter()
+	request := httptest.NewRequest(http.MethodPost, "http://localhost:8000/api/v1/exchange-rates", strings.NewReader(body))
+	authorize(request, userId)
+	request.Header.Add("Content-Type", contentType)
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+	return recorder
+}
+
+func TestCreateExchangeRatesSuccess(t *testing.T) {
+	user := createUser()
+	defer clearUserDataAfterTest(user.Id)
+
+	recorder := sendExchangeRates(testAdminId, "application/json", `{"rates": [
+		{"base": "SGD", "quote": "IDR", "rate": 11000, "effective_from": "2023-12-01"},
+		{"base": "SGD", "quote": "IDR", "rate": 11500.50, "effective_from": "2023-12-10"}
+	]}`)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+
+	var responseBody map[string]interface{}
+	err := json.NewDecoder(recorder.Body).Decode(&responseBody)
+	if err != nil {
+		panic(err)
+	}
+	assert.Equal(t, http.StatusCreated, int(responseBody["code"].(float64)))
+	assert.Equal(t, 2, len(responseBody["data"].([]interface{})))
+
+	router := setupRouter()
+	request := httptest.NewRequest(http.MethodGet, "http://localhost:8000/api/v1/exchange-rates?base=sgd&quote=idr", nil)
+	authorize(request, user.Id)
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), `"rate":11000,"effective_from":1701388800000`)
+	assert.Contains(t, recorder.Body.String(), `"rate":11500.50,"effective_from":1702166400000`)
+}
+
+func TestImportExchangeRatesSuccess(t *testing.T) {
+	user := createUser()
+	defer clearUserDataAfterTest(user.Id)
+
+	recorder := sendExchangeRates(testAdminId, "text/csv; charset=utf-8",
+		"effective_from,base,quote,rate\n2023-12-01,chf,idr,17500\n2023-12-01,IDR,JPY,0.0095\n")
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), `"base":"CHF","quote":"IDR","rate":17500`)
+	assert.Contains(t, recorder.Body.String(), `"base":"IDR","quote":"JPY","rate":0.0095`)
+}
+
+func TestCreateExchangeRatesFailed(t *testing.T) {
+	user := createUser()
+	defer clearUserDataAfterTest(user.Id)
+
+	tests := []struct {
+		contentType string
+		body        string
+		detail      string
+	}{
+		{"application/json", `{"rates": [{"base": "SGD", "quote": "IDR", "rate": -1, "effective_from": "2023-12-01"}]}`, "rate must be a positive decimal number"},
+		{"application/json", `{"rates": [{"base": "SGD", "quote": "SGD", "rate": 1, "effective_from": "2023-12-01"}]}`, "the request has invalid fields"},
+		{"application/json", `{"rates": [{"base": "SGD", "quote": "IDR", "rate": 1, "effective_from": "soon"}]}`, "effective_from must be a date"},
+		{"text/csv", "base,quote,rate\nSGD,IDR,11000\n", "the CSV file must have a header with the columns base, quote, rate, effective_from"},
+		{"text/csv", "base,quote,rate,effective_from\nSGD,IDR\n", "line 2 of the CSV file is invalid"},
+	}
+	for _, test := range tests {
+		recorder := sendExchangeRates(testAdminId, test.contentType, test.body)
+		assert.Equal(t, http.StatusBadRequest, recorder.Code, test.body)
+		assert.Contains(t, recorder.Body.String(), test.detail)
+	}
+}
+
+func TestCreateExchangeRatesForbidden(t *testing.T) {
+	user := createUser()
+	defer clearUserDataAfterTest(user.Id)
+
+	// The rates convert the spendings of every user, so a user who is not
+	// an administrator can not change them.
+	tests := []struct {
+		contentType string
+		body        string
+	}{
+		{"application/json", `{"rates": [{"base": "SGD", "quote": "IDR", "rate": 1, "effective_from": "2023-12-01"}]}`},
+		{"text/csv", "base,quote,rate,effective_from\nSGD,IDR,1,2023-12-01\n"},
+	}
+	for _, test := range tests {
+		recorder := sendExchangeRates(user.Id, test.contentType, test.body)
+		assert.Equal(t, http.StatusForbidden, recorder.Code)
+		assert.Contains(t, recorder.Body.String(), "only administrators can change the exchange rates")
+	}
+
+	router := setupRouter()
+	request := httptest.NewRequest(http.MethodGet, "http://localhost:8000/api/v1/exchange-rates?base=SGD&quote=IDR", nil)
+	authorize(request, user.Id)
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.NotContains(t, recorder.Body.String(), `"rate":1,"effective_from":1701388800000`)
+}
+
+// TestConvertSpendingsToHomeCurrencySuccess test that the spendings are
+// converted into the home currency of the user by the rate effective at
+// their date, in the list of spendings and in the summary report.
+func TestConvertSpendingsToHomeCurrencySuccess(t *testing.T) {
+	user := createUser()
+	defer clearUserDataAfterTest(user.Id)
+
+	recorder := sendExchangeRates(testAdminId, "text/csv",
+		"base,quote,rate,effective_from\nSGD,IDR,11000,2023-12-01\nSGD,IDR,11500.50,2023-12-10\nIDR,JPY,0.0095,2023-12-01\n")
+	assert.Equal(t, http.StatusOK, recorder.Code)
+
+	amounts := []domain.Money{
+		{Minor: 1000, Currency: "SGD"},
+		{Minor: 1000, Currency: "SGD"},
+		{Minor: 1000, Currency: "JPY"},
+		{Minor: 1000, Currency: "MYR"},
+	}
+	dates := []int64{
+		time.Date(2023, 12, 5, 0, 0, 0, 0, time.UTC).UnixMilli(),
+		time.Date(2023, 12, 15, 0, 0, 0, 0, time.UTC).UnixMilli(),
+		time.Date(2023, 12, 6, 0, 0, 0, 0, time.UTC).UnixMilli(),
+		time.Date(2023, 12, 7, 0, 0, 0, 0, time.UTC).UnixMilli(),
+	}
+	for i := range amounts {
+		spendingId, _ := uuid.NewRandom()
+		spending, _ := testRepositories.Spending.Save(context.Background(), domain.Spending{
+			Id:        spendingId.String(),
+			UserId:    user.Id,
+			Title:     "Travel",
+			Amount:    amounts[i],
+			Date:      dates[i],
+			Category:  "food",
+			CreatedAt: dates[i],
+		})
+		defer clearSpendingDataAfterTest(spending.Id)
+	}
+
+	router := setupRouter()
+	request := httptest.NewRequest(http.MethodGet, "http://localhost:8000/api/v1/users/"+user.Id+"/spendings?from=2023-12-01&to=2023-12-31", nil)
+	authorize(request, user.Id)
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+
+	body := recorder.Body.String()
+	assert.Contains(t, body, `"amount":10.00,"currency":"SGD","converted_amount":110000.00,"converted_currency":"IDR"`)
+	assert.Contains(t, body, `"amount":10.00,"currency":"SGD","converted_amount":115005.00,"converted_currency":"IDR"`)
+	assert.Contains(t, body, `"amount":1000,"currency":"JPY","converted_amount":105263.16,"converted_currency":"IDR"`)
+	assert.Contains(t, body, `"amount":10.00,"currency":"MYR","date"`)
+
+	request = httptest.NewRequest(http.MethodGet, "http://localhost:8000/api/v1/users/"+user.Id+"/reports/summary?period=month&from=2023-12-01&to=2023-12-31&tz=UTC", nil)
+	authorize(request, user.Id)
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+
+	var responseBody map[string]interface{}
+	err := json.NewDecoder(recorder.Body).Decode(&responseBody)
+	if err != nil {
+		panic(err)
+	}
+	data := responseBody["data"].(map[string]interface{})
+	assert.Equal(t, "IDR", data["currency"])
+	assert.Equal(t, float64(1), data["unconverted"])
+	assert.Equal(t, float64(3), data["count"])
+	assert.Equal(t, 330268.16, data["total"])
+}
@@ -0,0 +1,105 @@
+package test
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// sendLocaleRequest sends the request to the router then return the status
+// code, the Content-Language header and the body of the response.
+func sendLocaleRequest(router http.Handler, request *http.Request) (int, string, map[string]interface{}) {
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	var responseBody map[string]interface{}
+	err := json.Unmarshal(recorder.Body.Bytes(), &responseBody)
+	if err != nil {
+		panic(err)
+	}
+	return recorder.Code, recorder.Header().Get("Content-Language"), responseBody
+}
+
+func TestValidationErrorAcceptLanguageIndonesian(t *testing.T) {
+	router := setupRouter()
+
+	requestBody := strings.NewReader(`{"name": "Test User", "email": "", "password": "secret"}`)
+	request := httptest.NewRequest(http.MethodPost, "http://localhost:8000/api/v1/users", requestBody)
+	request.Header.Add("Content-Type", "application/json")
+	request.Header.Add("Accept-Language", "fr-FR, id-ID;q=0.9, en;q=0.8")
+
+	code, language, responseBody := sendLocaleRequest(router, request)
+	assert.Equal(t, http.StatusBadRequest, code)
+	assert.Equal(t, "id", language)
+	assert.Equal(t, "Permintaan Tidak Valid", responseBody["title"])
+	assert.Equal(t, "permintaan memiliki field yang tidak valid", responseBody["detail"])
+
+	fieldErrors := responseBody["errors"].([]interface{})
+	assert.Equal(t, "email wajib diisi", fieldErrors[0].(map[string]interface{})["message"])
+}
+
+func TestUnauthorizedAcceptLanguageIndonesian(t *testing.T) {
+	router := setupRouter()
+
+	request := httptest.NewRequest(http.MethodGet, "http://localhost:8000/api/v1/users/unknown", nil)
+	request.Header.Add("Accept-Language", "id")
+
+	code, language, responseBody := sendLocaleRequest(router, request)
+	assert.Equal(t, http.StatusUnauthorized, code)
+	assert.Equal(t, "id", language)
+	assert.Equal(t, "access token tidak ada", responseBody["detail"])
+}
+
+func TestNotFoundUserPreferenceIndonesian(t *testing.T) {
+	router := setupRouter()
+
+	user := createUser()
+	defer clearUserDataAfterTest(user.Id)
+	user.Language = "id"
+	testRepositories.User.Update(context.Background(), user)
+
+	request := httptest.NewRequest(http.MethodGet, "http://localhost:8000/api/v1/spendings/not-found", nil)
+	authorize(request, user.Id)
+
+	code, language, responseBody := sendLocaleRequest(router, request)
+	assert.Equal(t, http.StatusNotFound, code)
+	assert.Equal(t, "id", language)
+	assert.Equal(t, "Tidak Ditemukan", responseBody["title"])
+	assert.Equal(t, "item tidak ditemukan", responseBody["detail"])
+
+	// The Accept-Language header takes precedence over the preference.
+	request = httptest.NewRequest(http.MethodGet, "http://localhost:8000/api/v1/spendings/not-found", nil)
+	authorize(request, user.Id)
+	request.Header.Add("Accept-Language", "en-US")
+
+	code, language, responseBody = sendLocaleRequest(router, request)
+	assert.Equal(t, http.StatusNotFound, code)
+	assert.Equal(t, "en", language)
+	assert.Equal(t, "item not found", responseBody["detail"])
+}
+
+// TestLocaleVaryOnce test that the responses vary by the Accept-Language
+// header only once, whether or not the request is authenticated.
+func TestLocaleVaryOnce(t *testing.T) {
+	router := setupRouter()
+
+	user := createUser()
+	defer clearUserDataAfterTest(user.Id)
+
+	request := httptest.NewRequest(http.MethodGet, "http://localhost:8000/api/v1/users/"+user.Id, nil)
+	authorize(request, user.Id)
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, []string{"Accept-Language"}, recorder.Header().Values("Vary"))
+
+	request = httptest.NewRequest(http.MethodGet, "http://localhost:8000/api/v1/users/"+user.Id, nil)
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+	assert.Equal(t, http.StatusUnauthorized, recorder.Code)
+	assert.Equal(t, []string{"Accept-Language"}, recorder.Header().Values("Vary"))
+}
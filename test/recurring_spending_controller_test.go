@@ -0,0 +1,229 @@
+package test
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/refandas/duit-api/exception"
+	"github.com/refandas/duit-api/model/domain"
+	"github.com/refandas/duit-api/service"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// sendRecurringSpending sends a request with the JSON data to the route of
+// the recurring spendings of the user then return the status code and the
+// body of the response.
+func sendRecurringSpending(method string, userId string, route string, jsonData string) (int, map[string]interface{}) {
+	router := setupRouter()
+	request := httptest.NewRequest(method, "http://localhost:8000/api/v1/users/"+userId+"/recurring-spendings"+route, strings.NewReader(jsonData))
+	authorize(request, userId)
+	request.Header.Add("Content-Type", "application/json")
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+	if recorder.Code == http.StatusNoContent || recorder.Body.Len() == 0 {
+		return recorder.Code, nil
+	}
+
+	var responseBody map[string]interface{}
+	err := json.Unmarshal(recorder.Body.Bytes(), &responseBody)
+	if err != nil {
+		panic(err)
+	}
+	if code, ok := responseBody["code"].(float64); ok {
+		return int(code), responseBody
+	}
+	return recorder.Code, responseBody
+}
+
+func TestCreateRecurringSpendingSuccess(t *testing.T) {
+	user := createUser()
+	defer clearUserDataAfterTest(user.Id)
+
+	code, responseBody := sendRecurringSpending(http.MethodPost, user.Id, "", `{
+		"title": "Sewa kos", "amount": 1500000, "category": "bills",
+		"frequency": "monthly", "start": 1698710400000, "count": 3
+	}`)
+	data := responseBody["data"].(map[string]interface{})
+	defer clearRecurringSpendingDataAfterTest(data["id"].(string))
+
+	assert.Equal(t, http.StatusCreated, code)
+	assert.Equal(t, user.Id, data["user_id"])
+	assert.Equal(t, float64(1500000), data["amount"])
+	assert.Equal(t, "IDR", data["currency"])
+	assert.Equal(t, float64(1), data["interval"])
+	assert.Equal(t, float64(1698710400000), data["next_occurrence"])
+	assert.Nil(t, data["last_occurrence"])
+
+	code, responseBody = sendRecurringSpending(http.MethodGet, user.Id, "/"+data["id"].(string), "")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, "Sewa kos", responseBody["data"].(map[string]interface{})["title"])
+
+	code, responseBody = sendRecurringSpending(http.MethodGet, user.Id, "", "")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, 1, len(responseBody["data"].([]interface{})))
+}
+
+func TestCreateRecurringSpendingFailed(t *testing.T) {
+	user := createUser()
+	defer clearUserDataAfterTest(user.Id)
+
+	tests := []struct {
+		jsonData string
+		detail   string
+	}{
+		{`{"title": "Sewa kos", "amount": 1500000, "frequency": "hourly", "start": 1698710400000}`, "the request has invalid fields"},
+		{`{"title": "Sewa kos", "amount": 1500000, "category": "bills", "frequency": "monthly", "by_day": ["MO"], "start": 1698710400000}`, "by_day is only allowed with the weekly frequency"},
+		{`{"title": "Sewa kos", "amount": 1500000, "category": "bills", "frequency": "monthly", "start": 1698710400000, "until": 1709251200000, "count": 3}`, "until and count must not both be set"},
+		{`{"title": "Sewa kos", "amount": 1500000, "category": "rent", "frequency": "monthly", "start": 1698710400000}`, "category not found"},
+	}
+	for _, test := range tests {
+		code, responseBody := sendRecurringSpending(http.MethodPost, user.Id, "", test.jsonData)
+		assert.Equal(t, http.StatusBadRequest, code)
+		body, _ := json.Marshal(responseBody)
+		assert.Contains(t, string(body), test.detail)
+	}
+}
+
+func TestUpdateAndDeleteRecurringSpendingSuccess(t *testing.T) {
+	users := createUsers()
+	for _, user := range users {
+		defer clearUserDataAfterTest(user.Id)
+	}
+
+	_, responseBody := sendRecurringSpending(http.MethodPost, users[0].Id, "", `{
+		"title": "Sewa kos", "amount": 1500000, "category": "bills",
+		"frequency": "monthly", "start": 1698710400000
+	}`)
+	recurringId := responseBody["data"].(map[string]interface{})["id"].(string)
+	defer clearRecurringSpendingDataAfterTest(recurringId)
+
+	code, responseBody := sendRecurringSpending(http.MethodPut, users[0].Id, "/"+recurringId, `{
+		"title": "Les renang", "amount": 200000, "category": "education",
+		"frequency": "weekly", "by_day": ["MO", "WE"], "start": 1701648000000
+	}`)
+	assert.Equal(t, http.StatusOK, code)
+	data := responseBody["data"].(map[string]interface{})
+	assert.Equal(t, "Les renang", data["title"])
+	assert.Equal(t, []interface{}{"MO", "WE"}, data["by_day"])
+	assert.Equal(t, float64(1701648000000), data["next_occurrence"])
+
+	code, _ = sendRecurringSpending(http.MethodDelete, users[1].Id, "/"+recurringId, "")
+	assert.Equal(t, http.StatusNotFound, code)
+
+	code, _ = sendRecurringSpending(http.MethodDelete, users[0].Id, "/"+recurringId, "")
+	assert.Equal(t, http.StatusNoContent, code)
+
+	code, _ = sendRecurringSpending(http.MethodGet, users[0].Id, "/"+recurringId, "")
+	assert.Equal(t, http.StatusNotFound, code)
+}
+
+func TestMaterializeRecurringSpendingSuccess(t *testing.T) {
+	user := createUser()
+	defer clearUserDataAfterTest(user.Id)
+
+	_, responseBody := sendRecurringSpending(http.MethodPost, user.Id, "", `{
+		"title": "Sewa kos", "amount": 1500000, "category": "bills",
+		"frequency": "monthly", "start": 1698710400000, "count": 3
+	}`)
+	recurringId := responseBody["data"].(map[string]interface{})["id"].(string)
+	defer clearRecurringSpendingDataAfterTest(recurringId)
+
+	recurringSpendingService := service.NewRecurringSpendingService(testRepositories.RecurringSpending, testRepositories.Spending,
+		testRepositories.Category, testRepositories.User, nil, service.OwnershipPolicyNotFound)
+	ctx := context.Background()
+
+	// The rule starts on the 31st, so November is skipped.
+	created, err := recurringSpendingService.Materialize(ctx, 1704067200000)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, created)
+
+	recurring, err := testRepositories.RecurringSpending.FindById(ctx, recurringId)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1703980800000), recurring.LastOccurrence)
+	assert.Equal(t, int64(1706659200000), recurring.NextOccurrence)
+
+	for _, date := range []int64{1698710400000, 1703980800000, 1706659200000} {
+		defer clearSpendingDataAfterTest(recurring.Occurrence(date, 0).Id)
+	}
+	spending, err := testRepositories.Spending.FindById(ctx, recurring.Occurrence(1698710400000, 0).Id)
+	assert.Nil(t, err)
+	assert.Equal(t, user.Id, spending.UserId)
+	assert.Equal(t, "bills", spending.Category)
+	assert.Equal(t, domain.Money{Minor: 150000000, Currency: "IDR"}, spending.Amount)
+
+	// Materializing again creates nothing.
+	created, err = recurringSpendingService.Materialize(ctx, 1704067200000)
+	assert.Nil(t, err)
+	assert.Equal(t, 0, created)
+
+	created, err = recurringSpendingService.Materialize(ctx, 1709251200000)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, created)
+
+	recurring, err = testRepositories.RecurringSpending.FindById(ctx, recurringId)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(0), recurring.NextOccurrence)
+}
+
+func TestAdvanceRecurringSpendingConditionally(t *testing.T) {
+	user := createUser()
+	defer clearUserDataAfterTest(user.Id)
+
+	_, responseBody := sendRecurringSpending(http.MethodPost, user.Id, "", `{
+		"title": "Sewa kos", "amount": 1500000, "category": "bills",
+		"frequency": "monthly", "start": 1698710400000
+	}`)
+	recurringId := responseBody["data"].(map[string]interface{})["id"].(string)
+	defer clearRecurringSpendingDataAfterTest(recurringId)
+	ctx := context.Background()
+
+	// Advancing from a stale last occurrence leaves the recurring spending
+	// unchanged, as another server has advanced it already.
+	advanced, err := testRepositories.RecurringSpending.Advance(ctx, recurringId, 0, 1698710400000, 1703980800000)
+	assert.Nil(t, err)
+	assert.True(t, advanced)
+	advanced, err = testRepositories.RecurringSpending.Advance(ctx, recurringId, 0, 1698710400000, 1701388800000)
+	assert.Nil(t, err)
+	assert.False(t, advanced)
+	recurring, err := testRepositories.RecurringSpending.FindById(ctx, recurringId)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1703980800000), recurring.NextOccurrence)
+
+	// A deleted recurring spending is neither advanced nor updated back.
+	code, _ := sendRecurringSpending(http.MethodDelete, user.Id, "/"+recurringId, "")
+	assert.Equal(t, http.StatusNoContent, code)
+	advanced, err = testRepositories.RecurringSpending.Advance(ctx, recurringId, 1698710400000, 1703980800000, 1706659200000)
+	assert.Nil(t, err)
+	assert.False(t, advanced)
+	_, err = testRepositories.RecurringSpending.Update(ctx, recurring)
+	assert.ErrorIs(t, err, exception.ErrNotFound)
+	_, err = testRepositories.RecurringSpending.FindById(ctx, recurringId)
+	assert.ErrorIs(t, err, exception.ErrNotFound)
+}
+
+func TestRecurrenceRuleNext(t *testing.T) {
+	// A rule every other week on Mondays and Wednesdays, starting on
+	// Wednesday, December 6th 2023.
+	rule := domain.RecurrenceRule{
+		Frequency: domain.FrequencyWeekly,
+		Interval:  2,
+		ByDay:     []string{"WE", "MO"},
+		Start:     1701820800000,
+		Count:     3,
+	}
+
+	var dates []int64
+	for date, ok := rule.Next(0, time.UTC); ok; date, ok = rule.Next(date, time.UTC) {
+		dates = append(dates, date)
+	}
+	assert.Equal(t, []int64{
+		1701820800000,
+		time.Date(2023, 12, 18, 0, 0, 0, 0, time.UTC).UnixMilli(),
+		time.Date(2023, 12, 20, 0, 0, 0, 0, time.UTC).UnixMilli(),
+	}, dates)
+}
@@ -0,0 +1,255 @@
+package test
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/google/uuid"
+	"github.com/refandas/duit-api/model/domain"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestGetSummaryReportSuccess(t *testing.T) {
+	router := setupRouter()
+
+	user := createUser()
+	defer clearUserDataAfterTest(user.Id)
+
+	spendings := createSpendings(user.Id)
+	for _, spending := range spendings {
+		defer clearSpendingDataAfterTest(spending.Id)
+	}
+
+	request := httptest.NewRequest(http.MethodGet, "http://localhost:8000/api/v1/users/"+user.Id+"/reports/summary?period=month&from=2023-12-01&to=2023-12-31", nil)
+	authorize(request, user.Id)
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	response := recorder.Result()
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+
+	var responseBody map[string]interface{}
+	err := json.NewDecoder(response.Body).Decode(&responseBody)
+	if err != nil {
+		panic(err)
+	}
+
+	data := responseBody["data"].(map[string]interface{})
+	assert.Equal(t, http.StatusOK, int(responseBody["code"].(float64)))
+	assert.Equal(t, "OK", responseBody["status"])
+	assert.Equal(t, "month", data["period"])
+	assert.Equal(t, float64(110000), data["total"])
+	assert.Equal(t, float64(3), data["count"])
+	assert.Equal(t, float64(25000), data["min"])
+	assert.Equal(t, float64(50000), data["max"])
+
+	periods := data["periods"].([]interface{})
+	assert.Equal(t, 1, len(periods))
+	assert.Equal(t, float64(110000), periods[0].(map[string]interface{})["total"])
+
+	categories := data["categories"].([]interface{})
+	assert.Equal(t, 1, len(categories))
+	assert.Equal(t, "food", categories[0].(map[string]interface{})["category"])
+	assert.Equal(t, float64(3), categories[0].(map[string]interface{})["count"])
+}
+
+// TestGetSummaryReportExactAmounts test that the amounts of the report are
+// exact decimal numbers in the minor unit of the currency, even when they are
+// too large to be represented exactly by a floating point number.
+func TestGetSummaryReportExactAmounts(t *testing.T) {
+	router := setupRouter()
+
+	user := createUser()
+	defer clearUserDataAfterTest(user.Id)
+
+	for _, minor := range []int64{4503599627370497, 4503599627370496, 1} {
+		spending, _ := testRepositories.Spending.Save(context.Background(), domain.Spending{
+			Id:        uuid.NewString(),
+			UserId:    user.Id,
+			Title:     "Cicilan rumah",
+			Date:      1702141200000,
+			Amount:    domain.Money{Minor: minor, Currency: "IDR"},
+			Category:  "bills",
+			CreatedAt: time.Now().UnixMilli(),
+		})
+		defer clearSpendingDataAfterTest(spending.Id)
+	}
+
+	request := httptest.NewRequest(http.MethodGet, "http://localhost:8000/api/v1/users/"+user.Id+"/reports/summary?period=month&from=2023-12-01&to=2023-12-31", nil)
+	authorize(request, user.Id)
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+
+	var responseBody map[string]interface{}
+	decoder := json.NewDecoder(recorder.Body)
+	decoder.UseNumber()
+	err := decoder.Decode(&responseBody)
+	if err != nil {
+		panic(err)
+	}
+
+	data := responseBody["data"].(map[string]interface{})
+	assert.Equal(t, json.Number("90071992547409.94"), data["total"])
+	assert.Equal(t, json.Number("30023997515803.31"), data["average"])
+	assert.Equal(t, json.Number("0.01"), data["min"])
+	assert.Equal(t, json.Number("45035996273704.97"), data["max"])
+}
+
+// TestGetSummaryReportTimeZoneSuccess test that the periods of the report
+// start at midnight in the requested time zone.
+func TestGetSummaryReportTimeZoneSuccess(t *testing.T) {
+	router := setupRouter()
+
+	user := createUser()
+	defer clearUserDataAfterTest(user.Id)
+
+	spendings := createSpendings(user.Id)
+	for _, spending := range spendings {
+		defer clearSpendingDataAfterTest(spending.Id)
+	}
+
+	tests := []struct {
+		timeZone string
+		expected []float64
+	}{
+		{"UTC", []float64{25000, 35000, 50000, 0}},
+		{"Asia/Jakarta", []float64{0, 25000, 35000, 50000}},
+	}
+
+	for _, test := range tests {
+		request := httptest.NewRequest(http.MethodGet, "http://localhost:8000/api/v1/users/"+user.Id+"/reports/summary?period=day&from=2023-12-09&to=2023-12-12&tz="+test.timeZone, nil)
+		authorize(request, user.Id)
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, request)
+
+		response := recorder.Result()
+		assert.Equal(t, http.StatusOK, response.StatusCode, test.timeZone)
+
+		var responseBody map[string]interface{}
+		err := json.NewDecoder(response.Body).Decode(&responseBody)
+		if err != nil {
+			panic(err)
+		}
+
+		totals := []float64{}
+		for _, period := range responseBody["data"].(map[string]interface{})["periods"].([]interface{}) {
+			totals = append(totals, period.(map[string]interface{})["total"].(float64))
+		}
+		assert.Equal(t, test.expected, totals, test.timeZone)
+	}
+}
+
+func TestGetSummaryReportFailed(t *testing.T) {
+	router := setupRouter()
+
+	user := createUser()
+	defer clearUserDataAfterTest(user.Id)
+
+	request := httptest.NewRequest(http.MethodGet, "http://localhost:8000/api/v1/users/"+user.Id+"/reports/summary?period=quarter", nil)
+	authorize(request, user.Id)
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	response := recorder.Result()
+	assert.Equal(t, http.StatusBadRequest, response.StatusCode)
+
+	var responseBody map[string]interface{}
+	err := json.NewDecoder(response.Body).Decode(&responseBody)
+	if err != nil {
+		panic(err)
+	}
+
+	assert.Equal(t, http.StatusBadRequest, int(responseBody["status"].(float64)))
+	assert.Equal(t, "validation_failed", responseBody["code"])
+}
+
+// getBalance gets the balance of the user with the given query parameters
+// then return the data of the response.
+func getBalance(router http.Handler, userId string, query string) (int, map[string]interface{}) {
+	request := httptest.NewRequest(http.MethodGet, "http://localhost:8000/api/v1/users/"+userId+"/balance?"+query, nil)
+	authorize(request, userId)
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	var responseBody map[string]interface{}
+	err := json.NewDecoder(recorder.Body).Decode(&responseBody)
+	if err != nil {
+		panic(err)
+	}
+	return recorder.Code, responseBody
+}
+
+func TestGetBalanceSuccess(t *testing.T) {
+	router := setupRouter()
+
+	user := createUser()
+	defer clearUserDataAfterTest(user.Id)
+
+	spendings := createSpendings(user.Id)
+	for _, spending := range spendings {
+		defer clearSpendingDataAfterTest(spending.Id)
+	}
+	incomes := []domain.Spending{
+		createIncomeOn(user.Id, 1700870400000, 100000000),
+		createIncomeOn(user.Id, 1701388800000, 500000000),
+	}
+	for _, income := range incomes {
+		defer clearSpendingDataAfterTest(income.Id)
+	}
+
+	code, responseBody := getBalance(router, user.Id, "period=month&from=2023-11-01&to=2023-12-31")
+	assert.Equal(t, http.StatusOK, code)
+	data := responseBody["data"].(map[string]interface{})
+	assert.Equal(t, "month", data["period"])
+	assert.Equal(t, "IDR", data["currency"])
+	assert.Equal(t, float64(6000000), data["income"])
+	assert.Equal(t, float64(110000), data["expenses"])
+	assert.Equal(t, float64(5890000), data["net"])
+
+	periods := data["periods"].([]interface{})
+	assert.Equal(t, 2, len(periods))
+	assert.Equal(t, map[string]interface{}{
+		"start": float64(1698796800000), "end": float64(1701388799999),
+		"income": float64(1000000), "expenses": float64(0), "net": float64(1000000),
+	}, periods[0])
+	assert.Equal(t, map[string]interface{}{
+		"start": float64(1701388800000), "end": float64(1704067199999),
+		"income": float64(5000000), "expenses": float64(110000), "net": float64(4890000),
+	}, periods[1])
+
+	// The incomes are not spendings of the report.
+	request := httptest.NewRequest(http.MethodGet, "http://localhost:8000/api/v1/users/"+user.Id+"/reports/summary?period=month&from=2023-11-01&to=2023-12-31", nil)
+	authorize(request, user.Id)
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+	err := json.NewDecoder(recorder.Body).Decode(&responseBody)
+	if err != nil {
+		panic(err)
+	}
+	assert.Equal(t, float64(110000), responseBody["data"].(map[string]interface{})["total"])
+}
+
+func TestGetBalanceFailed(t *testing.T) {
+	router := setupRouter()
+
+	user := createUser()
+	defer clearUserDataAfterTest(user.Id)
+
+	tests := []struct {
+		query  string
+		detail string
+	}{
+		{"period=quarter", "the request has invalid fields"},
+		{"from=2023-12-31&to=2023-12-01", "from must not be after to"},
+		{"from=yesterday", "from must be a date"},
+	}
+	for _, test := range tests {
+		code, responseBody := getBalance(router, user.Id, test.query)
+		assert.Equal(t, http.StatusBadRequest, code)
+		assert.Equal(t, test.detail, responseBody["detail"])
+	}
+}
@@ -0,0 +1,86 @@
+package test
+
+import (
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestGetListOfUserSessionSuccess(t *testing.T) {
+	router := setupRouter()
+
+	user := createUser()
+	defer clearUserDataAfterTest(user.Id)
+
+	login(router)
+	login(router)
+
+	request := httptest.NewRequest(http.MethodGet, "http://localhost:8000/api/v1/users/"+user.Id+"/sessions", nil)
+	authorize(request, user.Id)
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	response := recorder.Result()
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+
+	body, err := io.ReadAll(response.Body)
+	if err != nil {
+		panic(err)
+	}
+
+	var responseBody map[string]interface{}
+	err = json.Unmarshal(body, &responseBody)
+	if err != nil {
+		panic(err)
+	}
+
+	assert.Equal(t, http.StatusOK, int(responseBody["code"].(float64)))
+	assert.Equal(t, "OK", responseBody["status"])
+	assert.Len(t, responseBody["data"].([]interface{}), 2)
+}
+
+func TestDeleteUserSessionSuccess(t *testing.T) {
+	router := setupRouter()
+
+	user := createUser()
+	defer clearUserDataAfterTest(user.Id)
+
+	refreshToken := login(router)["refresh_token"].(string)
+
+	request := httptest.NewRequest(http.MethodGet, "http://localhost:8000/api/v1/users/"+user.Id+"/sessions", nil)
+	authorize(request, user.Id)
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	var responseBody map[string]interface{}
+	err := json.Unmarshal(recorder.Body.Bytes(), &responseBody)
+	if err != nil {
+		panic(err)
+	}
+	sessionId := responseBody["data"].([]interface{})[0].(map[string]interface{})["id"].(string)
+
+	request = httptest.NewRequest(http.MethodDelete, "http://localhost:8000/api/v1/users/"+user.Id+"/sessions/"+sessionId, nil)
+	authorize(request, user.Id)
+
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	response := recorder.Result()
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+
+	err = json.Unmarshal(recorder.Body.Bytes(), &responseBody)
+	if err != nil {
+		panic(err)
+	}
+
+	assert.Equal(t, http.StatusNoContent, int(responseBody["code"].(float64)))
+	assert.Equal(t, "DELETED", responseBody["status"])
+
+	response = postRefreshToken(router, "refresh", refreshToken)
+	assert.Equal(t, http.StatusUnauthorized, response.StatusCode)
+}
@@ -2,72 +2,174 @@ package test
 
 import (
 	"context"
-	"github.com/go-playground/validator/v10"
 	"github.com/google/uuid"
 	"github.com/refandas/duit-api/app"
 	"github.com/refandas/duit-api/controller"
 	"github.com/refandas/duit-api/helper"
+	"github.com/refandas/duit-api/middleware"
 	"github.com/refandas/duit-api/model/domain"
 	"github.com/refandas/duit-api/repository"
 	"github.com/refandas/duit-api/service"
 	"golang.org/x/crypto/bcrypt"
 	"net/http"
+	"os"
 	"time"
 )
 
-const testUserTableName = "TestUsers"
-const testSpendingTableName = "TestSpending"
+// testRepositories are the repositories the tests run against. They keep
+// the data in memory, unless the TEST_STORAGE environment variable selects
+// another storage, such as `dynamodb` to run against the tables prefixed
+// with `Test`, `postgres` to run against the database at the URL set in the
+// TEST_DATABASE_URL environment variable, or `sqlite` to run against the
+// database file at the path set in the TEST_SQLITE_PATH environment
+// variable.
+var testRepositories = app.SetupRepositories(context.Background(), app.Config{
+	Storage:             getTestStorage(),
+	DatabaseURL:         os.Getenv("TEST_DATABASE_URL"),
+	SQLitePath:          os.Getenv("TEST_SQLITE_PATH"),
+	DynamoDBTablePrefix: "Test",
+})
 
-func setupTestDB(tableName string) *helper.DynamoDB {
-	client := app.SetupClient(context.TODO())
-	db := &helper.DynamoDB{Client: client}
-	db.TableName = tableName
+// testBlobStore keeps the files attached to the spendings in a temporary
+// directory.
+var testBlobStore = repository.NewBlobStoreLocal(createTempDir())
 
-	if tableName == testUserTableName {
-		app.CreateTable(context.Background(), db, app.CreateTableUser)
+// testAttachmentMaxSize is the largest size in bytes of a file attached to
+// a spending in the tests.
+const testAttachmentMaxSize = 64 << 10
+
+// testAdminId is the id of the administrator of the tests, allowed to change
+// the exchange rates.
+const testAdminId = "0b5c3a8e-4d2f-4e6a-9c1b-7f0e2d3c4b5a"
+
+var testTokenManager = helper.NewHS256TokenManager([]byte("test-secret"), time.Minute)
+
+func createTempDir() string {
+	dir, err := os.MkdirTemp("", "duit-attachments-")
+	if err != nil {
+		panic(err)
 	}
-	if tableName == testSpendingTableName {
-		app.CreateTable(context.Background(), db, app.CreateTableSpending)
+	return dir
+}
+
+func getTestStorage() string {
+	if storage := os.Getenv("TEST_STORAGE"); storage != "" {
+		return storage
 	}
-	return db
+	return app.StorageMemory
 }
 
-func setupRouter(db *helper.DynamoDB) http.Handler {
-	validate := validator.New()
+func setupRouter() http.Handler {
+	return setupRouterWithPolicy(service.OwnershipPolicyNotFound)
+}
+
+// setupRouterWithPolicy sets up the router whose services respond to an
+// access of another user's data according to the given policy.
+func setupRouterWithPolicy(policy service.OwnershipPolicy) http.Handler {
+	validate := app.NewValidator()
+	translator := app.NewTranslator(validate)
 
-	userRepository := repository.NewUserRepository()
-	userService := service.NewUserService(userRepository, db, validate)
+	userService := service.NewUserService(testRepositories.User, testRepositories.Category, validate, policy)
 	userController := controller.NewUserController(userService)
 
-	spendingRepository := repository.NewSpendingRepository()
-	spendingService := service.NewSpendingService(spendingRepository, db, validate)
+	spendingService := service.NewSpendingService(testRepositories.Spending, testRepositories.Category, testRepositories.User, testRepositories.ExchangeRate, testRepositories.Account, testRepositories.Attachment, testBlobStore, validate, policy, []byte("test-secret"))
 	spendingController := controller.NewSpendingController(spendingService)
 
+	sessionService := service.NewSessionService(testRepositories.Session, policy)
+	sessionController := controller.NewSessionController(sessionService)
+
+	reportService := service.NewReportService(testRepositories.Spending, testRepositories.User, testRepositories.ExchangeRate, validate, policy)
+	reportController := controller.NewReportController(reportService)
+
+	budgetService := service.NewBudgetService(testRepositories.Budget, testRepositories.Spending, testRepositories.User, testRepositories.ExchangeRate, validate, policy)
+	budgetController := controller.NewBudgetController(budgetService)
+
+	categoryService := service.NewCategoryService(testRepositories.Category, testRepositories.Spending, testRepositories.Budget, testRepositories.RecurringSpending, validate, policy)
+	categoryController := controller.NewCategoryController(categoryService)
+
+	exchangeRateService := service.NewExchangeRateService(testRepositories.ExchangeRate, validate, service.NewAdminPolicy([]string{testAdminId}))
+	exchangeRateController := controller.NewExchangeRateController(exchangeRateService)
+
+	recurringSpendingService := service.NewRecurringSpendingService(testRepositories.RecurringSpending, testRepositories.Spending, testRepositories.Category, testRepositories.User, validate, policy)
+	recurringSpendingController := controller.NewRecurringSpendingController(recurringSpendingService)
+
+	accountService := service.NewAccountService(testRepositories.Account, testRepositories.Transfer, testRepositories.Spending, validate, policy)
+	accountController := controller.NewAccountController(accountService)
+
+	attachmentService := service.NewAttachmentService(testRepositories.Attachment, testRepositories.Spending, testBlobStore, validate, policy, testAttachmentMaxSize)
+	attachmentController := controller.NewAttachmentController(attachmentService, testAttachmentMaxSize)
+
+	authService := service.NewAuthService(testRepositories.User, testRepositories.Session, validate, testTokenManager, time.Hour)
+	authController := controller.NewAuthController(authService)
+
 	registerRouter := app.Router{
-		UserController:     userController,
-		SpendingController: spendingController,
+		UserController:              userController,
+		SpendingController:          spendingController,
+		AuthController:              authController,
+		SessionController:           sessionController,
+		ReportController:            reportController,
+		BudgetController:            budgetController,
+		CategoryController:          categoryController,
+		ExchangeRateController:      exchangeRateController,
+		RecurringSpendingController: recurringSpendingController,
+		AccountController:           accountController,
+		AttachmentController:        attachmentController,
 	}
 	router := registerRouter.NewRouter()
-	return router
+	authMiddleware := middleware.NewAuthMiddleware(router, testTokenManager, app.PublicRoutes...)
+	return middleware.NewLocaleMiddleware(authMiddleware, translator, testRepositories.User)
+}
+
+// authorize sets the Authorization header of the request with an access
+// token issued for the given user id.
+func authorize(request *http.Request, userId string) {
+	token, _ := testTokenManager.Issue(userId)
+	request.Header.Set("Authorization", "Bearer "+token)
+}
+
+func clearUserDataAfterTest(id string) {
+	testRepositories.User.Delete(context.Background(), domain.User{
+		Id: id,
+	})
+}
+
+func clearBudgetDataAfterTest(id string) {
+	testRepositories.Budget.Delete(context.Background(), domain.Budget{
+		Id: id,
+	})
 }
 
-func clearUserDataAfterTest(db *helper.DynamoDB, id string) {
-	userRepository := repository.NewUserRepository()
-	userRepository.Delete(context.Background(), db, domain.User{
+func clearRecurringSpendingDataAfterTest(id string) {
+	testRepositories.RecurringSpending.Delete(context.Background(), domain.RecurringSpending{
 		Id: id,
 	})
 }
 
-func clearSpendingDataAfterTest(db *helper.DynamoDB, id string) {
-	spendingRepository := repository.NewSpendingRepository()
-	spendingRepository.Delete(context.Background(), db, domain.Spending{
+func clearAccountDataAfterTest(id string) {
+	testRepositories.Account.Delete(context.Background(), domain.Account{
+		Id: id,
+	})
+}
+
+func clearTransferDataAfterTest(id string) {
+	testRepositories.Transfer.Delete(context.Background(), domain.Transfer{
+		Id: id,
+	})
+}
+
+func clearAttachmentDataAfterTest(attachment domain.Attachment) {
+	testBlobStore.Delete(context.Background(), attachment.BlobKey())
+	testRepositories.Attachment.Delete(context.Background(), attachment)
+}
+
+func clearSpendingDataAfterTest(id string) {
+	testRepositories.Spending.Delete(context.Background(), domain.Spending{
 		Id: id,
 	})
 }
 
 // createUser creates a user then return the user's data
-func createUser(db *helper.DynamoDB) domain.User {
-	userRepository := repository.NewUserRepository()
+func createUser() domain.User {
 	userId, _ := uuid.NewRandom()
 	password := []byte("secret")
 	hashedPassword, err := bcrypt.GenerateFromPassword(password, bcrypt.DefaultCost)
@@ -75,19 +177,19 @@ func createUser(db *helper.DynamoDB) domain.User {
 		panic(err)
 	}
 
-	user := userRepository.Save(context.Background(), db, domain.User{
+	user, _ := testRepositories.User.Save(context.Background(), domain.User{
 		Id:        userId.String(),
 		Name:      "Test User",
 		Email:     "test@example.com",
 		Password:  string(hashedPassword),
 		CreatedAt: time.Now().UnixMilli(),
 	})
+	createCategories(user.Id)
 	return user
 }
 
 // createUser creates three users then return the user's data
-func createUsers(db *helper.DynamoDB) []domain.User {
-	userRepository := repository.NewUserRepository()
+func createUsers() []domain.User {
 	password := []byte("secret")
 	hashedPassword, err := bcrypt.GenerateFromPassword(password, bcrypt.DefaultCost)
 	if err != nil {
@@ -118,21 +220,41 @@ func createUsers(db *helper.DynamoDB) []domain.User {
 	for i := 0; i < 3; i++ {
 		userId, _ := uuid.NewRandom()
 		users[i].Id = userId.String()
-		users[i] = userRepository.Save(context.Background(), db, users[i])
+		users[i], _ = testRepositories.User.Save(context.Background(), users[i])
+		createCategories(users[i].Id)
 	}
 	return users
 }
 
-func createSpending(db *helper.DynamoDB, userId string) domain.Spending {
-	spendingRepository := repository.NewSpendingRepository()
+// createCategories creates the default categories of the user then return
+// the categories' data
+func createCategories(userId string) []domain.Category {
+	categories := domain.DefaultCategories()
+	for i := range categories {
+		categoryId, _ := uuid.NewRandom()
+		categories[i].Id = categoryId.String()
+		categories[i].UserId = userId
+		categories[i].CreatedAt = time.Now().UnixMilli()
+		categories[i], _ = testRepositories.Category.Save(context.Background(), categories[i])
+	}
+	return categories
+}
+
+func createSpending(userId string) domain.Spending {
+	return createSpendingOn(userId, 1701795600000)
+}
+
+// createSpendingOn creates a spending dated on the given date in Unix time
+// format then return the spending's data
+func createSpendingOn(userId string, date int64) domain.Spending {
 	spendingId, _ := uuid.NewRandom()
 
-	spending := spendingRepository.Save(context.Background(), db, domain.Spending{
+	spending, _ := testRepositories.Spending.Save(context.Background(), domain.Spending{
 		Id:          spendingId.String(),
 		UserId:      userId,
 		Title:       "Makan malam",
-		Date:        1701795600000,
-		Amount:      50000,
+		Date:        date,
+		Amount:      domain.Money{Minor: 5000000, Currency: "IDR"},
 		Category:    "food",
 		Description: "Makan malam dengan sate kambing",
 		CreatedAt:   time.Now().UnixMilli(),
@@ -140,15 +262,33 @@ func createSpending(db *helper.DynamoDB, userId string) domain.Spending {
 	return spending
 }
 
-func createSpendings(db *helper.DynamoDB, userId string) []domain.Spending {
-	spendingRepository := repository.NewSpendingRepository()
+// createIncomeOn creates an income of the given amount in minor units of IDR
+// dated on the given date in Unix time format then return the income's data
+func createIncomeOn(userId string, date int64, amount int64) domain.Spending {
+	spendingId, _ := uuid.NewRandom()
+
+	spending, _ := testRepositories.Spending.Save(context.Background(), domain.Spending{
+		Id:          spendingId.String(),
+		UserId:      userId,
+		Title:       "Gaji",
+		Date:        date,
+		Amount:      domain.Money{Minor: amount, Currency: "IDR"},
+		Category:    "other",
+		Type:        domain.SpendingTypeIncome,
+		Description: "Gaji bulanan",
+		CreatedAt:   time.Now().UnixMilli(),
+	})
+	return spending
+}
+
+func createSpendings(userId string) []domain.Spending {
 
 	spendings := []domain.Spending{
 		domain.Spending{
 			UserId:      userId,
 			Title:       "Makan malam",
 			Date:        1702141200000,
-			Amount:      25000,
+			Amount:      domain.Money{Minor: 2500000, Currency: "IDR"},
 			Category:    "food",
 			Description: "Makan malam dengan ayam bakar",
 			CreatedAt:   time.Now().UnixMilli(),
@@ -157,7 +297,7 @@ func createSpendings(db *helper.DynamoDB, userId string) []domain.Spending {
 			UserId:      userId,
 			Title:       "Makan malam",
 			Date:        1702227600000,
-			Amount:      35000,
+			Amount:      domain.Money{Minor: 3500000, Currency: "IDR"},
 			Category:    "food",
 			Description: "Makan malam dengan nasi goreng",
 			CreatedAt:   time.Now().UnixMilli(),
@@ -166,7 +306,7 @@ func createSpendings(db *helper.DynamoDB, userId string) []domain.Spending {
 			UserId:      userId,
 			Title:       "Makan malam",
 			Date:        1702314000000,
-			Amount:      50000,
+			Amount:      domain.Money{Minor: 5000000, Currency: "IDR"},
 			Category:    "food",
 			Description: "Makan malam dengan sate kambing",
 			CreatedAt:   time.Now().UnixMilli(),
@@ -176,7 +316,7 @@ func createSpendings(db *helper.DynamoDB, userId string) []domain.Spending {
 	for i := 0; i < 3; i++ {
 		spendingId, _ := uuid.NewRandom()
 		spendings[i].Id = spendingId.String()
-		spendings[i] = spendingRepository.Save(context.Background(), db, spendings[i])
+		spendings[i], _ = testRepositories.Spending.Save(context.Background(), spendings[i])
 	}
 	return spendings
 }
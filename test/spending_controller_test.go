@@ -1,23 +1,25 @@
 package test
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
+	"github.com/google/uuid"
+	"github.com/refandas/duit-api/model/domain"
 	"github.com/stretchr/testify/assert"
 	"io"
 	"net/http"
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 )
 
 func TestCreateSpendingSuccess(t *testing.T) {
-	userDb := setupTestDB(testUserTableName)
-	spendingDb := setupTestDB(testSpendingTableName)
-	router := setupRouter(spendingDb)
+	router := setupRouter()
 
-	user := createUser(userDb)
-	defer clearUserDataAfterTest(userDb, user.Id)
+	user := createUser()
+	defer clearUserDataAfterTest(user.Id)
 
 	// Create the spending data
 	jsonData := `
@@ -34,6 +36,7 @@ func TestCreateSpendingSuccess(t *testing.T) {
 
 	requestBody := strings.NewReader(jsonData)
 	request := httptest.NewRequest(http.MethodPost, "http://localhost:8000/api/v1/spendings", requestBody)
+	authorize(request, user.Id)
 	request.Header.Add("Content-Type", "application/json")
 
 	// Apply mock testing
@@ -54,7 +57,7 @@ func TestCreateSpendingSuccess(t *testing.T) {
 		panic(err)
 	}
 	spendingId := responseBody["data"].(map[string]interface{})["id"]
-	defer clearSpendingDataAfterTest(spendingDb, spendingId.(string))
+	defer clearSpendingDataAfterTest(spendingId.(string))
 
 	assert.Equal(t, http.StatusCreated, int(responseBody["code"].(float64)))
 	assert.Equal(t, "CREATED", responseBody["status"])
@@ -67,12 +70,10 @@ func TestCreateSpendingSuccess(t *testing.T) {
 }
 
 func TestCreateSpendingFailed(t *testing.T) {
-	userDb := setupTestDB(testUserTableName)
-	spendingDb := setupTestDB(testSpendingTableName)
-	router := setupRouter(spendingDb)
+	router := setupRouter()
 
-	user := createUser(userDb)
-	defer clearUserDataAfterTest(userDb, user.Id)
+	user := createUser()
+	defer clearUserDataAfterTest(user.Id)
 
 	// Create the spending data
 	jsonData := `
@@ -89,6 +90,7 @@ func TestCreateSpendingFailed(t *testing.T) {
 
 	requestBody := strings.NewReader(jsonData)
 	request := httptest.NewRequest(http.MethodPost, "http://localhost:8000/api/v1/spendings", requestBody)
+	authorize(request, user.Id)
 	request.Header.Add("Content-Type", "application/json")
 
 	// Apply mock testing
@@ -109,21 +111,61 @@ func TestCreateSpendingFailed(t *testing.T) {
 		panic(err)
 	}
 
-	assert.Equal(t, http.StatusBadRequest, int(responseBody["code"].(float64)))
-	assert.Equal(t, "BAD REQUEST", responseBody["status"])
+	assert.Equal(t, http.StatusBadRequest, int(responseBody["status"].(float64)))
+	assert.Equal(t, "validation_failed", responseBody["code"])
 }
 
-func TestUpdateSpendingSuccess(t *testing.T) {
-	userDb := setupTestDB(testUserTableName)
-	spendingDb := setupTestDB(testSpendingTableName)
+func TestCreateSpendingAmount(t *testing.T) {
+	router := setupRouter()
+
+	user := createUser()
+	defer clearUserDataAfterTest(user.Id)
+
+	tests := []struct {
+		amount   string
+		currency string
+		status   int
+		expected string
+	}{
+		{"12.34", "USD", http.StatusOK, `"amount":12.34,"currency":"USD"`},
+		{"0.1", "", http.StatusOK, `"amount":0.10,"currency":"IDR"`},
+		{"1500", "JPY", http.StatusOK, `"amount":1500,"currency":"JPY"`},
+		{"1.005", "KWD", http.StatusOK, `"amount":1.005,"currency":"KWD"`},
+		{"12.345", "USD", http.StatusBadRequest, "amount must be a positive amount with at most 2 decimal places"},
+		{"1500.5", "JPY", http.StatusBadRequest, "amount must be a positive amount with at most 0 decimal places"},
+		{"-5", "USD", http.StatusBadRequest, "amount must be a positive amount with at most 2 decimal places"},
+		{"0", "USD", http.StatusBadRequest, "amount must be a positive amount with at most 2 decimal places"},
+		{"10", "XYZ", http.StatusBadRequest, "the request has invalid fields"},
+	}
+	for _, test := range tests {
+		jsonData := fmt.Sprintf(`{"user_id": "%s", "amount": %s, "currency": "%s", "date": 1701795600000, "category": "food", "title": "Makan malam"}`,
+			user.Id, test.amount, test.currency)
+		request := httptest.NewRequest(http.MethodPost, "http://localhost:8000/api/v1/spendings", strings.NewReader(jsonData))
+		authorize(request, user.Id)
+		request.Header.Add("Content-Type", "application/json")
+
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, request)
+
+		body, _ := io.ReadAll(recorder.Result().Body)
+		assert.Equal(t, test.status, recorder.Code, test.amount+" "+test.currency)
+		assert.Contains(t, string(body), test.expected)
+
+		var responseBody map[string]interface{}
+		if err := json.Unmarshal(body, &responseBody); err == nil && recorder.Code == http.StatusOK {
+			clearSpendingDataAfterTest(responseBody["data"].(map[string]interface{})["id"].(string))
+		}
+	}
+}
 
-	router := setupRouter(spendingDb)
+func TestUpdateSpendingSuccess(t *testing.T) {
+	router := setupRouter()
 
-	user := createUser(userDb)
-	defer clearUserDataAfterTest(userDb, user.Id)
+	user := createUser()
+	defer clearUserDataAfterTest(user.Id)
 
-	spending := createSpending(spendingDb, user.Id)
-	defer clearSpendingDataAfterTest(spendingDb, spending.Id)
+	spending := createSpending(user.Id)
+	defer clearSpendingDataAfterTest(spending.Id)
 
 	jsonData := `
 	{
@@ -142,6 +184,7 @@ func TestUpdateSpendingSuccess(t *testing.T) {
 
 	requestBody := strings.NewReader(jsonData)
 	request := httptest.NewRequest(http.MethodPut, "http://localhost:8000/api/v1/spendings/"+spending.Id, requestBody)
+	authorize(request, user.Id)
 	request.Header.Add("Content-Type", "application/json")
 
 	recorder := httptest.NewRecorder()
@@ -172,16 +215,13 @@ func TestUpdateSpendingSuccess(t *testing.T) {
 }
 
 func TestUpdateSpendingFailed(t *testing.T) {
-	userDb := setupTestDB(testUserTableName)
-	spendingDb := setupTestDB(testSpendingTableName)
-
-	router := setupRouter(spendingDb)
+	router := setupRouter()
 
-	user := createUser(userDb)
-	defer clearUserDataAfterTest(userDb, user.Id)
+	user := createUser()
+	defer clearUserDataAfterTest(user.Id)
 
-	spending := createSpending(spendingDb, user.Id)
-	defer clearSpendingDataAfterTest(spendingDb, spending.Id)
+	spending := createSpending(user.Id)
+	defer clearSpendingDataAfterTest(spending.Id)
 
 	jsonData := `
 	{
@@ -200,6 +240,7 @@ func TestUpdateSpendingFailed(t *testing.T) {
 
 	requestBody := strings.NewReader(jsonData)
 	request := httptest.NewRequest(http.MethodPut, "http://localhost:8000/api/v1/spendings/"+spending.Id, requestBody)
+	authorize(request, user.Id)
 	request.Header.Add("Content-Type", "application/json")
 
 	recorder := httptest.NewRecorder()
@@ -219,26 +260,24 @@ func TestUpdateSpendingFailed(t *testing.T) {
 		panic(err)
 	}
 
-	assert.Equal(t, http.StatusBadRequest, int(responseBody["code"].(float64)))
-	assert.Equal(t, "BAD REQUEST", responseBody["status"])
+	assert.Equal(t, http.StatusBadRequest, int(responseBody["status"].(float64)))
+	assert.Equal(t, "validation_failed", responseBody["code"])
 }
 
 func TestGetSpendingSuccess(t *testing.T) {
-	userDb := setupTestDB(testUserTableName)
-	spendingDb := setupTestDB(testSpendingTableName)
-
 	// create user data
-	user := createUser(userDb)
-	defer clearUserDataAfterTest(userDb, user.Id)
+	user := createUser()
+	defer clearUserDataAfterTest(user.Id)
 
 	// create user's spending data
-	spending := createSpending(spendingDb, user.Id)
-	defer clearSpendingDataAfterTest(spendingDb, spending.Id)
+	spending := createSpending(user.Id)
+	defer clearSpendingDataAfterTest(spending.Id)
 
 	request := httptest.NewRequest(http.MethodGet, "http://localhost:8000/api/v1/spendings/"+spending.Id, nil)
+	authorize(request, user.Id)
 	recorder := httptest.NewRecorder()
 
-	router := setupRouter(spendingDb)
+	router := setupRouter()
 	router.ServeHTTP(recorder, request)
 
 	response := recorder.Result()
@@ -266,12 +305,11 @@ func TestGetSpendingSuccess(t *testing.T) {
 }
 
 func TestGetSpendingFailed(t *testing.T) {
-	spendingDb := setupTestDB(testSpendingTableName)
-
 	request := httptest.NewRequest(http.MethodGet, "http://localhost:8000/api/v1/spendings/100", nil)
+	authorize(request, "404")
 	recorder := httptest.NewRecorder()
 
-	router := setupRouter(spendingDb)
+	router := setupRouter()
 	router.ServeHTTP(recorder, request)
 
 	response := recorder.Result()
@@ -288,27 +326,25 @@ func TestGetSpendingFailed(t *testing.T) {
 		panic(err)
 	}
 
-	assert.Equal(t, http.StatusNotFound, int(responseBody["code"].(float64)))
-	assert.Equal(t, "NOT FOUND", responseBody["status"])
+	assert.Equal(t, http.StatusNotFound, int(responseBody["status"].(float64)))
+	assert.Equal(t, "not_found", responseBody["code"])
 }
 
 // The route to be tested is /api/v1/{user_id}/spendings
 func TestGetListOfUserSpendingSuccess(t *testing.T) {
-	userDb := setupTestDB(testUserTableName)
-	spendingDb := setupTestDB(testSpendingTableName)
-
-	user := createUser(userDb)
-	defer clearUserDataAfterTest(userDb, user.Id)
+	user := createUser()
+	defer clearUserDataAfterTest(user.Id)
 
-	spendings := createSpendings(spendingDb, user.Id)
-	defer clearSpendingDataAfterTest(spendingDb, spendings[0].Id)
-	defer clearSpendingDataAfterTest(spendingDb, spendings[1].Id)
-	defer clearSpendingDataAfterTest(spendingDb, spendings[2].Id)
+	spendings := createSpendings(user.Id)
+	defer clearSpendingDataAfterTest(spendings[0].Id)
+	defer clearSpendingDataAfterTest(spendings[1].Id)
+	defer clearSpendingDataAfterTest(spendings[2].Id)
 
 	request := httptest.NewRequest(http.MethodGet, "http://localhost:8000/api/v1/users/"+user.Id+"/spendings", nil)
+	authorize(request, user.Id)
 	recorder := httptest.NewRecorder()
 
-	router := setupRouter(spendingDb)
+	router := setupRouter()
 	router.ServeHTTP(recorder, request)
 
 	response := recorder.Result()
@@ -335,7 +371,8 @@ func TestGetListOfUserSpendingSuccess(t *testing.T) {
 	assert.Equal(t, spendings[0].Title, spendingResponse1["title"])
 	assert.Equal(t, spendings[0].Description, spendingResponse1["description"])
 	assert.Equal(t, spendings[0].Category, spendingResponse1["category"])
-	assert.Equal(t, spendings[0].Amount, spendingResponse1["amount"])
+	assert.Equal(t, float64(25000), spendingResponse1["amount"])
+	assert.Equal(t, spendings[0].Amount.Currency, spendingResponse1["currency"])
 	assert.Equal(t, spendings[0].Date, int64(spendingResponse1["date"].(float64)))
 
 	assert.Equal(t, spendings[1].Id, spendingResponse2["id"])
@@ -343,7 +380,8 @@ func TestGetListOfUserSpendingSuccess(t *testing.T) {
 	assert.Equal(t, spendings[1].Title, spendingResponse2["title"])
 	assert.Equal(t, spendings[1].Description, spendingResponse2["description"])
 	assert.Equal(t, spendings[1].Category, spendingResponse2["category"])
-	assert.Equal(t, spendings[1].Amount, spendingResponse2["amount"])
+	assert.Equal(t, float64(35000), spendingResponse2["amount"])
+	assert.Equal(t, spendings[1].Amount.Currency, spendingResponse2["currency"])
 	assert.Equal(t, spendings[1].Date, int64(spendingResponse2["date"].(float64)))
 
 	assert.Equal(t, spendings[2].Id, spendingResponse3["id"])
@@ -351,7 +389,8 @@ func TestGetListOfUserSpendingSuccess(t *testing.T) {
 	assert.Equal(t, spendings[2].Title, spendingResponse3["title"])
 	assert.Equal(t, spendings[2].Description, spendingResponse3["description"])
 	assert.Equal(t, spendings[2].Category, spendingResponse3["category"])
-	assert.Equal(t, spendings[2].Amount, spendingResponse3["amount"])
+	assert.Equal(t, float64(50000), spendingResponse3["amount"])
+	assert.Equal(t, spendings[2].Amount.Currency, spendingResponse3["currency"])
 	assert.Equal(t, spendings[2].Date, int64(spendingResponse3["date"].(float64)))
 }
 
@@ -359,12 +398,11 @@ func TestGetListOfUserSpendingSuccess(t *testing.T) {
 // but the user's id is not found.
 // The route to be tested is /api/v1/{user_id}/spendings
 func TestGetListOfUserSpendingFailed(t *testing.T) {
-	spendingDb := setupTestDB(testSpendingTableName)
-
 	request := httptest.NewRequest(http.MethodGet, "http://localhost:8000/api/v1/users/404/spendings", nil)
+	authorize(request, "404")
 	recorder := httptest.NewRecorder()
 
-	router := setupRouter(spendingDb)
+	router := setupRouter()
 	router.ServeHTTP(recorder, request)
 
 	response := recorder.Result()
@@ -377,27 +415,25 @@ func TestGetListOfUserSpendingFailed(t *testing.T) {
 		panic(err)
 	}
 
-	assert.Equal(t, http.StatusNotFound, int(responseBody["code"].(float64)))
-	assert.Equal(t, "NOT FOUND", responseBody["status"])
+	assert.Equal(t, http.StatusNotFound, int(responseBody["status"].(float64)))
+	assert.Equal(t, "not_found", responseBody["code"])
 }
 
 func TestDeleteSpendingSuccess(t *testing.T) {
-	userDb := setupTestDB(testUserTableName)
-	spendingDb := setupTestDB(testSpendingTableName)
-
 	// create user data
-	user := createUser(userDb)
-	defer clearUserDataAfterTest(userDb, user.Id)
+	user := createUser()
+	defer clearUserDataAfterTest(user.Id)
 
 	// create user's spending data
-	spending := createSpending(spendingDb, user.Id)
-	defer clearSpendingDataAfterTest(spendingDb, spending.Id)
+	spending := createSpending(user.Id)
+	defer clearSpendingDataAfterTest(spending.Id)
 
 	request := httptest.NewRequest(http.MethodDelete, "http://localhost:8000/api/v1/spendings/"+spending.Id, nil)
+	authorize(request, user.Id)
 	request.Header.Add("Content-Type", "application/json")
 	recorder := httptest.NewRecorder()
 
-	router := setupRouter(spendingDb)
+	router := setupRouter()
 	router.ServeHTTP(recorder, request)
 
 	response := recorder.Result()
@@ -419,13 +455,97 @@ func TestDeleteSpendingSuccess(t *testing.T) {
 }
 
 func TestDeleteSpendingFailed(t *testing.T) {
-	spendingDb := setupTestDB(testSpendingTableName)
-
 	request := httptest.NewRequest(http.MethodDelete, "http://localhost:8000/api/v1/spendings/100", nil)
+	authorize(request, "404")
+	request.Header.Add("Content-Type", "application/json")
+	recorder := httptest.NewRecorder()
+
+	router := setupRouter()
+	router.ServeHTTP(recorder, request)
+
+	response := recorder.Result()
+	assert.Equal(t, http.StatusNotFound, response.StatusCode)
+
+	body, err := io.ReadAll(response.Body)
+	if err != nil {
+		panic(err)
+	}
+
+	var responseBody map[string]interface{}
+	err = json.Unmarshal(body, &responseBody)
+	if err != nil {
+		panic(err)
+	}
+
+	assert.Equal(t, http.StatusNotFound, int(responseBody["status"].(float64)))
+	assert.Equal(t, "not_found", responseBody["code"])
+}
+
+// TestCreateSpendingForAnotherUserSuccess test to create a spending with
+// the id of another user in the request body, which is ignored in favor
+// of the authenticated user.
+func TestCreateSpendingForAnotherUserSuccess(t *testing.T) {
+	router := setupRouter()
+
+	users := createUsers()
+	defer clearUserDataAfterTest(users[0].Id)
+	defer clearUserDataAfterTest(users[1].Id)
+	defer clearUserDataAfterTest(users[2].Id)
+
+	jsonData := `
+	{
+		"user_id": "%s",
+		"amount": 50000,
+		"date": 1701795600000,
+		"category": "food",
+		"title": "Makan malam",
+		"description": "Makan malam dengan sate kambing"
+	}
+`
+	jsonData = fmt.Sprintf(jsonData, users[1].Id)
+
+	requestBody := strings.NewReader(jsonData)
+	request := httptest.NewRequest(http.MethodPost, "http://localhost:8000/api/v1/spendings", requestBody)
+	authorize(request, users[0].Id)
 	request.Header.Add("Content-Type", "application/json")
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	response := recorder.Result()
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+
+	body, err := io.ReadAll(response.Body)
+	if err != nil {
+		panic(err)
+	}
+
+	var responseBody map[string]interface{}
+	err = json.Unmarshal(body, &responseBody)
+	if err != nil {
+		panic(err)
+	}
+	spendingId := responseBody["data"].(map[string]interface{})["id"]
+	defer clearSpendingDataAfterTest(spendingId.(string))
+
+	assert.Equal(t, http.StatusCreated, int(responseBody["code"].(float64)))
+	assert.Equal(t, users[0].Id, responseBody["data"].(map[string]interface{})["user_id"])
+}
+
+func TestGetSpendingOfAnotherUserFailed(t *testing.T) {
+	users := createUsers()
+	defer clearUserDataAfterTest(users[0].Id)
+	defer clearUserDataAfterTest(users[1].Id)
+	defer clearUserDataAfterTest(users[2].Id)
+
+	spending := createSpending(users[1].Id)
+	defer clearSpendingDataAfterTest(spending.Id)
+
+	request := httptest.NewRequest(http.MethodGet, "http://localhost:8000/api/v1/spendings/"+spending.Id, nil)
+	authorize(request, users[0].Id)
 	recorder := httptest.NewRecorder()
 
-	router := setupRouter(spendingDb)
+	router := setupRouter()
 	router.ServeHTTP(recorder, request)
 
 	response := recorder.Result()
@@ -442,6 +562,303 @@ func TestDeleteSpendingFailed(t *testing.T) {
 		panic(err)
 	}
 
-	assert.Equal(t, http.StatusNotFound, int(responseBody["code"].(float64)))
-	assert.Equal(t, "NOT FOUND", responseBody["status"])
+	assert.Equal(t, http.StatusNotFound, int(responseBody["status"].(float64)))
+	assert.Equal(t, "not_found", responseBody["code"])
+}
+
+// TestGetListOfUserSpendingPaginationSuccess test to list the user's
+// spending data page by page following the next cursor.
+func TestGetListOfUserSpendingPaginationSuccess(t *testing.T) {
+	user := createUser()
+	defer clearUserDataAfterTest(user.Id)
+
+	spendings := createSpendings(user.Id)
+	defer clearSpendingDataAfterTest(spendings[0].Id)
+	defer clearSpendingDataAfterTest(spendings[1].Id)
+	defer clearSpendingDataAfterTest(spendings[2].Id)
+
+	router := setupRouter()
+
+	request := httptest.NewRequest(http.MethodGet, "http://localhost:8000/api/v1/users/"+user.Id+"/spendings?limit=2", nil)
+	authorize(request, user.Id)
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	response := recorder.Result()
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+
+	var responseBody map[string]interface{}
+	err := json.NewDecoder(response.Body).Decode(&responseBody)
+	if err != nil {
+		panic(err)
+	}
+
+	spendingResponses := responseBody["data"].([]interface{})
+	pagination := responseBody["pagination"].(map[string]interface{})
+	assert.Len(t, spendingResponses, 2)
+	assert.Equal(t, spendings[0].Id, spendingResponses[0].(map[string]interface{})["id"])
+	assert.Equal(t, spendings[1].Id, spendingResponses[1].(map[string]interface{})["id"])
+	assert.Equal(t, true, pagination["has_more"])
+
+	nextCursor := pagination["next_cursor"].(string)
+	request = httptest.NewRequest(http.MethodGet, "http://localhost:8000/api/v1/users/"+user.Id+"/spendings?limit=2&cursor="+nextCursor, nil)
+	authorize(request, user.Id)
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	response = recorder.Result()
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+
+	err = json.NewDecoder(response.Body).Decode(&responseBody)
+	if err != nil {
+		panic(err)
+	}
+
+	spendingResponses = responseBody["data"].([]interface{})
+	pagination = responseBody["pagination"].(map[string]interface{})
+	assert.Len(t, spendingResponses, 1)
+	assert.Equal(t, spendings[2].Id, spendingResponses[0].(map[string]interface{})["id"])
+	assert.Equal(t, false, pagination["has_more"])
+}
+
+// TestGetListOfUserSpendingInvalidCursorFailed test to list the user's
+// spending data with a tampered cursor.
+func TestGetListOfUserSpendingInvalidCursorFailed(t *testing.T) {
+	request := httptest.NewRequest(http.MethodGet, "http://localhost:8000/api/v1/users/404/spendings?cursor=tampered", nil)
+	authorize(request, "404")
+	recorder := httptest.NewRecorder()
+
+	router := setupRouter()
+	router.ServeHTTP(recorder, request)
+
+	response := recorder.Result()
+	assert.Equal(t, http.StatusBadRequest, response.StatusCode)
+
+	body, _ := io.ReadAll(response.Body)
+	var responseBody map[string]interface{}
+	err := json.Unmarshal(body, &responseBody)
+	if err != nil {
+		panic(err)
+	}
+
+	assert.Equal(t, http.StatusBadRequest, int(responseBody["status"].(float64)))
+	assert.Equal(t, "validation_failed", responseBody["code"])
+}
+
+// TestGetListOfUserSpendingFilterSuccess test to list the user's spending
+// data narrowed down by the query parameters.
+func TestGetListOfUserSpendingFilterSuccess(t *testing.T) {
+	user := createUser()
+	defer clearUserDataAfterTest(user.Id)
+
+	spendings := createSpendings(user.Id)
+	defer clearSpendingDataAfterTest(spendings[0].Id)
+	defer clearSpendingDataAfterTest(spendings[1].Id)
+	defer clearSpendingDataAfterTest(spendings[2].Id)
+
+	// The amount of a spending in another currency is not compared with
+	// the amount filters in IDR, even though its minor units are larger.
+	usdSpending, _ := testRepositories.Spending.Save(context.Background(), domain.Spending{
+		Id:        uuid.NewString(),
+		UserId:    user.Id,
+		Title:     "Hotel",
+		Date:      1702400400000,
+		Amount:    domain.Money{Minor: 5000000, Currency: "USD"},
+		Category:  "other",
+		CreatedAt: time.Now().UnixMilli(),
+	})
+	defer clearSpendingDataAfterTest(usdSpending.Id)
+
+	router := setupRouter()
+
+	tests := []struct {
+		query    string
+		expected []string
+	}{
+		{"min_amount=30000&order=desc", []string{spendings[2].Id, spendings[1].Id}},
+		{"min_amount=30000&currency=USD", []string{usdSpending.Id}},
+		{"currency=USD", []string{usdSpending.Id}},
+		{"max_amount=30000&category=food", []string{spendings[0].Id}},
+		{"from=1702227600000&to=1702314000000", []string{spendings[1].Id, spendings[2].Id}},
+		{"q=nasi", []string{spendings[1].Id}},
+		{"q=NASI%20Goreng", []string{spendings[1].Id}},
+		{"q=hotel", []string{usdSpending.Id}},
+		{"q=makan%20MALAM&order=desc", []string{spendings[2].Id, spendings[1].Id, spendings[0].Id}},
+		{"category=transport", []string{}},
+	}
+
+	for _, test := range tests {
+		request := httptest.NewRequest(http.MethodGet, "http://localhost:8000/api/v1/users/"+user.Id+"/spendings?"+test.query, nil)
+		authorize(request, user.Id)
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, request)
+
+		response := recorder.Result()
+		assert.Equal(t, http.StatusOK, response.StatusCode, test.query)
+
+		var responseBody map[string]interface{}
+		err := json.NewDecoder(response.Body).Decode(&responseBody)
+		if err != nil {
+			panic(err)
+		}
+
+		spendingIds := []string{}
+		for _, spendingResponse := range responseBody["data"].([]interface{}) {
+			spendingIds = append(spendingIds, spendingResponse.(map[string]interface{})["id"].(string))
+		}
+		assert.Equal(t, test.expected, spendingIds, test.query)
+	}
+}
+
+// TestGetListOfUserUpcomingSpendingSuccess test to list the user's spending
+// data dated in the future, which is hidden from the default listing.
+func TestGetListOfUserUpcomingSpendingSuccess(t *testing.T) {
+	user := createUser()
+	defer clearUserDataAfterTest(user.Id)
+
+	postedSpending := createSpending(user.Id)
+	defer clearSpendingDataAfterTest(postedSpending.Id)
+
+	plannedSpending := createSpendingOn(user.Id, time.Now().AddDate(0, 0, 7).UnixMilli())
+	defer clearSpendingDataAfterTest(plannedSpending.Id)
+
+	router := setupRouter()
+
+	tests := []struct {
+		route    string
+		expected []string
+		statuses []string
+	}{
+		{"/spendings", []string{postedSpending.Id}, []string{"posted"}},
+		{"/spendings?include_future=true", []string{postedSpending.Id, plannedSpending.Id}, []string{"posted", "planned"}},
+		{"/spendings/upcoming", []string{plannedSpending.Id}, []string{"planned"}},
+	}
+
+	for _, test := range tests {
+		request := httptest.NewRequest(http.MethodGet, "http://localhost:8000/api/v1/users/"+user.Id+test.route, nil)
+		authorize(request, user.Id)
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, request)
+
+		response := recorder.Result()
+		assert.Equal(t, http.StatusOK, response.StatusCode, test.route)
+
+		var responseBody map[string]interface{}
+		err := json.NewDecoder(response.Body).Decode(&responseBody)
+		if err != nil {
+			panic(err)
+		}
+
+		spendingIds := []string{}
+		statuses := []string{}
+		for _, spendingResponse := range responseBody["data"].([]interface{}) {
+			spendingIds = append(spendingIds, spendingResponse.(map[string]interface{})["id"].(string))
+			statuses = append(statuses, spendingResponse.(map[string]interface{})["status"].(string))
+		}
+		assert.Equal(t, test.expected, spendingIds, test.route)
+		assert.Equal(t, test.statuses, statuses, test.route)
+	}
+}
+
+// TestCreateSpendingUnknownCategoryFailed test to create a spending in a
+// category the user does not have.
+func TestCreateSpendingUnknownCategoryFailed(t *testing.T) {
+	router := setupRouter()
+
+	user := createUser()
+	defer clearUserDataAfterTest(user.Id)
+
+	jsonData := `
+	{
+		"amount": 50000,
+		"date": 1701795600000,
+		"category": "foods",
+		"title": "Makan malam",
+		"description": "Makan malam dengan sate kambing"
+	}
+`
+	requestBody := strings.NewReader(jsonData)
+	request := httptest.NewRequest(http.MethodPost, "http://localhost:8000/api/v1/spendings", requestBody)
+	authorize(request, user.Id)
+	request.Header.Add("Content-Type", "application/json")
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	response := recorder.Result()
+	assert.Equal(t, http.StatusBadRequest, response.StatusCode)
+}
+
+// TestCreateIncomeSuccess test that an income is created with its type,
+// keeps its type when it is updated without one, and is listed by its type.
+func TestCreateIncomeSuccess(t *testing.T) {
+	router := setupRouter()
+
+	user := createUser()
+	defer clearUserDataAfterTest(user.Id)
+
+	requestBody := strings.NewReader(`{"title": "Gaji", "amount": 5000000, "date": 1701795600000, "category": "other", "type": "income"}`)
+	request := httptest.NewRequest(http.MethodPost, "http://localhost:8000/api/v1/spendings", requestBody)
+	authorize(request, user.Id)
+	request.Header.Add("Content-Type", "application/json")
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	var responseBody map[string]interface{}
+	err := json.NewDecoder(recorder.Body).Decode(&responseBody)
+	if err != nil {
+		panic(err)
+	}
+	data := responseBody["data"].(map[string]interface{})
+	defer clearSpendingDataAfterTest(data["id"].(string))
+	assert.Equal(t, http.StatusCreated, int(responseBody["code"].(float64)))
+	assert.Equal(t, "income", data["type"])
+
+	requestBody = strings.NewReader(`{"title": "Gaji dan bonus", "amount": 6000000, "date": 1701795600000, "category": "other"}`)
+	request = httptest.NewRequest(http.MethodPut, "http://localhost:8000/api/v1/spendings/"+data["id"].(string), requestBody)
+	authorize(request, user.Id)
+	request.Header.Add("Content-Type", "application/json")
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	err = json.NewDecoder(recorder.Body).Decode(&responseBody)
+	if err != nil {
+		panic(err)
+	}
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, "income", responseBody["data"].(map[string]interface{})["type"])
+
+	spending := createSpending(user.Id)
+	defer clearSpendingDataAfterTest(spending.Id)
+	tests := []struct {
+		query    string
+		expected []string
+	}{
+		{"type=income", []string{data["id"].(string)}},
+		{"type=expense", []string{spending.Id}},
+	}
+	for _, test := range tests {
+		request = httptest.NewRequest(http.MethodGet, "http://localhost:8000/api/v1/users/"+user.Id+"/spendings?"+test.query, nil)
+		authorize(request, user.Id)
+		recorder = httptest.NewRecorder()
+		router.ServeHTTP(recorder, request)
+
+		err = json.NewDecoder(recorder.Body).Decode(&responseBody)
+		if err != nil {
+			panic(err)
+		}
+		spendingIds := []string{}
+		for _, spendingResponse := range responseBody["data"].([]interface{}) {
+			spendingIds = append(spendingIds, spendingResponse.(map[string]interface{})["id"].(string))
+		}
+		assert.Equal(t, test.expected, spendingIds, test.query)
+	}
+
+	requestBody = strings.NewReader(`{"title": "Gaji", "amount": 5000000, "date": 1701795600000, "category": "other", "type": "refund"}`)
+	request = httptest.NewRequest(http.MethodPost, "http://localhost:8000/api/v1/spendings", requestBody)
+	authorize(request, user.Id)
+	request.Header.Add("Content-Type", "application/json")
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
 }
@@ -0,0 +1,186 @@
+package test
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"github.com/google/uuid"
+	"github.com/refandas/duit-api/model/domain"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// exportSpendings gets the export of the spendings of the user with the
+// given query parameters and returns the response recorder.
+func exportSpendings(userId string, query string) *httptest.ResponseRecorder {
+	router := setupRouter()
+	request := httptest.NewRequest(http.MethodGet, "http://localhost:8000/api/v1/users/"+userId+"/spendings/export?"+query, nil)
+	authorize(request, userId)
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+	return recorder
+}
+
+// TestExportSpendingsCSVSuccess test that the spendings are exported with
+// their dates in the time zone of the user.
+func TestExportSpendingsCSVSuccess(t *testing.T) {
+	user := createUser()
+	defer clearUserDataAfterTest(user.Id)
+	user.TimeZone = "Asia/Jakarta"
+	if _, err := testRepositories.User.Update(context.Background(), user); err != nil {
+		panic(err)
+	}
+
+	spendings := createSpendings(user.Id)
+	for _, spending := range spendings {
+		defer clearSpendingDataAfterTest(spending.Id)
+	}
+
+	recorder := exportSpendings(user.Id, "")
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, "text/csv; charset=utf-8", recorder.Header().Get("Content-Type"))
+	assert.Equal(t, `attachment; filename=spendings.csv`, recorder.Header().Get("Content-Disposition"))
+	assert.Equal(t, "id,date,title,description,category,type,amount,currency,converted_amount,converted_currency,status\n"+
+		spendings[0].Id+",2023-12-10 00:00:00,Makan malam,Makan malam dengan ayam bakar,food,expense,25000.00,IDR,25000.00,IDR,posted\n"+
+		spendings[1].Id+",2023-12-11 00:00:00,Makan malam,Makan malam dengan nasi goreng,food,expense,35000.00,IDR,35000.00,IDR,posted\n"+
+		spendings[2].Id+",2023-12-12 00:00:00,Makan malam,Makan malam dengan sate kambing,food,expense,50000.00,IDR,50000.00,IDR,posted\n",
+		recorder.Body.String())
+
+	recorder = exportSpendings(user.Id, "tz=UTC&max_amount=30000")
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, 2, strings.Count(recorder.Body.String(), "\n"))
+	assert.Contains(t, recorder.Body.String(), spendings[0].Id+",2023-12-09 17:00:00,")
+}
+
+func TestExportSpendingsJSONLinesSuccess(t *testing.T) {
+	user := createUser()
+	defer clearUserDataAfterTest(user.Id)
+
+	spendings := createSpendings(user.Id)
+	for _, spending := range spendings {
+		defer clearSpendingDataAfterTest(spending.Id)
+	}
+
+	recorder := exportSpendings(user.Id, "format=jsonl&order=desc&tz=Asia/Jakarta")
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, "application/jsonl; charset=utf-8", recorder.Header().Get("Content-Type"))
+
+	lines := strings.Split(strings.TrimSpace(recorder.Body.String()), "\n")
+	assert.Equal(t, 3, len(lines))
+	var spending map[string]interface{}
+	if err := json.Unmarshal([]byte(lines[0]), &spending); err != nil {
+		panic(err)
+	}
+	assert.Equal(t, spendings[2].Id, spending["id"])
+	assert.Equal(t, "2023-12-12T00:00:00+07:00", spending["date"])
+	assert.Equal(t, 50000.0, spending["amount"])
+	assert.Equal(t, "IDR", spending["currency"])
+}
+
+func TestExportSpendingsXLSXSuccess(t *testing.T) {
+	user := createUser()
+	defer clearUserDataAfterTest(user.Id)
+
+	spendings := createSpendings(user.Id)
+	for _, spending := range spendings {
+		defer clearSpendingDataAfterTest(spending.Id)
+	}
+
+	recorder := exportSpendings(user.Id, "format=xlsx")
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet", recorder.Header().Get("Content-Type"))
+
+	body := recorder.Body.Bytes()
+	workbook, err := zip.NewReader(bytes.NewReader(body), int64(len(body)))
+	if err != nil {
+		panic(err)
+	}
+	sheet, err := workbook.Open("xl/worksheets/sheet1.xml")
+	if err != nil {
+		panic(err)
+	}
+	content, err := io.ReadAll(sheet)
+	if err != nil {
+		panic(err)
+	}
+	assert.Equal(t, 4, strings.Count(string(content), "<row>"))
+	assert.Contains(t, string(content), `<t xml:space="preserve">`+spendings[0].Id+`</t>`)
+	assert.Contains(t, string(content), `<t xml:space="preserve">2023-12-09 17:00:00</t>`)
+	assert.Contains(t, string(content), `<c><v>25000.00</v></c>`)
+}
+
+// TestExportSpendingsEscapesFormulas test that the texts starting like a
+// formula are escaped in the CSV and XLSX exports but not in JSON Lines.
+func TestExportSpendingsEscapesFormulas(t *testing.T) {
+	user := createUser()
+	defer clearUserDataAfterTest(user.Id)
+
+	spending, _ := testRepositories.Spending.Save(context.Background(), domain.Spending{
+		Id:          uuid.NewString(),
+		UserId:      user.Id,
+		Title:       `=HYPERLINK("http://example.com","Klik")`,
+		Description: "@SUM(1+1)",
+		Category:    "-food",
+		Date:        1702141200000,
+		Amount:      domain.Money{Minor: 2500000, Currency: "IDR"},
+		CreatedAt:   time.Now().UnixMilli(),
+	})
+	defer clearSpendingDataAfterTest(spending.Id)
+
+	recorder := exportSpendings(user.Id, "tz=UTC")
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), spending.Id+`,2023-12-09 17:00:00,"'=HYPERLINK(""http://example.com"",""Klik"")",'@SUM(1+1),'-food,expense,`)
+
+	recorder = exportSpendings(user.Id, "format=xlsx")
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	body := recorder.Body.Bytes()
+	workbook, err := zip.NewReader(bytes.NewReader(body), int64(len(body)))
+	if err != nil {
+		panic(err)
+	}
+	sheet, err := workbook.Open("xl/worksheets/sheet1.xml")
+	if err != nil {
+		panic(err)
+	}
+	content, err := io.ReadAll(sheet)
+	if err != nil {
+		panic(err)
+	}
+	assert.Contains(t, string(content), `<t xml:space="preserve">&#39;=HYPERLINK(&#34;http://example.com&#34;,&#34;Klik&#34;)</t>`)
+	assert.Contains(t, string(content), `<t xml:space="preserve">&#39;@SUM(1+1)</t>`)
+	assert.Contains(t, string(content), `<t xml:space="preserve">&#39;-food</t>`)
+
+	recorder = exportSpendings(user.Id, "format=jsonl")
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	var exported map[string]interface{}
+	if err := json.Unmarshal(recorder.Body.Bytes(), &exported); err != nil {
+		panic(err)
+	}
+	assert.Equal(t, spending.Title, exported["title"])
+}
+
+func TestExportSpendingsFailed(t *testing.T) {
+	user := createUser()
+	defer clearUserDataAfterTest(user.Id)
+
+	tests := []struct {
+		query  string
+		detail string
+	}{
+		{"format=pdf", "the request has invalid fields"},
+		{"tz=Mars/Olympus", "invalid time zone"},
+		{"from=2023-12-10&to=2023-12-01", "from must not be after to"},
+	}
+	for _, test := range tests {
+		recorder := exportSpendings(user.Id, test.query)
+		assert.Equal(t, http.StatusBadRequest, recorder.Code)
+		assert.Contains(t, recorder.Body.String(), test.detail)
+	}
+}
@@ -0,0 +1,158 @@
+package test
+
+import (
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+// spendingImportCSV is a CSV file of spendings exported by a spreadsheet
+// with semicolons as the delimiter and commas as the decimal separator. Its
+// first row is a duplicate of the spending created by createSpending.
+const spendingImportCSV = `Judul;Jumlah;Tanggal;Kategori;Keterangan
+Makan malam;50.000;05/12/2023 17:00;food;Makan malam dengan sate kambing
+Kopi susu;1.234,56;06/12/2023 08:00;Food;Kopi di kantor
+Kopi susu;1.234,56;06/12/2023 08:00;food;Kopi di kantor
+Tiket bus;12.500;07/12/2023 07:30;transportation;
+Buku;10.000,001;08/12/2023 10:00;foods;
+Ok;sepuluh;kemarin;food;
+`
+
+// importSpendings posts the CSV file of spendings of the user with the
+// given query parameters and returns the response recorder.
+func importSpendings(userId string, query string, body string) *httptest.ResponseRecorder {
+	router := setupRouter()
+	request := httptest.NewRequest(http.MethodPost, "http://localhost:8000/api/v1/users/"+userId+"/spendings/import?"+query, strings.NewReader(body))
+	authorize(request, userId)
+	request.Header.Add("Content-Type", "text/csv")
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+	return recorder
+}
+
+// decodeSpendingImport returns the data of the response of an import of
+// spendings.
+func decodeSpendingImport(recorder *httptest.ResponseRecorder) map[string]interface{} {
+	var responseBody map[string]interface{}
+	err := json.NewDecoder(recorder.Body).Decode(&responseBody)
+	if err != nil {
+		panic(err)
+	}
+	return responseBody["data"].(map[string]interface{})
+}
+
+func TestImportSpendingsSuccess(t *testing.T) {
+	user := createUser()
+	defer clearUserDataAfterTest(user.Id)
+	spending := createSpending(user.Id)
+	defer clearSpendingDataAfterTest(spending.Id)
+
+	query := url.Values{
+		"delimiter":          {";"},
+		"decimal_separator":  {","},
+		"date_format":        {"DD/MM/YYYY HH:mm"},
+		"title_column":       {"judul"},
+		"amount_column":      {"jumlah"},
+		"date_column":        {"tanggal"},
+		"category_column":    {"kategori"},
+		"description_column": {"keterangan"},
+	}
+	recorder := importSpendings(user.Id, query.Encode()+"&dry_run=true", spendingImportCSV)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	data := decodeSpendingImport(recorder)
+	assert.Equal(t, true, data["dry_run"])
+	assert.Equal(t, 6, int(data["total"].(float64)))
+	assert.Equal(t, 2, int(data["valid"].(float64)))
+	assert.Equal(t, 0, int(data["created"].(float64)))
+	assert.Equal(t, 2, int(data["duplicates"].(float64)))
+	assert.Equal(t, 2, int(data["invalid"].(float64)))
+
+	statuses := []string{}
+	for _, row := range data["rows"].([]interface{}) {
+		statuses = append(statuses, row.(map[string]interface{})["status"].(string))
+	}
+	assert.Equal(t, []string{"duplicate", "valid", "duplicate", "valid", "invalid", "invalid"}, statuses)
+
+	rows := data["rows"].([]interface{})
+	assert.Equal(t, 2, int(rows[0].(map[string]interface{})["line"].(float64)))
+	invalidAmount := rows[4].(map[string]interface{})["errors"].([]interface{})
+	assert.Equal(t, 2, len(invalidAmount))
+	assert.Equal(t, "amount", invalidAmount[0].(map[string]interface{})["field"])
+	assert.Equal(t, "category", invalidAmount[1].(map[string]interface{})["field"])
+	invalidFields := []string{}
+	for _, fieldError := range rows[5].(map[string]interface{})["errors"].([]interface{}) {
+		invalidFields = append(invalidFields, fieldError.(map[string]interface{})["field"].(string))
+	}
+	assert.Equal(t, []string{"title", "date"}, invalidFields)
+
+	recorder = importSpendings(user.Id, query.Encode(), spendingImportCSV)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	data = decodeSpendingImport(recorder)
+	assert.Equal(t, false, data["dry_run"])
+	assert.Equal(t, 2, int(data["created"].(float64)))
+
+	for _, row := range data["rows"].([]interface{}) {
+		if id, ok := row.(map[string]interface{})["id"].(string); ok {
+			defer clearSpendingDataAfterTest(id)
+		}
+	}
+	rows = data["rows"].([]interface{})
+	assert.Equal(t, "created", rows[1].(map[string]interface{})["status"])
+
+	router := setupRouter()
+	request := httptest.NewRequest(http.MethodGet, "http://localhost:8000/api/v1/spendings/"+rows[1].(map[string]interface{})["id"].(string), nil)
+	authorize(request, user.Id)
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), `"title":"Kopi susu","description":"Kopi di kantor","amount":1234.56,"currency":"IDR","converted_amount":1234.56,"converted_currency":"IDR","date":1701849600000,"category":"food"`)
+
+	// Importing the file again creates nothing.
+	recorder = importSpendings(user.Id, query.Encode(), spendingImportCSV)
+	data = decodeSpendingImport(recorder)
+	assert.Equal(t, 0, int(data["created"].(float64)))
+	assert.Equal(t, 4, int(data["duplicates"].(float64)))
+}
+
+func TestImportSpendingsFailed(t *testing.T) {
+	user := createUser()
+	defer clearUserDataAfterTest(user.Id)
+
+	tests := []struct {
+		query  string
+		body   string
+		detail string
+	}{
+		{"", "title,amount\nKopi,10000\n", "the CSV file must have a header with the columns title, amount, date"},
+		{"", "", "the CSV file must have a header with the columns title, amount, date"},
+		{"", "title,amount,date\n\"Kopi,10000,2023-12-01\n", "line 2 of the CSV file is invalid"},
+		{"dry_run=maybe", "title,amount,date\n", "dry_run must be a boolean"},
+		{"decimal_separator=x", "title,amount,date\n", "the request has invalid fields"},
+		{"delimiter=%22", "title,amount,date\n", "delimiter is invalid"},
+		{"delimiter=%0A", "title,amount,date\n", "delimiter is invalid"},
+		{"delimiter=%FF", "title,amount,date\n", "delimiter is invalid"},
+	}
+	for _, test := range tests {
+		recorder := importSpendings(user.Id, test.query, test.body)
+		assert.Equal(t, http.StatusBadRequest, recorder.Code)
+		assert.Contains(t, recorder.Body.String(), test.detail)
+	}
+}
+
+// TestImportSpendingsMultibyteDelimiterSuccess test that a delimiter of more
+// than one byte in UTF-8 delimits the fields as a whole character.
+func TestImportSpendingsMultibyteDelimiterSuccess(t *testing.T) {
+	user := createUser()
+	defer clearUserDataAfterTest(user.Id)
+
+	recorder := importSpendings(user.Id, "delimiter=%E2%82%AC&dry_run=true", "title€amount€date€category\nKopi susu€10000€2023-12-01€food\n")
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	data := decodeSpendingImport(recorder)
+	assert.Equal(t, 1, int(data["total"].(float64)))
+	assert.Equal(t, 1, int(data["valid"].(float64)))
+}
@@ -0,0 +1,62 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"github.com/refandas/duit-api/exception"
+	"github.com/refandas/duit-api/model/domain"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+// TestFindSpendingByUserIdOrderSuccess test that the spending history of a
+// user is listed by date in both orders, and that every spending is listed
+// exactly once when it is read a page at a time.
+func TestFindSpendingByUserIdOrderSuccess(t *testing.T) {
+	user := createUser()
+	defer clearUserDataAfterTest(user.Id)
+
+	spendings := createSpendings(user.Id)
+	sameDateSpending := createSpendingOn(user.Id, spendings[1].Date)
+	spendings = append(spendings, sameDateSpending)
+	for _, spending := range spendings {
+		defer clearSpendingDataAfterTest(spending.Id)
+	}
+
+	for _, descending := range []bool{false, true} {
+		query := domain.SpendingQuery{
+			UserId:     user.Id,
+			Limit:      1,
+			Descending: descending,
+		}
+
+		var dates []int64
+		ids := map[string]bool{}
+		for {
+			page, err := testRepositories.Spending.FindByUserId(context.Background(), query)
+			assert.Nil(t, err)
+			for _, spending := range page.Spendings {
+				dates = append(dates, spending.Date)
+				ids[spending.Id] = true
+			}
+			if page.Next == nil {
+				break
+			}
+			query.After = page.Next
+		}
+
+		expected := []int64{spendings[0].Date, spendings[1].Date, spendings[1].Date, spendings[2].Date}
+		if descending {
+			expected = []int64{spendings[2].Date, spendings[1].Date, spendings[1].Date, spendings[0].Date}
+		}
+		assert.Equal(t, expected, dates)
+		assert.Equal(t, len(spendings), len(ids))
+	}
+}
+
+// TestFindSpendingByIdNotFound test that a missing spending is reported with
+// the not found error rather than a panic.
+func TestFindSpendingByIdNotFound(t *testing.T) {
+	_, err := testRepositories.Spending.FindById(context.Background(), "not-found")
+	assert.True(t, errors.Is(err, exception.ErrNotFound))
+}
@@ -0,0 +1,133 @@
+package test
+
+import (
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// sendSpendingRequest sends a request with the given JSON body to the path
+// of the API as the user then return the status code and the body of the
+// response.
+func sendSpendingRequest(router http.Handler, method string, userId string, path string, jsonData string) (int, map[string]interface{}) {
+	request := httptest.NewRequest(method, "http://localhost:8000/api/v1"+path, strings.NewReader(jsonData))
+	authorize(request, userId)
+	request.Header.Add("Content-Type", "application/json")
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	var responseBody map[string]interface{}
+	err := json.Unmarshal(recorder.Body.Bytes(), &responseBody)
+	if err != nil {
+		panic(err)
+	}
+	return recorder.Code, responseBody
+}
+
+// spendingIds returns the ids of the spendings of the data of a response.
+func spendingIds(data interface{}) []string {
+	ids := []string{}
+	for _, spending := range data.([]interface{}) {
+		ids = append(ids, spending.(map[string]interface{})["id"].(string))
+	}
+	return ids
+}
+
+func TestSpendingTagsSuccess(t *testing.T) {
+	router := setupRouter()
+
+	user := createUser()
+	defer clearUserDataAfterTest(user.Id)
+
+	var ids []string
+	for _, spending := range []string{
+		`{"title": "Tiket pesawat", "amount": 1500000, "date": 1701795600000, "category": "other", "tags": ["Business-Trip ", "reimbursable", "business-trip"]}`,
+		`{"title": "Hotel", "amount": 800000, "date": 1701882000000, "category": "other", "tags": ["business-trip"]}`,
+		`{"title": "Makan malam", "amount": 50000, "date": 1701968400000, "category": "food"}`,
+	} {
+		_, responseBody := sendSpendingRequest(router, http.MethodPost, user.Id, "/spendings", spending)
+		data := responseBody["data"].(map[string]interface{})
+		defer clearSpendingDataAfterTest(data["id"].(string))
+		ids = append(ids, data["id"].(string))
+	}
+
+	_, responseBody := sendSpendingRequest(router, http.MethodGet, user.Id, "/spendings/"+ids[0], "")
+	assert.Equal(t, []interface{}{"business-trip", "reimbursable"}, responseBody["data"].(map[string]interface{})["tags"])
+	_, responseBody = sendSpendingRequest(router, http.MethodGet, user.Id, "/spendings/"+ids[2], "")
+	assert.Equal(t, []interface{}{}, responseBody["data"].(map[string]interface{})["tags"])
+
+	tests := []struct {
+		query string
+		ids   []string
+	}{
+		{"tags=reimbursable,business-trip", []string{ids[0], ids[1]}},
+		{"tags=reimbursable,business-trip&tag_match=all", []string{ids[0]}},
+		{"tags=Reimbursable", []string{ids[0]}},
+		{"tags=business", []string{}},
+	}
+	for _, test := range tests {
+		code, responseBody := sendSpendingRequest(router, http.MethodGet, user.Id, "/users/"+user.Id+"/spendings?"+test.query, "")
+		assert.Equal(t, http.StatusOK, code)
+		assert.Equal(t, test.ids, spendingIds(responseBody["data"]), test.query)
+	}
+
+	// The tags are added to and removed from every spending at once, and the
+	// spendings already tagged are left unchanged.
+	code, responseBody := sendSpendingRequest(router, http.MethodPost, user.Id, "/users/"+user.Id+"/spendings/tags",
+		`{"spending_ids": ["`+ids[0]+`", "`+ids[1]+`", "`+ids[2]+`"], "add": ["reimbursable"], "remove": ["business-trip"]}`)
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, 3.0, responseBody["data"].(map[string]interface{})["updated"])
+
+	code, responseBody = sendSpendingRequest(router, http.MethodGet, user.Id, "/users/"+user.Id+"/tags", "")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, []interface{}{
+		map[string]interface{}{"tag": "reimbursable", "count": 3.0},
+	}, responseBody["data"])
+
+	// A spending keeps its tags unless others are requested on update.
+	code, responseBody = sendSpendingRequest(router, http.MethodPut, user.Id, "/spendings/"+ids[1],
+		`{"title": "Hotel bintang", "amount": 800000, "date": 1701882000000, "category": "other"}`)
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, []interface{}{"reimbursable"}, responseBody["data"].(map[string]interface{})["tags"])
+	code, responseBody = sendSpendingRequest(router, http.MethodPut, user.Id, "/spendings/"+ids[1],
+		`{"title": "Hotel bintang", "amount": 800000, "date": 1701882000000, "category": "other", "tags": []}`)
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, []interface{}{}, responseBody["data"].(map[string]interface{})["tags"])
+}
+
+func TestUpdateSpendingTagsFailed(t *testing.T) {
+	router := setupRouter()
+
+	users := createUsers()
+	for _, user := range users {
+		defer clearUserDataAfterTest(user.Id)
+	}
+	spending := createSpending(users[0].Id)
+	defer clearSpendingDataAfterTest(spending.Id)
+	otherSpending := createSpending(users[1].Id)
+	defer clearSpendingDataAfterTest(otherSpending.Id)
+
+	tests := []struct {
+		body   string
+		code   int
+		detail string
+	}{
+		{`{"spending_ids": ["` + spending.Id + `"]}`, http.StatusBadRequest, "add or remove must have a tag"},
+		{`{"spending_ids": [], "add": ["trip"]}`, http.StatusBadRequest, "the request has invalid fields"},
+		{`{"spending_ids": ["` + spending.Id + `"], "add": ["a,b"]}`, http.StatusBadRequest, "the request has invalid fields"},
+		{`{"spending_ids": ["` + spending.Id + `", "` + otherSpending.Id + `"], "add": ["trip"]}`, http.StatusNotFound, "item not found"},
+	}
+	for _, test := range tests {
+		code, responseBody := sendSpendingRequest(router, http.MethodPost, users[0].Id, "/users/"+users[0].Id+"/spendings/tags", test.body)
+		assert.Equal(t, test.code, code)
+		assert.Equal(t, test.detail, responseBody["detail"])
+	}
+
+	// No spending is tagged when one of them is not found.
+	_, responseBody := sendSpendingRequest(router, http.MethodGet, users[0].Id, "/spendings/"+spending.Id, "")
+	assert.Equal(t, []interface{}{}, responseBody["data"].(map[string]interface{})["tags"])
+}
@@ -0,0 +1,40 @@
+package test
+
+import (
+	"context"
+	"github.com/refandas/duit-api/app"
+	"github.com/refandas/duit-api/model/domain"
+	"github.com/refandas/duit-api/repository"
+	"github.com/stretchr/testify/assert"
+	"path/filepath"
+	"testing"
+)
+
+// TestBackupSQLiteSuccess test that a backup of an SQLite database taken
+// while the database is open contains the data written before the backup.
+func TestBackupSQLiteSuccess(t *testing.T) {
+	ctx := context.Background()
+	directory := t.TempDir()
+
+	repositories := app.SetupSQLite(ctx, filepath.Join(directory, "duit.db"))
+	user, err := repositories.User.Save(ctx, domain.User{
+		Id:        "f9ed2a0e-7d6f-4b8e-9c21-3d0f5c6a1b2e",
+		Name:      "Test User",
+		Email:     "test@example.com",
+		TimeZone:  "UTC",
+		CreatedAt: 1702141200000,
+	})
+	assert.Nil(t, err)
+
+	db := app.OpenSQLite(filepath.Join(directory, "duit.db"))
+	defer db.Close()
+	backupPath := filepath.Join(directory, "backup.db")
+	err = app.BackupSQLite(ctx, db, backupPath)
+	assert.Nil(t, err)
+
+	backup := app.OpenSQLite(backupPath)
+	defer backup.Close()
+	restored, err := repository.NewUserRepositorySQL(backup).FindById(ctx, user.Id)
+	assert.Nil(t, err)
+	assert.Equal(t, user, restored)
+}
@@ -0,0 +1,106 @@
+package test
+
+import (
+	"context"
+	"database/sql"
+	"github.com/refandas/duit-api/app"
+	"github.com/refandas/duit-api/model/domain"
+	"github.com/stretchr/testify/assert"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+)
+
+// openSQLiteBefore opens the SQLite database at the path with the SQLite
+// migrations before the given version applied.
+func openSQLiteBefore(t *testing.T, path string, version string) *sql.DB {
+	migrations := os.DirFS("../app/migrations/sqlite")
+	names, err := fs.Glob(migrations, "*.sql")
+	assert.Nil(t, err)
+	previous := fstest.MapFS{}
+	for _, name := range names {
+		if name < version {
+			data, err := fs.ReadFile(migrations, name)
+			assert.Nil(t, err)
+			previous[name] = &fstest.MapFile{Data: data}
+		}
+	}
+	db := app.OpenSQLite(path)
+	assert.Nil(t, app.Migrate(context.Background(), db, previous))
+	return db
+}
+
+// TestMigrateSpendingAmountsSQLiteSuccess test that the amounts recorded
+// before the amounts were stored in minor units are converted exactly.
+func TestMigrateSpendingAmountsSQLiteSuccess(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "duit.db")
+
+	// Apply the migrations before the amounts were stored in minor units.
+	db := openSQLiteBefore(t, path, "0007")
+	defer db.Close()
+
+	_, err := db.ExecContext(ctx, `INSERT INTO spending (id, user_id, title, amount, date, category, created_at)
+		VALUES ('a', 'u', 'Makan malam', 50000, 1701795600000, 'food', 1701795600000),
+			('b', 'u', 'Parkir', 0.1, 1701795600001, 'transport', 1701795600000)`)
+	assert.Nil(t, err)
+
+	repositories := app.SetupSQLite(ctx, path)
+	page, err := repositories.Spending.FindByUserId(ctx, domain.SpendingQuery{UserId: "u"})
+	assert.Nil(t, err)
+	assert.Len(t, page.Spendings, 2)
+	assert.Equal(t, domain.Money{Minor: 5000000, Currency: "IDR"}, page.Spendings[0].Amount)
+	assert.Equal(t, domain.Money{Minor: 10, Currency: "IDR"}, page.Spendings[1].Amount)
+}
+
+// TestMigrateBudgetAmountsSQLiteSuccess test that the amounts of the budgets
+// recorded before the amounts were stored in minor units are converted into
+// the home currency of their users.
+func TestMigrateBudgetAmountsSQLiteSuccess(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "duit.db")
+
+	db := openSQLiteBefore(t, path, "0017")
+	defer db.Close()
+
+	_, err := db.ExecContext(ctx, `INSERT INTO users (id, name, email, home_currency)
+		VALUES ('u', 'User 1', 'user1@example.com', ''), ('v', 'User 2', 'user2@example.com', 'JPY')`)
+	assert.Nil(t, err)
+	_, err = db.ExecContext(ctx, `INSERT INTO budgets (id, user_id, category, amount, period, created_at)
+		VALUES ('a', 'u', 'food', 1500000.5, 'month', 1701795600000),
+			('b', 'v', 'food', 20000, 'month', 1701795600000)`)
+	assert.Nil(t, err)
+
+	repositories := app.SetupSQLite(ctx, path)
+	budget, err := repositories.Budget.FindById(ctx, "a")
+	assert.Nil(t, err)
+	assert.Equal(t, domain.Money{Minor: 150000050, Currency: "IDR"}, budget.Amount)
+	budget, err = repositories.Budget.FindById(ctx, "b")
+	assert.Nil(t, err)
+	assert.Equal(t, domain.Money{Minor: 20000, Currency: "JPY"}, budget.Amount)
+}
+
+// TestMigrateDuplicateEmailsSQLiteFailed test that the emails are not made
+// unique while some are registered to more than one user, which are listed
+// instead of failing on the unique index.
+func TestMigrateDuplicateEmailsSQLiteFailed(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "duit.db")
+
+	db := openSQLiteBefore(t, path, "0016")
+	defer db.Close()
+
+	_, err := db.ExecContext(ctx, `INSERT INTO users (id, name, email)
+		VALUES ('u', 'User 1', 'user1@example.com'), ('v', 'User 1', 'User1@Example.com'),
+			('w', 'User 2', 'user2@example.com')`)
+	assert.Nil(t, err)
+
+	err = app.Migrate(ctx, db, os.DirFS("../app/migrations/sqlite"))
+	assert.EqualError(t, err, "the emails user1@example.com are registered to more than one user, change them before the emails are made unique")
+
+	_, err = db.ExecContext(ctx, "UPDATE users SET email = 'user3@example.com' WHERE id = 'v'")
+	assert.Nil(t, err)
+	assert.Nil(t, app.Migrate(ctx, db, os.DirFS("../app/migrations/sqlite")))
+}
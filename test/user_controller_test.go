@@ -5,21 +5,22 @@ import (
 	"encoding/json"
 	"fmt"
 	"github.com/google/uuid"
+	"github.com/refandas/duit-api/exception"
 	"github.com/refandas/duit-api/model/domain"
-	"github.com/refandas/duit-api/repository"
+	"github.com/refandas/duit-api/service"
 	"github.com/stretchr/testify/assert"
 	"golang.org/x/crypto/bcrypt"
 	"io"
 	"net/http"
 	"net/http/httptest"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 )
 
 func TestCreateUserSuccess(t *testing.T) {
-	db := setupTestDB(testUserTableName)
-	router := setupRouter(db)
+	router := setupRouter()
 
 	jsonData := `
 	{
@@ -49,17 +50,17 @@ func TestCreateUserSuccess(t *testing.T) {
 		panic(err)
 	}
 	userId := responseBody["data"].(map[string]interface{})["id"]
-	defer clearUserDataAfterTest(db, userId.(string))
+	defer clearUserDataAfterTest(userId.(string))
 
 	assert.Equal(t, http.StatusCreated, int(responseBody["code"].(float64)))
 	assert.Equal(t, "CREATED", responseBody["status"])
 	assert.Equal(t, "Test User", responseBody["data"].(map[string]interface{})["name"])
 	assert.Equal(t, "test@example.com", responseBody["data"].(map[string]interface{})["email"])
+	assert.Equal(t, "IDR", responseBody["data"].(map[string]interface{})["home_currency"])
 }
 
 func TestCreateUserFailed(t *testing.T) {
-	db := setupTestDB(testUserTableName)
-	router := setupRouter(db)
+	router := setupRouter()
 
 	jsonData := `
 	{
@@ -89,23 +90,59 @@ func TestCreateUserFailed(t *testing.T) {
 		panic(err)
 	}
 
-	assert.Equal(t, http.StatusBadRequest, int(responseBody["code"].(float64)))
-	assert.Equal(t, "BAD REQUEST", responseBody["status"])
+	assert.Equal(t, "application/problem+json", response.Header.Get("Content-Type"))
+	assert.Equal(t, http.StatusBadRequest, int(responseBody["status"].(float64)))
+	assert.Equal(t, "validation_failed", responseBody["code"])
+	assert.Equal(t, "/api/v1/users", responseBody["instance"])
+
+	fieldErrors := responseBody["errors"].([]interface{})
+	assert.Len(t, fieldErrors, 1)
+	assert.Equal(t, "email", fieldErrors[0].(map[string]interface{})["field"])
+	assert.Equal(t, "required", fieldErrors[0].(map[string]interface{})["code"])
+	assert.Equal(t, "email is a required field", fieldErrors[0].(map[string]interface{})["message"])
 }
 
-func TestUpdateUserSuccess(t *testing.T) {
-	db := setupTestDB(testUserTableName)
+func TestCreateUserInvalidBodyFailed(t *testing.T) {
+	router := setupRouter()
+
+	requestBody := strings.NewReader(`{"name": "Test User",`)
+	request := httptest.NewRequest(http.MethodPost, "http://localhost:8000/api/v1/users", requestBody)
+	request.Header.Add("Content-Type", "application/json")
 
-	router := setupRouter(db)
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	response := recorder.Result()
+	assert.Equal(t, http.StatusBadRequest, response.StatusCode)
 
-	user := createUser(db)
-	defer clearUserDataAfterTest(db, user.Id)
+	body, err := io.ReadAll(response.Body)
+	if err != nil {
+		panic(err)
+	}
+
+	var responseBody map[string]interface{}
+	err = json.Unmarshal(body, &responseBody)
+	if err != nil {
+		panic(err)
+	}
+
+	assert.Equal(t, http.StatusBadRequest, int(responseBody["status"].(float64)))
+	assert.Equal(t, "validation_failed", responseBody["code"])
+	assert.Equal(t, "invalid request body", responseBody["detail"])
+}
+
+func TestUpdateUserSuccess(t *testing.T) {
+	router := setupRouter()
+
+	user := createUser()
+	defer clearUserDataAfterTest(user.Id)
 
 	jsonData := `
 		{
 			"name": "%s",
 			"email": "%s",
-			"password": "%s"
+			"password": "%s",
+			"home_currency": "SGD"
 		}
 	`
 	updatedName := "Test User 2"
@@ -121,6 +158,7 @@ func TestUpdateUserSuccess(t *testing.T) {
 
 	requestBody := strings.NewReader(jsonData)
 	request := httptest.NewRequest(http.MethodPut, "http://localhost:8000/api/v1/users/"+user.Id, requestBody)
+	authorize(request, user.Id)
 	request.Header.Add("Content-Type", "application/json")
 
 	recorder := httptest.NewRecorder()
@@ -145,15 +183,14 @@ func TestUpdateUserSuccess(t *testing.T) {
 	assert.Equal(t, user.Id, responseBody["data"].(map[string]interface{})["id"])
 	assert.Equal(t, updatedName, responseBody["data"].(map[string]interface{})["name"])
 	assert.Equal(t, updatedEmail, responseBody["data"].(map[string]interface{})["email"])
+	assert.Equal(t, "SGD", responseBody["data"].(map[string]interface{})["home_currency"])
 }
 
 func TestUpdateUserWithoutPasswordAttributeSuccess(t *testing.T) {
-	db := setupTestDB(testUserTableName)
+	user := createUser()
+	defer clearUserDataAfterTest(user.Id)
 
-	user := createUser(db)
-	defer clearUserDataAfterTest(db, user.Id)
-
-	router := setupRouter(db)
+	router := setupRouter()
 
 	jsonData := `
 	{
@@ -167,6 +204,7 @@ func TestUpdateUserWithoutPasswordAttributeSuccess(t *testing.T) {
 
 	requestBody := strings.NewReader(jsonData)
 	request := httptest.NewRequest(http.MethodPut, "http://localhost:8000/api/v1/users/"+user.Id, requestBody)
+	authorize(request, user.Id)
 	request.Header.Add("Content-Type", "application/json")
 
 	recorder := httptest.NewRecorder()
@@ -191,15 +229,22 @@ func TestUpdateUserWithoutPasswordAttributeSuccess(t *testing.T) {
 	assert.Equal(t, user.Id, responseBody["data"].(map[string]interface{})["id"])
 	assert.Equal(t, updatedName, responseBody["data"].(map[string]interface{})["name"])
 	assert.Equal(t, updatedEmail, responseBody["data"].(map[string]interface{})["email"])
+
+	// The password is kept, so the user still logs in with it.
+	requestBody = strings.NewReader(fmt.Sprintf(`{"email": "%s", "password": "secret"}`, updatedEmail))
+	request = httptest.NewRequest(http.MethodPost, "http://localhost:8000/api/v1/auth/login", requestBody)
+	request.Header.Add("Content-Type", "application/json")
+
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+	assert.Equal(t, http.StatusOK, recorder.Code)
 }
 
 func TestUpdateUserFailed(t *testing.T) {
-	db := setupTestDB(testUserTableName)
-
-	user := createUser(db)
-	defer clearUserDataAfterTest(db, user.Id)
+	user := createUser()
+	defer clearUserDataAfterTest(user.Id)
 
-	router := setupRouter(db)
+	router := setupRouter()
 
 	jsonData := `
 	{
@@ -211,6 +256,7 @@ func TestUpdateUserFailed(t *testing.T) {
 
 	requestBody := strings.NewReader(jsonData)
 	request := httptest.NewRequest(http.MethodPut, "http://localhost:8000/api/v1/users/"+user.Id, requestBody)
+	authorize(request, user.Id)
 	request.Header.Add("Content-Type", "application/json")
 
 	recorder := httptest.NewRecorder()
@@ -230,19 +276,18 @@ func TestUpdateUserFailed(t *testing.T) {
 		panic(err)
 	}
 
-	assert.Equal(t, http.StatusBadRequest, int(responseBody["code"].(float64)))
-	assert.Equal(t, "BAD REQUEST", responseBody["status"])
+	assert.Equal(t, http.StatusBadRequest, int(responseBody["status"].(float64)))
+	assert.Equal(t, "validation_failed", responseBody["code"])
 }
 
 func TestGetUserSuccess(t *testing.T) {
-	db := setupTestDB(testUserTableName)
+	user := createUser()
+	defer clearUserDataAfterTest(user.Id)
 
-	user := createUser(db)
-	defer clearUserDataAfterTest(db, user.Id)
-
-	router := setupRouter(db)
+	router := setupRouter()
 
 	request := httptest.NewRequest(http.MethodGet, "http://localhost:8000/api/v1/users/"+user.Id, nil)
+	authorize(request, user.Id)
 	recorder := httptest.NewRecorder()
 	router.ServeHTTP(recorder, request)
 
@@ -268,11 +313,10 @@ func TestGetUserSuccess(t *testing.T) {
 }
 
 func TestGetUserFailed(t *testing.T) {
-	db := setupTestDB(testUserTableName)
-
-	router := setupRouter(db)
+	router := setupRouter()
 
 	request := httptest.NewRequest(http.MethodGet, "http://localhost:8000/api/v1/users/404", nil)
+	authorize(request, "404")
 
 	recorder := httptest.NewRecorder()
 	router.ServeHTTP(recorder, request)
@@ -291,14 +335,11 @@ func TestGetUserFailed(t *testing.T) {
 		panic(err)
 	}
 
-	assert.Equal(t, http.StatusNotFound, int(responseBody["code"].(float64)))
-	assert.Equal(t, "NOT FOUND", responseBody["status"])
+	assert.Equal(t, http.StatusNotFound, int(responseBody["status"].(float64)))
+	assert.Equal(t, "not_found", responseBody["code"])
 }
 
 func TestDeleteUserSuccess(t *testing.T) {
-	db := setupTestDB(testUserTableName)
-
-	userRepository := repository.NewUserRepository()
 	userId, _ := uuid.NewRandom()
 	password := []byte("secret")
 	hashedPassword, err := bcrypt.GenerateFromPassword(password, bcrypt.DefaultCost)
@@ -306,7 +347,7 @@ func TestDeleteUserSuccess(t *testing.T) {
 		panic(err)
 	}
 
-	user := userRepository.Save(context.Background(), db, domain.User{
+	user, _ := testRepositories.User.Save(context.Background(), domain.User{
 		Id:        userId.String(),
 		Name:      "Test User",
 		Email:     "test@example.com",
@@ -314,9 +355,10 @@ func TestDeleteUserSuccess(t *testing.T) {
 		CreatedAt: time.Now().UnixMilli(),
 	})
 
-	router := setupRouter(db)
+	router := setupRouter()
 
 	request := httptest.NewRequest(http.MethodDelete, "http://localhost:8000/api/v1/users/"+user.Id, nil)
+	authorize(request, user.Id)
 	request.Header.Add("Content-Type", "application/json")
 
 	recorder := httptest.NewRecorder()
@@ -341,11 +383,10 @@ func TestDeleteUserSuccess(t *testing.T) {
 }
 
 func TestDeleteUserFailed(t *testing.T) {
-	db := setupTestDB(testUserTableName)
-
-	router := setupRouter(db)
+	router := setupRouter()
 
 	request := httptest.NewRequest(http.MethodDelete, "http://localhost:8000/api/v1/users/404", nil)
+	authorize(request, "404")
 	request.Header.Add("Content-Type", "application/json")
 
 	recorder := httptest.NewRecorder()
@@ -365,6 +406,191 @@ func TestDeleteUserFailed(t *testing.T) {
 		panic(err)
 	}
 
-	assert.Equal(t, http.StatusNotFound, int(responseBody["code"].(float64)))
-	assert.Equal(t, "NOT FOUND", responseBody["status"])
+	assert.Equal(t, http.StatusNotFound, int(responseBody["status"].(float64)))
+	assert.Equal(t, "not_found", responseBody["code"])
+}
+
+func TestGetAnotherUserFailed(t *testing.T) {
+	users := createUsers()
+	defer clearUserDataAfterTest(users[0].Id)
+	defer clearUserDataAfterTest(users[1].Id)
+	defer clearUserDataAfterTest(users[2].Id)
+
+	router := setupRouter()
+
+	request := httptest.NewRequest(http.MethodGet, "http://localhost:8000/api/v1/users/"+users[1].Id, nil)
+	authorize(request, users[0].Id)
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	response := recorder.Result()
+	assert.Equal(t, http.StatusNotFound, response.StatusCode)
+
+	body, err := io.ReadAll(response.Body)
+	if err != nil {
+		panic(err)
+	}
+
+	var responseBody map[string]interface{}
+	err = json.Unmarshal(body, &responseBody)
+	if err != nil {
+		panic(err)
+	}
+
+	assert.Equal(t, http.StatusNotFound, int(responseBody["status"].(float64)))
+	assert.Equal(t, "not_found", responseBody["code"])
+}
+
+func TestDeleteAnotherUserForbidden(t *testing.T) {
+	users := createUsers()
+	defer clearUserDataAfterTest(users[0].Id)
+	defer clearUserDataAfterTest(users[1].Id)
+	defer clearUserDataAfterTest(users[2].Id)
+
+	router := setupRouterWithPolicy(service.OwnershipPolicyForbidden)
+
+	request := httptest.NewRequest(http.MethodDelete, "http://localhost:8000/api/v1/users/"+users[1].Id, nil)
+	authorize(request, users[0].Id)
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	response := recorder.Result()
+	assert.Equal(t, http.StatusForbidden, response.StatusCode)
+
+	body, err := io.ReadAll(response.Body)
+	if err != nil {
+		panic(err)
+	}
+
+	var responseBody map[string]interface{}
+	err = json.Unmarshal(body, &responseBody)
+	if err != nil {
+		panic(err)
+	}
+
+	assert.Equal(t, http.StatusForbidden, int(responseBody["status"].(float64)))
+	assert.Equal(t, "forbidden", responseBody["code"])
+}
+
+func TestCreateUserDuplicateEmailFailed(t *testing.T) {
+	router := setupRouter()
+
+	user := createUser()
+	defer clearUserDataAfterTest(user.Id)
+
+	jsonData := `
+	{
+		"name": "Another User",
+		"email": "Test@Example.com",
+		"password": "secret"
+	}
+`
+	requestBody := strings.NewReader(jsonData)
+	request := httptest.NewRequest(http.MethodPost, "http://localhost:8000/api/v1/users", requestBody)
+	request.Header.Add("Content-Type", "application/json")
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	response := recorder.Result()
+	assert.Equal(t, http.StatusConflict, response.StatusCode)
+
+	body, err := io.ReadAll(response.Body)
+	if err != nil {
+		panic(err)
+	}
+
+	var responseBody map[string]interface{}
+	err = json.Unmarshal(body, &responseBody)
+	if err != nil {
+		panic(err)
+	}
+
+	assert.Equal(t, http.StatusConflict, int(responseBody["status"].(float64)))
+	assert.Equal(t, "conflict", responseBody["code"])
+}
+
+func TestUpdateUserDuplicateEmailFailed(t *testing.T) {
+	users := createUsers()
+	defer clearUserDataAfterTest(users[0].Id)
+	defer clearUserDataAfterTest(users[1].Id)
+	defer clearUserDataAfterTest(users[2].Id)
+
+	router := setupRouter()
+
+	jsonData := fmt.Sprintf(`{"name": "User 1", "email": "%s"}`, users[1].Email)
+
+	requestBody := strings.NewReader(jsonData)
+	request := httptest.NewRequest(http.MethodPut, "http://localhost:8000/api/v1/users/"+users[0].Id, requestBody)
+	authorize(request, users[0].Id)
+	request.Header.Add("Content-Type", "application/json")
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	response := recorder.Result()
+	assert.Equal(t, http.StatusConflict, response.StatusCode)
+
+	body, err := io.ReadAll(response.Body)
+	if err != nil {
+		panic(err)
+	}
+
+	var responseBody map[string]interface{}
+	err = json.Unmarshal(body, &responseBody)
+	if err != nil {
+		panic(err)
+	}
+
+	assert.Equal(t, http.StatusConflict, int(responseBody["status"].(float64)))
+	assert.Equal(t, "conflict", responseBody["code"])
+}
+
+func TestSaveUserRegisteredEmailConcurrently(t *testing.T) {
+	ctx := context.Background()
+	users := make([]domain.User, 8)
+	errs := make([]error, len(users))
+
+	// Only one of the users registering the same email at once, regardless
+	// of its case, is saved; the others get a conflict.
+	var wait sync.WaitGroup
+	for i := range users {
+		users[i] = domain.User{Id: uuid.NewString(), Name: "Racer", Email: "racer@example.com", CreatedAt: time.Now().UnixMilli()}
+		if i%2 == 1 {
+			users[i].Email = "Racer@Example.com"
+		}
+		wait.Add(1)
+		go func(i int) {
+			defer wait.Done()
+			_, errs[i] = testRepositories.User.Save(ctx, users[i])
+		}(i)
+	}
+	wait.Wait()
+
+	saved := -1
+	for i, err := range errs {
+		if err == nil {
+			assert.Equal(t, -1, saved, "more than one user was saved")
+			saved = i
+			defer clearUserDataAfterTest(users[i].Id)
+		} else {
+			assert.ErrorIs(t, err, exception.ErrConflict)
+		}
+	}
+	assert.NotEqual(t, -1, saved)
+
+	// Another user cannot take the email by updating theirs, while the
+	// email is freed once its user is deleted.
+	other := createUser()
+	defer clearUserDataAfterTest(other.Id)
+	other.Email = "racer@example.com"
+	_, err := testRepositories.User.Update(ctx, other)
+	assert.ErrorIs(t, err, exception.ErrConflict)
+
+	err = testRepositories.User.Delete(ctx, users[saved])
+	assert.Nil(t, err)
+	_, err = testRepositories.User.Update(ctx, other)
+	assert.Nil(t, err)
 }